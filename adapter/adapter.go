@@ -0,0 +1,64 @@
+// Package adapter provides func types implementing single-method
+// interfaces, so scripts pass funcs where hosts expect interfaces
+// without declaring named types:
+//
+//	import "github.com/goplus/gossa/adapter"
+//
+//	io.Copy(os.Stdout, adapter.ReaderFunc(func(p []byte) (int, error) { ... }))
+//
+// Importing the package registers it for interpreted scripts.
+package adapter
+
+import (
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+// ReaderFunc is an adapter to allow the use of funcs as io.Reader.
+type ReaderFunc func(p []byte) (n int, err error)
+
+func (f ReaderFunc) Read(p []byte) (n int, err error) {
+	return f(p)
+}
+
+// WriterFunc is an adapter to allow the use of funcs as io.Writer.
+type WriterFunc func(p []byte) (n int, err error)
+
+func (f WriterFunc) Write(p []byte) (n int, err error) {
+	return f(p)
+}
+
+// CloserFunc is an adapter to allow the use of funcs as io.Closer.
+type CloserFunc func() error
+
+func (f CloserFunc) Close() error {
+	return f()
+}
+
+// StringerFunc is an adapter to allow the use of funcs as fmt.Stringer.
+type StringerFunc func() string
+
+func (f StringerFunc) String() string {
+	return f()
+}
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name:       "adapter",
+		Path:       "github.com/goplus/gossa/adapter",
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"CloserFunc":   {Typ: reflect.TypeOf((*CloserFunc)(nil)).Elem(), Methods: "Close"},
+			"ReaderFunc":   {Typ: reflect.TypeOf((*ReaderFunc)(nil)).Elem(), Methods: "Read"},
+			"StringerFunc": {Typ: reflect.TypeOf((*StringerFunc)(nil)).Elem(), Methods: "String"},
+			"WriterFunc":   {Typ: reflect.TypeOf((*WriterFunc)(nil)).Elem(), Methods: "Write"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,227 @@
+package gossa
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// NewPackageFromMap returns the package path with the items by name:
+//
+//	reflect.Type      type, named if declared in path, alias otherwise
+//	func              function
+//	pointer           variable pointed to
+//	constant.Value    untyped constant
+//	bool, number, string
+//	                  untyped constant, typed constant for named types
+//
+// The package can be registered by RegisterPackage or Context.RegisterPackage.
+func NewPackageFromMap(path string, items map[string]interface{}) (*Package, error) {
+	pkg := newBindPackage(path)
+	for name, item := range items {
+		if err := pkg.bind(name, item); err != nil {
+			return nil, err
+		}
+	}
+	return pkg.Package, nil
+}
+
+// NewPackageFromStruct returns the package path with the exported methods
+// and fields of the struct v, or of the struct pointed to by v, as items of
+// NewPackageFromMap. Methods are bound to v. Fields are variables if v is a
+// pointer, func fields are always functions.
+func NewPackageFromStruct(path string, v interface{}) (*Package, error) {
+	rv := reflect.ValueOf(v)
+	sv := reflect.Indirect(rv)
+	if sv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("bind %v: %T is not a struct or struct pointer", path, v)
+	}
+	pkg := newBindPackage(path)
+	rt := rv.Type()
+	for i := 0; i < rt.NumMethod(); i++ {
+		if m := rt.Method(i); m.PkgPath == "" {
+			pkg.Funcs[m.Name] = rv.Method(i)
+		}
+	}
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		if _, ok := pkg.Funcs[f.Name]; ok {
+			return nil, fmt.Errorf("bind %v: field %v conflicts with method", path, f.Name)
+		}
+		fv := sv.Field(i)
+		var err error
+		switch {
+		case f.Type.Kind() == reflect.Func:
+			err = pkg.bind(f.Name, fv.Interface())
+		case fv.CanAddr():
+			err = pkg.bind(f.Name, fv.Addr().Interface())
+		default:
+			err = pkg.bind(f.Name, fv.Interface())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pkg.Package, nil
+}
+
+type bindPackage struct {
+	*Package
+}
+
+func newBindPackage(path string) *bindPackage {
+	name := path[strings.LastIndex(path, "/")+1:]
+	return &bindPackage{&Package{
+		Name:          name,
+		Path:          path,
+		Deps:          make(map[string]string),
+		Interfaces:    make(map[string]reflect.Type),
+		NamedTypes:    make(map[string]NamedType),
+		AliasTypes:    make(map[string]reflect.Type),
+		Vars:          make(map[string]reflect.Value),
+		Funcs:         make(map[string]reflect.Value),
+		TypedConsts:   make(map[string]TypedConst),
+		UntypedConsts: make(map[string]UntypedConst),
+	}}
+}
+
+func (p *bindPackage) bind(name string, item interface{}) error {
+	if item == nil {
+		return fmt.Errorf("bind %v.%v: nil item", p.Path, name)
+	}
+	if rt, ok := item.(reflect.Type); ok {
+		p.bindType(name, rt)
+		return nil
+	}
+	if c, ok := item.(constant.Value); ok {
+		if c.Kind() == constant.Unknown {
+			return fmt.Errorf("bind %v.%v: unknown constant", p.Path, name)
+		}
+		p.UntypedConsts[name] = UntypedConst{untypedConstType(c.Kind()), c}
+		return nil
+	}
+	v := reflect.ValueOf(item)
+	switch v.Kind() {
+	case reflect.Func:
+		if v.IsNil() {
+			return fmt.Errorf("bind %v.%v: nil func", p.Path, name)
+		}
+		p.Funcs[name] = v
+	case reflect.Ptr:
+		if v.IsNil() {
+			return fmt.Errorf("bind %v.%v: nil pointer", p.Path, name)
+		}
+		p.Vars[name] = v
+	default:
+		c, ok := constantOf(v)
+		if !ok {
+			return fmt.Errorf("bind %v.%v: unsupported item %T", p.Path, name, item)
+		}
+		if v.Type().PkgPath() == "" {
+			p.UntypedConsts[name] = UntypedConst{untypedConstType(c.Kind()), c}
+		} else {
+			p.TypedConsts[name] = TypedConst{v.Type(), c}
+		}
+	}
+	p.addDeps(v.Type())
+	return nil
+}
+
+func (p *bindPackage) bindType(name string, rt reflect.Type) {
+	p.addDeps(rt)
+	if rt.PkgPath() != p.Path || rt.Name() != name {
+		p.AliasTypes[name] = rt
+		return
+	}
+	if rt.Kind() == reflect.Interface {
+		p.Interfaces[name] = rt
+		return
+	}
+	p.NamedTypes[name] = NamedType{
+		Typ:        rt,
+		Methods:    methodNames(rt),
+		PtrMethods: methodNames(reflect.PtrTo(rt)),
+	}
+}
+
+// addDeps adds the packages of the named types used by rt to the package deps.
+func (p *bindPackage) addDeps(rt reflect.Type) {
+	if path := rt.PkgPath(); path != "" {
+		if path != p.Path {
+			p.Deps[path] = path[strings.LastIndex(path, "/")+1:]
+		}
+		return
+	}
+	switch rt.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Ptr, reflect.Slice:
+		p.addDeps(rt.Elem())
+	case reflect.Map:
+		p.addDeps(rt.Key())
+		p.addDeps(rt.Elem())
+	case reflect.Func:
+		for i := 0; i < rt.NumIn(); i++ {
+			p.addDeps(rt.In(i))
+		}
+		for i := 0; i < rt.NumOut(); i++ {
+			p.addDeps(rt.Out(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < rt.NumField(); i++ {
+			p.addDeps(rt.Field(i).Type)
+		}
+	}
+}
+
+// methodNames returns the sorted exported method names of rt joined by comma.
+func methodNames(rt reflect.Type) string {
+	var names []string
+	for i := 0; i < rt.NumMethod(); i++ {
+		if m := rt.Method(i); m.PkgPath == "" {
+			names = append(names, m.Name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func constantOf(v reflect.Value) (constant.Value, bool) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return constant.MakeBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return constant.MakeUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return constant.MakeFloat64(v.Float()), true
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return constant.BinaryOp(constant.MakeFloat64(real(c)), token.ADD,
+			constant.MakeImag(constant.MakeFloat64(imag(c)))), true
+	case reflect.String:
+		return constant.MakeString(v.String()), true
+	}
+	return nil, false
+}
+
+func untypedConstType(kind constant.Kind) string {
+	switch kind {
+	case constant.Bool:
+		return types.Typ[types.UntypedBool].String()
+	case constant.String:
+		return types.Typ[types.UntypedString].String()
+	case constant.Float:
+		return types.Typ[types.UntypedFloat].String()
+	case constant.Complex:
+		return types.Typ[types.UntypedComplex].String()
+	}
+	return types.Typ[types.UntypedInt].String()
+}
@@ -0,0 +1,141 @@
+package gossa
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// BlockInfo describes how a basic block was compiled.
+type BlockInfo struct {
+	Block  *ssa.BasicBlock // ssa block
+	Offset int             // index of the block first closure in Function.Instrs
+	Instrs []InstrInfo     // all ssa instructions of the block, in order
+}
+
+// InstrInfo describes how an ssa instruction was compiled.
+type InstrInfo struct {
+	Instr  ssa.Instruction // ssa instruction
+	PC     int             // index in Function.Instrs, -1 if elided
+	Elided string          // reason of elided instruction
+}
+
+// BlockInfos returns the compiled blocks of the function, indexed by
+// ssa block index.
+func (p *Function) BlockInfos() []*BlockInfo {
+	return p.blockInfos
+}
+
+// Dump writes the listing of the compiled function to w: the registers
+// of the values by index, with the constant pool contents of constants,
+// globals and functions, and the instructions of each block with their pc,
+// or the reason if elided, followed by the registers of their value and
+// operands.
+func (p *Function) Dump(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%v %v\n", p.Fn, p.Fn.Signature)
+	values := make([]ssa.Value, 0, len(p.index))
+	for v := range p.index {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return p.index[values[i]]&0xffffff < p.index[values[j]]&0xffffff
+	})
+	b.WriteString("registers:\n")
+	for _, v := range values {
+		index, kind := p.index[v]&0xffffff, kind(p.index[v]>>24)
+		fmt.Fprintf(&b, "\tr%v\t%v\t%v", index, v.Name(), v.Type())
+		switch kind {
+		case kindConst:
+			fmt.Fprintf(&b, "\tconst %#v", p.stack[index])
+		case kindGlobal:
+			fmt.Fprintf(&b, "\tglobal %v", v)
+		case kindFunction:
+			fmt.Fprintf(&b, "\tfunc %v", v)
+		}
+		b.WriteByte('\n')
+	}
+	for _, info := range p.blockInfos {
+		fmt.Fprintf(&b, "%v:", info.Block.Index)
+		if info.Block.Comment != "" {
+			fmt.Fprintf(&b, " %v", info.Block.Comment)
+		}
+		b.WriteByte('\n')
+		for _, instr := range info.Instrs {
+			if instr.PC == -1 {
+				fmt.Fprintf(&b, "\t-\t%v\t(%v)\n", instrString(instr.Instr), instr.Elided)
+				continue
+			}
+			fmt.Fprintf(&b, "\t%v\t%v", instr.PC, instrString(instr.Instr))
+			if regs := p.instrRegs(instr.Instr); regs != "" {
+				fmt.Fprintf(&b, "\t%v", regs)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// instrString returns instr printed like ssa function listings.
+func instrString(instr ssa.Instruction) string {
+	if v, ok := instr.(ssa.Value); ok && v.Name() != "" {
+		return v.Name() + " = " + instr.String()
+	}
+	return instr.String()
+}
+
+// instrRegs returns the registers of the value and the operands of instr,
+// eg. "r2 <- r0 r1".
+func (p *Function) instrRegs(instr ssa.Instruction) string {
+	var regs []string
+	for _, op := range instr.Operands(nil) {
+		if op == nil || *op == nil {
+			continue
+		}
+		if i, ok := p.index[*op]; ok {
+			regs = append(regs, fmt.Sprintf("r%v", i&0xffffff))
+		}
+	}
+	s := strings.Join(regs, " ")
+	if v, ok := instr.(ssa.Value); ok {
+		if i, ok := p.index[v]; ok {
+			s = strings.TrimSpace(fmt.Sprintf("r%v <- %v", i&0xffffff, s))
+		}
+	}
+	return s
+}
+
+// LookupFunction returns the compiled function of fn.
+func (i *Interp) LookupFunction(fn *ssa.Function) (*Function, bool) {
+	i.funcsMutex.RLock()
+	pfn, ok := i.funcs[fn]
+	i.funcsMutex.RUnlock()
+	return pfn, ok
+}
+
+// elidedReason reports why makeInstr emits no closure for instr.
+func elidedReason(instr ssa.Instruction) string {
+	switch instr := instr.(type) {
+	case *ssa.Extract:
+		return "unreferenced extract"
+	case *ssa.Store:
+		if addr, ok := instr.Addr.(*ssa.FieldAddr); ok {
+			if s, ok := addr.X.Type().(*types.Pointer).Elem().(*types.Struct); ok {
+				if s.Field(addr.Field).Name() == "_" {
+					return "store to blank field"
+				}
+			}
+		}
+	case *ssa.Call:
+		if fn, ok := instr.Call.Value.(*ssa.Function); ok && fn.Name() == "init" {
+			return "no code for package init"
+		}
+	}
+	return "no effect"
+}
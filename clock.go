@@ -0,0 +1,322 @@
+package gossa
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of a program, see Interp.SetClock.
+type Clock interface {
+	Now() time.Time
+	// Sleep returns after d, a fake clock advances its time by d.
+	Sleep(d time.Duration)
+	// AfterFunc calls f after d, unless stop is called before, which
+	// reports whether it stopped the call. f does not block.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// SetClock sets the clock of time.Now, time.Since, time.Until, time.Sleep,
+// time.After, time.Tick, time.NewTimer, time.AfterFunc and time.NewTicker
+// for the program, and of the Stop and Reset methods of the timers and
+// tickers they create. A nil c restores the host clock. SetClock should be
+// called before running the interpreter.
+func (i *Interp) SetClock(c Clock) {
+	i.clock = c
+}
+
+// clockTimer is a time.Timer or time.Ticker of a Clock.
+type clockTimer struct {
+	mu     sync.Mutex
+	clock  Clock
+	fire   func()
+	period time.Duration // of a ticker
+	stop   func() bool
+}
+
+func (t *clockTimer) start(d time.Duration) {
+	t.stop = t.clock.AfterFunc(d, func() {
+		t.fire()
+		t.mu.Lock()
+		if t.period > 0 {
+			t.start(t.period)
+		}
+		t.mu.Unlock()
+	})
+}
+
+// Stop stops the timer, and reports whether it was active.
+func (t *clockTimer) Stop() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.period = 0
+	return t.stop()
+}
+
+// Reset restarts the timer after d, a ticker of period d.
+func (t *clockTimer) Reset(d time.Duration, ticker bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	active := t.stop()
+	if ticker {
+		t.period = d
+	}
+	t.start(d)
+	return active
+}
+
+// newClockTimer returns the timer of c firing fire after d, and every
+// period after if period is positive.
+func newClockTimer(c Clock, d, period time.Duration, fire func()) *clockTimer {
+	t := &clockTimer{clock: c, fire: fire, period: period}
+	t.mu.Lock()
+	t.start(d)
+	t.mu.Unlock()
+	return t
+}
+
+// sendTime returns the func sending the time of c to ch without blocking,
+// like the runtime timers.
+func sendTime(c Clock, ch chan time.Time) func() {
+	return func() {
+		select {
+		case ch <- c.Now():
+		default:
+		}
+	}
+}
+
+// setClockTimer sets ct as the clockTimer of the *time.Timer or
+// *time.Ticker t. The entry is kept for Reset after t fires or stops, and
+// deleted when t is garbage collected: it is keyed by the address of t,
+// not keeping t alive.
+func (i *Interp) setClockTimer(t interface{}, ct *clockTimer) {
+	key := reflect.ValueOf(t).Pointer()
+	i.timers.Store(key, ct)
+	runtime.SetFinalizer(t, func(interface{}) {
+		i.timers.Delete(key)
+	})
+}
+
+// clockTimerOf returns the clockTimer of the *time.Timer or *time.Ticker t.
+func (i *Interp) clockTimerOf(t interface{}) *clockTimer {
+	if v, ok := i.timers.Load(reflect.ValueOf(t).Pointer()); ok {
+		return v.(*clockTimer)
+	}
+	return nil
+}
+
+// findClockFunc returns the function name of the time package bound to the
+// clock of interp, see SetClock.
+func findClockFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	var fn interface{}
+	switch name {
+	case "time.Now":
+		fn = func() time.Time {
+			if c := interp.clock; c != nil {
+				return c.Now()
+			}
+			return time.Now()
+		}
+	case "time.Since":
+		fn = func(t time.Time) time.Duration {
+			if c := interp.clock; c != nil {
+				return c.Now().Sub(t)
+			}
+			return time.Since(t)
+		}
+	case "time.Until":
+		fn = func(t time.Time) time.Duration {
+			if c := interp.clock; c != nil {
+				return t.Sub(c.Now())
+			}
+			return time.Until(t)
+		}
+	case "time.Sleep":
+		fn = func(d time.Duration) {
+			if c := interp.clock; c != nil {
+				c.Sleep(d)
+				return
+			}
+			time.Sleep(d)
+		}
+	case "time.After":
+		fn = func(d time.Duration) <-chan time.Time {
+			c := interp.clock
+			if c == nil {
+				return time.After(d)
+			}
+			ch := make(chan time.Time, 1)
+			newClockTimer(c, d, 0, sendTime(c, ch))
+			return ch
+		}
+	case "time.Tick":
+		fn = func(d time.Duration) <-chan time.Time {
+			c := interp.clock
+			if c == nil {
+				return time.Tick(d)
+			}
+			if d <= 0 {
+				return nil
+			}
+			ch := make(chan time.Time, 1)
+			newClockTimer(c, d, d, sendTime(c, ch))
+			return ch
+		}
+	case "time.NewTimer":
+		fn = func(d time.Duration) *time.Timer {
+			c := interp.clock
+			if c == nil {
+				return time.NewTimer(d)
+			}
+			ch := make(chan time.Time, 1)
+			t := &time.Timer{C: ch}
+			interp.setClockTimer(t, newClockTimer(c, d, 0, sendTime(c, ch)))
+			return t
+		}
+	case "time.AfterFunc":
+		fn = func(d time.Duration, f func()) *time.Timer {
+			c := interp.clock
+			if c == nil {
+				return time.AfterFunc(d, f)
+			}
+			t := &time.Timer{}
+			interp.setClockTimer(t, newClockTimer(c, d, 0, func() { go f() }))
+			return t
+		}
+	case "time.NewTicker":
+		fn = func(d time.Duration) *time.Ticker {
+			c := interp.clock
+			if c == nil {
+				return time.NewTicker(d)
+			}
+			if d <= 0 {
+				panic("non-positive interval for NewTicker")
+			}
+			ch := make(chan time.Time, 1)
+			t := &time.Ticker{C: ch}
+			interp.setClockTimer(t, newClockTimer(c, d, d, sendTime(c, ch)))
+			return t
+		}
+	case "(*time.Timer).Stop":
+		fn = func(t *time.Timer) bool {
+			if ct := interp.clockTimerOf(t); ct != nil {
+				return ct.Stop()
+			}
+			return t.Stop()
+		}
+	case "(*time.Timer).Reset":
+		fn = func(t *time.Timer, d time.Duration) bool {
+			if ct := interp.clockTimerOf(t); ct != nil {
+				return ct.Reset(d, false)
+			}
+			return t.Reset(d)
+		}
+	case "(*time.Ticker).Stop":
+		fn = func(t *time.Ticker) {
+			if ct := interp.clockTimerOf(t); ct != nil {
+				ct.Stop()
+				return
+			}
+			t.Stop()
+		}
+	case "(*time.Ticker).Reset":
+		fn = func(t *time.Ticker, d time.Duration) {
+			if ct := interp.clockTimerOf(t); ct != nil {
+				if d <= 0 {
+					panic("non-positive interval for Ticker.Reset")
+				}
+				ct.Reset(d, true)
+				return
+			}
+			t.Reset(d)
+		}
+	default:
+		return
+	}
+	return reflect.ValueOf(fn), true
+}
+
+// FakeClock is a Clock of virtual time, advanced by Advance and by Sleep,
+// which fire the timers due at once: the sleeps and timers of hours of a
+// scheduler script take no time. The goroutines sleeping all advance the
+// one time of the clock.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    int
+	events []*fakeEvent
+}
+
+type fakeEvent struct {
+	when time.Time
+	seq  int // firing order of the events of the same time
+	f    func()
+}
+
+// NewFakeClock returns a FakeClock of the time now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) (stop func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	e := &fakeEvent{when: c.now.Add(d), seq: c.seq, f: f}
+	c.events = append(c.events, e)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for n, v := range c.events {
+			if v == e {
+				c.events = append(c.events[:n], c.events[n+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance advances the clock by d, firing the timers due in time order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		sort.Slice(c.events, func(i, j int) bool {
+			if c.events[i].when.Equal(c.events[j].when) {
+				return c.events[i].seq < c.events[j].seq
+			}
+			return c.events[i].when.Before(c.events[j].when)
+		})
+		if len(c.events) == 0 || c.events[0].when.After(end) {
+			break
+		}
+		e := c.events[0]
+		c.events = c.events[1:]
+		if e.when.After(c.now) {
+			c.now = e.when
+		}
+		c.mu.Unlock()
+		e.f()
+		c.mu.Lock()
+	}
+	// the timers may advance the clock past end
+	if end.After(c.now) {
+		c.now = end
+	}
+	c.mu.Unlock()
+}
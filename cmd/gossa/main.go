@@ -23,10 +23,13 @@ import (
 	"strings"
 
 	"github.com/goplus/gossa/cmd/internal/base"
+	"github.com/goplus/gossa/cmd/internal/conform"
 	"github.com/goplus/gossa/cmd/internal/help"
 	"github.com/goplus/gossa/cmd/internal/run"
 	"github.com/goplus/gossa/cmd/internal/test"
+	"github.com/goplus/gossa/worker"
 
+	_ "github.com/goplus/gossa/adapter"
 	_ "github.com/goplus/gossa/pkg"
 	_ "github.com/goplus/reflectx/icall/icall8192"
 )
@@ -41,10 +44,12 @@ func init() {
 	base.Gossa.Commands = []*base.Command{
 		run.Cmd,
 		test.Cmd,
+		conform.Cmd,
 	}
 }
 
 func main() {
+	worker.Main()
 	flag.Parse()
 	args := flag.Args()
 	if len(args) < 1 {
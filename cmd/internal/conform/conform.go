@@ -0,0 +1,100 @@
+/*
+ Copyright 2021 The GoPlus Authors (goplus.org)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package conform implements the ``gossa conform'' command.
+package conform
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/goplus/gossa/cmd/internal/base"
+	"github.com/goplus/gossa/conformance"
+)
+
+// Cmd - gossa conform
+var Cmd = &base.Command{
+	UsageLine: "gossa conform [-v] [-run regexp] [-errata file] [-isolate] [-compare] [-timeout d] dir",
+	Short:     "run $GOROOT/test style conformance tests",
+}
+
+var (
+	flag        = &Cmd.Flag
+	flagVerbose bool
+	flagRun     string
+	flagErrata  string
+	flagIsolate bool
+	flagCompare bool
+	flagTimeout time.Duration
+)
+
+func init() {
+	Cmd.Run = runCmd
+	flag.BoolVar(&flagVerbose, "v", false, "print the status of each test")
+	flag.StringVar(&flagRun, "run", "", "run only tests matching the regexp")
+	flag.StringVar(&flagErrata, "errata", "", "file of known failures")
+	flag.BoolVar(&flagIsolate, "isolate", false, "run each test in a worker process")
+	flag.BoolVar(&flagCompare, "compare", false, "compare run tests with the programs compiled by the go command")
+	flag.DurationVar(&flagTimeout, "timeout", time.Minute, "timeout of each test")
+}
+
+func runCmd(cmd *base.Command, args []string) {
+	flag.Parse(args)
+	if flag.NArg() != 1 {
+		cmd.Usage(os.Stderr)
+		os.Exit(2)
+	}
+	s := &conformance.Suite{
+		Dir:      flag.Arg(0),
+		Isolated: flagIsolate,
+		Compare:  flagCompare,
+		Timeout:  flagTimeout,
+	}
+	if flagRun != "" {
+		re, err := regexp.Compile(flagRun)
+		if err != nil {
+			log.Fatalln("invalid -run:", err)
+		}
+		s.Match = re
+	}
+	if flagErrata != "" {
+		f, err := os.Open(flagErrata)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		s.Errata, err = conformance.ParseErrata(f)
+		f.Close()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if flagVerbose {
+		s.Progress = func(r *conformance.Result) {
+			fmt.Fprintf(os.Stderr, "%v %v %0.3fs %v\n", r.Status, r.Name, r.Duration.Seconds(), r.Reason)
+		}
+	}
+	report, err := s.Run()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	report.WriteTo(os.Stdout)
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"go/build"
 	"hash/fnv"
 	"os"
 	"path/filepath"
@@ -12,7 +13,32 @@ import (
 )
 
 func apipath(base string) string {
-	return filepath.Join(os.Getenv("GOROOT"), "api", base)
+	return filepath.Join(build.Default.GOROOT, "api", base)
+}
+
+// sinceInclude returns the regexp of the symbols of pkg added by api on
+// all platforms, the types of added methods included, or nil if none.
+func sinceInclude(api *GoApi, pkg string) *regexp.Regexp {
+	seen := make(map[string]bool)
+	var names []string
+	for _, info := range api.Keys {
+		if info.Pkg != pkg || info.Tags[0] != "-" {
+			continue
+		}
+		name := info.Name
+		if info.Kind == "method" {
+			name = info.MethodType
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return regexp.MustCompile("^(" + strings.Join(names, "|") + ")$")
 }
 
 //pkg syscall (windows-386), const CERT_E_CN_NO_MATCH = 2148204815
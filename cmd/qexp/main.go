@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"go/build"
 	"log"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/goplus/gossa/export"
 )
 
 var (
@@ -15,6 +20,13 @@ var (
 	flagBuildContext   string
 	flagCustomTags     string
 	flagExportFileName string
+	flagInclude        string
+	flagExclude        string
+	flagDoc            bool
+	flagSince          string
+	flagUsed           string
+	sinceApi           *GoApi
+	usedSymbols        map[string][]string
 )
 
 func init() {
@@ -23,17 +35,45 @@ func init() {
 	flag.StringVar(&flagBuildContext, "contexts", "", "set custome build contexts goos_goarch list. eg \"drawin_amd64 darwin_arm64\"")
 	flag.StringVar(&flagCustomTags, "addtags", "", "add custom tags, split by ;")
 	flag.StringVar(&flagExportFileName, "filename", "export", "set export file name")
+	flag.StringVar(&flagInclude, "include", "", "export only symbols matching regexp")
+	flag.StringVar(&flagExclude, "exclude", "", "skip symbols matching regexp")
+	flag.BoolVar(&flagDoc, "doc", false, "comment exported symbols with their doc")
+	flag.StringVar(&flagSince, "since", "", "export only the symbols added in a Go release by $GOROOT/api, eg. go1.21")
+	flag.StringVar(&flagUsed, "used", "", "export only the symbols used by the Go files of dir, all used packages by default")
 }
 
 func main() {
 	flag.Parse()
 	args := flag.Args()
+	if flagUsed != "" {
+		used, err := loadUsed(flagUsed)
+		if err != nil {
+			log.Fatalln("load used symbols failed", err)
+		}
+		usedSymbols = used
+		if len(args) == 0 {
+			for pkg := range used {
+				args = append(args, pkg)
+			}
+			sort.Strings(args)
+		}
+	}
 	if len(args) == 0 {
 		flag.Usage()
 	}
 	if len(args) == 1 && args[0] == "std" {
 		args = stdList
 	}
+	if flagSince != "" {
+		api, err := LoadApi(flagSince, false)
+		if err != nil {
+			log.Fatalln("load api failed", err)
+		}
+		sinceApi = api
+		if flagExportFileName == "export" {
+			flagExportFileName = strings.TrimSuffix(export.VersionFileName(flagSince), ".go")
+		}
+	}
 	if flagExportFileName == "" {
 		flagExportFileName = "export"
 	}
@@ -114,16 +154,39 @@ func Export(pkg string, ctxList []*build.Context) {
 }
 
 func ExportPkg(pkg string, ctx *build.Context) (string, error) {
-	prog, err := loadProgram(pkg, ctx)
+	opts := &export.Options{Build: ctx, Doc: flagDoc}
+	if flagCustomTags != "" {
+		opts.Tags = strings.Split(flagCustomTags, ";")
+	}
+	if flagInclude != "" {
+		opts.Include = regexp.MustCompile(flagInclude)
+	}
+	if flagExclude != "" {
+		opts.Exclude = regexp.MustCompile(flagExclude)
+	}
+	if sinceApi != nil {
+		if opts.Include = sinceInclude(sinceApi, pkg); opts.Include == nil {
+			return "", fmt.Errorf("no symbols of %v added in %v", pkg, flagSince)
+		}
+		if opts.Tags == nil {
+			opts.Tags = export.SinceTags(flagSince)
+		}
+	}
+	if usedSymbols != nil {
+		names, ok := usedSymbols[pkg]
+		if !ok {
+			return "", fmt.Errorf("no symbols of %v used in %v", pkg, flagUsed)
+		}
+		opts.Include = export.IncludeNames(names)
+	}
+	e, err := export.Load(pkg, opts)
 	if err != nil {
-		return "", fmt.Errorf("load pkg %v error: %v", pkg, err)
+		return "", err
 	}
-	e := prog.ExportPkg(pkg, "q")
-	var tags []string
-	if flagCustomTags != "" {
-		tags = strings.Split(flagCustomTags, ";")
+	if e.IsEmpty() {
+		return "", fmt.Errorf("no symbols of %v to export", pkg)
 	}
-	data, err := exportPkg(e, "q", "", tags)
+	data, err := e.Source()
 	if err != nil {
 		panic(err)
 	}
@@ -142,6 +205,25 @@ func ExportPkg(pkg string, ctx *build.Context) (string, error) {
 	return filepath.Join(fpath, fname), err
 }
 
+// loadUsed returns the symbols used by the Go files of dir and its
+// subdirectories, see export.UsedSymbols.
+func loadUsed(dir string) (map[string][]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return export.UsedSymbols(files...)
+}
+
 func parserContextList(list string) (ctxs []*build.Context) {
 	for _, info := range strings.Split(list, " ") {
 		info = strings.TrimSpace(info)
@@ -0,0 +1,460 @@
+// Package conformance runs a directory of $GOROOT/test style programs as
+// a suite, so forks and embedders can check that their registrations and
+// options keep the interpreter conforming to the language.
+//
+// The first line comment of a test file is its action:
+//
+//	// run [args]    the program must exit 0 with the output of name.out
+//	// compile       the program must load
+//	// build         the program must load
+//	// errorcheck    the program must fail to load
+//
+// Other actions are skipped, as are files excluded by build constraints
+// and directories named testdata or *.dir.
+//
+// In Compare mode the run tests, and the files without action, are
+// differential tests of main programs: each program is also built by the
+// go command and run, and the interpreted program must exit with the code
+// and the combined stdout and stderr output of the compiled program. The
+// output of a panicking program ends at its "panic: " line, as the
+// goroutine traces of compiled and interpreted programs differ. Embedders
+// validate their package registrations, and the interpreter new Go
+// releases, against compiled Go:
+//
+//	s := &conformance.Suite{Dir: "testdata", Compare: true, Timeout: time.Minute}
+//	r, err := s.Run()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	if r.Failed() {
+//		r.WriteTo(os.Stderr)
+//		t.Fail()
+//	}
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/build"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goplus/gossa"
+	"github.com/goplus/gossa/worker"
+	"github.com/goplus/reflectx"
+)
+
+// Status is the status of a test.
+type Status int
+
+const (
+	Pass           Status = iota // test passed
+	Fail                         // test failed
+	Skip                         // test skipped
+	ExpectedFail                 // test in errata failed
+	UnexpectedPass               // test in errata passed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "PASS"
+	case Fail:
+		return "FAIL"
+	case Skip:
+		return "SKIP"
+	case ExpectedFail:
+		return "XFAIL"
+	case UnexpectedPass:
+		return "XPASS"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Suite is a conformance test suite.
+type Suite struct {
+	Dir        string                // test directory
+	Mode       gossa.Mode            // interpreter options
+	NewContext func() *gossa.Context // creates the context of a test, gossa.NewContext(Mode) if nil, not used if Isolated
+	Errata     map[string]string     // known failures: slash separated name => reason
+	Skips      map[string]string     // skipped tests: slash separated name => reason
+	Match      *regexp.Regexp        // run only tests with matching names, if non-nil
+	Timeout    time.Duration         // timeout of a test, if non-zero
+	Isolated   bool                  // run tests in worker processes, see package worker
+	Compare    bool                  // compare run tests with the compiled programs
+	GoCmd      string                // go command building the compiled programs, "go" if empty
+	Progress   func(r *Result)       // called after each test, if non-nil
+}
+
+// Result is the result of a test.
+type Result struct {
+	Name     string        // slash separated name relative to Dir
+	Status   Status        // test status
+	Reason   string        // failure or skip reason
+	Output   string        // combined output of run tests
+	Want     string        // combined output of the compiled program in Compare mode
+	Duration time.Duration // test duration
+}
+
+// Report is the results of a suite run, sorted by name.
+type Report struct {
+	Results []*Result
+}
+
+// Count returns the number of results with status s.
+func (r *Report) Count(s Status) (n int) {
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return
+}
+
+// Failed reports whether any test failed.
+func (r *Report) Failed() bool {
+	return r.Count(Fail) != 0
+}
+
+// WriteTo writes the failures and summary of the report to w.
+func (r *Report) WriteTo(w io.Writer) (n int64, err error) {
+	var buf bytes.Buffer
+	for _, res := range r.Results {
+		if res.Status == Fail || res.Status == UnexpectedPass {
+			fmt.Fprintf(&buf, "%v %v: %v\n", res.Status, res.Name, res.Reason)
+		}
+	}
+	fmt.Fprintf(&buf, "%d passed, %d failed, %d skipped, %d expected failures, %d unexpected passes\n",
+		r.Count(Pass), r.Count(Fail), r.Count(Skip), r.Count(ExpectedFail), r.Count(UnexpectedPass))
+	return buf.WriteTo(w)
+}
+
+// ParseErrata parses an errata file. Each line is a test name followed
+// by an optional reason, separated by a colon; # starts a comment.
+func ParseErrata(r io.Reader) (map[string]string, error) {
+	errata := make(map[string]string)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, reason := line, "errata"
+		if i := strings.Index(line, ":"); i >= 0 {
+			name, reason = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		errata[name] = reason
+	}
+	return errata, s.Err()
+}
+
+// Run runs the tests of the suite.
+func (s *Suite) Run() (*Report, error) {
+	root, err := filepath.Abs(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && (info.Name() == "testdata" || strings.HasSuffix(info.Name(), ".dir")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	report := &Report{}
+	for _, name := range names {
+		if s.Match != nil && !s.Match.MatchString(name) {
+			continue
+		}
+		start := time.Now()
+		res := s.runTest(root, name)
+		res.Duration = time.Since(start)
+		if reason, ok := s.Errata[name]; ok {
+			switch res.Status {
+			case Fail:
+				res.Status, res.Reason = ExpectedFail, reason+": "+res.Reason
+			case Pass:
+				res.Status, res.Reason = UnexpectedPass, "errata "+reason
+			}
+		}
+		report.Results = append(report.Results, res)
+		if s.Progress != nil {
+			s.Progress(res)
+		}
+	}
+	return report, nil
+}
+
+func (s *Suite) runTest(root string, name string) *Result {
+	res := &Result{Name: name}
+	if reason, ok := s.Skips[name]; ok {
+		res.Status, res.Reason = Skip, reason
+		return res
+	}
+	path := filepath.Join(root, filepath.FromSlash(name))
+	dir, file := filepath.Split(path)
+	if ok, err := build.Default.MatchFile(dir, file); err != nil {
+		res.Status, res.Reason = Fail, err.Error()
+		return res
+	} else if !ok {
+		res.Status, res.Reason = Skip, "build constraints"
+		return res
+	}
+	action, args, err := readAction(path)
+	if err != nil {
+		res.Status, res.Reason = Fail, err.Error()
+		return res
+	}
+	switch {
+	case s.Compare && (action == "run" || action == ""):
+		s.compare(res, path, args)
+	case action == "run":
+		s.run(res, path, args)
+	case action == "compile" || action == "build":
+		if err := s.load(path); err != nil {
+			res.Status, res.Reason = Fail, err.Error()
+		}
+	case action == "errorcheck":
+		if err := s.load(path); err == nil {
+			res.Status, res.Reason = Fail, "no compile error"
+		}
+	default:
+		res.Status, res.Reason = Skip, "unsupported action "+strings.TrimSpace(action)
+	}
+	return res
+}
+
+// readAction returns the action and arguments of the first line comment.
+func readAction(path string) (action string, args []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "//go:build") || strings.HasPrefix(line, "// +build") {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "//"))
+		if len(fields) == 0 {
+			break
+		}
+		return fields[0], fields[1:], nil
+	}
+	return "", nil, s.Err()
+}
+
+func (s *Suite) newContext() *gossa.Context {
+	if s.NewContext != nil {
+		return s.NewContext()
+	}
+	return gossa.NewContext(s.Mode)
+}
+
+func (s *Suite) load(path string) error {
+	reflectx.Reset()
+	_, err := s.newContext().LoadFile(token.NewFileSet(), path, nil)
+	return err
+}
+
+// interpret runs the program at path by the interpreter.
+func (s *Suite) interpret(path string, args []string) (output string, code int, err error) {
+	if !s.Isolated {
+		return s.runInProcess(path, args)
+	}
+	var out bytes.Buffer
+	r := &worker.Runner{
+		Mode:    s.Mode,
+		Dir:     filepath.Dir(path),
+		Stdout:  &out,
+		Stderr:  &out,
+		Timeout: s.Timeout,
+	}
+	code, err = r.RunFile(path, nil, args)
+	return out.String(), code, err
+}
+
+func (s *Suite) run(res *Result, path string, args []string) {
+	var code int
+	var err error
+	res.Output, code, err = s.interpret(path, args)
+	switch {
+	case err != nil:
+		res.Status, res.Reason = Fail, err.Error()
+		return
+	case code != 0:
+		res.Status, res.Reason = Fail, fmt.Sprintf("exit code %v", code)
+		return
+	}
+	want, err := ioutil.ReadFile(strings.TrimSuffix(path, ".go") + ".out")
+	if err != nil && !os.IsNotExist(err) {
+		res.Status, res.Reason = Fail, err.Error()
+		return
+	}
+	if res.Output != string(want) {
+		res.Status, res.Reason = Fail, "unexpected output"
+	}
+}
+
+// compare runs the program at path by the interpreter and compiled by the
+// go command, comparing their exit codes and outputs.
+func (s *Suite) compare(res *Result, path string, args []string) {
+	want, wantCode, err := s.runCompiled(path, args)
+	if err != nil {
+		res.Status, res.Reason = Fail, err.Error()
+		return
+	}
+	res.Want = want
+	output, code, err := s.interpret(path, args)
+	if err != nil {
+		output += "panic: " + panicString(err) + "\n"
+	}
+	res.Output = trimPanic(output)
+	switch {
+	case code != wantCode:
+		res.Status, res.Reason = Fail, fmt.Sprintf("exit code %v, want %v", code, wantCode)
+	case res.Output != res.Want:
+		res.Status, res.Reason = Fail, "unexpected output"
+	}
+}
+
+// runCompiled builds the program at path and runs it.
+func (s *Suite) runCompiled(path string, args []string) (output string, code int, err error) {
+	gocmd := s.GoCmd
+	if gocmd == "" {
+		gocmd = "go"
+	}
+	bin, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(bin)
+	exe := filepath.Join(bin, strings.TrimSuffix(filepath.Base(path), ".go"))
+	cmd := exec.Command(gocmd, "build", "-o", exe, filepath.Base(path))
+	cmd.Dir = filepath.Dir(path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", 0, fmt.Errorf("go build: %v\n%s", err, out)
+	}
+	ctx := context.Background()
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	var out bytes.Buffer
+	cmd = exec.CommandContext(ctx, exe, args...)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return "", 0, fmt.Errorf("compiled: timeout after %v", s.Timeout)
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", 0, err
+	}
+	return trimPanic(out.String()), cmd.ProcessState.ExitCode(), nil
+}
+
+// panicString returns the run error err printed like a panic value by the
+// Go runtime.
+func panicString(err error) string {
+	if info, ok := gossa.TargetPanic(err); ok {
+		switch v := info.Value.(type) {
+		case error:
+			return v.Error()
+		case fmt.Stringer:
+			return v.String()
+		}
+	}
+	return err.Error()
+}
+
+// trimPanic returns output up to the end of its first "panic: " line.
+func trimPanic(output string) string {
+	i := strings.Index(output, "panic: ")
+	for i > 0 && output[i-1] != '\n' {
+		j := strings.Index(output[i+1:], "panic: ")
+		if j < 0 {
+			return output
+		}
+		i += 1 + j
+	}
+	if i < 0 {
+		return output
+	}
+	if j := strings.IndexByte(output[i:], '\n'); j >= 0 {
+		return output[:i+j+1]
+	}
+	return output + "\n"
+}
+
+// runInProcess runs the program at path, capturing its output by
+// gossa.CapturedOutput. A timed out program keeps running in the
+// background.
+func (s *Suite) runInProcess(path string, args []string) (output string, code int, err error) {
+	var out bytes.Buffer
+	gossa.CapturedOutput = &out
+	defer func() {
+		gossa.CapturedOutput = nil
+	}()
+	type result struct {
+		code int
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		reflectx.Reset()
+		code, err := s.newContext().RunFile(path, nil, args)
+		done <- result{code, err}
+	}()
+	var timeout <-chan time.Time
+	if s.Timeout > 0 {
+		timeout = time.After(s.Timeout)
+	}
+	select {
+	case res := <-done:
+		code, err = res.code, res.err
+	case <-timeout:
+		code, err = 2, fmt.Errorf("timeout after %v", s.Timeout)
+	}
+	return out.String(), code, err
+}
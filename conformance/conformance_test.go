@@ -0,0 +1,277 @@
+package conformance
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/goplus/gossa/pkg/fmt"
+	_ "github.com/goplus/gossa/pkg/os"
+	"github.com/goplus/gossa/worker"
+)
+
+func TestMain(m *testing.M) {
+	worker.Main()
+	os.Exit(m.Run())
+}
+
+var suiteFiles = map[string]string{
+	"hello.go": `// run
+
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+	println("world")
+}
+`,
+	"hello.out": "hello\nworld\n",
+	"exit.go": `// run
+
+package main
+
+func main() {
+	panic("BUG")
+}
+`,
+	"output.go": `// run
+
+package main
+
+func main() {
+	println("unexpected")
+}
+`,
+	"known.go": `// run
+
+package main
+
+func main() {
+	var m map[int]int
+	m[0] = 1
+}
+`,
+	"sub/compile.go": `// compile
+
+package p
+
+func F() int { return 1 }
+`,
+	"sub/errors.go": `// errorcheck
+
+package main
+
+func main() {
+	x := 1 // ERROR "declared but not used"
+}
+`,
+	"sub/rundir.go": `// rundir
+
+package ignored
+`,
+	"never.go": `//go:build never
+// +build never
+
+// run
+
+package main
+
+func main() {}
+`,
+	"testdata/skipped.go": `// run
+
+package main
+
+func main() { panic(0) }
+`,
+}
+
+var compareFiles = map[string]string{
+	"hello.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+	println("world")
+}
+`,
+	"args.go": `// run a b
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, os.Args[1:])
+}
+`,
+	"exit.go": `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println("exit")
+	os.Exit(3)
+}
+`,
+	"panic.go": `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("before")
+	panic(fmt.Sprint("boom ", 1))
+}
+`,
+	"runtime.go": `package main
+
+func main() {
+	var a []int
+	println(len(a))
+	println(a[1])
+}
+`,
+	"missing.go": `package main
+
+import (
+	"fmt"
+	"net/url"
+)
+
+func main() {
+	fmt.Println(url.PathEscape("a b"))
+}
+`,
+	"compile.go": `// compile
+
+package p
+`,
+	"never.go": `//go:build never
+// +build never
+
+package main
+
+func main() {}
+`,
+	"testdata/data.go": `package main
+
+func main() { panic(0) }
+`,
+}
+
+func writeSuite(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func checkReport(t *testing.T, r *Report) {
+	want := map[string]Status{
+		"exit.go":        Fail,
+		"hello.go":       Pass,
+		"known.go":       ExpectedFail,
+		"never.go":       Skip,
+		"output.go":      Fail,
+		"sub/compile.go": Pass,
+		"sub/errors.go":  Pass,
+		"sub/rundir.go":  Skip,
+	}
+	if len(r.Results) != len(want) {
+		t.Fatalf("bad results %v", len(r.Results))
+	}
+	for _, res := range r.Results {
+		if res.Status != want[res.Name] {
+			t.Errorf("%v: status %v, want %v (%v)", res.Name, res.Status, want[res.Name], res.Reason)
+		}
+	}
+	if !r.Failed() {
+		t.Fatal("must failed")
+	}
+	var sb strings.Builder
+	r.WriteTo(&sb)
+	if !strings.HasSuffix(sb.String(), "3 passed, 2 failed, 2 skipped, 1 expected failures, 0 unexpected passes\n") {
+		t.Fatalf("bad summary %q", sb.String())
+	}
+}
+
+func TestSuite(t *testing.T) {
+	dir := writeSuite(t, suiteFiles)
+	defer os.RemoveAll(dir)
+	errata, err := ParseErrata(strings.NewReader("# known failures\nknown.go: nil map\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, isolated := range []bool{false, true} {
+		s := &Suite{Dir: dir, Errata: errata, Isolated: isolated, Timeout: 10 * time.Second}
+		r, err := s.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkReport(t, r)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("no go command")
+	}
+	dir := writeSuite(t, compareFiles)
+	defer os.RemoveAll(dir)
+	want := map[string]Status{
+		"args.go":    Pass,
+		"compile.go": Pass,
+		"exit.go":    Pass,
+		"hello.go":   Pass,
+		"missing.go": Fail,
+		"never.go":   Skip,
+		"panic.go":   Pass,
+		"runtime.go": Pass,
+	}
+	for _, isolated := range []bool{false, true} {
+		s := &Suite{Dir: dir, Compare: true, Isolated: isolated, Timeout: time.Minute}
+		r, err := s.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Results) != len(want) {
+			t.Fatalf("bad results %v", len(r.Results))
+		}
+		for _, res := range r.Results {
+			if res.Status != want[res.Name] {
+				t.Errorf("isolated %v: %v: status %v, want %v (%v %q %q)", isolated, res.Name, res.Status, want[res.Name], res.Reason, res.Output, res.Want)
+			}
+		}
+		if res := r.Results[3]; res.Name != "hello.go" || res.Output != "hello\nworld\n" {
+			t.Fatalf("bad hello result %+v", res)
+		}
+		var sb strings.Builder
+		r.WriteTo(&sb)
+		if !strings.HasSuffix(sb.String(), "6 passed, 1 failed, 1 skipped, 0 expected failures, 0 unexpected passes\n") {
+			t.Fatalf("bad summary %q", sb.String())
+		}
+	}
+}
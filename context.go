@@ -1,15 +1,15 @@
 package gossa
 
 import (
-	"flag"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"go/types"
-	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"strings"
 	"time"
 
@@ -19,16 +19,33 @@ import (
 	"golang.org/x/tools/go/ssa/ssautil"
 )
 
-// Mode is a bitmask of options affecting the interpreter.
+// Mode is a bitmask of options affecting the interpreter. The mode of
+// NewContext applies to its loader, its packages and all its interpreters.
+// The flags read by NewInterp when it compiles the program can be added for
+// one interpreter by WithMode and the other options of NewInterp, the flags
+// of the loader and packages, DisableUnexportMethods and EnableDumpInstr,
+// only by NewContext.
 type Mode uint
 
 const (
-	DisableRecover         Mode = 1 << iota // Disable recover() in target programs; show interpreter crash instead.
-	DisableUnexportMethods                  // Disable unexport methods
-	EnableTracing                           // Print a trace of all instructions as they are interpreted.
-	EnableDumpInstr                         // Print packages & SSA instruction code
+	DisableRecover         Mode = 1 << iota // Disable recover() in target programs; show interpreter crash instead, see WithRecoverDisabled.
+	DisableUnexportMethods                  // Do not load the unexported methods of host types into the loader.
+	EnableTracing                           // Log a trace of all instructions as they are interpreted, see WithTracing.
+	EnableDumpInstr                         // Print packages & SSA instruction code when the context builds them.
+	EnableProfiling                         // Record function and instruction statistics, see Interp.Profile.
+	EnablePprofLabels                       // Set pprof goroutine labels of interpreted functions for host CPU profiles.
+	StrictPanicSeparation                   // Report interpreter crashes as InterpInternalError instead of target panics.
+	DisableInline                           // Disable inlining of small functions, eg. to see all calls with SetTracer.
+	DisableUnsafe                           // Reject programs using denied packages and symbols, see SetDenylist.
+	CheckOverflow                           // Check signed integer add, sub and mul for overflow, see SetOverflow.
+	EnableRuntimePos                        // Add the positions of failed operations to runtime errors, eg. index out of range.
+	DisableMethodSynthesis                  // Do not synthesize the methods of script types by reflectx, for platforms where reflectx methods break.
+	DisableClosureCompiler                  // Evaluate instructions by a switch without folding, inlining and compiled closures, to bisect miscompiles.
 )
 
+// contextModes are the flags of the loader and packages of a context.
+const contextModes = DisableUnexportMethods | EnableDumpInstr
+
 // types loader interface
 type Loader interface {
 	Import(path string) (*types.Package, error)
@@ -44,9 +61,18 @@ type Context struct {
 	ParserMode  parser.Mode              // parser mode
 	BuilderMode ssa.BuilderMode          // ssa builder mode
 	External    types.Importer           // external import
-	Sizes       types.Sizes              // types size for package unsafe
+	Sizes       types.Sizes              // types size for package unsafe, default host gc sizes
 	debugFunc   func(*DebugInfo)         // debug func
+	growFunc    func(*SliceGrowInfo)     // slice growth func
+	spawnFunc   func(*GoroutineInfo)     // goroutine spawn func
 	override    map[string]reflect.Value // override function
+	logger      Logger                   // gossa/log backend
+	store       Store                    // gossa/store backend
+	diagFunc    func(*Diagnostic)        // unsupported construct func
+	maxDepth    int                      // max interpreted call depth, see SetMaxCallDepth
+	maxSteps    int64                    // step budget of interpreters, see SetMaxSteps
+	denylist    []string                 // denied packages and symbols, see SetDenylist
+	overflow    func(*OverflowInfo)      // signed integer overflow func, see SetOverflow
 }
 
 func NewContext(mode Mode) *Context {
@@ -56,18 +82,52 @@ func NewContext(mode Mode) *Context {
 		ParserMode:  parser.AllErrors,
 		BuilderMode: 0, //ssa.SanityCheckFunctions,
 		override:    make(map[string]reflect.Value),
-	}
-	if mode&EnableDumpInstr != 0 {
-		ctx.BuilderMode |= ssa.PrintFunctions
+		Sizes:       types.SizesFor("gc", runtime.GOARCH),
 	}
 	return ctx
 }
 
+// RegisterPackage registers or replaces pkg for packages loaded by the
+// context after construction, see TypesLoader.RegisterPackage.
+func (c *Context) RegisterPackage(pkg *Package) error {
+	r, ok := c.Loader.(interface{ RegisterPackage(*Package) })
+	if !ok {
+		return fmt.Errorf("loader %T does not support package registration", c.Loader)
+	}
+	r.RegisterPackage(pkg)
+	return nil
+}
+
+// SetImportFilter sets the import policy of the context loader, see
+// TypesLoader.SetImportFilter.
+func (c *Context) SetImportFilter(fn func(path string) error) error {
+	r, ok := c.Loader.(interface{ SetImportFilter(func(string) error) })
+	if !ok {
+		return fmt.Errorf("loader %T does not support import filters", c.Loader)
+	}
+	r.SetImportFilter(fn)
+	return nil
+}
+
 func (c *Context) SetDebug(fn func(*DebugInfo)) {
 	c.BuilderMode |= ssa.GlobalDebug
 	c.debugFunc = fn
 }
 
+// SetSliceGrow sets the func called when the append builtin grows a slice.
+func (c *Context) SetSliceGrow(fn func(*SliceGrowInfo)) {
+	c.growFunc = fn
+}
+
+// SetMaxCallDepth limits the depth of interpreted calls of a goroutine to n,
+// counting the calls of the program through host functions calling back.
+// A deeper call panics with the runtime error "stack overflow", which the
+// program can recover, instead of crashing the host when it exceeds the
+// goroutine stack. Zero is no limit.
+func (c *Context) SetMaxCallDepth(n int) {
+	c.maxDepth = n
+}
+
 // register external function to override function.
 // match func fullname and signature
 func (c *Context) SetOverrideFunction(key string, fn interface{}) {
@@ -145,14 +205,7 @@ func (c *Context) LoadAstPackage(fset *token.FileSet, apkg *ast.Package) (*ssa.P
 }
 
 func (c *Context) RunPkg(mainPkg *ssa.Package, input string, args []string) (exitCode int, err error) {
-	// reset os args and flag
-	os.Args = []string{input}
-	if args != nil {
-		os.Args = append(os.Args, args...)
-	}
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-
-	interp, err := c.NewInterp(mainPkg)
+	interp, err := c.NewInterp(mainPkg, WithArgs(append([]string{input}, args...)))
 	if err != nil {
 		return 2, err
 	}
@@ -167,8 +220,8 @@ func (c *Context) RunFunc(mainPkg *ssa.Package, fnname string, args ...Value) (r
 	return interp.RunFunc(fnname, args...)
 }
 
-func (c *Context) NewInterp(mainPkg *ssa.Package) (*Interp, error) {
-	return NewInterp(c, mainPkg)
+func (c *Context) NewInterp(mainPkg *ssa.Package, opts ...Option) (*Interp, error) {
+	return NewInterp(c, mainPkg, opts...)
 }
 
 func (c *Context) TestPkg(pkgs []*ssa.Package, input string, args []string) error {
@@ -195,13 +248,8 @@ func (c *Context) TestPkg(pkgs []*ssa.Package, input string, args []string) erro
 	if len(testPkgs) == 0 {
 		fmt.Println("testing: warning: no tests to run")
 	}
-	os.Args = []string{input}
-	if args != nil {
-		os.Args = append(os.Args, args...)
-	}
-	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	for _, pkg := range testPkgs {
-		interp, err := NewInterp(c, pkg)
+		interp, err := NewInterp(c, pkg, WithArgs(append([]string{input}, args...)))
 		if err != nil {
 			failed = true
 			fmt.Printf("create interp failed: %v\n", err)
@@ -218,8 +266,16 @@ func (c *Context) TestPkg(pkgs []*ssa.Package, input string, args []string) erro
 	return nil
 }
 
+// newFileSet returns the file set of a SourceLoader, or a new one.
+func (c *Context) newFileSet() *token.FileSet {
+	if l, ok := c.Loader.(SourceLoader); ok {
+		return l.FileSet()
+	}
+	return token.NewFileSet()
+}
+
 func (c *Context) RunFile(filename string, src interface{}, args []string) (exitCode int, err error) {
-	fset := token.NewFileSet()
+	fset := c.newFileSet()
 	pkg, err := c.LoadFile(fset, filename, src)
 	if err != nil {
 		return 2, err
@@ -231,7 +287,7 @@ func (c *Context) Run(path string, args []string) (exitCode int, err error) {
 	if strings.HasSuffix(path, ".go") {
 		return c.RunFile(path, nil, args)
 	}
-	fset := token.NewFileSet()
+	fset := c.newFileSet()
 	pkgs, err := c.LoadDir(fset, path)
 	if err != nil {
 		return 2, err
@@ -244,7 +300,7 @@ func (c *Context) Run(path string, args []string) (exitCode int, err error) {
 }
 
 func (c *Context) RunTest(path string, args []string) error {
-	fset := token.NewFileSet()
+	fset := c.newFileSet()
 	// preload regexp for create testing
 	c.Loader.Import("regexp")
 	pkgs, err := c.LoadDir(fset, path)
@@ -254,6 +310,21 @@ func (c *Context) RunTest(path string, args []string) error {
 	return c.TestPkg(pkgs, path, args)
 }
 
+// RunTests interprets the Test and Benchmark functions of the package in path
+// whose names match the regular expression pattern, like
+// "go test -run pattern -bench pattern". An empty pattern runs all tests and
+// no benchmarks. It returns ErrTestFailed if any test failed.
+func (c *Context) RunTests(path string, pattern string) error {
+	var args []string
+	if pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return err
+		}
+		args = append(args, "-test.run="+pattern, "-test.bench="+pattern)
+	}
+	return c.RunTest(path, args)
+}
+
 func (ctx *Context) BuildPackage(fset *token.FileSet, pkg *types.Package, files []*ast.File) (*ssa.Package, *types.Info, error) {
 	if fset == nil {
 		panic("no token.FileSet")
@@ -270,12 +341,22 @@ func (ctx *Context) BuildPackage(fset *token.FileSet, pkg *types.Package, files
 		Scopes:     make(map[ast.Node]*types.Scope),
 		Selections: make(map[*ast.SelectorExpr]*types.Selection),
 	}
+	recordInstances(info)
 
+	if err := ctx.checkDiagnostics(cgoDiagnostics(fset, files)); err != nil {
+		return nil, nil, err
+	}
 	tc := &types.Config{
 		Importer: NewImporter(ctx.Loader, ctx.External),
 		Sizes:    ctx.Sizes,
 	}
-	if err := types.NewChecker(tc, fset, pkg, info).Files(files); err != nil {
+	if err := checkFiles(tc, fset, pkg, info, files); err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.checkDiagnostics(genericDiagnostics(fset, pkg, files, info, ctx.Loader)); err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.checkDiagnostics(rangeFuncDiagnostics(fset, files, info)); err != nil {
 		return nil, nil, err
 	}
 
@@ -283,20 +364,32 @@ func (ctx *Context) BuildPackage(fset *token.FileSet, pkg *types.Package, files
 
 	// Create SSA packages for all imports.
 	// Order is not significant.
+	src, hasSource := ctx.Loader.(SourceLoader)
+	var sources []*ssa.Package
 	created := make(map[*types.Package]bool)
 	var createAll func(pkgs []*types.Package)
 	createAll = func(pkgs []*types.Package) {
 		for _, p := range pkgs {
 			if !created[p] {
 				created[p] = true
+				if hasSource {
+					if files, info, ok := src.Source(p); ok {
+						if ctx.Mode&EnableDumpInstr != 0 {
+							ctx.dumpPackage("source", p)
+						}
+						sources = append(sources, prog.CreatePackage(p, files, info, true))
+						createAll(p.Imports())
+						continue
+					}
+				}
 				if !p.Complete() {
 					if ctx.Mode&EnableDumpInstr != 0 {
-						fmt.Println("# indirect", p)
+						ctx.dumpPackage("indirect", p)
 					}
 					p.MarkComplete()
 				} else {
 					if ctx.Mode&EnableDumpInstr != 0 {
-						fmt.Println("# imported", p)
+						ctx.dumpPackage("imported", p)
 					}
 				}
 				prog.CreatePackage(p, nil, nil, true)
@@ -311,10 +404,45 @@ func (ctx *Context) BuildPackage(fset *token.FileSet, pkg *types.Package, files
 
 	// Create and build the primary package.
 	ssapkg := prog.CreatePackage(pkg, files, info, false)
+	for _, p := range sources {
+		p.Build()
+	}
 	ssapkg.Build()
+	if ctx.Mode&EnableDumpInstr != 0 {
+		ctx.dumpFunctions(append(sources, ssapkg))
+	}
+	if ctx.diagFunc != nil {
+		for _, d := range ctx.Diagnose(ssapkg) {
+			ctx.diagFunc(d)
+		}
+	}
 	return ssapkg, info, nil
 }
 
+// checkFiles type checks the files of pkg like types.Checker.Files. The
+// continuation lines of the first error, eg. the references of an
+// initialization cycle, are joined to it like the compiler reports them.
+func checkFiles(conf *types.Config, fset *token.FileSet, pkg *types.Package, info *types.Info, files []*ast.File) error {
+	var errs []types.Error
+	conf.Error = func(err error) {
+		if err, ok := err.(types.Error); ok {
+			errs = append(errs, err)
+		}
+	}
+	err := types.NewChecker(conf, fset, pkg, info).Files(files)
+	if err == nil || len(errs) == 0 {
+		return err
+	}
+	first := errs[0]
+	for _, e := range errs[1:] {
+		if !strings.HasPrefix(e.Msg, "\t") {
+			break
+		}
+		first.Msg += fmt.Sprintf("\n\t%v: %v", fset.Position(e.Pos), e.Msg[1:])
+	}
+	return first
+}
+
 func RunFile(filename string, src interface{}, args []string, mode Mode) (exitCode int, err error) {
 	reflectx.Reset()
 	ctx := NewContext(mode)
@@ -332,3 +460,11 @@ func RunTest(path string, args []string, mode Mode) error {
 	ctx := NewContext(mode)
 	return ctx.RunTest(path, args)
 }
+
+// RunTests interprets the Test and Benchmark functions of the package in
+// path matching pattern in a new context of mode, see Context.RunTests.
+func RunTests(path string, pattern string, mode Mode) error {
+	reflectx.Reset()
+	ctx := NewContext(mode)
+	return ctx.RunTests(path, pattern)
+}
@@ -0,0 +1,177 @@
+package gossa
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// DefaultDenylist is the denylist of DisableUnsafe mode, see SetDenylist.
+var DefaultDenylist = []string{
+	"unsafe",
+	"syscall",
+	"os/exec",
+	"plugin",
+	"net",
+	"(reflect.Value).SetPointer",
+}
+
+// SetDenylist sets the packages and symbols rejected by DisableUnsafe mode,
+// replacing DefaultDenylist. An entry is an import path, denying the package
+// and its subpackages, or the full name of a function, method or variable,
+// eg. "os.Exit" or "(reflect.Value).SetPointer".
+func (c *Context) SetDenylist(list ...string) {
+	c.denylist = list
+}
+
+// deniedSymbols is the denylist of a context.
+type deniedSymbols []string
+
+// pkg reports whether the package path is denied.
+func (d deniedSymbols) pkg(path string) bool {
+	for _, s := range d {
+		if path == s || strings.HasPrefix(path, s+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// symbol reports whether the symbol name of package path is denied.
+func (d deniedSymbols) symbol(path string, name string) bool {
+	if d.pkg(path) {
+		return true
+	}
+	for _, s := range d {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deniedDiagnostics returns the imports of denied packages and the uses of
+// denied symbols by the interpreted packages pkgs.
+func deniedDiagnostics(prog *ssa.Program, pkgs []*ssa.Package, denied deniedSymbols) (diags []*Diagnostic) {
+	report := func(pos token.Pos, msg string) {
+		diags = append(diags, &Diagnostic{
+			Kind: DiagDenied,
+			Pos:  prog.Fset.Position(pos),
+			Msg:  msg,
+		})
+	}
+	interpreted := make(map[*ssa.Package]bool)
+	for _, pkg := range pkgs {
+		interpreted[pkg] = true
+		reported := make(map[string]bool)
+		// import positions are the package names of file scopes
+		scope := pkg.Pkg.Scope()
+		for i := 0; i < scope.NumChildren(); i++ {
+			file := scope.Child(i)
+			for _, name := range file.Names() {
+				if obj, ok := file.Lookup(name).(*types.PkgName); ok && denied.pkg(obj.Imported().Path()) {
+					reported[obj.Imported().Path()] = true
+					report(obj.Pos(), "import of denied package "+obj.Imported().Path())
+				}
+			}
+		}
+		// blank and dot imports
+		for _, imp := range pkg.Pkg.Imports() {
+			if !reported[imp.Path()] && denied.pkg(imp.Path()) {
+				report(token.NoPos, "import of denied package "+imp.Path()+" by "+pkg.Pkg.Path())
+			}
+		}
+	}
+	var fns []*ssa.Function
+	for _, pkg := range pkgs {
+		for _, m := range pkg.Members {
+			switch m := m.(type) {
+			case *ssa.Function:
+				fns = append(fns, m)
+			case *ssa.Type:
+				for _, typ := range []types.Type{m.Type(), types.NewPointer(m.Type())} {
+					mset := prog.MethodSets.MethodSet(typ)
+					for i := 0; i < mset.Len(); i++ {
+						if fn := prog.MethodValue(mset.At(i)); fn != nil && fn.Pkg == pkg {
+							fns = append(fns, fn)
+						}
+					}
+				}
+			}
+		}
+	}
+	seen := make(map[*ssa.Function]bool)
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		if seen[fn] {
+			return
+		}
+		seen[fn] = true
+		for _, anon := range fn.AnonFuncs {
+			visit(anon)
+		}
+		var ops []*ssa.Value
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				ops = instr.Operands(ops[:0])
+				if conv, ok := instr.(*ssa.Convert); ok && denied.pkg("unsafe") &&
+					(isUnsafePointer(conv.Type()) || isUnsafePointer(conv.X.Type())) {
+					report(instr.Pos(), "use of denied package unsafe: conversion from "+
+						conv.X.Type().String()+" to "+conv.Type().String())
+				}
+				for _, op := range ops {
+					switch v := (*op).(type) {
+					case *ssa.Builtin:
+						if (v.Name() == "Add" || v.Name() == "Slice") && denied.pkg("unsafe") {
+							report(instr.Pos(), "use of denied symbol unsafe."+v.Name())
+						}
+					case *ssa.Function:
+						if v.Pkg != nil && interpreted[v.Pkg] {
+							visit(v)
+							continue
+						}
+						if v.Name() == "init" && v.Signature.Recv() == nil {
+							// reported by the imports
+							continue
+						}
+						// method values and expressions are wrappers
+						name := strings.TrimSuffix(strings.TrimSuffix(v.String(), "$bound"), "$thunk")
+						if path := funcPkgPath(v); path != "" && denied.symbol(path, name) {
+							report(instr.Pos(), "use of denied symbol "+name)
+						}
+					case *ssa.Global:
+						if v.Pkg != nil && !interpreted[v.Pkg] && denied.symbol(v.Pkg.Pkg.Path(), v.String()) {
+							report(instr.Pos(), "use of denied symbol "+v.String())
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, fn := range fns {
+		visit(fn)
+	}
+	sortDiagnostics(diags)
+	return
+}
+
+// funcPkgPath returns the package path of the function fn.
+func funcPkgPath(fn *ssa.Function) string {
+	if fn.Pkg != nil {
+		return fn.Pkg.Pkg.Path()
+	}
+	if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+		return obj.Pkg().Path()
+	}
+	return ""
+}
+
+// denied returns the denylist of c.
+func (c *Context) denied() deniedSymbols {
+	if c.denylist == nil {
+		return DefaultDenylist
+	}
+	return c.denylist
+}
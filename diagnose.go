@@ -0,0 +1,180 @@
+package gossa
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// DiagnosticKind is the kind of a construct the interpreter cannot execute.
+type DiagnosticKind int
+
+const (
+	DiagCgo         DiagnosticKind = iota // import "C", rejected at load time
+	DiagGeneric                           // generic declaration or instantiation, rejected at load time
+	DiagNoBody                            // function without body and extern value, eg. assembly
+	DiagExtern                            // call of an extern function not registered
+	DiagUnsafe                            // unsafe.Pointer conversion or unsafe builtin
+	DiagImport                            // import of a package neither registered nor interpreted
+	DiagUnsupported                       // instruction the interpreter cannot execute
+	DiagDenied                            // use of a denied package or symbol, see DisableUnsafe and SetImportFilter
+)
+
+func (k DiagnosticKind) String() string {
+	switch k {
+	case DiagCgo:
+		return "cgo"
+	case DiagGeneric:
+		return "generic"
+	case DiagNoBody:
+		return "nobody"
+	case DiagExtern:
+		return "extern"
+	case DiagUnsafe:
+		return "unsafe"
+	case DiagImport:
+		return "import"
+	case DiagUnsupported:
+		return "unsupported"
+	case DiagDenied:
+		return "denied"
+	}
+	return fmt.Sprintf("DiagnosticKind(%d)", int(k))
+}
+
+// Diagnostic reports a construct the interpreter cannot execute, or may
+// not execute under the current configuration.
+type Diagnostic struct {
+	Kind DiagnosticKind // construct kind
+	Pos  token.Position // construct position
+	Msg  string         // description
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v: %v", d.Pos, d.Msg)
+}
+
+// SetDiagnostic sets the func called at load time for each unsupported
+// construct of loaded packages. Cgo, generics and range over funcs make
+// the load fail, denied uses in DisableUnsafe mode make NewInterp fail,
+// other diagnostics are warnings of runtime panics.
+func (c *Context) SetDiagnostic(fn func(*Diagnostic)) {
+	c.diagFunc = fn
+}
+
+// cgoDiagnostics returns the cgo imports of files.
+func cgoDiagnostics(fset *token.FileSet, files []*ast.File) (diags []*Diagnostic) {
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == "C" {
+				diags = append(diags, &Diagnostic{
+					Kind: DiagCgo,
+					Pos:  fset.Position(spec.Pos()),
+					Msg:  "cgo is not supported",
+				})
+			}
+		}
+	}
+	return
+}
+
+// checkDiagnostics reports the load time diagnostics to the context
+// diagnostic func, and returns the error of the first one.
+func (c *Context) checkDiagnostics(diags []*Diagnostic) error {
+	if len(diags) == 0 {
+		return nil
+	}
+	sortDiagnostics(diags)
+	if c.diagFunc != nil {
+		for _, d := range diags {
+			c.diagFunc(d)
+		}
+	}
+	return fmt.Errorf("%v", diags[0])
+}
+
+// Diagnose scans the SSA of the interpreted packages of the program of pkg
+// for constructs that panic at run time under the context configuration.
+func (c *Context) Diagnose(pkg *ssa.Package) (diags []*Diagnostic) {
+	prog := pkg.Prog
+	interp := &Interp{ctx: c, loader: c.Loader}
+	interpreted := make(map[*ssa.Package]bool)
+	for _, p := range prog.AllPackages() {
+		if init := p.Func("init"); init != nil && init.Blocks != nil {
+			interpreted[p] = true
+		}
+	}
+	report := func(kind DiagnosticKind, pos token.Pos, format string, args ...interface{}) {
+		diags = append(diags, &Diagnostic{
+			Kind: kind,
+			Pos:  prog.Fset.Position(pos),
+			Msg:  fmt.Sprintf(format, args...),
+		})
+	}
+	for fn := range ssautil.AllFunctions(prog) {
+		if fn.Pkg == nil || !interpreted[fn.Pkg] {
+			continue
+		}
+		if fn.Blocks == nil {
+			if _, ok := findExternFunc(interp, fn); !ok {
+				report(DiagNoBody, fn.Pos(), "missing function body: %v", fn)
+			}
+			continue
+		}
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.Convert:
+					if isUnsafePointer(instr.Type()) || isUnsafePointer(instr.X.Type()) {
+						report(DiagUnsafe, instr.Pos(), "unsafe conversion from %v to %v", instr.X.Type(), instr.Type())
+					}
+				case ssa.CallInstruction:
+					if m := instantiate(c.Loader, instr); m != nil {
+						report(DiagExtern, instr.Pos(), "%v", m)
+						continue
+					}
+					call := instr.Common()
+					if b, ok := call.Value.(*ssa.Builtin); ok && (b.Name() == "Add" || b.Name() == "Slice") {
+						report(DiagUnsafe, instr.Pos(), "unsafe.%v", b.Name())
+					}
+					callee := call.StaticCallee()
+					if callee == nil || callee.Blocks != nil || callee.Pkg == nil || interpreted[callee.Pkg] {
+						continue
+					}
+					if callee.Name() == "init" {
+						continue
+					}
+					if _, ok := findExternFunc(interp, callee); !ok {
+						report(DiagExtern, instr.Pos(), "no registered function: %v", callee)
+					}
+				}
+			}
+		}
+	}
+	sortDiagnostics(diags)
+	return
+}
+
+func isUnsafePointer(typ types.Type) bool {
+	t, ok := typ.Underlying().(*types.Basic)
+	return ok && t.Kind() == types.UnsafePointer
+}
+
+func sortDiagnostics(diags []*Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		pi, pj := diags[i].Pos, diags[j].Pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		if pi.Line != pj.Line {
+			return pi.Line < pj.Line
+		}
+		return pi.Column < pj.Column
+	})
+}
@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package gossa
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// recordInstances makes the type checker record the instantiations of
+// generics in info, see genericDiagnostics.
+func recordInstances(info *types.Info) {
+	info.Instances = make(map[*ast.Ident]types.Instance)
+}
+
+func genericDiagnostics(fset *token.FileSet, pkg *types.Package, files []*ast.File, info *types.Info, loader Loader) (diags []*Diagnostic) {
+	report := func(pos token.Pos, msg string) {
+		diags = append(diags, &Diagnostic{Kind: DiagGeneric, Pos: fset.Position(pos), Msg: msg})
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Type.TypeParams != nil {
+					report(decl.Name.Pos(), "generic function "+decl.Name.Name+" is not supported")
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok && spec.TypeParams != nil {
+						report(spec.Name.Pos(), "generic type "+spec.Name.Name+" is not supported")
+					}
+				}
+			}
+		}
+	}
+	// callees with the type arguments inferred, the calls of the generic
+	// functions of registered packages instantiated by instantiate
+	callees := make(map[*ast.Ident]bool)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				fun := call.Fun
+				for p, ok := fun.(*ast.ParenExpr); ok; p, ok = fun.(*ast.ParenExpr) {
+					fun = p.X
+				}
+				switch fun := fun.(type) {
+				case *ast.Ident:
+					callees[fun] = true
+				case *ast.SelectorExpr:
+					callees[fun.Sel] = true
+				}
+			}
+			return true
+		})
+	}
+	// instantiations of imported generics, local ones are reported above
+	for id := range info.Instances {
+		if obj := info.Uses[id]; obj != nil && obj.Pkg() != pkg {
+			if callees[id] && isRegisteredGeneric(loader, obj) {
+				continue
+			}
+			report(id.Pos(), "instantiation of generic "+obj.Name()+" is not supported")
+		}
+	}
+	return
+}
+
+// isRegisteredGeneric reports whether obj is a function of the
+// GenericFuncs of a registered package.
+func isRegisteredGeneric(loader Loader, obj types.Object) bool {
+	if _, ok := obj.(*types.Func); !ok || loader == nil {
+		return false
+	}
+	pkg, ok := loader.Installed(obj.Pkg().Path())
+	if !ok {
+		return false
+	}
+	_, ok = pkg.GenericFuncs[obj.Name()]
+	return ok
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+// +build go1.23
+
+package gossa
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// rangeFuncDiagnostics returns the range statements over funcs (Go 1.23)
+// of files, which the SSA builder cannot build.
+func rangeFuncDiagnostics(fset *token.FileSet, files []*ast.File, info *types.Info) (diags []*Diagnostic) {
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if s, ok := n.(*ast.RangeStmt); ok {
+				if _, ok := info.TypeOf(s.X).Underlying().(*types.Signature); ok {
+					diags = append(diags, &Diagnostic{
+						Kind: DiagUnsupported,
+						Pos:  fset.Position(s.For),
+						Msg:  "range over func is not supported",
+					})
+				}
+			}
+			return true
+		})
+	}
+	return
+}
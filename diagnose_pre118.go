@@ -0,0 +1,17 @@
+//go:build !go1.18
+// +build !go1.18
+
+package gossa
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+func recordInstances(info *types.Info) {
+}
+
+func genericDiagnostics(fset *token.FileSet, pkg *types.Package, files []*ast.File, info *types.Info, loader Loader) []*Diagnostic {
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !go1.23
+// +build !go1.23
+
+package gossa
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+func rangeFuncDiagnostics(fset *token.FileSet, files []*ast.File, info *types.Info) []*Diagnostic {
+	return nil
+}
@@ -1,6 +1,16 @@
 package gossa
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
 
 var (
 	ErrNoPackage        = errors.New("no package")
@@ -10,3 +20,179 @@ var (
 	ErrNotFoundPackage  = errors.New("not found package")
 	ErrNotFoundImporter = errors.New("not found provider for types.Importer")
 )
+
+// InterpInternalError is an interpreter crash while executing an
+// instruction, reported in StrictPanicSeparation mode instead of the
+// original panic value. It is not recoverable by the target program.
+type InterpInternalError struct {
+	Value interface{}     // original panic value
+	Instr ssa.Instruction // crashed instruction
+	Func  *ssa.Function   // function of instruction
+	Pos   token.Position  // instruction position
+}
+
+func (e *InterpInternalError) Error() string {
+	return fmt.Sprintf("gossa internal error: %v\n\t%v: %v in %v", e.Value, e.Pos, e.Instr, e.Func)
+}
+
+// wrapInternalError converts non-target panics of ifn to InterpInternalError.
+// Instructions running other code are not wrapped, the panics of
+// external functions belong to the target program.
+func wrapInternalError(pfn *Function, instr ssa.Instruction, ifn func(fr *frame)) func(fr *frame) {
+	switch instr.(type) {
+	case *ssa.Call, *ssa.Defer, *ssa.Go, *ssa.RunDefers, *ssa.Panic:
+		return ifn
+	}
+	return func(fr *frame) {
+		defer func() {
+			if p := recover(); p != nil {
+				switch p.(type) {
+				case targetPanic, exitPanic, plainError, runtime.Error, *InterpInternalError, *TimeoutError:
+					panic(p)
+				}
+				panic(&InterpInternalError{
+					Value: p,
+					Instr: instr,
+					Func:  pfn.Fn,
+					Pos:   pfn.Interp.fset.Position(instr.Pos()),
+				})
+			}
+		}()
+		ifn(fr)
+	}
+}
+
+// ExitError is the error of a call of the host into the program ended by
+// os.Exit outside Run, eg. an event handler called by Interp.Emit.
+type ExitError struct {
+	Code int // exit code
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %v", e.Code)
+}
+
+// unexpectedPanic is the error of a panic of the interpreter, not of the
+// target program, outside of StrictPanicSeparation checks.
+type unexpectedPanic struct {
+	v interface{}
+}
+
+func (p unexpectedPanic) Error() string {
+	return fmt.Sprintf("unexpected type: %T: %v", p.v, p.v)
+}
+
+// ErrMissingSymbol is an external function, method or variable used by the
+// program without registered implementation.
+type ErrMissingSymbol struct {
+	Pkg  string // package path
+	Name string // symbol name, Type.Method for methods
+	Kind string // "func", "method", "var" or "instance"
+}
+
+func (e *ErrMissingSymbol) Error() string {
+	if e.Kind == "instance" {
+		return fmt.Sprintf("missing instance %v.%v: register the instantiation in the GenericFuncs of the package %q", e.Pkg, e.Name, e.Pkg)
+	}
+	if e.Kind == "method" {
+		return fmt.Sprintf("missing method %v.%v: register the package %q", e.Pkg, e.Name, e.Pkg)
+	}
+	return fmt.Sprintf("missing %v %v.%v: register the package %q, eg. import _ \"github.com/goplus/gossa/pkg/%v\" for the standard library, or gossa.RegisterExternal(%q, value)",
+		e.Kind, e.Pkg, e.Name, e.Pkg, e.Pkg, e.Pkg+"."+e.Name)
+}
+
+// MissingSymbolsError is the missing symbols of a program, returned by
+// NewInterp for all unresolved symbols at once.
+type MissingSymbolsError []*ErrMissingSymbol
+
+func (e MissingSymbolsError) Error() string {
+	msgs := make([]string, len(e))
+	for i, m := range e {
+		msgs[i] = m.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// DeniedError is the uses of denied packages and symbols of a program,
+// returned by NewInterp in DisableUnsafe mode.
+type DeniedError []*Diagnostic
+
+func (e DeniedError) Error() string {
+	msgs := make([]string, len(e))
+	for i, d := range e {
+		msgs[i] = d.String()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ImportDeniedError is an import or symbol denied by the import filter of
+// the loader, see TypesLoader.SetImportFilter.
+type ImportDeniedError struct {
+	Path string // import path or symbol name
+	Err  error  // filter error
+}
+
+func (e *ImportDeniedError) Error() string {
+	return fmt.Sprintf("%v denied: %v", e.Path, e.Err)
+}
+
+func (e *ImportDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// RewriteImport is returned by an import filter to import the registered
+// package of the path instead, which declares the same names as the
+// filtered import.
+type RewriteImport string
+
+func (e RewriteImport) Error() string {
+	return "import rewritten to " + string(e)
+}
+
+// missingFunc returns the missing symbol of the external function fn.
+func missingFunc(fn *ssa.Function) *ErrMissingSymbol {
+	e := &ErrMissingSymbol{Name: fn.Name(), Kind: "func"}
+	if fn.Pkg != nil {
+		e.Pkg = fn.Pkg.Pkg.Path()
+	} else if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+		e.Pkg = obj.Pkg().Path()
+	}
+	if recv := fn.Signature.Recv(); recv != nil {
+		e.Name = recvTypeName(recv) + "." + e.Name
+		e.Kind = "method"
+	}
+	return e
+}
+
+// missingMethod returns the missing symbol of the external method fn.
+func missingMethod(fn *types.Func) *ErrMissingSymbol {
+	e := &ErrMissingSymbol{Name: fn.Name(), Kind: "method"}
+	if fn.Pkg() != nil {
+		e.Pkg = fn.Pkg().Path()
+	}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		e.Name = recvTypeName(recv) + "." + e.Name
+	}
+	return e
+}
+
+func recvTypeName(recv *types.Var) string {
+	typ := recv.Type()
+	if p, ok := typ.(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+	if named, ok := typ.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return typ.String()
+}
+
+// sortMissing sorts the missing symbols by package and name.
+func sortMissing(list MissingSymbolsError) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Pkg != list[j].Pkg {
+			return list[i].Pkg < list[j].Pkg
+		}
+		return list[i].Name < list[j].Name
+	})
+}
@@ -0,0 +1,432 @@
+package gossa
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// In DisableClosureCompiler mode the instructions are not compiled by
+// makeInstr: evalInstr executes each instruction by a switch over the
+// instruction types, reading its operands from the registers at run time.
+// The constant folding, inlining and the specialized closures of makeInstr
+// are not used, so a program behaving differently in this mode points to
+// a miscompile, not to the semantics of the operations.
+
+// makeEvalInstr returns the closure evaluating instr by evalInstr, or nil
+// if instr has no effect. The registers of the value and the operands of
+// instr are allocated at compile time, so evalInstr only reads the
+// register index of pfn.
+func makeEvalInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *frame) {
+	switch instr := instr.(type) {
+	case *ssa.Store:
+		if addr, ok := instr.Addr.(*ssa.FieldAddr); ok {
+			if s, ok := addr.X.Type().(*types.Pointer).Elem().(*types.Struct); ok {
+				if s.Field(addr.Field).Name() == "_" {
+					return nil
+				}
+			}
+		}
+	case *ssa.Call:
+		if fn, ok := instr.Call.Value.(*ssa.Function); ok && fn.Blocks == nil {
+			if _, ok := findExternFunc(interp, fn); !ok {
+				if fn.Pkg != nil && fn.Name() == "init" {
+					return nil
+				}
+				panic(missingFunc(fn))
+			}
+		}
+	case *ssa.MakeMap:
+		if st, ok := instr.Type().Underlying().(*types.Map).Key().Underlying().(*types.Struct); ok && hasUnderscore(st) {
+			pfn.mapUnderscoreKey[instr.Type()] = true
+		}
+	case *ssa.DebugRef:
+		if interp.debugFunc == nil {
+			return nil
+		}
+	}
+	if v, ok := instr.(ssa.Value); ok {
+		pfn.regIndex(v)
+	}
+	for _, op := range instr.Operands(nil) {
+		if op != nil && *op != nil {
+			pfn.regIndex(*op)
+		}
+	}
+	return func(fr *frame) {
+		evalInstr(fr, instr)
+	}
+}
+
+// get returns the value of the register of v.
+func (fr *frame) get(v ssa.Value) value {
+	return fr.stack[fr.pfn.regIndex(v)]
+}
+
+// set sets the register of v to x.
+func (fr *frame) set(v ssa.Value, x value) {
+	fr.stack[fr.pfn.regIndex(v)] = x
+}
+
+// evalInstr executes instr in the frame fr.
+func evalInstr(fr *frame, instr ssa.Instruction) {
+	interp := fr.interp
+	switch instr := instr.(type) {
+	case *ssa.Alloc:
+		typ := interp.toType(instr.Type()).Elem()
+		if v := fr.get(instr); v != nil && !instr.Heap {
+			// a local alloc in a loop is zeroed at each iteration
+			SetValue(reflect.ValueOf(v).Elem(), reflect.New(typ).Elem())
+		} else {
+			fr.set(instr, reflect.New(typ).Interface())
+		}
+	case *ssa.Phi:
+		for i, pred := range instr.Block().Preds {
+			if fr.pred == pred.Index {
+				fr.set(instr, fr.get(instr.Edges[i]))
+				break
+			}
+		}
+	case *ssa.Call:
+		fn, args := evalCall(fr, &instr.Call)
+		fr.set(instr, interp.call(fr, fn, args, instr.Call.Args))
+	case *ssa.BinOp:
+		fr.set(instr, binop(instr, instr.X.Type(), fr.get(instr.X), fr.get(instr.Y)))
+	case *ssa.UnOp:
+		fr.set(instr, unop(instr, fr.get(instr.X)))
+	case *ssa.ChangeInterface:
+		fr.set(instr, fr.get(instr.X))
+	case *ssa.ChangeType:
+		typ := interp.toType(instr.Type())
+		if x := fr.get(instr.X); x == nil {
+			fr.set(instr, reflect.New(typ).Elem().Interface())
+		} else {
+			fr.set(instr, changeType(reflect.ValueOf(x), typ).Interface())
+		}
+	case *ssa.Convert:
+		fr.set(instr, evalConvert(fr.get(instr.X), interp.toType(instr.X.Type()), interp.toType(instr.Type())))
+	case *ssa.MakeInterface:
+		typ := interp.toType(instr.Type())
+		x := fr.get(instr.X)
+		if typ == tyEmptyInterface {
+			fr.set(instr, x)
+		} else if interp.mode&DisableMethodSynthesis != 0 {
+			fr.set(instr, interp.wrapIface(typ, x))
+		} else {
+			v := reflect.New(typ).Elem()
+			if x != nil {
+				SetValue(v, reflect.ValueOf(x))
+			}
+			fr.set(instr, v.Interface())
+		}
+	case *ssa.MakeClosure:
+		fn := instr.Fn.(*ssa.Function)
+		bindings := make([]value, len(instr.Bindings))
+		for i, v := range instr.Bindings {
+			bindings[i] = fr.get(v)
+		}
+		fr.set(instr, interp.makeFunc(interp.toType(fn.Type()), interp.lookupFunc(fn), bindings).Interface())
+	case *ssa.MakeChan:
+		typ := interp.toType(instr.Type())
+		size := asInt(fr.get(instr.Size))
+		if size < 0 {
+			panic(runtimeError{msg: "makechan: size out of range"})
+		}
+		ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, typ.Elem()), size)
+		fr.set(instr, convertChan(ch, typ).Interface())
+	case *ssa.MakeMap:
+		typ := interp.toType(instr.Type())
+		if instr.Reserve == nil {
+			fr.set(instr, reflect.MakeMap(typ).Interface())
+		} else {
+			fr.set(instr, reflect.MakeMapWithSize(typ, asInt(fr.get(instr.Reserve))).Interface())
+		}
+	case *ssa.MakeSlice:
+		Len := asInt(fr.get(instr.Len))
+		if Len < 0 || Len >= maxMemLen {
+			panic(runtimeError{msg: "makeslice: len out of range"})
+		}
+		Cap := asInt(fr.get(instr.Cap))
+		if Cap < 0 || Cap >= maxMemLen {
+			panic(runtimeError{msg: "makeslice: cap out of range"})
+		}
+		fr.set(instr, reflect.MakeSlice(interp.toType(instr.Type()), Len, Cap).Interface())
+	case *ssa.Slice:
+		typ := interp.toType(instr.Type())
+		_, makesliceCheck := instr.X.(*ssa.Alloc)
+		pfn := fr.pfn
+		v := slice(fr, instr, makesliceCheck, pfn.regIndex(instr.X), pfn.regIndex(instr.High), pfn.regIndex(instr.Low), pfn.regIndex(instr.Max))
+		if v.Type() != typ {
+			v = v.Convert(typ)
+		}
+		fr.set(instr, v.Interface())
+	case *ssa.FieldAddr:
+		v, err := FieldAddr(fr.get(instr.X), instr.Field)
+		if err != nil {
+			panic(runtimeError{msg: err.Error()})
+		}
+		fr.set(instr, v)
+	case *ssa.Field:
+		v, err := Field(fr.get(instr.X), instr.Field)
+		if err != nil {
+			panic(runtimeError{msg: err.Error()})
+		}
+		fr.set(instr, v)
+	case *ssa.IndexAddr:
+		v := reflect.ValueOf(fr.get(instr.X))
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			panic(runtimeError{msg: "invalid memory address or nil pointer dereference"})
+		}
+		index := asInt(fr.get(instr.Index))
+		if index < 0 {
+			panic(runtimeError{msg: fmt.Sprintf("index out of range [%v]", index)})
+		} else if length := v.Len(); index >= length {
+			panic(runtimeError{msg: fmt.Sprintf("index out of range [%v] with length %v", index, length)})
+		}
+		fr.set(instr, v.Index(index).Addr().Interface())
+	case *ssa.Index:
+		fr.set(instr, reflect.ValueOf(fr.get(instr.X)).Index(asInt(fr.get(instr.Index))).Interface())
+	case *ssa.Lookup:
+		x := reflect.ValueOf(fr.get(instr.X))
+		if x.Kind() == reflect.String {
+			fr.set(instr, x.String()[asInt(fr.get(instr.Index))])
+			return
+		}
+		typ := interp.toType(instr.X.Type())
+		v := x.MapIndex(evalMapKey(fr, instr.X.Type(), x, typ.Key(), fr.get(instr.Index)))
+		ok := v.IsValid()
+		if !ok {
+			v = reflect.Zero(typ.Elem())
+		}
+		if instr.CommaOk {
+			fr.set(instr, tuple{v.Interface(), ok})
+		} else {
+			fr.set(instr, v.Interface())
+		}
+	case *ssa.Select:
+		fr.set(instr, evalSelect(fr, instr))
+	case *ssa.SliceToArrayPointer:
+		typ := interp.toType(instr.Type())
+		v := reflect.ValueOf(fr.get(instr.X))
+		if vLen, tLen := v.Len(), typ.Elem().Len(); tLen > vLen {
+			panic(runtimeError{msg: fmt.Sprintf("cannot convert slice with length %v to pointer to array with length %v", vLen, tLen)})
+		}
+		fr.set(instr, v.Convert(typ).Interface())
+	case *ssa.Range:
+		v := reflect.ValueOf(fr.get(instr.X))
+		switch v.Kind() {
+		case reflect.String:
+			fr.set(instr, &stringIter{Reader: strings.NewReader(v.String())})
+		case reflect.Map:
+			fr.set(instr, &mapIter{iter: v.MapRange()})
+		default:
+			fr.set(instr, newIntIter(v))
+		}
+	case *ssa.Next:
+		switch it := fr.get(instr.Iter).(type) {
+		case *stringIter:
+			fr.set(instr, it.next())
+		case *intIter:
+			fr.set(instr, it.next())
+		case *mapIter:
+			fr.set(instr, it.next())
+		}
+	case *ssa.TypeAssert:
+		fr.set(instr, typeAssert(interp, instr, interp.toType(instr.AssertedType), fr.get(instr.X)))
+	case *ssa.Extract:
+		fr.set(instr, fr.get(instr.Tuple).(tuple)[instr.Index])
+	// Instructions executed for effect
+	case *ssa.Jump:
+		if fr.block.Succs[0].Index <= fr.block.Index && fr.deadline != nil {
+			fr.deadline.check(fr)
+		}
+		fr.pred, fr.block = fr.block.Index, fr.block.Succs[0]
+		fr.pc = fr.pfn.Blocks[fr.block.Index]
+	case *ssa.If:
+		succ := 1
+		if reflect.ValueOf(fr.get(instr.Cond)).Bool() {
+			succ = 0
+		}
+		if fr.block.Succs[succ].Index <= fr.block.Index && fr.deadline != nil {
+			fr.deadline.check(fr)
+		}
+		fr.pred, fr.block = fr.block.Index, fr.block.Succs[succ]
+		fr.pc = fr.pfn.Blocks[fr.block.Index]
+	case *ssa.Return:
+		fr.results = make([]int, len(instr.Results))
+		for i, v := range instr.Results {
+			fr.results[i] = fr.pfn.regIndex(v)
+		}
+		fr.pc = -1
+	case *ssa.RunDefers:
+		fr.runDefers()
+	case *ssa.Panic:
+		panic(targetPanic{fr.get(instr.X), fr})
+	case *ssa.Go:
+		fn, args := evalCall(fr, &instr.Call)
+		atomic.AddInt32(&interp.goroutines, 1)
+		go interp.spawn(instr, interp.goroutineRun(), func() {
+			interp.callDiscardsResult(nil, fn, args, instr.Call.Args)
+			atomic.AddInt32(&interp.goroutines, -1)
+		})
+	case *ssa.Defer:
+		fn, args := evalCall(fr, &instr.Call)
+		fr.defers = &deferred{
+			fn:      fn,
+			args:    args,
+			ssaArgs: instr.Call.Args,
+			instr:   instr,
+			tail:    fr.defers,
+		}
+	case *ssa.Send:
+		ch := reflect.ValueOf(fr.get(instr.Chan))
+		if !ch.IsValid() {
+			// send on nil channel blocks forever
+			select {}
+		}
+		ch.Send(valueOrZero(fr.get(instr.X), ch.Type().Elem()))
+	case *ssa.Store:
+		x := reflect.ValueOf(fr.get(instr.Addr)).Elem()
+		SetValue(x, valueOrZero(fr.get(instr.Val), x.Type()))
+	case *ssa.MapUpdate:
+		vm := reflect.ValueOf(fr.get(instr.Map))
+		typ := vm.Type()
+		// nil interface values are stored, not deleted
+		vm.SetMapIndex(evalMapKey(fr, instr.Map.Type(), vm, typ.Key(), fr.get(instr.Key)),
+			valueOrZero(fr.get(instr.Value), typ.Elem()))
+	case *ssa.DebugRef:
+		ref := &DebugInfo{DebugRef: instr, fset: interp.fset}
+		ref.toValue = func() (*types.Var, interface{}, bool) {
+			if v, ok := instr.Object().(*types.Var); ok {
+				return v, fr.get(instr.X), true
+			}
+			return nil, nil, false
+		}
+		interp.debugFunc(ref)
+	default:
+		panic(fmt.Errorf("unreachable %T", instr))
+	}
+}
+
+// evalCall returns the callee and the arguments of call, the receiver
+// first for invoke mode.
+func evalCall(fr *frame, call *ssa.CallCommon) (fn value, args []value) {
+	pfn := fr.pfn
+	ia := make([]int, len(call.Args))
+	for i, v := range call.Args {
+		ia[i] = pfn.regIndex(v)
+	}
+	var ib []int
+	if f, ok := call.Value.(*ssa.MakeClosure); ok {
+		ib = make([]int, len(f.Bindings))
+		for i, v := range f.Bindings {
+			ib[i] = pfn.regIndex(v)
+		}
+	}
+	return fr.interp.prepareCall(fr, call, pfn.regIndex(call.Value), ia, ib)
+}
+
+// valueOrZero returns the reflect value of v, or the zero value of typ for
+// a nil v.
+func valueOrZero(v value, typ reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(v)
+}
+
+// evalMapKey returns the key k of the map vm of type t with key type ktyp.
+// Keys of structs with blank fields are matched to the equal key of the map.
+func evalMapKey(fr *frame, t types.Type, vm reflect.Value, ktyp reflect.Type, k value) reflect.Value {
+	vk := mapKey(ktyp, k)
+	if fr.pfn.mapUnderscoreKey[t] {
+		for _, v := range vm.MapKeys() {
+			if equalStruct(vk, v) {
+				return v
+			}
+		}
+	}
+	return vk
+}
+
+// evalConvert returns x of type xtyp converted to typ.
+func evalConvert(x value, xtyp, typ reflect.Type) value {
+	v := reflect.ValueOf(x)
+	switch xtyp.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch typ.Kind() {
+		case reflect.String:
+			r := v.Int()
+			if int64(rune(r)) != r {
+				r = utf8.RuneError
+			}
+			return reflect.ValueOf(string(rune(r))).Convert(typ).Interface()
+		case reflect.Float32:
+			return reflect.ValueOf(float32(v.Int())).Convert(typ).Interface()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch typ.Kind() {
+		case reflect.String:
+			r := v.Uint()
+			if r > unicode.MaxRune {
+				r = utf8.RuneError
+			}
+			return reflect.ValueOf(string(rune(r))).Convert(typ).Interface()
+		case reflect.Float32:
+			return reflect.ValueOf(float32(v.Uint())).Convert(typ).Interface()
+		}
+	case reflect.Float32, reflect.Float64:
+		if fn, ok := floatToInt[typ.Kind()]; ok {
+			return reflect.ValueOf(fn(v.Float())).Convert(typ).Interface()
+		}
+	}
+	return convert(x, typ)
+}
+
+// evalSelect executes the select statement instr, returning the tuple of
+// the chosen case, the recvOk flag and the received values.
+func evalSelect(fr *frame, instr *ssa.Select) value {
+	var cases []reflect.SelectCase
+	if !instr.Blocking {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+	for _, state := range instr.States {
+		c := reflect.SelectCase{Dir: reflect.SelectRecv}
+		if state.Dir != types.RecvOnly {
+			c.Dir = reflect.SelectSend
+		}
+		// a nil channel is never ready, reflect.Select ignores the case of
+		// a zero Chan
+		if ch := reflect.ValueOf(fr.get(state.Chan)); ch.IsValid() && !ch.IsNil() {
+			c.Chan = ch
+			if state.Send != nil {
+				c.Send = valueOrZero(fr.get(state.Send), ch.Type().Elem())
+			}
+		}
+		cases = append(cases, c)
+	}
+	chosen, recv, recvOk := reflect.Select(cases)
+	if !instr.Blocking {
+		chosen-- // default case should have index -1.
+	}
+	r := tuple{chosen, recvOk}
+	for i, state := range instr.States {
+		if state.Dir == types.RecvOnly {
+			if i == chosen && recvOk {
+				r = append(r, recv.Interface())
+			} else {
+				r = append(r, reflect.Zero(fr.interp.toType(state.Chan.Type()).Elem()).Interface())
+			}
+		}
+	}
+	return r
+}
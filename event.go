@@ -0,0 +1,195 @@
+package gossa
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EventPkgPath is the import path of the script event package. Scripts
+// subscribe handlers to events emitted by the host by Interp.Emit:
+//
+//	import "gossa/event"
+//
+//	event.On("tick", func(n int) {
+//		println("tick", n)
+//	})
+//	event.Off("tick")
+const EventPkgPath = "gossa/event"
+
+// HandlerError is the error of an event handler called by Interp.Emit, a
+// panic of the handler, a bad argument or an error result.
+type HandlerError struct {
+	Event string // event name
+	Index int    // index of the handler in the handlers of the event
+	Err   error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("event %v handler %v: %v", e.Event, e.Index, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// EmitError is the errors of the failed handlers of an Interp.Emit call.
+type EmitError []*HandlerError
+
+func (e EmitError) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%v (and %v more errors)", e[0], len(e)-1)
+}
+
+// on subscribes the script func handler to the event name.
+func (i *Interp) on(name string, handler interface{}) {
+	v := reflect.ValueOf(handler)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		panic(fmt.Errorf("gossa/event: handler of %v is %T, not a func", name, handler))
+	}
+	i.eventsMutex.Lock()
+	if i.events == nil {
+		i.events = make(map[string][]reflect.Value)
+	}
+	i.events[name] = append(i.events[name], v)
+	i.eventsMutex.Unlock()
+}
+
+// off removes the handlers of the event name.
+func (i *Interp) off(name string) {
+	i.eventsMutex.Lock()
+	delete(i.events, name)
+	i.eventsMutex.Unlock()
+}
+
+// Handlers returns the number of handlers subscribed to the event name.
+func (i *Interp) Handlers(name string) int {
+	i.eventsMutex.Lock()
+	defer i.eventsMutex.Unlock()
+	return len(i.events[name])
+}
+
+// Emit calls the handlers subscribed by the script to the event name with
+// args, in the order of subscription. The args are converted to the
+// parameter types of each handler, nil is the zero value. A panic of a
+// handler, a bad argument or a non-nil error result of a handler does not
+// stop the other handlers, it is reported in the EmitError result. os.Exit
+// called by a handler stops the handlers, it is reported by an ExitError.
+// Handlers may subscribe handlers, which are called by the next Emit.
+func (i *Interp) Emit(name string, args ...interface{}) error {
+	i.eventsMutex.Lock()
+	handlers := i.events[name]
+	i.eventsMutex.Unlock()
+	var errs EmitError
+	for n, h := range handlers {
+		if err := callHandler(h, args); err != nil {
+			errs = append(errs, &HandlerError{Event: name, Index: n, Err: err})
+			if _, ok := err.(*ExitError); ok {
+				break
+			}
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// callHandler calls the handler fn with args, returning its panic or
+// error result as error.
+func callHandler(fn reflect.Value, args []interface{}) (err error) {
+	typ := fn.Type()
+	in, err := handlerArgs(typ, args)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		switch p := recover().(type) {
+		case nil:
+		case exitPanic:
+			err = &ExitError{Code: int(p)}
+		case error:
+			err = p
+		case string:
+			err = plainError(p)
+		default:
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	out := fn.Call(in)
+	if n := len(out); n > 0 && typ.Out(n-1) == tyErrorInterface {
+		if e, ok := out[n-1].Interface().(error); ok && e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// handlerArgs returns args converted to the parameter types of the
+// handler type typ.
+func handlerArgs(typ reflect.Type, args []interface{}) ([]reflect.Value, error) {
+	n := typ.NumIn()
+	if typ.IsVariadic() {
+		if len(args) < n-1 {
+			return nil, fmt.Errorf("not enough arguments: have %v, want at least %v", len(args), n-1)
+		}
+	} else if len(args) != n {
+		return nil, fmt.Errorf("wrong number of arguments: have %v, want %v", len(args), n)
+	}
+	in := make([]reflect.Value, len(args))
+	for j, arg := range args {
+		var ptyp reflect.Type
+		if typ.IsVariadic() && j >= n-1 {
+			ptyp = typ.In(n - 1).Elem()
+		} else {
+			ptyp = typ.In(j)
+		}
+		if arg == nil {
+			in[j] = reflect.Zero(ptyp)
+			continue
+		}
+		v := reflect.ValueOf(arg)
+		if !v.Type().AssignableTo(ptyp) {
+			if !v.Type().ConvertibleTo(ptyp) {
+				return nil, fmt.Errorf("cannot use %T as %v in argument %v", arg, ptyp, j)
+			}
+			v = v.Convert(ptyp)
+		}
+		in[j] = v
+	}
+	return in, nil
+}
+
+// findEventFunc returns the gossa/event function name bound to the interp.
+func findEventFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	switch name {
+	case "On":
+		return reflect.ValueOf(func(name string, handler interface{}) {
+			interp.on(name, handler)
+		}), true
+	case "Off":
+		return reflect.ValueOf(func(name string) {
+			interp.off(name)
+		}), true
+	}
+	return
+}
+
+func init() {
+	RegisterPackage(&Package{
+		Name:       "event",
+		Path:       EventPkgPath,
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"On":  reflect.ValueOf(func(name string, handler interface{}) {}),
+			"Off": reflect.ValueOf(func(name string) {}),
+		},
+		TypedConsts:   map[string]TypedConst{},
+		UntypedConsts: map[string]UntypedConst{},
+	})
+}
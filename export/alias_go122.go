@@ -0,0 +1,12 @@
+//go:build go1.22
+// +build go1.22
+
+package export
+
+import "go/types"
+
+// unalias returns the type denoted by the alias type t, which go/types
+// represents by *types.Alias since Go 1.22.
+func unalias(t types.Type) types.Type {
+	return types.Unalias(t)
+}
@@ -0,0 +1,11 @@
+//go:build !go1.22
+// +build !go1.22
+
+package export
+
+import "go/types"
+
+// unalias returns the type denoted by the alias type t.
+func unalias(t types.Type) types.Type {
+	return t
+}
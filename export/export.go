@@ -0,0 +1,454 @@
+// Package export generates the gossa.Package registration source of a Go
+// package, the files of the gossa/pkg tree. It is the library behind
+// cmd/qexp:
+//
+//	pkg, err := export.Load("strings", &export.Options{
+//		Exclude: regexp.MustCompile(`^Title$`),
+//		Doc:     true,
+//	})
+//	data, err := pkg.Source()
+//
+// With Doc, the generated entries are commented with the doc of their
+// symbol, and Package.Docs keeps the docs for tooling showing help.
+package export
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/loader"
+)
+
+// Options are the options of Load.
+type Options struct {
+	Build   *build.Context // build context, nil for build.Default
+	Include *regexp.Regexp // export the symbols matching by name, nil for all
+	Exclude *regexp.Regexp // skip the symbols matching by name
+	Tags    []string       // build constraint lines, nil for VersionTags of runtime.Version
+	Doc     bool           // comment entries and record docs
+	Name    string         // import name of the package in the source, "q" by default
+}
+
+// Package is the exported symbols of a package. The entries are Go source
+// of the gossa.Package map items.
+type Package struct {
+	Name          string
+	Path          string
+	Tags          []string
+	Deps          []string
+	NamedTypes    []string
+	Interfaces    []string
+	AliasTypes    []string
+	Vars          []string
+	Funcs         []string
+	TypedConsts   []string
+	UntypedConsts []string
+	Docs          map[string]string // name or Type.Method => doc, with Doc option
+	sname         string
+	comments      map[string]string // entry => comment
+}
+
+// Load loads the package path and returns its exported symbols.
+func Load(path string, opts *Options) (*Package, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	var cfg loader.Config
+	cfg.Build = opts.Build
+	if opts.Doc {
+		cfg.ParserMode = parser.ParseComments
+	}
+	cfg.Import(path)
+	iprog, err := cfg.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load pkg %v error: %v", path, err)
+	}
+	info := iprog.Package(path)
+	sname := opts.Name
+	if sname == "" {
+		sname = "q"
+	}
+	tags := opts.Tags
+	if tags == nil {
+		tags = VersionTags(runtime.Version())
+	}
+	e := &Package{
+		Name:     info.Pkg.Name(),
+		Path:     info.Pkg.Path(),
+		Tags:     tags,
+		sname:    sname,
+		comments: make(map[string]string),
+	}
+	if opts.Doc {
+		e.Docs = docs(info.Files)
+	}
+	e.export(info.Pkg, opts)
+	return e, nil
+}
+
+// VersionTags returns the build constraint of the export files of a Go
+// version, eg. "go1.17" => "//+build go1.17,!go1.18".
+func VersionTags(version string) []string {
+	minor, ok := goMinor(version)
+	if !ok {
+		return nil
+	}
+	return []string{fmt.Sprintf("//+build go1.%v,!go1.%v", minor, minor+1)}
+}
+
+// SinceTags returns the build constraint of the export files of the symbols
+// added in a Go version, eg. "go1.21" => "//+build go1.21".
+func SinceTags(version string) []string {
+	minor, ok := goMinor(version)
+	if !ok {
+		return nil
+	}
+	return []string{fmt.Sprintf("//+build go1.%v", minor)}
+}
+
+// VersionFileName returns the file name of the export files of a Go version,
+// eg. "go1.17" => "go117_export.go".
+func VersionFileName(version string) string {
+	minor, ok := goMinor(version)
+	if !ok {
+		return "export.go"
+	}
+	return fmt.Sprintf("go1%v_export.go", minor)
+}
+
+func goMinor(version string) (int, bool) {
+	if !strings.HasPrefix(version, "go1.") {
+		return 0, false
+	}
+	v := version[4:]
+	if i := strings.IndexFunc(v, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		v = v[:i]
+	}
+	minor, err := strconv.Atoi(v)
+	return minor, err == nil
+}
+
+func (e *Package) export(pkg *types.Package, opts *Options) {
+	pkgName := e.sname
+	for _, v := range pkg.Imports() {
+		e.Deps = append(e.Deps, fmt.Sprintf("%q: %q", v.Path(), v.Name()))
+	}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if !token.IsExported(name) {
+			continue
+		}
+		if opts.Include != nil && !opts.Include.MatchString(name) {
+			continue
+		}
+		if opts.Exclude != nil && opts.Exclude.MatchString(name) {
+			continue
+		}
+		obj := scope.Lookup(name)
+		if isGeneric(obj) {
+			continue
+		}
+		var entry string
+		switch t := obj.(type) {
+		case *types.Const:
+			named := pkgName + "." + t.Name()
+			if typ := t.Type().String(); strings.HasPrefix(typ, "untyped ") {
+				entry = fmt.Sprintf("%q: {%q, %v}", t.Name(), typ, constToLit(named, t.Val()))
+				e.UntypedConsts = append(e.UntypedConsts, entry)
+			} else {
+				entry = fmt.Sprintf("%q: {reflect.TypeOf(%v), %v}", t.Name(), named, constToLit(named, t.Val()))
+				e.TypedConsts = append(e.TypedConsts, entry)
+			}
+		case *types.Var:
+			entry = fmt.Sprintf("%q: reflect.ValueOf(&%v.%v)", t.Name(), pkgName, t.Name())
+			e.Vars = append(e.Vars, entry)
+		case *types.Func:
+			entry = fmt.Sprintf("%q: reflect.ValueOf(%v.%v)", t.Name(), pkgName, t.Name())
+			e.Funcs = append(e.Funcs, entry)
+		case *types.TypeName:
+			if t.IsAlias() {
+				switch typ := unalias(t.Type()).(type) {
+				case *types.Named:
+					entry = fmt.Sprintf("%q: reflect.TypeOf((*%v.%v)(nil)).Elem()", name, pkgName, name)
+				case *types.Basic:
+					entry = fmt.Sprintf("%q: reflect.TypeOf((*%v)(nil)).Elem()", name, typ.Name())
+				default:
+					log.Panicln("error parser", typ)
+				}
+				e.AliasTypes = append(e.AliasTypes, entry)
+				break
+			}
+			typ := t.Type()
+			if types.IsInterface(typ) {
+				entry = fmt.Sprintf("%q: reflect.TypeOf((*%v.%v)(nil)).Elem()", name, pkgName, name)
+				e.Interfaces = append(e.Interfaces, entry)
+				break
+			}
+			var ms, pms []string
+			recvId := typ.String()
+			for _, method := range intuitiveMethodSet(typ) {
+				mname := method.Obj().Name()
+				mid := method.Obj().Type().(*types.Signature).Recv().Type().String()
+				if mid[0] == '*' {
+					if mid[1:] == recvId {
+						pms = append(pms, mname)
+					}
+				} else if mid == recvId {
+					ms = append(ms, mname)
+				}
+			}
+			entry = fmt.Sprintf("%q: {reflect.TypeOf((*%v.%v)(nil)).Elem(), \"%v\", \"%v\"}", name, pkgName, name,
+				strings.Join(ms, ","), strings.Join(pms, ","))
+			e.NamedTypes = append(e.NamedTypes, entry)
+		default:
+			continue
+		}
+		if doc := e.Docs[name]; doc != "" {
+			e.comments[entry] = doc
+		}
+	}
+}
+
+// docs returns the docs of the package level declarations and methods.
+func docs(files []*ast.File) map[string]string {
+	m := make(map[string]string)
+	add := func(name string, groups ...*ast.CommentGroup) {
+		for _, g := range groups {
+			if text := strings.TrimSpace(g.Text()); text != "" {
+				m[name] = text
+				return
+			}
+		}
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Doc == nil {
+					continue
+				}
+				if d.Recv == nil {
+					add(d.Name.Name, d.Doc)
+				} else if len(d.Recv.List) == 1 {
+					typ := d.Recv.List[0].Type
+					if star, ok := typ.(*ast.StarExpr); ok {
+						typ = star.X
+					}
+					if id, ok := typ.(*ast.Ident); ok {
+						add(id.Name+"."+d.Name.Name, d.Doc)
+					}
+				}
+			case *ast.GenDecl:
+				var outer *ast.CommentGroup
+				if len(d.Specs) == 1 {
+					outer = d.Doc
+				}
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						add(s.Name.Name, s.Doc, outer, s.Comment)
+					case *ast.ValueSpec:
+						for _, id := range s.Names {
+							add(id.Name, s.Doc, outer, s.Comment)
+						}
+					}
+				}
+			}
+		}
+	}
+	return m
+}
+
+// joinList returns the sorted entries as map items, preceded by their comments.
+func (e *Package) joinList(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	sort.Strings(list)
+	var buf strings.Builder
+	for _, entry := range list {
+		buf.WriteString("\n")
+		if doc, ok := e.comments[entry]; ok {
+			for _, line := range strings.Split(doc, "\n") {
+				buf.WriteString("\t// " + line + "\n")
+			}
+		}
+		buf.WriteString("\t" + entry + ",")
+	}
+	return buf.String() + "\n"
+}
+
+// IsEmpty reports whether the package has no exported symbols.
+func (e *Package) IsEmpty() bool {
+	return len(e.NamedTypes)+len(e.Interfaces)+len(e.AliasTypes)+len(e.Vars)+len(e.Funcs)+
+		len(e.TypedConsts)+len(e.UntypedConsts) == 0
+}
+
+// Source returns the formatted registration source of the package.
+func (e *Package) Source() ([]byte, error) {
+	imports := []string{fmt.Sprintf("%v %q\n", e.sname, e.Path)}
+	imports = append(imports, `"reflect"`)
+	if len(e.UntypedConsts) > 0 || len(e.TypedConsts) > 0 {
+		imports = append(imports, `"go/constant"`)
+		var hasToken bool
+		for _, c := range append(e.UntypedConsts, e.TypedConsts...) {
+			if strings.Contains(c, "token.") {
+				hasToken = true
+				break
+			}
+		}
+		if hasToken {
+			imports = append(imports, `"go/token"`)
+		}
+	}
+	r := strings.NewReplacer("$PKGNAME", e.Name,
+		"$IMPORTS", strings.Join(imports, "\n"),
+		"$PKGPATH", e.Path,
+		"$DEPS", e.joinList(e.Deps),
+		"$NAMEDTYPES", e.joinList(e.NamedTypes),
+		"$INTERFACES", e.joinList(e.Interfaces),
+		"$ALIASTYPES", e.joinList(e.AliasTypes),
+		"$VARS", e.joinList(e.Vars),
+		"$FUNCS", e.joinList(e.Funcs),
+		"$TYPEDCONSTS", e.joinList(e.TypedConsts),
+		"$UNTYPEDCONSTS", e.joinList(e.UntypedConsts),
+		"$TAGS", strings.Join(e.Tags, "\n"))
+	src := r.Replace(templatePkg)
+	data, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("format pkg %v error: %v", e.Path, err)
+	}
+	return data, nil
+}
+
+var templatePkg = `// export by github.com/goplus/gossa/cmd/qexp
+
+$TAGS
+
+package $PKGNAME
+
+import (
+	$IMPORTS
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package {
+		Name: "$PKGNAME",
+		Path: "$PKGPATH",
+		Deps: map[string]string{$DEPS},
+		Interfaces: map[string]reflect.Type{$INTERFACES},
+		NamedTypes: map[string]gossa.NamedType{$NAMEDTYPES},
+		AliasTypes: map[string]reflect.Type{$ALIASTYPES},
+		Vars: map[string]reflect.Value{$VARS},
+		Funcs: map[string]reflect.Value{$FUNCS},
+		TypedConsts: map[string]gossa.TypedConst{$TYPEDCONSTS},
+		UntypedConsts: map[string]gossa.UntypedConst{$UNTYPEDCONSTS},
+	})
+}
+`
+
+func constToLit(named string, c constant.Value) string {
+	switch c.Kind() {
+	case constant.Bool:
+		if named != "" {
+			return fmt.Sprintf("constant.MakeBool(bool(%v))", named)
+		}
+		return fmt.Sprintf("constant.MakeBool(%v)", constant.BoolVal(c))
+	case constant.String:
+		if named != "" {
+			return fmt.Sprintf("constant.MakeString(string(%v))", named)
+		}
+		return fmt.Sprintf("constant.MakeString(%q)", constant.StringVal(c))
+	case constant.Int:
+		if v, ok := constant.Int64Val(c); ok {
+			if named != "" {
+				return fmt.Sprintf("constant.MakeInt64(int64(%v))", named)
+			}
+			return fmt.Sprintf("constant.MakeInt64(%v)", v)
+		} else if v, ok := constant.Uint64Val(c); ok {
+			if named != "" {
+				return fmt.Sprintf("constant.MakeUint64(uint64(%v))", named)
+			}
+			return fmt.Sprintf("constant.MakeUint64(%v)", v)
+		}
+		return fmt.Sprintf("constant.MakeFromLiteral(%q, token.INT, 0)", c.ExactString())
+	case constant.Float:
+		s := c.ExactString()
+		if pos := strings.IndexByte(s, '/'); pos >= 0 {
+			sx := s[:pos]
+			sy := s[pos+1:]
+			// simplify 314/100 => 3.14
+			if strings.HasPrefix(sy, "1") && strings.Count(sy, "0") == len(sy)-1 {
+				if len(sx) == len(sy) {
+					return fmt.Sprintf("constant.MakeFromLiteral(\"%v.%v\", token.FLOAT, 0)", sx[:1], sx[1:])
+				} else if len(sx) == len(sy)-1 {
+					return fmt.Sprintf("constant.MakeFromLiteral(\"0.%v\", token.FLOAT, 0)", sx)
+				} else if len(sx) < len(sy) {
+					return fmt.Sprintf("constant.MakeFromLiteral(\"%v.%ve-%v\", token.FLOAT, 0)", sx[:1], sx[1:], len(sy)-len(sx))
+				}
+			} else if strings.HasPrefix(sy, "5") && strings.Count(sy, "0") == len(sy)-1 {
+				if len(sx) == len(sy) {
+					c := constant.BinaryOp(constant.MakeFromLiteral(sx, token.INT, 0), token.MUL, constant.MakeInt64(2))
+					sx = c.ExactString()
+					return fmt.Sprintf("constant.MakeFromLiteral(\"%v.%v\", token.FLOAT, 0)", sx[:1], sx[1:])
+				}
+			}
+			x := fmt.Sprintf("constant.MakeFromLiteral(%q, token.INT, 0)", sx)
+			y := fmt.Sprintf("constant.MakeFromLiteral(%q, token.INT, 0)", sy)
+			return fmt.Sprintf("constant.BinaryOp(%v, token.QUO, %v)", x, y)
+		}
+		if pos := strings.LastIndexAny(s, "123456789"); pos != -1 {
+			sx := s[:pos+1]
+			return fmt.Sprintf("constant.MakeFromLiteral(\"%v.%ve+%v\", token.FLOAT, 0)", sx[:1], sx[1:], len(s)-1)
+		}
+		return fmt.Sprintf("constant.MakeFromLiteral(%q, token.FLOAT, 0)", s)
+	case constant.Complex:
+		re := constToLit("", constant.Real(c))
+		im := constToLit("", constant.Imag(c))
+		return fmt.Sprintf("constant.BinaryOp(%v, token.ADD, constant.MakeImag(%v))", re, im)
+	default:
+		panic("unreachable")
+	}
+}
+
+// intuitiveMethodSet is golang.org/x/tools/go/types/typeutil.IntuitiveMethodSet.
+func intuitiveMethodSet(T types.Type) []*types.Selection {
+	isPointerToConcrete := func(T types.Type) bool {
+		ptr, ok := T.(*types.Pointer)
+		return ok && !types.IsInterface(ptr.Elem())
+	}
+
+	var result []*types.Selection
+	mset := types.NewMethodSet(T)
+	if types.IsInterface(T) || isPointerToConcrete(T) {
+		for i, n := 0, mset.Len(); i < n; i++ {
+			result = append(result, mset.At(i))
+		}
+	} else {
+		// T is some other concrete type.
+		// Report methods of T and *T, preferring those of T.
+		pmset := types.NewMethodSet(types.NewPointer(T))
+		for i, n := 0, pmset.Len(); i < n; i++ {
+			meth := pmset.At(i)
+			if m := mset.Lookup(meth.Obj().Pkg(), meth.Obj().Name()); m != nil {
+				meth = m
+			}
+			result = append(result, meth)
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	pkg, err := Load("strings", &Options{
+		Include: regexp.MustCompile(`^(Contains|ContainsAny|Builder)$`),
+		Exclude: regexp.MustCompile(`Any$`),
+		Tags:    []string{"//+build go1.18"},
+		Doc:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Funcs) != 1 || len(pkg.NamedTypes) != 1 || len(pkg.Vars) != 0 {
+		t.Fatalf("bad filtered symbols %v %v %v", pkg.Funcs, pkg.NamedTypes, pkg.Vars)
+	}
+	if doc := pkg.Docs["Contains"]; !strings.HasPrefix(doc, "Contains reports") {
+		t.Fatalf("bad Contains doc %q", doc)
+	}
+	if doc := pkg.Docs["Builder.String"]; doc == "" {
+		t.Fatal("missing Builder.String doc")
+	}
+	data, err := pkg.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(data)
+	for _, s := range []string{
+		"+build go1.18\n",
+		"\t\t\t// Contains reports",
+		`"Contains": reflect.ValueOf(q.Contains),`,
+		`"Builder": {reflect.TypeOf((*q.Builder)(nil)).Elem(), "", "`,
+	} {
+		if !strings.Contains(src, s) {
+			t.Fatalf("source missing %q:\n%v", s, src)
+		}
+	}
+	if strings.Contains(src, "ContainsAny") {
+		t.Fatalf("excluded symbol exported:\n%v", src)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if tags := VersionTags("go1.17.5"); !reflect.DeepEqual(tags, []string{"//+build go1.17,!go1.18"}) {
+		t.Fatalf("bad tags %v", tags)
+	}
+	if tags := VersionTags("devel"); tags != nil {
+		t.Fatalf("bad devel tags %v", tags)
+	}
+	if name := VersionFileName("go1.18beta1"); name != "go118_export.go" {
+		t.Fatalf("bad file name %v", name)
+	}
+	if tags := SinceTags("go1.21"); !reflect.DeepEqual(tags, []string{"//+build go1.21"}) {
+		t.Fatalf("bad since tags %v", tags)
+	}
+}
+
+func TestUsedSymbols(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	str "strings"
+	. "errors"
+	_ "embed"
+)
+
+func main() {
+	var b str.Builder
+	b.WriteString(str.ToUpper("a"))
+	fmt.Println(b.String(), New("e"), str.ToUpper("b"))
+}
+`
+	fname := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(fname, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	used, err := UsedSymbols(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"fmt":     {"Println"},
+		"strings": {"Builder", "ToUpper"},
+		"errors":  {"*"},
+	}
+	if !reflect.DeepEqual(used, want) {
+		t.Fatalf("bad used symbols %v", used)
+	}
+	if IncludeNames(used["errors"]) != nil {
+		t.Fatal("dot import must include all symbols")
+	}
+	pkg, err := Load("strings", &Options{Include: IncludeNames(used["strings"])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Funcs) != 1 || len(pkg.NamedTypes) != 1 || len(pkg.Vars) != 0 {
+		t.Fatalf("bad used symbols %v %v %v", pkg.Funcs, pkg.NamedTypes, pkg.Vars)
+	}
+}
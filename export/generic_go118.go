@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package export
+
+import "go/types"
+
+// isGeneric reports whether obj is a generic function or type, which has
+// no reflect value to export.
+func isGeneric(obj types.Object) bool {
+	switch t := obj.(type) {
+	case *types.Func:
+		return t.Type().(*types.Signature).TypeParams().Len() != 0
+	case *types.TypeName:
+		if named, ok := t.Type().(*types.Named); ok && !t.IsAlias() && named.TypeParams().Len() != 0 {
+			return true
+		}
+		if iface, ok := t.Type().Underlying().(*types.Interface); ok {
+			// constraint interfaces, eg. cmp.Ordered
+			return !iface.IsMethodSet()
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UsedSymbols returns the package level symbols of the imports referenced
+// by the Go source files, import path => sorted names. Exporting only the
+// used symbols by IncludeNames lets the linker drop the rest of a package,
+// which its reflect values registered otherwise keep:
+//
+//	used, err := export.UsedSymbols(scripts...)
+//	pkg, err := export.Load("strings", &export.Options{
+//		Include: export.IncludeNames(used["strings"]),
+//	})
+//
+// A package imported by a dot import has the name "*", all its symbols are
+// used.
+func UsedSymbols(filenames ...string) (map[string][]string, error) {
+	fset := token.NewFileSet()
+	used := make(map[string]map[string]bool)
+	add := func(path, name string) {
+		if used[path] == nil {
+			used[path] = make(map[string]bool)
+		}
+		used[path][name] = true
+	}
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]string) // import name => path
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return nil, err
+			}
+			name := path[strings.LastIndex(path, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			switch name {
+			case "_":
+			case ".":
+				add(path, "*")
+			default:
+				names[name] = path
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+					if path, ok := names[id.Name]; ok {
+						add(path, sel.Sel.Name)
+					}
+				}
+			}
+			return true
+		})
+	}
+	m := make(map[string][]string, len(used))
+	for path, set := range used {
+		var list []string
+		for name := range set {
+			list = append(list, name)
+		}
+		sort.Strings(list)
+		m[path] = list
+	}
+	return m, nil
+}
+
+// IncludeNames returns the Options.Include regexp matching the names, nil
+// for all symbols if names has "*".
+func IncludeNames(names []string) *regexp.Regexp {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		if name == "*" {
+			return nil
+		}
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return regexp.MustCompile("^(" + strings.Join(quoted, "|") + ")$")
+}
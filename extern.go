@@ -0,0 +1,42 @@
+package gossa
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// SetExternCallHook sets fn called before each call of a registered host
+// function or method by the program, with the full name of the callee, eg.
+// "os.Open" or "(*bytes.Buffer).Write", and the call arguments. Host func
+// values called dynamically are named by the host runtime. An error of fn
+// vetoes the call and panics in the program with the error, which the
+// program can recover. A nil fn removes the hook. SetExternCallHook should
+// be called before running the interpreter.
+func (i *Interp) SetExternCallHook(fn func(fn string, args []reflect.Value) error) {
+	i.externHook = fn
+}
+
+// externFunc is a registered host function of a deferred or go call.
+type externFunc struct {
+	name string
+	fn   reflect.Value
+}
+
+// auditExtern calls the extern call hook for the call of fn with ins by
+// caller, and panics with its error.
+func (i *Interp) auditExtern(caller *frame, name string, fn reflect.Value, ins []reflect.Value) {
+	if name == "" {
+		name = externFuncName(fn)
+	}
+	if err := i.externHook(name, ins); err != nil {
+		panic(targetPanic{err, caller})
+	}
+}
+
+// externFuncName returns the host runtime name of the func value fn.
+func externFuncName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return fn.Type().String()
+}
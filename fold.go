@@ -0,0 +1,255 @@
+package gossa
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// folding is the result of the constant folding pass over a function,
+// run by the visitor before makeInstr compiles its instructions.
+//
+// Binary and unary operations of boolean, integer and string constants are
+// evaluated once and their results bound to constant registers. Branches on
+// constant conditions become jumps and the blocks they no longer reach are
+// not compiled. Floating point operations are left to run time, as their
+// constant evaluation is exact and may differ from IEEE rounding.
+type folding struct {
+	consts map[ssa.Value]constant.Value // folded values
+	jumps  map[*ssa.If]int              // taken successor of constant branches
+	live   []bool                       // reachable blocks by index
+}
+
+// foldFunction runs the folding pass over pfn and binds the folded values
+// to constant registers of pfn.
+func foldFunction(pfn *Function) *folding {
+	fn := pfn.Fn
+	f := &folding{
+		consts: make(map[ssa.Value]constant.Value),
+		jumps:  make(map[*ssa.If]int),
+		live:   make([]bool, len(fn.Blocks)),
+	}
+	// values dominate their uses out of phis
+	for _, b := range fn.DomPreorder() {
+		for _, instr := range b.Instrs {
+			var c constant.Value
+			var ok bool
+			switch instr := instr.(type) {
+			case *ssa.BinOp:
+				c, ok = f.binop(instr)
+			case *ssa.UnOp:
+				c, ok = f.unop(instr)
+			case *ssa.If:
+				if c, ok := f.value(instr.Cond); ok && c.Kind() == constant.Bool {
+					if constant.BoolVal(c) {
+						f.jumps[instr] = 0
+					} else {
+						f.jumps[instr] = 1
+					}
+				}
+			}
+			if ok {
+				v := instr.(ssa.Value)
+				f.consts[v] = c
+				pfn.index[v] = pfn.regInstr(ssa.NewConst(c, v.Type()))
+			}
+		}
+	}
+	f.mark(fn.Blocks[0])
+	if fn.Recover != nil {
+		f.mark(fn.Recover)
+	}
+	return f
+}
+
+// noFolding returns the folding of fn with no folded values, all blocks
+// are live.
+func noFolding(fn *ssa.Function) *folding {
+	f := &folding{live: make([]bool, len(fn.Blocks))}
+	for i := range f.live {
+		f.live[i] = true
+	}
+	return f
+}
+
+// mark marks the blocks reachable from b.
+func (f *folding) mark(b *ssa.BasicBlock) {
+	if f.live[b.Index] {
+		return
+	}
+	f.live[b.Index] = true
+	if instr, ok := b.Instrs[len(b.Instrs)-1].(*ssa.If); ok {
+		if succ, ok := f.jumps[instr]; ok {
+			f.mark(b.Succs[succ])
+			return
+		}
+	}
+	for _, succ := range b.Succs {
+		f.mark(succ)
+	}
+}
+
+// elided reports why instr of a live or dead block is not compiled.
+func (f *folding) elided(instr ssa.Instruction) (string, bool) {
+	if !f.live[instr.Block().Index] {
+		return "unreachable block", true
+	}
+	if v, ok := instr.(ssa.Value); ok {
+		if _, ok := f.consts[v]; ok {
+			return "constant folded", true
+		}
+	}
+	return "", false
+}
+
+// jump returns the taken successor of instr, a branch on a constant.
+func (f *folding) jump(instr ssa.Instruction) (int, bool) {
+	if instr, ok := instr.(*ssa.If); ok {
+		succ, ok := f.jumps[instr]
+		return succ, ok
+	}
+	return 0, false
+}
+
+// makeJump returns the closure of a jump to the successor succ of the block
+// b.
+func makeJump(b *ssa.BasicBlock, succ int) func(fr *frame) {
+	if b.Succs[succ].Index <= b.Index {
+		// loop back edge, a safepoint of RunFuncTimeout
+		return func(fr *frame) {
+			if fr.deadline != nil {
+				fr.deadline.check(fr)
+			}
+			fr.pred, fr.block = fr.block.Index, fr.block.Succs[succ]
+			fr.pc = fr.pfn.Blocks[fr.block.Index]
+		}
+	}
+	return func(fr *frame) {
+		fr.pred, fr.block = fr.block.Index, fr.block.Succs[succ]
+		fr.pc = fr.pfn.Blocks[fr.block.Index]
+	}
+}
+
+// value returns the constant value of v.
+func (f *folding) value(v ssa.Value) (constant.Value, bool) {
+	if c, ok := v.(*ssa.Const); ok {
+		if c.Value == nil {
+			return nil, false
+		}
+		return c.Value, true
+	}
+	c, ok := f.consts[v]
+	return c, ok
+}
+
+// foldable reports whether the values of typ are folded.
+func foldable(typ types.Type) (*types.Basic, bool) {
+	t, ok := typ.Underlying().(*types.Basic)
+	if !ok || t.Info()&(types.IsBoolean|types.IsInteger|types.IsString) == 0 {
+		return nil, false
+	}
+	return t, true
+}
+
+func (f *folding) binop(instr *ssa.BinOp) (constant.Value, bool) {
+	t, ok := foldable(instr.X.Type())
+	if !ok {
+		return nil, false
+	}
+	x, ok := f.value(instr.X)
+	if !ok {
+		return nil, false
+	}
+	y, ok := f.value(instr.Y)
+	if !ok {
+		return nil, false
+	}
+	switch instr.Op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return constant.MakeBool(constant.Compare(x, instr.Op, y)), true
+	}
+	var r constant.Value
+	switch instr.Op {
+	case token.SHL, token.SHR:
+		s, ok := constant.Uint64Val(y)
+		if !ok || s >= 64 {
+			return nil, false
+		}
+		r = constant.Shift(x, instr.Op, uint(s))
+	case token.QUO, token.REM:
+		if constant.Sign(y) == 0 {
+			// division by zero panics at run time
+			return nil, false
+		}
+		op := instr.Op
+		if op == token.QUO {
+			op = token.QUO_ASSIGN // integer division
+		}
+		r = constant.BinaryOp(x, op, y)
+	default:
+		r = constant.BinaryOp(x, instr.Op, y)
+	}
+	if t.Info()&types.IsInteger != 0 && !representable(r, t) {
+		// overflow wraps around at run time
+		return nil, false
+	}
+	return r, true
+}
+
+func (f *folding) unop(instr *ssa.UnOp) (constant.Value, bool) {
+	t, ok := foldable(instr.X.Type())
+	if !ok {
+		return nil, false
+	}
+	var prec uint
+	switch instr.Op {
+	case token.SUB, token.NOT:
+	case token.XOR:
+		if t.Info()&types.IsUnsigned != 0 {
+			prec = uint(intBits(t))
+		}
+	default:
+		return nil, false
+	}
+	x, ok := f.value(instr.X)
+	if !ok {
+		return nil, false
+	}
+	r := constant.UnaryOp(instr.Op, x, prec)
+	if t.Info()&types.IsInteger != 0 && !representable(r, t) {
+		return nil, false
+	}
+	return r, true
+}
+
+// intBits returns the size in bits of the integer type t in the interpreter.
+func intBits(t *types.Basic) int {
+	switch t.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	case types.Int64, types.Uint64:
+		return 64
+	}
+	return intSize
+}
+
+// representable reports whether the integer constant c is a value of t.
+func representable(c constant.Value, t *types.Basic) bool {
+	if c.Kind() != constant.Int {
+		return false
+	}
+	bits := intBits(t)
+	if t.Info()&types.IsUnsigned != 0 {
+		max := constant.Shift(constant.MakeInt64(1), token.SHL, uint(bits))
+		return constant.Sign(c) >= 0 && constant.Compare(c, token.LSS, max)
+	}
+	max := constant.Shift(constant.MakeInt64(1), token.SHL, uint(bits-1))
+	min := constant.UnaryOp(token.SUB, max, 0)
+	return constant.Compare(c, token.GEQ, min) && constant.Compare(c, token.LSS, max)
+}
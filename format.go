@@ -0,0 +1,298 @@
+package gossa
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// findFmtFunc returns the print function name of package fmt, which
+// formats the arguments of interpreter defined types by their interpreted
+// Format, GoString, Error and String methods like compiled Go, without
+// calling them through the reflectx method wrappers. The output to
+// os.Stdout and os.Stderr is also written to CapturedOutput.
+func findFmtFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	var fn interface{}
+	switch name {
+	case "Print":
+		fn = func(a ...interface{}) (int, error) {
+			return fmt.Fprint(captured(os.Stdout), interp.sprint(a))
+		}
+	case "Println":
+		fn = func(a ...interface{}) (int, error) {
+			return fmt.Fprintln(captured(os.Stdout), interp.formatArgs(a)...)
+		}
+	case "Printf":
+		fn = func(format string, a ...interface{}) (int, error) {
+			return fmt.Fprintf(captured(os.Stdout), format, interp.formatfArgs(format, a)...)
+		}
+	case "Sprint":
+		fn = func(a ...interface{}) string {
+			return interp.sprint(a)
+		}
+	case "Sprintln":
+		fn = func(a ...interface{}) string {
+			return fmt.Sprintln(interp.formatArgs(a)...)
+		}
+	case "Sprintf":
+		fn = func(format string, a ...interface{}) string {
+			return fmt.Sprintf(format, interp.formatfArgs(format, a)...)
+		}
+	case "Fprint":
+		fn = func(w io.Writer, a ...interface{}) (int, error) {
+			return fmt.Fprint(captured(w), interp.sprint(a))
+		}
+	case "Fprintln":
+		fn = func(w io.Writer, a ...interface{}) (int, error) {
+			return fmt.Fprintln(captured(w), interp.formatArgs(a)...)
+		}
+	case "Fprintf":
+		fn = func(w io.Writer, format string, a ...interface{}) (int, error) {
+			return fmt.Fprintf(captured(w), format, interp.formatfArgs(format, a)...)
+		}
+	default:
+		// Errorf keeps the %w operands for errors.Unwrap
+		return
+	}
+	return reflect.ValueOf(fn), true
+}
+
+// formatArgs returns a with the values of interpreter defined types with
+// formatting methods and the func values of the program replaced by
+// formatters.
+func (i *Interp) formatArgs(a []interface{}) []interface{} {
+	var args []interface{}
+	for n, v := range a {
+		var f fmt.Formatter
+		if ff, ok := i.formatValue(v); ok {
+			f = ff
+		} else if fn, ok := i.FuncOf(v); ok && !hasFormatMethods(v) {
+			f = &funcFormatter{v, i.funcString(fn)}
+		} else {
+			continue
+		}
+		if args == nil {
+			args = append([]interface{}{}, a...)
+		}
+		args[n] = f
+	}
+	if args == nil {
+		return a
+	}
+	return args
+}
+
+// sprint is fmt.Sprint of the formatArgs of a. Print adds spaces between
+// operands when neither is a string, by the kinds of a rather than of
+// their formatters.
+func (i *Interp) sprint(a []interface{}) string {
+	args := i.formatArgs(a)
+	if len(a) == 0 || &args[0] == &a[0] {
+		return fmt.Sprint(a...)
+	}
+	var b strings.Builder
+	prevString := false
+	for n, arg := range args {
+		isString := a[n] != nil && reflect.TypeOf(a[n]).Kind() == reflect.String
+		if n > 0 && !isString && !prevString {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, arg)
+		prevString = isString
+	}
+	return b.String()
+}
+
+// formatfArgs is formatArgs of the arguments a of format. The arguments
+// of the verbs %T and %p are kept, fmt prints their type and pointer
+// without calling formatters.
+func (i *Interp) formatfArgs(format string, a []interface{}) []interface{} {
+	args := i.formatArgs(a)
+	if len(a) == 0 || &args[0] == &a[0] {
+		return args
+	}
+	for n, verb := range argVerbs(format, len(a)) {
+		if verb == 'T' || verb == 'p' {
+			args[n] = a[n]
+		}
+	}
+	return args
+}
+
+// argVerbs returns the verbs of the n arguments of format, parsed like fmt
+// with explicit argument indexes and * widths and precisions. An argument
+// of several verbs has the verb T or p if any.
+func argVerbs(format string, n int) []rune {
+	verbs := make([]rune, n)
+	argNum := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+	flags:
+		for i++; i < len(format); i++ {
+			switch c := format[i]; {
+			case c == '[':
+				j := strings.IndexByte(format[i:], ']')
+				if j < 0 {
+					return verbs
+				}
+				if k, err := strconv.Atoi(format[i+1 : i+j]); err == nil {
+					argNum = k - 1
+				}
+				i += j
+			case c == '*':
+				argNum++
+			case strings.IndexByte("+-# 0.", c) < 0 && (c < '0' || c > '9'):
+				break flags
+			}
+		}
+		if i >= len(format) {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		if verb == '%' {
+			continue
+		}
+		if argNum >= 0 && argNum < n && verbs[argNum] != 'T' && verbs[argNum] != 'p' {
+			verbs[argNum] = verb
+		}
+		argNum++
+	}
+	return verbs
+}
+
+// formatValue returns the formatter of v, if v is of an interpreter
+// defined type with formatting methods.
+func (i *Interp) formatValue(v interface{}) (*formatter, bool) {
+	if v == nil {
+		return nil, false
+	}
+	mset, ok := i.msets[reflect.TypeOf(v)]
+	if !ok {
+		return nil, false
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		// fmt prints <nil> if the methods panic
+		return nil, false
+	}
+	f := &formatter{
+		interp:   i,
+		v:        v,
+		goString: stringMethod(mset, "GoString"),
+		error:    stringMethod(mset, "Error"),
+		string:   stringMethod(mset, "String"),
+	}
+	if fn, ok := mset["Format"]; ok && isFormatMethod(fn) {
+		f.format = fn
+	}
+	if f.format == nil && f.goString == nil && f.error == nil && f.string == nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// hasFormatMethods reports whether fmt formats v by its methods, eg. of
+// the func types of package adapter.
+func hasFormatMethods(v interface{}) bool {
+	switch v.(type) {
+	case fmt.Formatter, fmt.GoStringer, fmt.Stringer, error:
+		return true
+	}
+	return false
+}
+
+// stringMethod returns the method name of type func() string in mset.
+func stringMethod(mset map[string]*ssa.Function, name string) *ssa.Function {
+	fn, ok := mset[name]
+	if !ok {
+		return nil
+	}
+	sig := fn.Signature
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 ||
+		!types.Identical(sig.Results().At(0).Type(), types.Typ[types.String]) {
+		return nil
+	}
+	return fn
+}
+
+// isFormatMethod reports whether fn is the method of fmt.Formatter.
+func isFormatMethod(fn *ssa.Function) bool {
+	sig := fn.Signature
+	if sig.Params().Len() != 2 || sig.Results().Len() != 0 {
+		return false
+	}
+	named, ok := sig.Params().At(0).Type().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "fmt" &&
+		named.Obj().Name() == "State" &&
+		types.Identical(sig.Params().At(1).Type(), types.Typ[types.Rune])
+}
+
+// formatter formats v like the handleMethods of fmt by the interpreted
+// methods of its type.
+type formatter struct {
+	interp   *Interp
+	v        interface{}
+	format   *ssa.Function
+	goString *ssa.Function
+	error    *ssa.Function
+	string   *ssa.Function
+}
+
+func (f *formatter) call(fn *ssa.Function, args ...value) value {
+	return f.interp.callFunction(f.interp.tryDeferFrame(), fn, append([]value{f.v}, args...), nil)
+}
+
+func (f *formatter) Format(s fmt.State, verb rune) {
+	if f.format != nil {
+		f.call(f.format, s, verb)
+		return
+	}
+	if verb == 'v' && s.Flag('#') {
+		if f.goString != nil {
+			io.WriteString(s, f.call(f.goString).(string))
+			return
+		}
+	} else {
+		switch verb {
+		case 'v', 's', 'x', 'X', 'q':
+			if f.error != nil {
+				fmt.Fprintf(s, formatDirective(s, verb), f.call(f.error))
+				return
+			}
+			if f.string != nil {
+				fmt.Fprintf(s, formatDirective(s, verb), f.call(f.string))
+				return
+			}
+		}
+	}
+	// other verbs format the value, which has no methods used by them
+	fmt.Fprintf(s, formatDirective(s, verb), f.v)
+}
+
+// formatDirective returns the directive of verb with the flags, width and
+// precision of s.
+func formatDirective(s fmt.State, verb rune) string {
+	b := []byte{'%'}
+	for _, c := range "+-# 0" {
+		if s.Flag(int(c)) {
+			b = append(b, byte(c))
+		}
+	}
+	if w, ok := s.Width(); ok {
+		b = strconv.AppendInt(b, int64(w), 10)
+	}
+	if p, ok := s.Precision(); ok {
+		b = append(b, '.')
+		b = strconv.AppendInt(b, int64(p), 10)
+	}
+	return string(append(b, string(verb)...))
+}
@@ -0,0 +1,117 @@
+package gossa
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// funcWord returns the closure pointer of the func value v.
+func funcWord(v interface{}) unsafe.Pointer {
+	return (*emptyInterface)(unsafe.Pointer(&v)).word
+}
+
+// funcData is the data of a func value made by makeFunc. Its call method
+// value is the implementation of reflect.MakeFunc, so FuncOf finds it from
+// the func value when needed rather than recording every func value made.
+type funcData struct {
+	interp *Interp
+	typ    reflect.Type
+	pfn    *Function
+	env    []value
+}
+
+func (f *funcData) call(args []reflect.Value) []reflect.Value {
+	return f.interp.callFunctionByReflect(f.interp.tryDeferFrame(), f.typ, f.pfn, args, f.env)
+}
+
+// callClosure is the closure of the method value f.call.
+type callClosure struct {
+	code uintptr
+	recv *funcData
+}
+
+// makeFuncLayout is the layout of the closures of reflect.MakeFunc: all
+// the func values it makes share the code pointer stub, its implementation
+// is the word at index impl of their closures.
+var makeFuncLayout struct {
+	once sync.Once
+	stub uintptr
+	call uintptr
+	impl int
+}
+
+// closureWord returns the word at index n of the closure p.
+func closureWord(p unsafe.Pointer, n int) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + uintptr(n)*unsafe.Sizeof(p)))
+}
+
+// findFuncData returns the funcData of the func value v made by makeFunc,
+// or nil.
+func findFuncData(v interface{}) *funcData {
+	l := &makeFuncLayout
+	l.once.Do(func() {
+		probe := &funcData{}
+		call := probe.call
+		l.call = (*callClosure)(funcWord(call)).code
+		p := funcWord(reflect.MakeFunc(reflect.TypeOf(call), call).Interface())
+		l.stub = *(*uintptr)(p)
+		for n := 1; n < 8; n++ {
+			if closureWord(p, n) == funcWord(call) {
+				l.impl = n
+				break
+			}
+		}
+	})
+	p := funcWord(v)
+	if l.impl == 0 || p == nil || *(*uintptr)(p) != l.stub {
+		return nil
+	}
+	mv := (*callClosure)(closureWord(p, l.impl))
+	if mv == nil || mv.code != l.call {
+		return nil
+	}
+	return mv.recv
+}
+
+// FuncOf returns the interpreted function of v, a function, method value
+// or closure of the program, eg. to name the funcs passed to the host in
+// diagnostics.
+func (i *Interp) FuncOf(v interface{}) (*ssa.Function, bool) {
+	if v == nil || reflect.TypeOf(v).Kind() != reflect.Func {
+		return nil, false
+	}
+	f := findFuncData(v)
+	if f == nil || f.interp != i || f.pfn == nil {
+		return nil, false
+	}
+	return f.pfn.Fn, true
+}
+
+// funcString returns the name of fn with its position, eg.
+// main.myFunc (main.go:5:6).
+func (i *Interp) funcString(fn *ssa.Function) string {
+	if !fn.Pos().IsValid() {
+		return fn.String()
+	}
+	return fmt.Sprintf("%v (%v)", fn, i.fset.Position(fn.Pos()))
+}
+
+// funcFormatter formats the func value v of the program by the name of its
+// function for the verbs v and s, see FuncOf.
+type funcFormatter struct {
+	v    interface{}
+	name string
+}
+
+func (f *funcFormatter) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		fmt.Fprintf(s, formatDirective(s, 's'), f.name)
+	default:
+		fmt.Fprintf(s, formatDirective(s, verb), f.v)
+	}
+}
@@ -0,0 +1,84 @@
+package gossa
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"time"
+)
+
+const (
+	fuzzMaxSteps = 100000          // step budget of a FuzzProgram run
+	fuzzMaxDepth = 1000            // call depth limit of a FuzzProgram run
+	fuzzTimeout  = 5 * time.Second // abandon a blocked FuzzProgram run after
+)
+
+// FuzzProgram parses, compiles and runs the main package src, the file
+// main.go, as a target of native Go fuzzing:
+//
+//	func FuzzInterp(f *testing.F) {
+//		f.Add([]byte("package main\n\nfunc main() {\n\tprintln(1 << 3)\n}\n"))
+//		f.Fuzz(func(t *testing.T, src []byte) {
+//			if err := gossa.FuzzProgram(src); err != nil {
+//				t.Fatal(err)
+//			}
+//		})
+//	}
+//
+// FuzzProgram returns nil for programs which fail to compile, panic or
+// exceed a strict step budget; it returns an error for interpreter
+// crashes, the panics which are not panics of the program, eg. a reflect
+// kind mismatch of an operator. Programs with imports or go statements are
+// not run, and programs blocked for seconds are abandoned.
+func FuzzProgram(src []byte) (err error) {
+	fset := token.NewFileSet()
+	f, perr := parser.ParseFile(fset, "main.go", src, 0)
+	if perr != nil || len(f.Imports) != 0 || hasGoStmt(f) {
+		return nil
+	}
+	ctx := NewContext(StrictPanicSeparation)
+	ctx.SetMaxSteps(fuzzMaxSteps)
+	ctx.SetMaxCallDepth(fuzzMaxDepth)
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			// the compiler crashed
+			if p := recover(); p != nil {
+				done <- unexpectedPanic{p}
+			}
+		}()
+		pkg, err := ctx.LoadAstFile(fset, f)
+		if err != nil {
+			done <- nil
+			return
+		}
+		interp, err := ctx.NewInterp(pkg)
+		if err == nil {
+			_, err = interp.Run("main")
+		}
+		done <- err
+	}()
+	select {
+	case err = <-done:
+	case <-time.After(fuzzTimeout):
+		return nil
+	}
+	var internal *InterpInternalError
+	var unexpected unexpectedPanic
+	if errors.As(err, &internal) || errors.As(err, &unexpected) {
+		return err
+	}
+	return nil
+}
+
+// hasGoStmt reports whether f has a go statement.
+func hasGoStmt(f *ast.File) (found bool) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		if _, ok := n.(*ast.GoStmt); ok {
+			found = true
+		}
+		return !found
+	})
+	return
+}
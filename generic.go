@@ -0,0 +1,78 @@
+package gossa
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// instanceSynthetic is the provenance of the functions replacing the
+// generic functions called, see instantiate.
+const instanceSynthetic = "instance of generic function"
+
+// instantiate replaces the callee of instr, a generic function of a
+// registered package, by the function of the instance of the GenericFuncs
+// of the package matching the argument types, and result type of a call.
+// The function of the instance has the name of the generic function and
+// the instantiated signature, findExternFunc resolves it to the instance.
+// The error of an unregistered instantiation is its missing symbol.
+func instantiate(loader Loader, instr ssa.CallInstruction) *ErrMissingSymbol {
+	call := instr.Common()
+	fn, ok := call.Value.(*ssa.Function)
+	if !ok || call.Method != nil || !isGenericFunc(fn) {
+		return nil
+	}
+	args := make([]*types.Var, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = types.NewParam(token.NoPos, nil, "", arg.Type())
+	}
+	var results *types.Tuple
+	if v := instr.Value(); v != nil {
+		if t, ok := v.Type().(*types.Tuple); ok {
+			results = t
+		} else {
+			results = types.NewTuple(types.NewParam(token.NoPos, nil, "", v.Type()))
+		}
+	}
+	want := types.NewSignature(nil, types.NewTuple(args...), results, fn.Signature.Variadic())
+	if _, sig, ok := findInstance(loader, fn.Object().Pkg().Path(), fn.Name(), want, results != nil); ok {
+		call.Value = fn.Prog.NewFunction(fn.String(), sig, instanceSynthetic)
+		return nil
+	}
+	return &ErrMissingSymbol{
+		Pkg:  fn.Object().Pkg().Path(),
+		Name: fn.Name() + " " + want.String(),
+		Kind: "instance",
+	}
+}
+
+// findInstance returns the instance of the generic function path.name with
+// the parameters of sig, and the results of sig if results is set.
+func findInstance(loader Loader, path string, name string, sig *types.Signature, results bool) (ext reflect.Value, isig *types.Signature, ok bool) {
+	pkg, found := loader.Installed(path)
+	if !found {
+		return
+	}
+	for _, v := range pkg.GenericFuncs[name].Instances {
+		rt, _ := loader.LookupTypes(v.Type())
+		t, ok := rt.(*types.Signature)
+		if !ok || t.Variadic() != sig.Variadic() || !types.Identical(t.Params(), sig.Params()) {
+			continue
+		}
+		if results && !types.Identical(t.Results(), sig.Results()) {
+			continue
+		}
+		return v, t, true
+	}
+	return
+}
+
+// findInstanceFunc returns the instance of fn, the function of an instance
+// replacing a generic function by instantiate.
+func findInstanceFunc(interp *Interp, fn *ssa.Function) (reflect.Value, bool) {
+	path, name, _ := splitPath(fn.Name())
+	ext, _, ok := findInstance(interp.loader, path, name, fn.Signature, true)
+	return ext, ok
+}
@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+package gossa
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// installGenerics declares the GenericTypes and GenericFuncs of pkg in p
+// by type checking their declarations as a file of p, importing the Deps
+// of pkg the declarations refer to. The types of the instances are
+// converted for findInstance.
+func (r *TypesLoader) installGenerics(p *types.Package, pkg *Package) error {
+	if len(pkg.GenericTypes) == 0 && len(pkg.GenericFuncs) == 0 {
+		return nil
+	}
+	var decls []string
+	for _, decl := range pkg.GenericTypes {
+		decls = append(decls, decl)
+	}
+	for _, fn := range pkg.GenericFuncs {
+		decls = append(decls, fn.Decl+" {}")
+		for _, v := range fn.Instances {
+			r.ToType(v.Type())
+		}
+	}
+	sort.Strings(decls)
+	src := strings.Join(decls, "\n")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "package %v\n\n", pkg.Name)
+	for path, name := range pkg.Deps {
+		if strings.Contains(src, name+".") {
+			fmt.Fprintf(&buf, "import %v %q\n", name, path)
+		}
+	}
+	buf.WriteString(src)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, pkg.Path+".generic.go", buf.Bytes(), 0)
+	if err != nil {
+		return err
+	}
+	var first error
+	conf := &types.Config{
+		Importer:         importerFunc(r.importPackage),
+		IgnoreFuncBodies: true,
+		Error: func(err error) {
+			if e, ok := err.(types.Error); (!ok || !e.Soft) && first == nil {
+				first = err
+			}
+		},
+	}
+	types.NewChecker(conf, fset, p, nil).Files([]*ast.File{f})
+	return first
+}
+
+// isGenericFunc reports whether fn is a generic function of a registered
+// package, called by scripts through its instantiations.
+func isGenericFunc(fn *ssa.Function) bool {
+	return fn.Blocks == nil && fn.Signature.TypeParams().Len() != 0
+}
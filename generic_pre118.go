@@ -0,0 +1,22 @@
+//go:build !go1.18
+// +build !go1.18
+
+package gossa
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// installGenerics declares the generics of pkg in p. There are no generic
+// declarations before Go 1.18, they are skipped.
+func (r *TypesLoader) installGenerics(p *types.Package, pkg *Package) error {
+	return nil
+}
+
+// isGenericFunc reports whether fn is a generic function of a registered
+// package. There are no generic functions before Go 1.18.
+func isGenericFunc(fn *ssa.Function) bool {
+	return false
+}
@@ -0,0 +1,317 @@
+package gossa
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/token"
+	"os"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/petermattis/goid"
+	"golang.org/x/tools/go/ssa"
+)
+
+// GoroutinePkgPath is the import path of the script goroutine package.
+// Scripts name and label their goroutines:
+//
+//	import "gossa/goroutine"
+//
+//	goroutine.SetName("worker")
+//	goroutine.SetLabel("job", id)
+//
+// Names and labels are set as pprof goroutine labels, showing up in
+// goroutine dumps and profiles, and name the tracer threads.
+const GoroutinePkgPath = "gossa/goroutine"
+
+// PprofLabelGoroutine is the pprof label of the goroutine name.
+const PprofLabelGoroutine = "gossa.goroutine"
+
+// GoroutineInfo describes an interpreted goroutine.
+type GoroutineInfo struct {
+	ID     int64             // goroutine id
+	Name   string            // goroutine name
+	Labels map[string]string // goroutine labels
+	Func   string            // entry function of go statement
+	Pos    token.Position    // position of go statement, invalid for host goroutines
+}
+
+type goroutine struct {
+	mu     sync.Mutex
+	info   GoroutineInfo
+	labels context.Context // pprof labels of info
+}
+
+// SetGoroutineSpawn sets the func called when an interpreted go statement
+// starts a goroutine, so the host names and labels goroutines at spawn time.
+func (c *Context) SetGoroutineSpawn(fn func(info *GoroutineInfo)) {
+	c.spawnFunc = fn
+}
+
+// Goroutines returns the named or labeled interpreted goroutines, sorted by id.
+func (i *Interp) Goroutines() []GoroutineInfo {
+	var infos []GoroutineInfo
+	i.routines.Range(func(k, v interface{}) bool {
+		g := v.(*goroutine)
+		g.mu.Lock()
+		info := g.info
+		info.Labels = make(map[string]string, len(g.info.Labels))
+		for k, v := range g.info.Labels {
+			info.Labels[k] = v
+		}
+		g.mu.Unlock()
+		infos = append(infos, info)
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// spawn runs fn as the entry of a goroutine started by instr in the call
+// run, nil outside Run and RunFunc.
+//
+// The entry frame of a goroutine has no caller, so the goroutine has its own
+// deferred calls and panic state: recover in its deferred calls recovers its
+// panics only, not the panic of the goroutine running the go statement.
+func (i *Interp) spawn(instr *ssa.Go, run *runState, fn func()) {
+	defer i.exitGoroutine()
+	if run != nil {
+		defer i.enterRun(run)()
+		defer i.enterDeadline(run.dl)()
+	}
+	defer i.crashGoroutine(run)
+	if i.ctx.spawnFunc != nil {
+		info := GoroutineInfo{
+			ID:     goid.Get(),
+			Labels: make(map[string]string),
+			Func:   goFuncName(instr),
+			Pos:    i.fset.Position(instr.Pos()),
+		}
+		i.ctx.spawnFunc(&info)
+		if info.Name != "" || len(info.Labels) != 0 {
+			g := &goroutine{info: info}
+			i.routines.Store(info.ID, g)
+			atomic.AddInt32(&i.labeled, 1)
+			g.update(i)
+		}
+	}
+	fn()
+}
+
+// GoroutinePanic is the error of a Run or RunFunc call ended by the
+// unrecovered panic of a goroutine started by the call, which crashes the
+// program like the Go runtime. Run exits with code 2.
+type GoroutinePanic struct {
+	Goroutine int64        // goroutine id
+	Value     interface{}  // panic value, or runtime error
+	Stack     []StackFrame // interpreted stack of the goroutine, innermost first
+	msg       string       // Value printed like the Go runtime
+}
+
+func (e *GoroutinePanic) Error() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "panic: %v\n\ngoroutine %v [running]:", e.msg, e.Goroutine)
+	for _, f := range e.Stack {
+		fmt.Fprintf(&b, "\n%v(...)\n\t%v", f.Func, f.Pos)
+	}
+	return b.String()
+}
+
+// crashGoroutine ends the call run at an unrecovered panic of the caller
+// goroutine like the Go runtime ends the program, with a GoroutinePanic
+// error and the exit code 2, or with the code of os.Exit called by the
+// goroutine. If the call has returned, or the goroutine was started
+// outside Run and RunFunc, only the goroutine ends and the panic is printed
+// to stderr. In DisableRecover mode the panic crashes the host.
+func (i *Interp) crashGoroutine(run *runState) {
+	if i.mode&DisableRecover != 0 {
+		return
+	}
+	p := recover()
+	if p == nil {
+		return
+	}
+	exitCode, err := 2, error(nil)
+	if code, ok := p.(exitPanic); ok {
+		exitCode = int(code)
+	} else if e, ok := p.(*TimeoutError); ok {
+		// the goroutine exceeds the step budget of the call
+		err = e
+	} else {
+		e := &GoroutinePanic{Goroutine: goid.Get(), Value: p}
+		if info, ok := TargetPanic(p); ok {
+			e.Value, e.Stack = info.Value, info.Stack
+		}
+		e.msg = i.panicString(e.Value)
+		err = e
+	}
+	if run != nil && run.crash(exitCode, err) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// panicString returns the panic value v printed like the Go runtime.
+func (i *Interp) panicString(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	if f, ok := i.formatValue(v); ok {
+		return fmt.Sprint(f)
+	}
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	if fn, ok := i.FuncOf(v); ok {
+		return fmt.Sprintf("(%v) %v", reflect.TypeOf(v), i.funcString(fn))
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		if rv.Type().PkgPath() != "" {
+			return fmt.Sprintf("%v(%v)", rv.Type(), toString(v))
+		}
+		if rv.Kind() == reflect.String {
+			return rv.String()
+		}
+		return toString(v)
+	}
+	return fmt.Sprintf("(%v) %v", reflect.TypeOf(v), toString(v))
+}
+
+func goFuncName(instr *ssa.Go) string {
+	if instr.Call.IsInvoke() {
+		return instr.Call.Method.FullName()
+	}
+	if fn := instr.Call.StaticCallee(); fn != nil {
+		return fn.String()
+	}
+	return instr.Call.Value.Name()
+}
+
+// exitGoroutine removes the info of the exiting caller goroutine.
+func (i *Interp) exitGoroutine() {
+	if atomic.LoadInt32(&i.labeled) == 0 {
+		return
+	}
+	id := goid.Get()
+	if _, ok := i.routines.Load(id); ok {
+		i.routines.Delete(id)
+		atomic.AddInt32(&i.labeled, -1)
+	}
+}
+
+// currentGoroutine returns the goroutine of the caller, creating it if needed.
+func (i *Interp) currentGoroutine() *goroutine {
+	id := goid.Get()
+	if v, ok := i.routines.Load(id); ok {
+		return v.(*goroutine)
+	}
+	g := &goroutine{info: GoroutineInfo{ID: id, Labels: make(map[string]string)}}
+	if v, loaded := i.routines.LoadOrStore(id, g); loaded {
+		return v.(*goroutine)
+	}
+	atomic.AddInt32(&i.labeled, 1)
+	return g
+}
+
+// goroutineLabels returns the pprof labels of the caller goroutine, or nil.
+func (i *Interp) goroutineLabels() context.Context {
+	if atomic.LoadInt32(&i.labeled) == 0 {
+		return nil
+	}
+	v, ok := i.routines.Load(goid.Get())
+	if !ok {
+		return nil
+	}
+	g := v.(*goroutine)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.labels
+}
+
+// update sets the pprof labels and tracer thread name of g,
+// it must be called by the goroutine of g.
+func (g *goroutine) update(interp *Interp) {
+	g.mu.Lock()
+	kv := make([]string, 0, 2*len(g.info.Labels)+2)
+	if g.info.Name != "" {
+		kv = append(kv, PprofLabelGoroutine, g.info.Name)
+	}
+	for k, v := range g.info.Labels {
+		kv = append(kv, k, v)
+	}
+	g.labels = pprof.WithLabels(context.Background(), pprof.Labels(kv...))
+	labels, name, id := g.labels, g.info.Name, g.info.ID
+	g.mu.Unlock()
+	pprof.SetGoroutineLabels(labels)
+	if t := interp.tracer; t != nil && name != "" {
+		t.threadName(id, name)
+	}
+}
+
+// findGoroutineFunc returns the gossa/goroutine function name bound to the interp.
+func findGoroutineFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	switch name {
+	case "SetName":
+		return reflect.ValueOf(func(name string) {
+			g := interp.currentGoroutine()
+			g.mu.Lock()
+			g.info.Name = name
+			g.mu.Unlock()
+			g.update(interp)
+		}), true
+	case "SetLabel":
+		return reflect.ValueOf(func(key, value string) {
+			g := interp.currentGoroutine()
+			g.mu.Lock()
+			g.info.Labels[key] = value
+			g.mu.Unlock()
+			g.update(interp)
+		}), true
+	case "Name":
+		return reflect.ValueOf(func() string {
+			v, ok := interp.routines.Load(goid.Get())
+			if !ok {
+				return ""
+			}
+			g := v.(*goroutine)
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			return g.info.Name
+		}), true
+	}
+	return
+}
+
+func init() {
+	RegisterPackage(&Package{
+		Name:       "goroutine",
+		Path:       GoroutinePkgPath,
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Name":     reflect.ValueOf(func() string { return "" }),
+			"SetLabel": reflect.ValueOf(func(key, value string) {}),
+			"SetName":  reflect.ValueOf(func(name string) {}),
+		},
+		TypedConsts:   map[string]TypedConst{},
+		UntypedConsts: map[string]UntypedConst{},
+	})
+}
@@ -0,0 +1,156 @@
+package gossa
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CostModel is the estimated cost in nanoseconds of an SSA instruction kind,
+// eg. "BinOp", in compiled Go. Kinds not in the model cost 1ns.
+type CostModel map[string]float64
+
+// DefaultCostModel is a cost model of compiled Go on current amd64 hardware.
+// Allocations and runtime calls dominate, most value operations are a
+// fraction of a cycle or disappear in register allocation.
+var DefaultCostModel = CostModel{
+	"Alloc":               15,
+	"BinOp":               0.3,
+	"ChangeInterface":     2,
+	"ChangeType":          0,
+	"Convert":             0.5,
+	"DebugRef":            0,
+	"Defer":               2,
+	"Extract":             0,
+	"Field":               0.3,
+	"FieldAddr":           0.3,
+	"Go":                  300,
+	"If":                  0.5,
+	"Index":               0.5,
+	"IndexAddr":           0.5,
+	"Jump":                0,
+	"Lookup":              15,
+	"MakeChan":            50,
+	"MakeClosure":         20,
+	"MakeInterface":       5,
+	"MakeMap":             50,
+	"MakeSlice":           25,
+	"MapUpdate":           20,
+	"Next":                5,
+	"Panic":               100,
+	"Phi":                 0,
+	"Range":               5,
+	"Return":              0.5,
+	"Select":              100,
+	"Send":                30,
+	"Slice":               1,
+	"SliceToArrayPointer": 1,
+	"Store":               0.5,
+	"TypeAssert":          3,
+	"UnOp":                0.5,
+}
+
+// HotFunc is the hot path record of an interpreted function.
+// Time and native cost exclude the Call and RunDefers instructions,
+// which are accounted to the called functions. Self time excludes the
+// timing overhead of the profile.
+type HotFunc struct {
+	*FuncProfile
+	Count     int64         // instructions executed by the function
+	Self      time.Duration // time spent in the function instructions, less the overhead
+	Native    time.Duration // estimated time of the instructions in compiled Go
+	Slowdown  float64       // Self / Native
+	Share     float64       // part of the total Self time of the profile
+	Candidate bool          // worth moving into a registered host package
+	Reason    string        // why the function is not a candidate, if hot
+}
+
+// Candidate thresholds of HotPath.
+const (
+	hotMinShare    = 0.05
+	hotMinSlowdown = 10
+)
+
+// isCalleeInstr reports whether the time of the kind is spent in callees.
+func isCalleeInstr(kind string) bool {
+	return kind == "Call" || kind == "RunDefers"
+}
+
+// HotPath returns the functions of the profile sorted by self time, with
+// the slowdown factor against compiled Go estimated by model, nil model
+// is DefaultCostModel. Functions spending a large share of the run time
+// with a large slowdown are reported as candidates for registered host
+// packages, unless they start goroutines or create closures, which are
+// hard to move out of the script.
+func (p *Profile) HotPath(model CostModel) []*HotFunc {
+	if model == nil {
+		model = DefaultCostModel
+	}
+	var hots []*HotFunc
+	var total time.Duration
+	for _, f := range p.Funcs {
+		h := &HotFunc{FuncProfile: f}
+		var native float64
+		for _, r := range f.Instrs {
+			if isCalleeInstr(r.Kind) {
+				continue
+			}
+			cost, ok := model[r.Kind]
+			if !ok {
+				cost = 1
+			}
+			h.Count += r.Count
+			h.Self += r.Time
+			native += cost * float64(r.Count)
+			switch r.Kind {
+			case "Go", "MakeClosure":
+				h.Reason = "uses " + r.Kind
+			}
+		}
+		if h.Self -= p.Overhead * time.Duration(h.Count); h.Self < 0 {
+			h.Self = 0
+		}
+		h.Native = time.Duration(native)
+		if native > 0 {
+			h.Slowdown = float64(h.Self) / native
+		}
+		total += h.Self
+		hots = append(hots, h)
+	}
+	for _, h := range hots {
+		if total > 0 {
+			h.Share = float64(h.Self) / float64(total)
+		}
+		if h.Share < hotMinShare || h.Slowdown < hotMinSlowdown {
+			h.Reason = ""
+		} else {
+			h.Candidate = h.Reason == ""
+		}
+	}
+	sort.SliceStable(hots, func(i, j int) bool {
+		return hots[i].Self > hots[j].Self
+	})
+	return hots
+}
+
+// WriteHotPath writes a text report of the profile hot path to w,
+// see HotPath.
+func (p *Profile) WriteHotPath(w io.Writer, model CostModel) (n int64, err error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%12s %14s %14s %9s %7s  %s\n", "instrs", "self", "native", "slowdown", "share", "function")
+	for _, h := range p.HotPath(model) {
+		var note string
+		switch {
+		case h.Candidate:
+			note = "  <- move to host package"
+		case h.Reason != "":
+			note = "  (" + h.Reason + ")"
+		}
+		fmt.Fprintf(&buf, "%12d %14v %14v %8.1fx %6.1f%%  %v %v%s\n",
+			h.Count, h.Self, h.Native, h.Slowdown, h.Share*100, h.Func, h.Pos, note)
+	}
+	c, err := io.WriteString(w, buf.String())
+	return int64(c), err
+}
@@ -0,0 +1,120 @@
+// Package httpadapter serves script functions as net/http handlers:
+//
+//	interp, err := ctx.NewInterp(pkg)
+//	...
+//	h, err := httpadapter.Handler(interp, "Serve")
+//	...
+//	http.Handle("/", h)
+//
+// A panic of the interpreted handler is a 500 Internal Server Error
+// response with the panic value and the interpreted stack trace.
+//
+// Importing the package registers net/http for interpreted scripts.
+package httpadapter
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/net/http"
+)
+
+// Handler returns the function name of the main package of interp as an
+// http.Handler. The function is a handler:
+//
+//	func Serve(w http.ResponseWriter, r *http.Request)
+//
+// or returns the handler, called once by Handler:
+//
+//	func NewHandler() http.Handler
+//	func NewHandler() http.HandlerFunc
+func Handler(interp *gossa.Interp, name string) (http.Handler, error) {
+	fn, ok := interp.GetFunc(name)
+	if !ok {
+		return nil, fmt.Errorf("httpadapter: no function %v", name)
+	}
+	switch fn := fn.(type) {
+	case func(http.ResponseWriter, *http.Request):
+		return handler{http.HandlerFunc(fn)}, nil
+	case func() http.Handler, func() http.HandlerFunc:
+		r, err := interp.RunFunc(name)
+		if err != nil {
+			return nil, fmt.Errorf("httpadapter: %v: %w", name, err)
+		}
+		h, _ := r.(http.Handler)
+		if h == nil {
+			return nil, fmt.Errorf("httpadapter: %v returns nil handler", name)
+		}
+		return handler{h}, nil
+	}
+	return nil, fmt.Errorf("httpadapter: %v is %T, not a handler", name, fn)
+}
+
+// handler serves the requests by the interpreted handler h, responding to
+// its panics.
+type handler struct {
+	h http.Handler
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rw := &responseWriter{ResponseWriter: w}
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		}
+		if p == http.ErrAbortHandler || rw.wroteHeader {
+			// the response is sent, abort it like net/http
+			panic(http.ErrAbortHandler)
+		}
+		http.Error(w, panicMessage(p), http.StatusInternalServerError)
+	}()
+	h.h.ServeHTTP(rw, r)
+}
+
+// panicMessage returns the panic p of the handler and its interpreted
+// stack trace, formatted like the Go runtime.
+func panicMessage(p interface{}) string {
+	var b bytes.Buffer
+	info, ok := gossa.TargetPanic(p)
+	if !ok {
+		fmt.Fprintf(&b, "panic: %v\n", p)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "panic: %v\n\n", info.Value)
+	for _, f := range info.Stack {
+		fmt.Fprintf(&b, "%v(...)\n\t%v\n", f.Func, f.Pos)
+	}
+	return b.String()
+}
+
+// responseWriter records whether the handler has sent the header.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseWriter) Flush() {
+	w.wroteHeader = true
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the ResponseWriter of the server, see
+// http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
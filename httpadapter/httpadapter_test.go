@@ -0,0 +1,111 @@
+package httpadapter
+
+import (
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/fmt"
+)
+
+func TestHandler(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func Serve(w http.ResponseWriter, r *http.Request) {
+	switch do := r.FormValue("do"); do {
+	case "panic":
+		fail(do)
+	case "late":
+		fmt.Fprint(w, "partial")
+		fail(do)
+	}
+	fmt.Fprintf(w, "hello %v", r.FormValue("name"))
+}
+
+func fail(do string) {
+	panic("cannot " + do)
+}
+
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "pong")
+	})
+	mux.HandleFunc("/nil", func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]int
+		m["a"] = 1
+	})
+	return mux
+}
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve, err := Handler(interp, "Serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux, err := Handler(interp, "NewHandler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(h http.Handler, target string) (int, string) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Code, string(body)
+	}
+
+	if code, body := get(serve, "/?name=gossa"); code != 200 || body != "hello gossa" {
+		t.Fatalf("Serve = %v %q", code, body)
+	}
+	code, body := get(serve, "/?do=panic")
+	want := "panic: cannot panic\n\nmain.fail(...)\n\tmain.go:20:7\nmain.Serve(...)\n\tmain.go:11:7\n\n"
+	if code != 500 || body != want {
+		t.Fatalf("Serve panic = %v %q, want %q", code, body, want)
+	}
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Fatalf("late panic = %v, want ErrAbortHandler", p)
+			}
+		}()
+		get(serve, "/?do=late")
+	}()
+
+	if code, body := get(mux, "/ping"); code != 200 || body != "pong" {
+		t.Fatalf("NewHandler = %v %q", code, body)
+	}
+	if code, body := get(mux, "/nil"); code != 500 || !strings.HasPrefix(body, "panic: assignment to entry in nil map\n\nmain.NewHandler$2(...)\n\tmain.go:30:4\n") {
+		t.Fatalf("NewHandler panic = %v %q", code, body)
+	}
+
+	if _, err := Handler(interp, "Add"); err == nil || err.Error() != "httpadapter: Add is func(int, int) int, not a handler" {
+		t.Fatalf("bad handler error %v", err)
+	}
+	if _, err := Handler(interp, "Missing"); err == nil || err.Error() != "httpadapter: no function Missing" {
+		t.Fatalf("bad missing error %v", err)
+	}
+}
@@ -22,14 +22,18 @@ func (i *Importer) Import(path string) (*types.Package, error) {
 	if pkg, ok := i.pkgs[path]; ok {
 		return pkg, nil
 	}
-	if pkg, err := i.loader.Import(path); err == nil {
+	pkg, err := i.loader.Import(path)
+	if err == nil {
 		i.pkgs[path] = pkg
 		return pkg, nil
 	}
+	if _, ok := err.(*ImportDeniedError); ok {
+		return nil, err
+	}
 	if i.impl == nil {
 		return nil, ErrNotFoundImporter
 	}
-	pkg, err := i.impl.Import(path)
+	pkg, err = i.impl.Import(path)
 	if err == nil {
 		i.pkgs[path] = pkg
 	}
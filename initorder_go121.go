@@ -0,0 +1,66 @@
+//go:build go1.21
+// +build go1.21
+
+package gossa
+
+import (
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// initOrder returns the interpreted packages pkgs imported by the main
+// package, and main, in the package initialization order of Go 1.21:
+// sorted by import path, the first package whose imports are initialized
+// is initialized next. The SSA package initializers initialize imports
+// depth first in source order, calls of initialized packages return.
+func initOrder(main *ssa.Package, pkgs []*ssa.Package) []*ssa.Package {
+	interpreted := make(map[*types.Package]*ssa.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		interpreted[pkg.Pkg] = pkg
+	}
+	var sorted []*ssa.Package
+	seen := make(map[*types.Package]bool)
+	var visit func(pkgs []*types.Package)
+	visit = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if pkg, ok := interpreted[p]; ok && !seen[p] {
+				seen[p] = true
+				sorted = append(sorted, pkg)
+				visit(p.Imports())
+			}
+		}
+	}
+	visit(main.Pkg.Imports())
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Pkg.Path() < sorted[j].Pkg.Path()
+	})
+
+	order := make([]*ssa.Package, 0, len(sorted)+1)
+	done := make(map[*types.Package]bool, len(sorted))
+	ready := func(pkg *ssa.Package) bool {
+		for _, p := range pkg.Pkg.Imports() {
+			if _, ok := interpreted[p]; ok && !done[p] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(order) < len(sorted) {
+		next := -1
+		for n, pkg := range sorted {
+			if !done[pkg.Pkg] && ready(pkg) {
+				next = n
+				break
+			}
+		}
+		if next < 0 {
+			// import cycle, rejected by the type checker
+			break
+		}
+		done[sorted[next].Pkg] = true
+		order = append(order, sorted[next])
+	}
+	return append(order, main)
+}
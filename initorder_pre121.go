@@ -0,0 +1,14 @@
+//go:build !go1.21
+// +build !go1.21
+
+package gossa
+
+import (
+	"golang.org/x/tools/go/ssa"
+)
+
+// initOrder returns the main package, whose initializer initializes the
+// imports depth first in source order like the Go toolchain.
+func initOrder(main *ssa.Package, pkgs []*ssa.Package) []*ssa.Package {
+	return []*ssa.Package{main}
+}
@@ -0,0 +1,121 @@
+package gossa
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// inlineMaxInstrs is the maximum number of instructions of an inlined
+// function, not counting its return.
+const inlineMaxInstrs = 8
+
+// inlineSite is a call compiled by makeInlineCall. The caller frame records
+// the running site, so that panic stacks keep the inlined callee.
+type inlineSite struct {
+	fn     *ssa.Function
+	pos    []token.Pos // position of the body instructions
+	caller *inlineSite // enclosing site of a call in an inlined body
+	pc     int         // index of the call in the body of caller
+}
+
+// canInline reports whether the static call of fn in pfn is compiled
+// inline: fn is a small single block function without defers or recover,
+// typically a getter or helper. Inlined calls allocate no frame, so they
+// are not inlined when profiling or tracing interpreted functions, or
+// adding the positions of runtime errors.
+func canInline(interp *Interp, pfn *Function, fn *ssa.Function) bool {
+	if interp.mode&(DisableInline|DisableClosureCompiler|EnableTracing|EnableProfiling|EnablePprofLabels|EnableRuntimePos) != 0 {
+		return false
+	}
+	if fn == pfn.Fn || pfn.inlining[fn] {
+		// recursive call
+		return false
+	}
+	if len(fn.Blocks) != 1 || fn.Recover != nil || len(fn.FreeVars) != 0 {
+		return false
+	}
+	if _, ok := interp.ctx.override[fn.String()]; ok {
+		return false
+	}
+	instrs := fn.Blocks[0].Instrs
+	if _, ok := instrs[len(instrs)-1].(*ssa.Return); !ok {
+		return false
+	}
+	var n int
+	for _, instr := range instrs[:len(instrs)-1] {
+		switch instr := instr.(type) {
+		case *ssa.DebugRef:
+			continue
+		case *ssa.Defer, *ssa.RunDefers:
+			return false
+		case *ssa.Call:
+			// recover in a deferred caller must not see its panic
+			if b, ok := instr.Call.Value.(*ssa.Builtin); ok && b.Name() == "recover" {
+				return false
+			}
+		}
+		n++
+	}
+	return n <= inlineMaxInstrs
+}
+
+// makeInlineCall returns the closure of the static call of fn with the
+// arguments ia and result ir in pfn. The instructions of fn are compiled
+// with the registers of pfn and run in the caller frame, which records the
+// running instruction for frameStack.
+func makeInlineCall(pfn *Function, interp *Interp, fn *ssa.Function, ir int, ia []int) func(fr *frame) {
+	ip := make([]int, len(fn.Params))
+	for i, p := range fn.Params {
+		ip[i] = pfn.regIndex(p)
+	}
+	if pfn.inlining == nil {
+		pfn.inlining = make(map[*ssa.Function]bool)
+	}
+	pfn.inlining[fn] = true
+	site := &inlineSite{fn: fn, caller: pfn.site}
+	if site.caller != nil {
+		site.pc = len(site.caller.pos)
+	}
+	pfn.site = site
+	instrs := fn.Blocks[0].Instrs
+	var body []func(fr *frame)
+	lastPos := fn.Pos()
+	for _, instr := range instrs[:len(instrs)-1] {
+		if pos := instr.Pos(); pos.IsValid() {
+			lastPos = pos
+		}
+		if ifn := makeInstr(interp, pfn, instr); ifn != nil {
+			body = append(body, ifn)
+			site.pos = append(site.pos, lastPos)
+		}
+	}
+	pfn.site = site.caller
+	delete(pfn.inlining, fn)
+	ret := instrs[len(instrs)-1].(*ssa.Return)
+	ires := make([]int, len(ret.Results))
+	for i, v := range ret.Results {
+		ires[i] = pfn.regIndex(v)
+	}
+	return func(fr *frame) {
+		for i := range ip {
+			fr.setReg(ip[i], fr.reg(ia[i]))
+		}
+		for pc, fn := range body {
+			fr.site, fr.sitePC = site, pc
+			fn(fr)
+		}
+		fr.site, fr.sitePC = site.caller, site.pc
+		switch n := len(ires); n {
+		case 0:
+		case 1:
+			fr.setReg(ir, fr.reg(ires[0]))
+		default:
+			res := make([]value, n, n)
+			for i := 0; i < n; i++ {
+				res[i] = fr.reg(ires[i])
+			}
+			fr.setReg(ir, tuple(res))
+		}
+	}
+}
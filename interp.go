@@ -20,8 +20,8 @@
 //
 // * The reflect package is only partially implemented.
 //
-// * The "testing" package is no longer supported because it
-// depends on low-level details that change too often.
+// * The "testing" package is supported through a synthesized testmain
+// package, see RunTest and RunTests.
 //
 // * "sync/atomic" operations are not atomic due to the "boxed" value
 // representation: it is not possible to read, modify and write an
@@ -49,10 +49,15 @@ import (
 	"go/constant"
 	"go/token"
 	"go/types"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
 	"reflect"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/petermattis/goid"
@@ -68,6 +73,10 @@ const intSize = 32 << (^uint(0) >> 63)
 func init() {
 	if intSize == 32 {
 		maxMemLen = 1<<31 - 1
+	} else if runtime.GOARCH == "wasm" {
+		// the 64-bit wasm heap has 32-bit addresses
+		v := int64(1)<<32 - 1
+		maxMemLen = int(v)
 	} else {
 		v := int64(1) << 59
 		maxMemLen = int(v)
@@ -80,15 +89,30 @@ func (e plainError) Error() string {
 	return string(e)
 }
 
-type runtimeError string
+// runtimeError is a run-time panic of the interpreted program. Pos is the
+// position of the failed operation in EnableRuntimePos mode.
+type runtimeError struct {
+	msg string
+	Pos token.Position
+}
 
 func (e runtimeError) RuntimeError() {}
 
 func (e runtimeError) Error() string {
-	return "runtime error: " + string(e)
+	if e.Pos.IsValid() {
+		return "runtime error: " + e.msg + " at " + e.Pos.String()
+	}
+	return "runtime error: " + e.msg
 }
 
 // State shared between all interpreted goroutines.
+//
+// An Interp is safe for concurrent use once created: RunFunc, Run, the
+// funcs of GetFunc and Bind and the Runner may be called from multiple host
+// goroutines. Each call has its own frames, panics and deferred calls. The
+// interpreted program must synchronize its shared state as compiled Go
+// does. Reload, RestoreGlobals and the Set methods of the Interp must not
+// be called while functions run.
 type Interp struct {
 	ctx          *Context
 	fset         *token.FileSet
@@ -98,14 +122,44 @@ type Interp struct {
 	mode         Mode                // interpreter options
 	goroutines   int32               // atomically updated
 	deferCount   int32
-	exited       bool
-	preloadTypes map[types.Type]reflect.Type
+	runs         sync.Map            // host calls: goid => *runState
+	convertTypes sync.Map // converted types: types.Type => reflect.Type
 	deferMap     sync.Map
 	loader       Loader
 	record       *TypesRecord
 	typesMutex   sync.RWMutex
-	funcs        map[*ssa.Function]*Function
+	funcs        map[*ssa.Function]*Function // compiled functions, written by loadFunction before the calls
+	funcsMutex   sync.RWMutex
 	msets        map[reflect.Type](map[string]*ssa.Function) // user defined type method sets
+	methods      sync.Map                                    // resolved dynamic method calls: methodKey => *methodValue
+	bounds       sync.Map                                    // extern bound method values: boundKey => method index
+	profile      *profiler                                   // EnableProfiling statistics
+	logger       Logger                                      // gossa/log backend
+	tracer       *tracer                                     // Chrome trace output, see SetTracer
+	panicFunc    func(*PanicInfo)                            // panic handler, see SetPanicHandler
+	routines     sync.Map                                    // named goroutines: goid => *goroutine
+	labeled      int32                                       // number of named goroutines, atomically updated
+	store        Store                                       // gossa/store backend
+	memStore     Store                                       // default gossa/store backend
+	memOnce      sync.Once
+	externHook   func(fn string, args []reflect.Value) error // see SetExternCallHook
+	deadlines    sync.Map                                    // RunFuncTimeout calls: goid => *deadline
+	depths       sync.Map                                    // call depths of the callers of host functions: goid => int
+	timed        int32                                       // number of goroutines with a deadline, atomically updated
+	events       map[string][]reflect.Value                  // gossa/event handlers: name => funcs
+	proc         procEnv                                     // os.Args, environment and working directory, see SetArgs
+	clock        Clock                                       // time source, see SetClock
+	timers       sync.Map                                    // timers of the clock: address of *time.Timer or *time.Ticker => *clockTimer
+	rand         *rand.Rand                                  // source of math/rand, see SetRandSeed
+	randSrc      *lockedSource                               // source of rand, guards rand.Read and rand.Seed
+	randOnce     sync.Once
+	dialer       Dialer                                      // dialer of net and net/http, see SetDialer
+	transport    http.RoundTripper                           // round tripper of net/http, see SetHTTPTransport
+	transports   sync.Map                                    // host transports of the program => clones dialing by dialer
+	funcWrappers sync.Map                                    // func values without free variables: funcKey => reflect.Value
+	traceOut     io.Writer                                   // output of EnableTracing mode, see WithTracing
+	debugFunc    func(*DebugInfo)                            // debugger of DebugRef instructions, see WithDebugger
+	eventsMutex  sync.Mutex
 }
 
 func (i *Interp) installed(path string) (pkg *Package, ok bool) {
@@ -114,6 +168,8 @@ func (i *Interp) installed(path string) (pkg *Package, ok bool) {
 }
 
 func (i *Interp) loadFunction(fn *ssa.Function) *Function {
+	i.funcsMutex.Lock()
+	defer i.funcsMutex.Unlock()
 	if pfn, ok := i.funcs[fn]; ok {
 		return pfn
 	}
@@ -128,6 +184,15 @@ func (i *Interp) loadFunction(fn *ssa.Function) *Function {
 	return pfn
 }
 
+// lookupFunc returns the compiled function of fn. Entries are added by
+// loadFunction at run time too, so the read takes funcsMutex.
+func (i *Interp) lookupFunc(fn *ssa.Function) *Function {
+	i.funcsMutex.RLock()
+	pfn := i.funcs[fn]
+	i.funcsMutex.RUnlock()
+	return pfn
+}
+
 func (i *Interp) findType(rt reflect.Type, local bool) (types.Type, bool) {
 	i.typesMutex.Lock()
 	defer i.typesMutex.Unlock()
@@ -157,17 +222,45 @@ func (i *Interp) FindMethod(mtyp reflect.Type, fn *types.Func) func([]reflect.Va
 	}
 	name := fn.FullName()
 	if v, ok := externValues[name]; ok && v.Kind() == reflect.Func {
+		if v.Type().IsVariadic() {
+			// the variadic arguments of a method wrapper are a slice
+			return func(args []reflect.Value) []reflect.Value {
+				return v.CallSlice(args)
+			}
+		}
 		return func(args []reflect.Value) []reflect.Value {
 			return v.Call(args)
 		}
 	}
-	panic(fmt.Sprintf("Not found method %v", fn))
+	panic(missingMethod(fn))
+}
+
+// funcKey is the key of Interp.funcWrappers, a function pfn as a value
+// of func type typ.
+type funcKey struct {
+	typ reflect.Type
+	pfn *Function
 }
 
+// makeFunc returns the func value of type typ calling pfn with the free
+// variables env. The func values of functions without free variables are
+// cached, so a function used as a value keeps its identity, eg. for %p or
+// FuncOf, and costs no allocations.
 func (i *Interp) makeFunc(typ reflect.Type, pfn *Function, env []value) reflect.Value {
-	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
-		return i.callFunctionByReflect(i.tryDeferFrame(), typ, pfn, args, env)
-	})
+	if pfn == nil || len(env) != 0 {
+		return i.newFunc(typ, pfn, env)
+	}
+	key := funcKey{typ, pfn}
+	if v, ok := i.funcWrappers.Load(key); ok {
+		return v.(reflect.Value)
+	}
+	v, _ := i.funcWrappers.LoadOrStore(key, i.newFunc(typ, pfn, nil))
+	return v.(reflect.Value)
+}
+
+func (i *Interp) newFunc(typ reflect.Type, pfn *Function, env []value) reflect.Value {
+	f := &funcData{interp: i, typ: typ, pfn: pfn, env: env}
+	return reflect.MakeFunc(typ, f.call)
 }
 
 type deferred struct {
@@ -190,6 +283,14 @@ type frame struct {
 	deferid   int64
 	stack     []value
 	results   []int
+	started   time.Time            // function entry time for EnableProfiling
+	cases     []reflect.SelectCase // scratch cases of select instructions
+	spare     []value              // stack of the last returned callee, see newStack
+	depth     int                  // interpreted call depth of the goroutine
+	panicked  bool                 // panic reported to the panic handler
+	deadline  *deadline            // deadline of the RunFuncTimeout call
+	site      *inlineSite          // inlined call running in the frame, see makeInlineCall
+	sitePC    int                  // index of the running body instruction of site
 }
 
 func (fr *frame) setReg(index int, v value) {
@@ -204,6 +305,20 @@ func (fr *frame) copyReg(dst int, src int) {
 	fr.stack[dst] = fr.stack[src]
 }
 
+// newStack returns the stack of a call of pfn by fr. The stack of the last
+// callee returned to fr is reused if large enough, so calls in loops and
+// recursive calls allocate no stacks. A frame calls one function at a time,
+// the spare stack is not shared by goroutines or recursive calls.
+func (fr *frame) newStack(pfn *Function) []value {
+	if s := fr.spare; cap(s) >= len(pfn.stack) {
+		fr.spare = nil
+		s = s[:len(pfn.stack)]
+		copy(s, pfn.stack)
+		return s
+	}
+	return append([]value{}, pfn.stack...)
+}
+
 type panicking struct {
 	value interface{}
 }
@@ -309,6 +424,19 @@ func (i *DebugInfo) AsFunc() (*types.Func, bool) {
 	return v, ok
 }
 
+// SliceGrowInfo describes a slice reallocated by the append builtin.
+type SliceGrowInfo struct {
+	OldCap int          // capacity before append
+	NewCap int          // capacity after append
+	Elem   reflect.Type // slice element type
+	Pos    token.Pos    // append call position
+	fset   *token.FileSet
+}
+
+func (i *SliceGrowInfo) Position() token.Position {
+	return i.fset.Position(i.Pos)
+}
+
 // prepareCall determines the function value and argument values for a
 // function call in a Call, Go or Defer instruction, performing
 // interface method lookup if needed.
@@ -326,10 +454,10 @@ func (i *Interp) prepareCall(fr *frame, call *ssa.CallCommon, iv int, ia []int,
 					if f.Pkg != nil && f.Name() == "init" {
 						fv = func() {}
 					} else {
-						panic(fmt.Errorf("no code for function: %v", f))
+						panic(missingFunc(f))
 					}
 				} else {
-					fv = ext
+					fv = &externFunc{f.String(), ext}
 				}
 			} else {
 				fv = f
@@ -345,24 +473,10 @@ func (i *Interp) prepareCall(fr *frame, call *ssa.CallCommon, iv int, ia []int,
 		}
 	} else {
 		v := fr.reg(iv)
-		rtype := reflect.TypeOf(v)
-		mname := call.Method.Name()
-		if mset, ok := i.msets[rtype]; ok {
-			if f, ok := mset[mname]; ok {
-				fv = f
-			} else {
-				ext, ok := findUserMethod(rtype, mname)
-				if !ok {
-					panic(fmt.Errorf("no code for method: %v.%v", rtype, mname))
-				}
-				fv = ext
-			}
+		if m := i.resolveMethod(reflect.TypeOf(v), call.Method); m.fn != nil {
+			fv = m.fn
 		} else {
-			ext, ok := findExternMethod(rtype, mname)
-			if !ok {
-				panic(fmt.Errorf("no code for method: %v.%v", rtype, mname))
-			}
-			fv = ext
+			fv = &externFunc{m.name, m.ext}
 		}
 		args = append(args, v)
 	}
@@ -385,10 +499,12 @@ func (i *Interp) call(caller *frame, fn value, args []value, ssaArgs []ssa.Value
 		return i.callFunction(caller, fn.Fn, args, fn.Env)
 	case *ssa.Builtin:
 		return i.callBuiltin(caller, fn, args, ssaArgs)
+	case *externFunc:
+		return i.callExternal(caller, fn.name, fn.fn, args, nil)
 	case reflect.Value:
-		return i.callExternal(caller, fn, args, nil)
+		return i.callExternal(caller, "", fn, args, nil)
 	default:
-		return i.callExternal(caller, reflect.ValueOf(fn), args, nil)
+		return i.callExternal(caller, "", reflect.ValueOf(fn), args, nil)
 	}
 	panic(fmt.Sprintf("cannot call %T %v", fn, reflect.ValueOf(fn).Kind()))
 }
@@ -405,10 +521,12 @@ func (i *Interp) callDiscardsResult(caller *frame, fn value, args []value, ssaAr
 		i.callFunctionDiscardsResult(caller, fn.Fn, args, fn.Env)
 	case *ssa.Builtin:
 		i.callBuiltinDiscardsResult(caller, fn, args, ssaArgs)
+	case *externFunc:
+		i.callExternalDiscardsResult(caller, fn.name, fn.fn, args, nil)
 	case reflect.Value:
-		i.callExternalDiscardsResult(caller, fn, args, nil)
+		i.callExternalDiscardsResult(caller, "", fn, args, nil)
 	default:
-		i.callExternalDiscardsResult(caller, reflect.ValueOf(fn), args, nil)
+		i.callExternalDiscardsResult(caller, "", reflect.ValueOf(fn), args, nil)
 	}
 }
 
@@ -419,15 +537,62 @@ func loc(fset *token.FileSet, pos token.Pos) string {
 	return " at " + fset.Position(pos).String()
 }
 
+// enterFrame sets the call depth of fr, a call deeper than the limit of
+// Context.SetMaxCallDepth panics. The depth of a call of the host into the
+// program continues the depth of the interpreted caller of the host.
+func (i *Interp) enterFrame(fr *frame) {
+	if fr.caller == nil {
+		if i.ctx.maxDepth > 0 {
+			if depth, ok := i.depths.Load(goid.Get()); ok {
+				fr.depth = depth.(int) + 1
+				if fr.depth > i.ctx.maxDepth {
+					panic(runtimeError{msg: "stack overflow"})
+				}
+			}
+		}
+		if fr.deadline = i.goroutineDeadline(); fr.deadline != nil {
+			fr.deadline.enter(fr)
+		}
+		return
+	}
+	fr.depth = fr.caller.depth + 1
+	if max := i.ctx.maxDepth; max > 0 && fr.depth > max {
+		panic(runtimeError{msg: "stack overflow"})
+	}
+	if fr.deadline = fr.caller.deadline; fr.deadline != nil {
+		fr.deadline.enter(fr)
+	}
+}
+
+// enterExtern records the call depth of caller for the calls of the host
+// function it calls into the program, see enterFrame. It returns the func
+// restoring the depth recorded before.
+func (i *Interp) enterExtern(caller *frame) func() {
+	id := goid.Get()
+	prev, ok := i.depths.Load(id)
+	i.depths.Store(id, caller.depth)
+	return func() {
+		if ok {
+			i.depths.Store(id, prev)
+		} else {
+			i.depths.Delete(id)
+		}
+	}
+}
+
 func (i *Interp) callFunction(caller *frame, fn *ssa.Function, args []value, env []value) (result value) {
+	if i.mode&EnablePprofLabels != 0 {
+		defer restoreLabels(runtime_getProfLabel())
+	}
 	fr := &frame{
 		interp: i,
 		caller: caller, // for panic/recover
-		pfn:    i.funcs[fn],
+		pfn:    i.lookupFunc(fn),
 	}
 	if caller != nil {
 		fr.deferid = caller.deferid
 	}
+	i.enterFrame(fr)
 	fr.stack = append([]value{}, fr.pfn.stack...)
 	fr.block = fr.pfn.Main
 	var ip = 0
@@ -450,11 +615,17 @@ func (i *Interp) callFunction(caller *frame, fn *ssa.Function, args []value, env
 		}
 		result = tuple(res)
 	}
+	if fr.deadline != nil {
+		fr.deadline.leave(fr)
+	}
 	fr.stack = nil
 	return
 }
 
 func (i *Interp) callFunctionByReflect(caller *frame, typ reflect.Type, pfn *Function, args []reflect.Value, env []value) (results []reflect.Value) {
+	if i.mode&EnablePprofLabels != 0 {
+		defer restoreLabels(runtime_getProfLabel())
+	}
 	fr := &frame{
 		interp: i,
 		caller: caller, // for panic/recover
@@ -463,6 +634,7 @@ func (i *Interp) callFunctionByReflect(caller *frame, typ reflect.Type, pfn *Fun
 	if caller != nil {
 		fr.deferid = caller.deferid
 	}
+	i.enterFrame(fr)
 	fr.stack = append([]value{}, fr.pfn.stack...)
 	fr.block = fr.pfn.Main
 	var ip = 0
@@ -482,11 +654,19 @@ func (i *Interp) callFunctionByReflect(caller *frame, typ reflect.Type, pfn *Fun
 			v := fr.stack[fr.results[i]]
 			if v == nil {
 				results[i] = reflect.New(typ.Out(i)).Elem()
+			} else if out := typ.Out(i); out.Kind() == reflect.Interface {
+				// host callers expect the declared interface type
+				r := reflect.New(out).Elem()
+				r.Set(reflect.ValueOf(v))
+				results[i] = r
 			} else {
 				results[i] = reflect.ValueOf(v)
 			}
 		}
 	}
+	if fr.deadline != nil {
+		fr.deadline.leave(fr)
+	}
 	fr.stack = nil
 	return
 }
@@ -495,11 +675,12 @@ func (i *Interp) callFunctionDiscardsResult(caller *frame, fn *ssa.Function, arg
 	fr := &frame{
 		interp: i,
 		caller: caller, // for panic/recover
-		pfn:    i.funcs[fn],
+		pfn:    i.lookupFunc(fn),
 	}
 	if caller != nil {
 		fr.deferid = caller.deferid
 	}
+	i.enterFrame(fr)
 	fr.stack = append([]value{}, fr.pfn.stack...)
 	fr.block = fr.pfn.Main
 	var ip = 0
@@ -512,6 +693,9 @@ func (i *Interp) callFunctionDiscardsResult(caller *frame, fn *ssa.Function, arg
 		ip++
 	}
 	fr.run()
+	if fr.deadline != nil {
+		fr.deadline.leave(fr)
+	}
 	fr.stack = nil
 }
 
@@ -522,7 +706,8 @@ func (i *Interp) callFunctionByStack(caller *frame, pfn *Function, ir int, ia []
 		pfn:     pfn,
 		deferid: caller.deferid,
 	}
-	fr.stack = append([]value{}, pfn.stack...)
+	i.enterFrame(fr)
+	fr.stack = caller.newStack(pfn)
 	fr.block = pfn.Main
 	for i := 0; i < len(ia); i++ {
 		fr.stack[i] = caller.reg(ia[i])
@@ -538,6 +723,10 @@ func (i *Interp) callFunctionByStack(caller *frame, pfn *Function, ir int, ia []
 		}
 		caller.setReg(ir, tuple(res))
 	}
+	if fr.deadline != nil {
+		fr.deadline.leave(fr)
+	}
+	caller.spare = fr.stack
 	fr.stack = nil
 }
 
@@ -548,15 +737,20 @@ func (i *Interp) callFunctionByStackNoRecover(caller *frame, pfn *Function, ir i
 		pfn:     pfn,
 		deferid: caller.deferid,
 	}
-	fr.stack = append([]value{}, pfn.stack...)
+	i.enterFrame(fr)
+	fr.stack = caller.newStack(pfn)
 	fr.block = pfn.Main
 	for i := 0; i < len(ia); i++ {
 		fr.stack[i] = caller.reg(ia[i])
 	}
-	for fr.pc != -1 {
-		fn := fr.pfn.Instrs[fr.pc]
-		fr.pc++
-		fn(fr)
+	if i.tracer != nil || i.panicFunc != nil || i.mode&EnableRuntimePos != 0 {
+		fr.run()
+	} else {
+		for fr.pc != -1 {
+			fn := fr.pfn.Instrs[fr.pc]
+			fr.pc++
+			fn(fr)
+		}
 	}
 	n := len(fr.results)
 	if n == 1 {
@@ -568,34 +762,48 @@ func (i *Interp) callFunctionByStackNoRecover(caller *frame, pfn *Function, ir i
 		}
 		caller.setReg(ir, tuple(res))
 	}
+	if fr.deadline != nil {
+		fr.deadline.leave(fr)
+	}
+	caller.spare = fr.stack
 	fr.stack = nil
 }
 
-func (i *Interp) callExternal(caller *frame, fn reflect.Value, args []value, env []value) value {
+// externArgs returns the n arguments of a call of the external function of
+// type typ, arg(i) is the i-th argument. The arguments of a variadic call
+// end with the slice of the variadic arguments, called by CallSlice. A nil
+// argument is the zero value of its parameter type, eg. a nil variadic
+// slice or a nil pointer, and an argument of a type not assignable to its
+// parameter type is converted to it.
+func externArgs(typ reflect.Type, n int, arg func(i int) value) []reflect.Value {
+	ins := make([]reflect.Value, n, n)
+	for i := 0; i < n; i++ {
+		ptyp := typ.In(i)
+		v := arg(i)
+		if v == nil {
+			ins[i] = reflect.Zero(ptyp)
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if rtyp := rv.Type(); rtyp != ptyp && !rtyp.AssignableTo(ptyp) && rtyp.ConvertibleTo(ptyp) {
+			rv = rv.Convert(ptyp)
+		}
+		ins[i] = rv
+	}
+	return ins
+}
+
+func (i *Interp) callExternal(caller *frame, name string, fn reflect.Value, args []value, env []value) value {
+	if caller != nil && i.ctx.maxDepth > 0 {
+		defer i.enterExtern(caller)()
+	}
 	if caller != nil && caller.deferid != 0 {
 		i.deferMap.Store(caller.deferid, caller)
 	}
-	var ins []reflect.Value
-	typ := fn.Type()
+	ins := externArgs(fn.Type(), len(args), func(n int) value { return args[n] })
 	isVariadic := fn.Type().IsVariadic()
-	if isVariadic {
-		for i := 0; i < len(args)-1; i++ {
-			if args[i] == nil {
-				ins = append(ins, reflect.New(typ.In(i)).Elem())
-			} else {
-				ins = append(ins, reflect.ValueOf(args[i]))
-			}
-		}
-		ins = append(ins, reflect.ValueOf(args[len(args)-1]))
-	} else {
-		ins = make([]reflect.Value, len(args), len(args))
-		for i := 0; i < len(args); i++ {
-			if args[i] == nil {
-				ins[i] = reflect.New(typ.In(i)).Elem()
-			} else {
-				ins[i] = reflect.ValueOf(args[i])
-			}
-		}
+	if i.externHook != nil {
+		i.auditExtern(caller, name, fn, ins)
 	}
 	var results []reflect.Value
 	if isVariadic {
@@ -616,65 +824,35 @@ func (i *Interp) callExternal(caller *frame, fn reflect.Value, args []value, env
 		return tuple(res)
 	}
 }
-func (i *Interp) callExternalDiscardsResult(caller *frame, fn reflect.Value, args []value, env []value) {
+func (i *Interp) callExternalDiscardsResult(caller *frame, name string, fn reflect.Value, args []value, env []value) {
+	if caller != nil && i.ctx.maxDepth > 0 {
+		defer i.enterExtern(caller)()
+	}
 	if caller != nil && caller.deferid != 0 {
 		i.deferMap.Store(caller.deferid, caller)
 	}
-	var ins []reflect.Value
-	typ := fn.Type()
-	isVariadic := fn.Type().IsVariadic()
-	if isVariadic {
-		for i := 0; i < len(args)-1; i++ {
-			if args[i] == nil {
-				ins = append(ins, reflect.New(typ.In(i)).Elem())
-			} else {
-				ins = append(ins, reflect.ValueOf(args[i]))
-			}
-		}
-		ins = append(ins, reflect.ValueOf(args[len(args)-1]))
+	ins := externArgs(fn.Type(), len(args), func(n int) value { return args[n] })
+	if i.externHook != nil {
+		i.auditExtern(caller, name, fn, ins)
+	}
+	if fn.Type().IsVariadic() {
 		fn.CallSlice(ins)
 	} else {
-		ins = make([]reflect.Value, len(args), len(args))
-		for i := 0; i < len(args); i++ {
-			if args[i] == nil {
-				ins[i] = reflect.New(typ.In(i)).Elem()
-			} else {
-				ins[i] = reflect.ValueOf(args[i])
-			}
-		}
 		fn.Call(ins)
 	}
 }
 
-func (i *Interp) callExternalByStack(caller *frame, fn reflect.Value, ir int, ia []int) {
+func (i *Interp) callExternalByStack(caller *frame, name string, fn reflect.Value, ir int, ia []int) {
+	if i.ctx.maxDepth > 0 {
+		defer i.enterExtern(caller)()
+	}
 	if caller.deferid != 0 {
 		i.deferMap.Store(caller.deferid, caller)
 	}
-	var ins []reflect.Value
-	typ := fn.Type()
+	ins := externArgs(fn.Type(), len(ia), func(n int) value { return caller.reg(ia[n]) })
 	isVariadic := fn.Type().IsVariadic()
-	if isVariadic {
-		var i int
-		for n := len(ia) - 1; i < n; i++ {
-			arg := caller.reg(ia[i])
-			if arg == nil {
-				ins = append(ins, reflect.New(typ.In(i)).Elem())
-			} else {
-				ins = append(ins, reflect.ValueOf(arg))
-			}
-		}
-		ins = append(ins, reflect.ValueOf(caller.reg(ia[i])))
-	} else {
-		n := len(ia)
-		ins = make([]reflect.Value, n, n)
-		for i := 0; i < n; i++ {
-			arg := caller.reg(ia[i])
-			if arg == nil {
-				ins[i] = reflect.New(typ.In(i)).Elem()
-			} else {
-				ins[i] = reflect.ValueOf(arg)
-			}
-		}
+	if i.externHook != nil {
+		i.auditExtern(caller, name, fn, ins)
 	}
 	var results []reflect.Value
 	if isVariadic {
@@ -712,18 +890,32 @@ func (i *Interp) callExternalByStack(caller *frame, fn reflect.Value, ir int, ia
 // control.
 //
 func (fr *frame) run() {
+	if t := fr.interp.tracer; t != nil {
+		t.begin(fr)
+		defer t.end(fr)
+	}
 	if fr.pfn.Recover != nil {
 		defer func() {
 			if fr.pc == -1 {
 				return // normal return
 			}
 			fr.panicking = &panicking{recover()}
+			if fr.deadline != nil {
+				fr.deadline.cur.Store(fr)
+			}
 			fr.runDefers()
 			for _, fn := range fr.pfn.Recover {
 				fn(fr)
 			}
 		}()
 	}
+	if fr.interp.panicFunc != nil {
+		defer fr.reportPanic()
+	}
+	defer fr.stackPanic()
+	if fr.interp.mode&EnableRuntimePos != 0 {
+		defer fr.addPanicPos()
+	}
 
 	for fr.pc != -1 {
 		fn := fr.pfn.Instrs[fr.pc]
@@ -742,12 +934,20 @@ func doRecover(caller *frame) value {
 		caller != nil && caller.panicking == nil &&
 		caller.caller != nil && caller.caller.panicking != nil {
 		p := caller.caller.panicking.value
+		switch p.(type) {
+		case *InterpInternalError, *TimeoutError, exitPanic:
+			// interpreter crash, timeout and os.Exit are not recoverable by the target program.
+			return nil
+		}
 		caller.caller.panicking = nil
+		caller.caller.recovered()
 		// TODO(adonovan): support runtime.Goexit.
 		switch p := p.(type) {
 		case targetPanic:
 			// The target program explicitly called panic().
 			return p.v
+		case runtimePanic:
+			return p.err
 		case runtime.Error:
 			// The interpreter encountered a runtime error.
 			return p
@@ -787,21 +987,31 @@ func setGlobal(i *Interp, pkg *ssa.Package, name string, v value) {
 // The SSA program must include the "runtime" package.
 //
 
-func NewInterp(ctx *Context, mainpkg *ssa.Package) (*Interp, error) {
+func NewInterp(ctx *Context, mainpkg *ssa.Package, opts ...Option) (*Interp, error) {
 	i := &Interp{
-		ctx:          ctx,
-		fset:         mainpkg.Prog.Fset,
-		prog:         mainpkg.Prog,
-		mainpkg:      mainpkg,
-		globals:      make(map[ssa.Value]value),
-		mode:         ctx.Mode,
-		loader:       ctx.Loader,
-		goroutines:   1,
-		preloadTypes: make(map[types.Type]reflect.Type),
-		funcs:        make(map[*ssa.Function]*Function),
-		msets:        make(map[reflect.Type](map[string]*ssa.Function)),
+		ctx:        ctx,
+		fset:       mainpkg.Prog.Fset,
+		prog:       mainpkg.Prog,
+		mainpkg:    mainpkg,
+		globals:    make(map[ssa.Value]value),
+		mode:       ctx.Mode,
+		loader:     ctx.Loader,
+		goroutines: 1,
+		funcs:      make(map[*ssa.Function]*Function),
+		msets:      make(map[reflect.Type](map[string]*ssa.Function)),
+		debugFunc:  ctx.debugFunc,
+	}
+	for _, opt := range opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
 	}
+	if i.mode&EnableProfiling != 0 {
+		i.profile = newProfiler(i.fset)
+	}
+	i.initProc()
 	i.record = NewTypesRecord(i.loader, i)
+	i.record.nomethods = i.mode&DisableMethodSynthesis != 0
 	i.record.Load(mainpkg)
 
 	var pkgs []*ssa.Package
@@ -826,40 +1036,179 @@ func NewInterp(ctx *Context, mainpkg *ssa.Package) (*Interp, error) {
 	if err != nil {
 		return i, err
 	}
+	setTestDeps(i)
 
-	_, err = i.Run("init")
+	err = i.runInit(pkgs)
 	if err != nil {
 		err = fmt.Errorf("init error: %w", err)
 	}
 	return i, err
 }
 
-func (i *Interp) loadType(typ types.Type) {
-	if _, ok := i.preloadTypes[typ]; !ok {
-		i.preloadTypes[typ] = i.record.ToType(typ)
-	}
+// preToType converts typ at compile time, see toType.
+func (i *Interp) preToType(typ types.Type) reflect.Type {
+	return i.toType(typ)
 }
 
-func (i *Interp) preToType(typ types.Type) reflect.Type {
-	if t, ok := i.preloadTypes[typ]; ok {
-		return t
+// toType converts typ on first use, reads are lock-free.
+func (i *Interp) toType(typ types.Type) reflect.Type {
+	if t, ok := i.convertTypes.Load(typ); ok {
+		return t.(reflect.Type)
+	}
+	i.typesMutex.Lock()
+	defer i.typesMutex.Unlock()
+	if t, ok := i.convertTypes.Load(typ); ok {
+		return t.(reflect.Type)
 	}
 	t := i.record.ToType(typ)
-	i.preloadTypes[typ] = t
+	i.convertTypes.Store(typ, t)
 	return t
 }
 
-func (i *Interp) toType(typ types.Type) reflect.Type {
-	if t, ok := i.preloadTypes[typ]; ok {
-		return t
+// checkFuncArgs checks args count and types by fn signature, converting
+// args to the parameter types. The variadic arguments of a variadic fn are
+// packed to a slice, unless args end with the slice, like f(s...).
+func (i *Interp) checkFuncArgs(fn *ssa.Function, args []Value) ([]Value, error) {
+	params := fn.Signature.Params()
+	if fn.Signature.Variadic() {
+		n := params.Len() - 1
+		if len(args) < n {
+			return nil, fmt.Errorf("%v: got %v arguments, want at least %v", fn, len(args), n)
+		}
+		param := params.At(n)
+		typ := i.toType(param.Type())
+		if len(args) == n+1 {
+			if v, err := convertArg(args[n], typ, param.Type().String()); err == nil {
+				return i.convertArgs(fn, append(args[:n:n], v))
+			}
+		}
+		elem := param.Type().(*types.Slice).Elem()
+		etyp := i.toType(elem)
+		s := reflect.MakeSlice(typ, len(args)-n, len(args)-n)
+		for j, arg := range args[n:] {
+			v, err := convertArg(arg, etyp, elem.String())
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v in argument %v", fn, err, paramName(param, n))
+			}
+			if v != nil {
+				s.Index(j).Set(reflect.ValueOf(v))
+			}
+		}
+		return i.convertArgs(fn, append(args[:n:n], s.Interface()))
 	}
-	// log.Panicf("toType %v %p\n", typ, typ)
-	i.typesMutex.Lock()
-	defer i.typesMutex.Unlock()
-	return i.record.ToType(typ)
+	if len(args) != params.Len() {
+		return nil, fmt.Errorf("%v: got %v arguments, want %v", fn, len(args), params.Len())
+	}
+	return i.convertArgs(fn, args)
+}
+
+// convertArgs converts args to the parameter types of fn.
+func (i *Interp) convertArgs(fn *ssa.Function, args []Value) ([]Value, error) {
+	params := fn.Signature.Params()
+	conv := make([]Value, len(args))
+	for n, arg := range args {
+		param := params.At(n)
+		v, err := convertArg(arg, i.toType(param.Type()), param.Type().String())
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v in argument %v", fn, err, paramName(param, n))
+		}
+		conv[n] = v
+	}
+	return conv, nil
+}
+
+// convertArg returns arg converted to typ, the type named tname of a
+// RunFunc argument. Nil is the zero value of a pointer, slice, map, chan,
+// func or interface type. Numbers are converted to other number types if
+// the converted integer has the same value, eg. an int to an int32.
+func convertArg(arg Value, typ reflect.Type, tname string) (Value, error) {
+	if arg == nil {
+		switch typ.Kind() {
+		case reflect.Interface:
+			return nil, nil
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			return reflect.Zero(typ).Interface(), nil
+		}
+		return nil, fmt.Errorf("cannot use nil as %v value", tname)
+	}
+	v := reflect.ValueOf(arg)
+	switch {
+	case v.Type().AssignableTo(typ):
+		if typ.Kind() == reflect.Interface {
+			return arg, nil
+		}
+		return v.Convert(typ).Interface(), nil
+	case v.Type().ConvertibleTo(typ) && v.Kind() == typ.Kind():
+		return v.Convert(typ).Interface(), nil
+	case isNumberKind(v.Kind()) && isNumberKind(typ.Kind()):
+		c := v.Convert(typ)
+		if typ.Kind() <= reflect.Uintptr && !sameInteger(v, c) {
+			return nil, fmt.Errorf("cannot use %T value %v as %v value (overflows or truncated)", arg, arg, tname)
+		}
+		return c.Interface(), nil
+	}
+	return nil, fmt.Errorf("cannot use %T as %v value", arg, tname)
+}
+
+// sameInteger reports whether the number x has the value of the integer y.
+func sameInteger(x, y reflect.Value) bool {
+	signed := y.Kind() <= reflect.Int64
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if signed {
+			return x.Int() == y.Int()
+		}
+		return x.Int() >= 0 && uint64(x.Int()) == y.Uint()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if signed {
+			return x.Uint() <= math.MaxInt64 && int64(x.Uint()) == y.Int()
+		}
+		return x.Uint() == y.Uint()
+	}
+	if signed {
+		return x.Float() == float64(y.Int())
+	}
+	return x.Float() == float64(y.Uint())
 }
 
+func paramName(param *types.Var, n int) string {
+	if name := param.Name(); name != "" && name != "_" {
+		return name
+	}
+	return fmt.Sprintf("#%v", n)
+}
+
+// RunFunc calls the function name of the main package with args, returning
+// its result, nil if it has no results or a Tuple if it has several. An
+// unrecovered panic of a goroutine started by the call ends the call with
+// a GoroutinePanic error. The variadic arguments of a variadic function
+// are passed one by one, or as a final slice like f(s...).
 func (i *Interp) RunFunc(name string, args ...Value) (r Value, err error) {
+	return i.RunFuncIn(i.mainpkg.Pkg.Path(), name, args...)
+}
+
+// RunFuncIn is RunFunc of the function name of the interpreted package
+// pkgPath, the main package or a source package of the program.
+func (i *Interp) RunFuncIn(pkgPath string, name string, args ...Value) (r Value, err error) {
+	return i.runFuncIn(nil, pkgPath, name, args)
+}
+
+// runFuncIn is RunFuncIn with the deadline dl of RunFuncTimeout, or nil.
+func (i *Interp) runFuncIn(dl *deadline, pkgPath string, name string, args []Value) (r Value, err error) {
+	// an abandoned call must not set the results
+	var res Value
+	var resErr error
+	run, ok := i.runCall(dl, func() {
+		res, resErr = i.runFunc(pkgPath, name, args)
+	})
+	if !ok {
+		return nil, run.err
+	}
+	return res, resErr
+}
+
+// runFunc is RunFuncIn in the goroutine of the call.
+func (i *Interp) runFunc(pkgPath string, name string, args []Value) (r Value, err error) {
 	defer func() {
 		if i.mode&DisableRecover != 0 {
 			return
@@ -873,15 +1222,28 @@ func (i *Interp) RunFunc(name string, args ...Value) (r Value, err error) {
 			err = p
 		case runtime.Error:
 			err = p
+		case *InterpInternalError:
+			err = p
+		case *TimeoutError:
+			err = p
+		case *ErrMissingSymbol:
+			err = p
 		case string:
 			err = plainError(p)
 		case plainError:
 			err = p
 		default:
-			err = fmt.Errorf("unexpected type: %T: %v", p, p)
+			err = unexpectedPanic{p}
 		}
 	}()
-	if fn := i.mainpkg.Func(name); fn != nil {
+	pkg, ok := i.lookupPackage(pkgPath)
+	if !ok {
+		return nil, fmt.Errorf("no package %v", pkgPath)
+	}
+	if fn := pkg.Func(name); fn != nil {
+		if args, err = i.checkFuncArgs(fn, args); err != nil {
+			return
+		}
 		r = i.call(nil, fn, args, nil)
 	} else {
 		err = fmt.Errorf("no function %v", name)
@@ -890,14 +1252,43 @@ func (i *Interp) RunFunc(name string, args ...Value) (r Value, err error) {
 }
 
 func (i *Interp) Run(entry string) (exitCode int, err error) {
+	return i.RunIn(i.mainpkg.Pkg.Path(), entry)
+}
+
+// RunIn is Run of the entry function of the interpreted package pkgPath,
+// the main package or a source package of the program. The program ends
+// at the return of the entry function, os.Exit or an unrecovered panic of
+// a goroutine, reported by a GoroutinePanic error.
+func (i *Interp) RunIn(pkgPath string, entry string) (exitCode int, err error) {
+	pkg, ok := i.lookupPackage(pkgPath)
+	if !ok {
+		return 1, fmt.Errorf("no package %v", pkgPath)
+	}
+	fn := pkg.Func(entry)
+	if fn == nil {
+		return 1, fmt.Errorf("no function %v", entry)
+	}
+	return i.runFuncs(fn)
+}
+
+// runFuncs calls the functions fns without arguments in order, until a
+// function panics or exits the program.
+func (i *Interp) runFuncs(fns ...*ssa.Function) (exitCode int, err error) {
+	code, codeErr := 2, error(nil)
+	run, ok := i.runCall(nil, func() {
+		code, codeErr = i.callFuncs(fns)
+	})
+	if !ok {
+		return run.exitCode, run.err
+	}
+	return code, codeErr
+}
+
+// callFuncs is runFuncs in the goroutine of the call.
+func (i *Interp) callFuncs(fns []*ssa.Function) (exitCode int, err error) {
 	// Top-level error handler.
-	i.exited = false
 	exitCode = 2
 	defer func() {
-		if i.exited {
-			return
-		}
-		i.exited = true
 		if i.mode&DisableRecover != 0 {
 			return
 		}
@@ -910,26 +1301,163 @@ func (i *Interp) Run(entry string) (exitCode int, err error) {
 			err = p
 		case runtime.Error:
 			err = p
+		case *InterpInternalError:
+			err = p
+		case *TimeoutError:
+			err = p
+		case *ErrMissingSymbol:
+			err = p
 		case string:
 			err = plainError(p)
 		case plainError:
 			err = p
 		default:
-			err = fmt.Errorf("unexpected type: %T: %v", p, p)
+			err = unexpectedPanic{p}
 		}
 	}()
-	if mainFn := i.mainpkg.Func(entry); mainFn != nil {
-		i.call(nil, mainFn, nil, nil)
-		exitCode = 0
-	} else {
-		err = fmt.Errorf("no function %v", entry)
-		exitCode = 1
+	for _, fn := range fns {
+		i.call(nil, fn, nil, nil)
 	}
+	exitCode = 0
 	return
 }
 
+// runState is the state of a call of the host into the program by Run or
+// RunFunc, shared by the goroutines started by the call. os.Exit and the
+// unrecovered panics of the goroutines end the call, they never exit the
+// host.
+type runState struct {
+	exited    int32         // the call returned, atomically set
+	abandoned int32         // the call returned at a crash, atomically set
+	once      sync.Once     // sets the crash
+	crashed   chan struct{} // closed at the crash of a goroutine of the call
+	exitCode  int           // exit code of the crash
+	err       error         // error of the crash, nil for os.Exit
+	dl        *deadline     // safepoint checks of the goroutines of the call: step budget and abandon
+}
+
+// runCall calls f in a new goroutine as a call of the host, with the
+// deadline dl of RunFuncTimeout or nil. The call has its own step budget,
+// shared by its goroutines and combined with dl. It reports false if a
+// goroutine started by the call crashes the program before f returns, f
+// is then abandoned: its goroutines stop at their next safepoint like at
+// os.Exit, and run has the exit code and error of the crash.
+func (i *Interp) runCall(dl *deadline, f func()) (run *runState, ok bool) {
+	run = &runState{crashed: make(chan struct{})}
+	run.dl = &deadline{interp: i, run: run}
+	if max := i.ctx.maxSteps; max > 0 {
+		steps := max
+		run.dl.budget, run.dl.steps = max, &steps
+	}
+	if dl == nil {
+		dl = run.dl
+	} else {
+		dl.budget, dl.steps, dl.run = run.dl.budget, run.dl.steps, run
+	}
+	done := make(chan interface{}, 1)
+	go func() {
+		defer i.enterRun(run)()
+		if dl != nil {
+			defer i.enterDeadline(dl)()
+		}
+		defer func() {
+			// the panics of DisableRecover mode crash the caller
+			done <- recover()
+		}()
+		f()
+	}()
+	select {
+	case p := <-done:
+		atomic.StoreInt32(&run.exited, 1)
+		if p != nil {
+			panic(p)
+		}
+		return run, true
+	case <-run.crashed:
+		atomic.StoreInt32(&run.exited, 1)
+		atomic.StoreInt32(&run.abandoned, 1)
+		return run, false
+	}
+}
+
+// crash ends the call run at the crash of one of its goroutines by os.Exit
+// or an unrecovered panic. It reports false if the call has returned.
+func (run *runState) crash(exitCode int, err error) bool {
+	if atomic.LoadInt32(&run.exited) != 0 {
+		return false
+	}
+	run.once.Do(func() {
+		run.exitCode, run.err = exitCode, err
+		close(run.crashed)
+	})
+	return true
+}
+
+// enterRun records run as the call of the caller goroutine. The returned
+// func restores the call of the goroutine, if the call is nested in a host
+// function called by another call.
+func (i *Interp) enterRun(run *runState) func() {
+	id := goid.Get()
+	prev, nested := i.runs.Load(id)
+	i.runs.Store(id, run)
+	return func() {
+		if nested {
+			i.runs.Store(id, prev)
+		} else {
+			i.runs.Delete(id)
+		}
+	}
+}
+
+// goroutineRun returns the call of the caller goroutine, or nil if it runs
+// outside Run and RunFunc, eg. a func of GetFunc called by the host.
+func (i *Interp) goroutineRun() *runState {
+	if v, ok := i.runs.Load(goid.Get()); ok {
+		return v.(*runState)
+	}
+	return nil
+}
+
+// runInit runs the package initializers of the interpreted packages pkgs
+// of the program in the order of the Go toolchain, see initOrder.
+func (i *Interp) runInit(pkgs []*ssa.Package) error {
+	var fns []*ssa.Function
+	for _, pkg := range initOrder(i.mainpkg, pkgs) {
+		fns = append(fns, pkg.Func("init"))
+	}
+	_, err := i.runFuncs(fns...)
+	return err
+}
+
+// lookupPackage returns the interpreted package of path.
+func (i *Interp) lookupPackage(path string) (*ssa.Package, bool) {
+	if path == i.mainpkg.Pkg.Path() {
+		return i.mainpkg, true
+	}
+	pkg := i.prog.ImportedPackage(path)
+	if pkg == nil || pkg.Func("init").Blocks == nil {
+		return nil, false
+	}
+	return pkg, true
+}
+
+// member returns the member key of the interpreted package pkgPath.
+func (i *Interp) member(pkgPath string, key string) (ssa.Member, bool) {
+	pkg, ok := i.lookupPackage(pkgPath)
+	if !ok {
+		return nil, false
+	}
+	m, ok := pkg.Members[key]
+	return m, ok
+}
+
 func (i *Interp) GetFunc(key string) (interface{}, bool) {
-	m, ok := i.mainpkg.Members[key]
+	return i.GetFuncIn(i.mainpkg.Pkg.Path(), key)
+}
+
+// GetFuncIn is GetFunc of the interpreted package pkgPath.
+func (i *Interp) GetFuncIn(pkgPath string, key string) (interface{}, bool) {
+	m, ok := i.member(pkgPath, key)
 	if !ok {
 		return nil, false
 	}
@@ -937,11 +1465,116 @@ func (i *Interp) GetFunc(key string) (interface{}, bool) {
 	if !ok {
 		return nil, false
 	}
-	return i.makeFunc(i.toType(fn.Type()), i.funcs[fn], nil).Interface(), true
+	return i.makeFunc(i.toType(fn.Type()), i.lookupFunc(fn), nil).Interface(), true
+}
+
+// GetMethod returns the method name of typ, an interpreted type found by
+// GetType or a pointer to it, as a func with the receiver as first
+// argument, like the method expression typ.name of the script.
+func (i *Interp) GetMethod(typ reflect.Type, name string) (interface{}, bool) {
+	elem := typ
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	T, ok := i.findType(elem, false)
+	if !ok {
+		return nil, false
+	}
+	if typ != elem {
+		T = types.NewPointer(T)
+	}
+	mset := i.prog.MethodSets.MethodSet(T)
+	for n := 0; n < mset.Len(); n++ {
+		sel := mset.At(n)
+		if sel.Obj().Name() != name || !sel.Obj().Exported() {
+			continue
+		}
+		fn := i.prog.MethodValue(sel)
+		sig := i.toType(fn.Signature)
+		in := []reflect.Type{typ}
+		for n := 0; n < sig.NumIn(); n++ {
+			in = append(in, sig.In(n))
+		}
+		out := make([]reflect.Type, sig.NumOut())
+		for n := range out {
+			out[n] = sig.Out(n)
+		}
+		mtyp := reflect.FuncOf(in, out, sig.IsVariadic())
+		return i.makeFunc(mtyp, i.loadFunction(fn), nil).Interface(), true
+	}
+	return nil, false
+}
+
+// Bind sets the func pointed to by fnPtr to call the function name of the
+// main package, checking the signatures once instead of at each RunFunc:
+//
+//	var handle func(Request) Response
+//	err := interp.Bind("Handle", &handle)
+//
+// Each argument and result must be assignable, or convertible with the same
+// kind, to the type of the callee. Panics of the function panic the caller.
+func (i *Interp) Bind(name string, fnPtr interface{}) error {
+	ptr := reflect.ValueOf(fnPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Func {
+		return fmt.Errorf("bind %v: %T is not a func pointer", name, fnPtr)
+	}
+	fn := i.mainpkg.Func(name)
+	if fn == nil {
+		return fmt.Errorf("no function %v", name)
+	}
+	ftyp, htyp := i.toType(fn.Type()), ptr.Elem().Type()
+	if ftyp.NumIn() != htyp.NumIn() || ftyp.NumOut() != htyp.NumOut() || ftyp.IsVariadic() != htyp.IsVariadic() {
+		return fmt.Errorf("bind %v: cannot use %v as %v", fn, ftyp, htyp)
+	}
+	for n := 0; n < ftyp.NumIn(); n++ {
+		if !bindable(htyp.In(n), ftyp.In(n)) {
+			return fmt.Errorf("bind %v: cannot use %v as %v value in argument %v", fn, htyp.In(n), ftyp.In(n), paramName(fn.Signature.Params().At(n), n))
+		}
+	}
+	for n := 0; n < ftyp.NumOut(); n++ {
+		if !bindable(ftyp.Out(n), htyp.Out(n)) {
+			return fmt.Errorf("bind %v: cannot use %v as %v value in result %v", fn, ftyp.Out(n), htyp.Out(n), n)
+		}
+	}
+	pfn := i.lookupFunc(fn)
+	ptr.Elem().Set(reflect.MakeFunc(htyp, func(args []reflect.Value) []reflect.Value {
+		for n, arg := range args {
+			args[n] = convertBound(arg, ftyp.In(n))
+		}
+		results := i.callFunctionByReflect(i.tryDeferFrame(), ftyp, pfn, args, nil)
+		for n, r := range results {
+			results[n] = convertBound(r, htyp.Out(n))
+		}
+		return results
+	}))
+	return nil
+}
+
+// bindable reports whether Bind converts values of from to the type to.
+func bindable(from, to reflect.Type) bool {
+	return from.AssignableTo(to) || from.ConvertibleTo(to) && from.Kind() == to.Kind()
+}
+
+// convertBound returns v of a bindable type as a value of typ.
+func convertBound(v reflect.Value, typ reflect.Type) reflect.Value {
+	if v.Type() == typ {
+		return v
+	}
+	if typ.Kind() == reflect.Interface {
+		r := reflect.New(typ).Elem()
+		r.Set(v)
+		return r
+	}
+	return v.Convert(typ)
 }
 
 func (i *Interp) GetVarAddr(key string) (interface{}, bool) {
-	m, ok := i.mainpkg.Members[key]
+	return i.GetVarAddrIn(i.mainpkg.Pkg.Path(), key)
+}
+
+// GetVarAddrIn is GetVarAddr of the interpreted package pkgPath.
+func (i *Interp) GetVarAddrIn(pkgPath string, key string) (interface{}, bool) {
+	m, ok := i.member(pkgPath, key)
 	if !ok {
 		return nil, false
 	}
@@ -954,7 +1587,12 @@ func (i *Interp) GetVarAddr(key string) (interface{}, bool) {
 }
 
 func (i *Interp) GetConst(key string) (constant.Value, bool) {
-	m, ok := i.mainpkg.Members[key]
+	return i.GetConstIn(i.mainpkg.Pkg.Path(), key)
+}
+
+// GetConstIn is GetConst of the interpreted package pkgPath.
+func (i *Interp) GetConstIn(pkgPath string, key string) (constant.Value, bool) {
+	m, ok := i.member(pkgPath, key)
 	if !ok {
 		return nil, false
 	}
@@ -966,7 +1604,12 @@ func (i *Interp) GetConst(key string) (constant.Value, bool) {
 }
 
 func (i *Interp) GetType(key string) (reflect.Type, bool) {
-	m, ok := i.mainpkg.Members[key]
+	return i.GetTypeIn(i.mainpkg.Pkg.Path(), key)
+}
+
+// GetTypeIn is GetType of the interpreted package pkgPath.
+func (i *Interp) GetTypeIn(pkgPath string, key string) (reflect.Type, bool) {
+	m, ok := i.member(pkgPath, key)
 	if !ok {
 		return nil, false
 	}
@@ -0,0 +1,184 @@
+//go:build go1.21
+// +build go1.21
+
+package gossa_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/cmp"
+	_ "github.com/goplus/gossa/pkg/log/slog"
+	_ "github.com/goplus/gossa/pkg/slices"
+)
+
+func TestInitOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gossa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.21\n",
+		"trace/trace.go": `package trace
+
+var Log []string
+
+func Add(s string) int {
+	Log = append(Log, s)
+	return len(Log)
+}
+`,
+		"a/a.go": `package a
+
+import "example.com/m/trace"
+
+var N = trace.Add("a")
+`,
+		"b/b.go": `package b
+
+import "example.com/m/trace"
+
+var N = trace.Add("b")
+`,
+		"c/c.go": `package c
+
+import (
+	"example.com/m/a"
+	"example.com/m/trace"
+)
+
+var N = trace.Add("c") + a.N
+`,
+		"unused/unused.go": `package unused
+
+import "example.com/m/trace"
+
+var N = trace.Add("unused")
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// packages are initialized sorted by import path, not in import order
+	src := `package main
+
+import (
+	"strings"
+
+	"example.com/m/b"
+	"example.com/m/c"
+	"example.com/m/trace"
+)
+
+var N = trace.Add("main")
+
+func main() {
+	if s := strings.Join(trace.Log, " "); s != "a b c main" {
+		panic(s)
+	}
+	if b.N != 2 || c.N != 4 {
+		panic("bad values")
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.Loader = gossa.NewGoModLoader(dir, nil)
+	code, err := ctx.RunFile("main.go", src, nil)
+	if err != nil || code != 0 {
+		t.Fatalf("exit %v, %v", code, err)
+	}
+}
+
+func TestStdlibSince(t *testing.T) {
+	// strings.CutPrefix of go1.20 and log/slog of go1.21 are registered by
+	// their version tagged export files, in addition to the go1.18 files
+	src := `package main
+
+import (
+	"log/slog"
+	"strings"
+)
+
+func main() {
+	s, ok := strings.CutPrefix("go1.21", "go")
+	if !ok || s != "1.21" {
+		panic(s)
+	}
+	if strings.TrimSpace(" x ") != "x" {
+		panic("missing go1.18 symbol")
+	}
+	if a := slog.String("version", s); a.String() != "version=1.21" {
+		panic(a.String())
+	}
+	if slog.LevelWarn.String() != "WARN" {
+		panic(slog.LevelWarn)
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenericFuncs(t *testing.T) {
+	src := `package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+func main() {
+	s := []string{"b", "c", "a"}
+	slices.Sort(s)
+	if strings.Join(s, ",") != "a,b,c" {
+		panic(s)
+	}
+	n := []int{3, 1, 2}
+	defer slices.Sort(n)
+	if slices.Max(n) != 3 || slices.Index(s, "c") != 2 {
+		panic("bad max or index")
+	}
+	if i, ok := slices.BinarySearch(s, "b"); i != 1 || !ok {
+		panic(i)
+	}
+	f := slices.Insert([]float64{1, 4}, 1, 2, 3)
+	if !slices.Equal(f, []float64{1, 2, 3, 4}) {
+		panic(f)
+	}
+	if slices.IndexFunc(n, func(v int) bool { return v < 2 }) != 1 {
+		panic("bad index func")
+	}
+	if cmp.Compare("a", "b") != -1 || cmp.Or(0, 0, 7) != 7 {
+		panic("bad cmp")
+	}
+}
+`
+	for _, mode := range []gossa.Mode{0, gossa.DisableClosureCompiler} {
+		if _, err := gossa.RunFile("main.go", src, nil, mode); err != nil {
+			t.Fatalf("mode %v: %v", mode, err)
+		}
+	}
+	for src, want := range map[string]string{
+		"package main\n\nimport \"slices\"\n\nfunc main() {\n\tslices.Sort([]int8{1})\n}\n":       "missing instance slices.Sort func([]int8)",
+		"package main\n\nimport \"slices\"\n\nfunc main() {\n\tslices.Sort[[]int]([]int{1})\n}\n": "instantiation of generic Sort is not supported",
+	} {
+		_, err := gossa.RunFile("main.go", src, nil, 0)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("got %v, want %v", err, want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+//go:build go1.22
+// +build go1.22
+
+package gossa_test
+
+import (
+	"testing"
+
+	"github.com/goplus/gossa"
+)
+
+func TestRangeInt(t *testing.T) {
+	src := `package main
+
+type Count uint8
+
+func main() {
+	var s []int
+	for i := range 5 {
+		s = append(s, i)
+	}
+	if len(s) != 5 || s[0] != 0 || s[4] != 4 {
+		panic(s)
+	}
+	n := 0
+	for range 3 {
+		n++
+	}
+	if n != 3 {
+		panic(n)
+	}
+	var c Count = 200
+	var last Count
+	for i := range c {
+		last = i
+	}
+	if last != 199 {
+		panic(last)
+	}
+	var u uint64
+	for i := range uint64(4) {
+		u += i
+	}
+	if u != 6 {
+		panic(u)
+	}
+	var i64 int64
+	for i64 = range 10 {
+		if i64 == 6 {
+			break
+		}
+	}
+	if i64 != 6 {
+		panic(i64)
+	}
+	neg := -3
+	for range neg {
+		panic("range over negative")
+	}
+	for range 0 {
+		panic("range over zero")
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRangeParity(t *testing.T) {
+	src := `package main
+
+func main() {
+	arr := [4]int{1, 2, 3, 4}
+	sum := 0
+	for i, v := range arr {
+		arr[3] = 0 // range over a copy of the array
+		sum += i * v
+	}
+	if sum != 0*1+1*2+2*3+3*4 {
+		panic(sum)
+	}
+	sum = 0
+	for i := range &arr {
+		sum += i
+	}
+	if sum != 6 {
+		panic(sum)
+	}
+	s := []int{1, 2, 3}
+	n := 0
+	for _, v := range s {
+		if n == 0 {
+			s = append(s, 4) // range over the original slice
+		}
+		n += v
+	}
+	if n != 6 {
+		panic(n)
+	}
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	n = 0
+	for v := range ch {
+		n += v
+	}
+	if n != 6 {
+		panic(n)
+	}
+	var nilSlice []int
+	for range nilSlice {
+		panic("range over nil slice")
+	}
+	var runes []rune
+	for i, r := range "héllo" {
+		runes = append(runes, r)
+		if i == 1 && r != 'é' {
+			panic(r)
+		}
+	}
+	if len(runes) != 5 {
+		panic(len(runes))
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build go1.23
+// +build go1.23
+
+package gossa_test
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/goplus/gossa"
+)
+
+func TestRangeFunc(t *testing.T) {
+	src := `package main
+
+func seq(n int) func(func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func main() {
+	for i := range seq(3) {
+		println(i)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	var diags []*gossa.Diagnostic
+	ctx.SetDiagnostic(func(d *gossa.Diagnostic) {
+		diags = append(diags, d)
+	})
+	_, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err == nil || !strings.Contains(err.Error(), "main.go:14:2: range over func is not supported") {
+		t.Fatalf("load error %v", err)
+	}
+	if len(diags) != 1 || diags[0].Kind != gossa.DiagUnsupported {
+		t.Fatalf("diagnostics %v", diags)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build go1.26
+// +build go1.26
+
+package gossa_test
+
+// printFloatOutput is the output of the floats and complex numbers of
+// TestPrintFloat, printed in the shortest representation since go1.26.
+const printFloatOutput = `1 -2.5 0.1 0 +Inf -Inf NaN 1e+100 1.23456789e+08 3
+(1-2i) (0.5+0i)
+`
@@ -0,0 +1,159 @@
+package gossa_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goplus/gossa"
+)
+
+// opKind is a basic type of the operator matrix, with the operand pairs
+// its binary operators are tested on.
+type opKind struct {
+	name  string
+	class int // opInt, opFloat, ...
+	pairs [][2]string
+}
+
+const (
+	opInt = iota
+	opUint
+	opFloat
+	opComplex
+	opString
+	opBool
+)
+
+var opKinds = []opKind{
+	{"int", opInt, [][2]string{{"7", "3"}, {"-7", "2"}, {"-9223372036854775808", "-1"}}},
+	{"int8", opInt, [][2]string{{"7", "3"}, {"-7", "2"}, {"127", "1"}, {"-128", "-1"}}},
+	{"int16", opInt, [][2]string{{"7", "3"}, {"-7", "2"}, {"32767", "2"}}},
+	{"int32", opInt, [][2]string{{"7", "3"}, {"-7", "2"}, {"2147483647", "2"}}},
+	{"int64", opInt, [][2]string{{"7", "3"}, {"-7", "2"}, {"9223372036854775807", "2"}}},
+	{"uint", opUint, [][2]string{{"7", "3"}, {"3", "7"}}},
+	{"uint8", opUint, [][2]string{{"7", "3"}, {"3", "7"}, {"255", "2"}}},
+	{"uint16", opUint, [][2]string{{"7", "3"}, {"3", "7"}, {"65535", "2"}}},
+	{"uint32", opUint, [][2]string{{"7", "3"}, {"3", "7"}, {"4294967295", "2"}}},
+	{"uint64", opUint, [][2]string{{"7", "3"}, {"3", "7"}, {"18446744073709551615", "2"}}},
+	{"uintptr", opUint, [][2]string{{"7", "3"}, {"3", "7"}}},
+	{"float32", opFloat, [][2]string{{"1.5", "0.25"}, {"0.1", "-3"}, {"3.4e38", "10"}}},
+	{"float64", opFloat, [][2]string{{"1.5", "0.25"}, {"0.1", "-3"}, {"1.7e308", "10"}}},
+	{"complex64", opComplex, [][2]string{{"1+2i", "3-1i"}, {"0.1i", "-3"}}},
+	{"complex128", opComplex, [][2]string{{"1+2i", "3-1i"}, {"0.1i", "-3"}}},
+	{"string", opString, [][2]string{{`"ab"`, `"cd"`}, {`"b"`, `"ab"`}, {`""`, `""`}}},
+	{"bool", opBool, [][2]string{{"true", "false"}, {"true", "true"}}},
+}
+
+// opClasses lists the classes of the operands of each operator.
+var opClasses = map[string][]int{
+	"+":  {opInt, opUint, opFloat, opComplex, opString},
+	"-":  {opInt, opUint, opFloat, opComplex},
+	"*":  {opInt, opUint, opFloat, opComplex},
+	"/":  {opInt, opUint, opFloat, opComplex},
+	"%":  {opInt, opUint},
+	"&":  {opInt, opUint},
+	"|":  {opInt, opUint},
+	"^":  {opInt, opUint},
+	"&^": {opInt, opUint},
+	"<<": {opInt, opUint},
+	">>": {opInt, opUint},
+	"==": {opInt, opUint, opFloat, opComplex, opString, opBool},
+	"!=": {opInt, opUint, opFloat, opComplex, opString, opBool},
+	"<":  {opInt, opUint, opFloat, opString},
+	"<=": {opInt, opUint, opFloat, opString},
+	">":  {opInt, opUint, opFloat, opString},
+	">=": {opInt, opUint, opFloat, opString},
+	"u-": {opInt, opUint, opFloat, opComplex},
+	"u^": {opInt, opUint},
+	"u!": {opBool},
+}
+
+var opOrder = []string{"+", "-", "*", "/", "%", "&", "|", "^", "&^", "<<", ">>", "==", "!=", "<", "<=", ">", ">=", "u-", "u^", "u!"}
+
+func hasClass(classes []int, class int) bool {
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// genOpMatrix returns a program checking each operator on each kind of
+// opKinds gives the same results for the kind and a named type of it,
+// the named results computed by the reflect paths of the operators.
+func genOpMatrix() string {
+	var buf bytes.Buffer
+	buf.WriteString("package main\n\nimport \"fmt\"\n\nvar failed []string\n\n")
+	buf.WriteString("func check(name string, got, want interface{}) {\n\tif fmt.Sprint(got) != fmt.Sprint(want) {\n\t\tfailed = append(failed, fmt.Sprintf(\"%v: got %v, want %v\", name, got, want))\n\t}\n}\n\n")
+	buf.WriteString("func recovered(fn func()) (r interface{}) {\n\tdefer func() {\n\t\tr = recover()\n\t}()\n\tfn()\n\treturn\n}\n\n")
+	buf.WriteString("func eval(fn func() interface{}) (r interface{}) {\n\tdefer func() {\n\t\tif e := recover(); e != nil {\n\t\t\tr = e\n\t\t}\n\t}()\n\treturn fn()\n}\n\n")
+	buf.WriteString("type C int8\n\n")
+	var calls []string
+	for _, k := range opKinds {
+		n := "N_" + k.name
+		fmt.Fprintf(&buf, "type %v %v\n\n", n, k.name)
+		for i, op := range opOrder {
+			if !hasClass(opClasses[op], k.class) {
+				continue
+			}
+			fn := fmt.Sprintf("test_%v_%v", k.name, i)
+			name := fmt.Sprintf("%v %v", n, strings.TrimPrefix(op, "u"))
+			switch {
+			case strings.HasPrefix(op, "u"):
+				op = op[1:]
+				fmt.Fprintf(&buf, "func %v(x %v) {\n\tcheck(%q, %v(%v%v(x)), %vx)\n}\n\n", fn, k.name, name, k.name, op, n, op)
+				for _, p := range k.pairs {
+					calls = append(calls, fmt.Sprintf("%v(%v)", fn, p[0]), fmt.Sprintf("%v(%v)", fn, p[1]))
+				}
+			case op == "<<" || op == ">>":
+				// shift counts of signed, unsigned and named types, a
+				// negative count panics
+				fmt.Fprintf(&buf, "func %v(x %v, s int) {\n", fn, k.name)
+				for _, c := range []string{"s", "uint(s)", "C(s)"} {
+					fmt.Fprintf(&buf, "\tcheck(%q, eval(func() interface{} { return %v(%v(x) %v %v) }), eval(func() interface{} { return x %v %v }))\n",
+						name+" "+c, k.name, n, op, c, op, c)
+				}
+				buf.WriteString("}\n\n")
+				for _, p := range k.pairs {
+					for _, s := range []string{"0", "3", "7", "8", "63", "64", "70", "-1"} {
+						calls = append(calls, fmt.Sprintf("%v(%v, %v)", fn, p[0], s))
+					}
+				}
+			case op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">=":
+				fmt.Fprintf(&buf, "func %v(x, y %v) {\n\tcheck(%q, %v(x) %v %v(y), x %v y)\n}\n\n", fn, k.name, name, n, op, n, op)
+				for _, p := range k.pairs {
+					calls = append(calls, fmt.Sprintf("%v(%v, %v)", fn, p[0], p[1]), fmt.Sprintf("%v(%v, %v)", fn, p[1], p[0]), fmt.Sprintf("%v(%v, %v)", fn, p[0], p[0]))
+				}
+			default:
+				fmt.Fprintf(&buf, "func %v(x, y %v) {\n\tcheck(%q, %v(%v(x) %v %v(y)), x %v y)\n}\n\n", fn, k.name, name, k.name, n, op, n, op)
+				for _, p := range k.pairs {
+					calls = append(calls, fmt.Sprintf("%v(%v, %v)", fn, p[0], p[1]), fmt.Sprintf("%v(%v, %v)", fn, p[1], p[0]))
+				}
+				if (op == "/" || op == "%") && (k.class == opInt || k.class == opUint) {
+					fmt.Fprintf(&buf, "func %v_zero(x %v) {\n\tvar zero %v\n\tcheck(%q, recovered(func() { _ = %v(x) %v zero }), recovered(func() { _ = x %v %v(zero) }))\n}\n\n",
+						fn, k.name, n, name+" 0", n, op, op, k.name)
+					calls = append(calls, fmt.Sprintf("%v_zero(%v)", fn, k.pairs[0][0]))
+				}
+			}
+		}
+	}
+	buf.WriteString("func main() {\n")
+	for _, call := range calls {
+		fmt.Fprintf(&buf, "\t%v\n", call)
+	}
+	buf.WriteString("\tfor _, f := range failed {\n\t\tprintln(f)\n\t}\n\tif len(failed) != 0 {\n\t\tpanic(\"failed\")\n\t}\n}\n")
+	return buf.String()
+}
+
+func TestOpMatrix(t *testing.T) {
+	src := genOpMatrix()
+	for _, mode := range []gossa.Mode{0, gossa.DisableClosureCompiler} {
+		_, err := gossa.RunFile("main.go", src, nil, mode)
+		if err != nil {
+			t.Errorf("mode %v: %v", mode, err)
+		}
+	}
+}
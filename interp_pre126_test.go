@@ -0,0 +1,10 @@
+//go:build !go1.26
+// +build !go1.26
+
+package gossa_test
+
+// printFloatOutput is the output of the floats and complex numbers of
+// TestPrintFloat, printed in exponent form before go1.26.
+const printFloatOutput = `+1.000000e+000 -2.500000e+000 +1.000000e-001 +0.000000e+000 +Inf -Inf NaN +1.000000e+100 +1.234568e+008 +3.000000e+000
+(+1.000000e+000-2.000000e+000i) (+5.000000e-001+0.000000e+000i)
+`
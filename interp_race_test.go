@@ -3,10 +3,182 @@
 
 package gossa_test
 
-func init() {
-	gorootTestSkips["atomicload.go"] = "unsupport race"
-	gorootTestSkips["deferfin.go"] = "unsupport race"
-	gorootTestSkips["mallocfin.go"] = "unsupport race"
-	gorootTestSkips["peano.go"] = "stack overflow on race"
-	gorootTestSkips["chan/goroutines.go"] = "race: limit on 8128 simultaneously alive goroutines is exceeded, dying"
+import (
+	"go/token"
+	"sync"
+	"testing"
+
+	"github.com/goplus/gossa"
+)
+
+// The race tests run interpreted programs using goroutines, run by go test
+// -race to find races of the interpreter state shared by the goroutines.
+
+func TestRaceGoroutines(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+type Shape interface {
+	Area() int
+}
+
+type Line int
+
+func (l Line) Area() int {
+	return int(l) * 2
+}
+
+type Square int
+
+func (s *Square) Area() int {
+	return int(*s) * int(*s)
+}
+
+func (s *Square) String() string {
+	return fmt.Sprintf("Square(%d)", int(*s))
+}
+
+type key struct {
+	id int
+}
+
+func work(n int) (r int) {
+	defer func() {
+		if err := recover(); err != nil {
+			r = -1
+		}
+	}()
+	var shape Shape
+	if n%2 == 0 {
+		shape = Line(n)
+	} else {
+		s := Square(n)
+		shape = &s
+		_ = fmt.Sprint(&s)
+	}
+	if n%7 == 0 {
+		panic("seven")
+	}
+	return shape.Area()
+}
+
+func main() {
+	in := make(chan int)
+	out := make(chan int, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range in {
+				out <- work(n)
+			}
+		}()
+	}
+	go func() {
+		for n := 1; n <= 200; n++ {
+			in <- n
+		}
+		close(in)
+	}()
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	var mu sync.Mutex
+	seen := make(map[key]int)
+	var sum int
+	for r := range out {
+		mu.Lock()
+		seen[key{r}]++
+		mu.Unlock()
+		sum += r
+	}
+	want := 0
+	for n := 1; n <= 200; n++ {
+		switch {
+		case n%7 == 0:
+			want--
+		case n%2 == 0:
+			want += n * 2
+		default:
+			want += n * n
+		}
+	}
+	if sum != want {
+		panic(fmt.Sprintf("sum %v, want %v", sum, want))
+	}
+	done := make(chan bool)
+	timeout := make(chan bool)
+	go func() {
+		select {
+		case <-done:
+		case <-timeout:
+			panic("timeout")
+		}
+	}()
+	close(done)
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRaceRunFunc(t *testing.T) {
+	src := `package main
+
+import "strings"
+
+type Counter struct {
+	n map[string]int
+}
+
+func (c *Counter) Add(s string) int {
+	c.n[s]++
+	return c.n[s]
+}
+
+func Count(words string) int {
+	c := &Counter{n: make(map[string]int)}
+	max := 0
+	for _, w := range strings.Fields(words) {
+		if n := c.Add(w); n > max {
+			max = n
+		}
+	}
+	return max
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				r, err := interp.RunFunc("Count", "a b a c a b")
+				if err != nil || r != 3 {
+					t.Errorf("Count = %v, %v", r, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
 }
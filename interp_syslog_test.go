@@ -0,0 +1,44 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package gossa_test
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/log/syslog"
+)
+
+func TestNetPolicySyslog(t *testing.T) {
+	src := `package main
+
+import "log/syslog"
+
+func dial() string {
+	_, err := syslog.Dial("tcp", "example.com:514", syslog.LOG_INFO, "main")
+	return err.Error()
+}
+
+func local() string {
+	_, err := syslog.New(syslog.LOG_INFO, "main")
+	return err.Error()
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.SetDialer(allowDialer{})
+	for _, fn := range []string{"dial", "local"} {
+		if v, err := interp.RunFunc(fn); err != nil || v != "dial: "+gossa.ErrNetDenied.Error() {
+			t.Fatalf("%v: got %v %v", fn, v, err)
+		}
+	}
+}
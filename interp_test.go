@@ -16,18 +16,46 @@ package gossa_test
 // fmt or testing, as it proved too fragile.
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"go/token"
+	"go/types"
+	"hash/adler32"
+	"io/ioutil"
 	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/goplus/gossa"
+	"github.com/goplus/gossa/adapter"
 	_ "github.com/goplus/gossa/pkg/bytes"
 	_ "github.com/goplus/gossa/pkg/context"
 	_ "github.com/goplus/gossa/pkg/crypto/md5"
+	_ "github.com/goplus/gossa/pkg/crypto/tls"
 	_ "github.com/goplus/gossa/pkg/encoding/binary"
+	_ "github.com/goplus/gossa/pkg/encoding/json"
 	_ "github.com/goplus/gossa/pkg/errors"
 	_ "github.com/goplus/gossa/pkg/flag"
 	_ "github.com/goplus/gossa/pkg/fmt"
@@ -36,11 +64,18 @@ import (
 	_ "github.com/goplus/gossa/pkg/io/ioutil"
 	_ "github.com/goplus/gossa/pkg/log"
 	_ "github.com/goplus/gossa/pkg/math"
+	_ "github.com/goplus/gossa/pkg/math/cmplx"
 	_ "github.com/goplus/gossa/pkg/math/rand"
+	_ "github.com/goplus/gossa/pkg/net"
+	_ "github.com/goplus/gossa/pkg/net/http"
+	_ "github.com/goplus/gossa/pkg/net/http/httptest"
+	_ "github.com/goplus/gossa/pkg/net/http/httputil"
+	_ "github.com/goplus/gossa/pkg/net/smtp"
 	_ "github.com/goplus/gossa/pkg/os"
 	_ "github.com/goplus/gossa/pkg/reflect"
 	_ "github.com/goplus/gossa/pkg/runtime"
 	_ "github.com/goplus/gossa/pkg/runtime/debug"
+	_ "github.com/goplus/gossa/pkg/sort"
 	_ "github.com/goplus/gossa/pkg/strconv"
 	_ "github.com/goplus/gossa/pkg/strings"
 	_ "github.com/goplus/gossa/pkg/sync"
@@ -48,11 +83,13 @@ import (
 	_ "github.com/goplus/gossa/pkg/syscall"
 	_ "github.com/goplus/gossa/pkg/testing"
 	_ "github.com/goplus/gossa/pkg/time"
+	"golang.org/x/tools/go/ssa"
 )
 
 // These are files in go.tools/go/ssa/interp/testdata/.
 var testdataTests = []string{
 	"boundmeth.go",
+	"complex.go",
 	"complit.go",
 	"coverage.go",
 	"defer.go",
@@ -157,6 +194,97 @@ func main() {
 	}
 }
 
+func TestExternVariadicArgs(t *testing.T) {
+	ctx := gossa.NewContext(0)
+	ctx.SetOverrideFunction("main.wrapf", func(err error, format string, args ...interface{}) error {
+		if err == nil {
+			return nil
+		}
+		return fmt.Errorf(format+": %w", append(args, err)...)
+	})
+	ctx.SetOverrideFunction("main.size", func(b *bytes.Buffer, extra ...int) int {
+		if b == nil {
+			return -1 - len(extra)
+		}
+		return b.Len() + len(extra)
+	})
+	ctx.SetOverrideFunction("main.push", func(s []int, v ...int) []int {
+		return append(s, v...)
+	})
+	src := `package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+func wrapf(err error, format string, args ...interface{}) error {
+	return nil
+}
+
+func size(b *bytes.Buffer, extra ...int) int {
+	return 0
+}
+
+func push(s []int, v ...int) []int {
+	return nil
+}
+
+type Ints []interface{}
+
+func check(got, want interface{}) {
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		panic(fmt.Sprintf("got %v, want %v", got, want))
+	}
+}
+
+func main() {
+	var args []interface{}
+	check(fmt.Sprint(nil), "<nil>")
+	check(fmt.Sprint(args...), "")
+	check(fmt.Sprint(nil...), "")
+	check(fmt.Sprintf("%v %v %v", nil, []int(nil), (*int)(nil)), "<nil> [] <nil>")
+	check(fmt.Sprintf("%v", args...), "%!v(MISSING)")
+	check(fmt.Sprintf("%v-%v", Ints{1, nil}...), "1-<nil>")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v", nil)
+	check(buf.String(), "<nil>")
+
+	check(wrapf(nil, "nothing"), nil)
+	base := errors.New("base")
+	err := wrapf(base, "%v %v", nil, 1)
+	check(err, "<nil> 1: base")
+	if !errors.Is(err, base) {
+		panic("not wrapped")
+	}
+	check(wrapf(base, "empty", args...), "empty: base")
+	check(wrapf(base, "nil", nil...), "nil: base")
+
+	check(size(nil), -1)
+	check(size(nil, nil...), -1)
+	check(size(nil, 1, 2), -3)
+	check(size(&buf, 1), 6)
+
+	check(push(nil), []int{})
+	check(push(nil, nil...), []int{})
+	check(push([]int{1}, 2, 3), []int{1, 2, 3})
+	check(len(push(nil)), 0)
+
+	f := wrapf
+	check(f(base, "func %v", nil), "func <nil>: base")
+	defer func() {
+		check(size(nil, nil...), -1)
+	}()
+	defer fmt.Sprint(nil...)
+}
+`
+	_, err := ctx.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestOsExit(t *testing.T) {
 	src := `package main
 
@@ -337,3 +465,6335 @@ func main() {
 		t.Fatal(err)
 	}
 }
+
+func TestOpInvoke(t *testing.T) {
+	src := `package main
+
+type I interface{ Get() int }
+
+type A int
+
+func (a A) Get() int { return int(a) }
+
+type B struct{ n int }
+
+func (b *B) Get() int { return b.n * 2 }
+
+func main() {
+	var n int
+	list := []I{A(1), &B{2}, A(3)}
+	for i := 0; i < 3; i++ {
+		for _, v := range list {
+			n += v.Get()
+		}
+	}
+	if n != 24 {
+		panic(n)
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	src := `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func main() {
+	if fib(10) != 55 {
+		panic("bad fib")
+	}
+}
+`
+	ctx := gossa.NewContext(gossa.EnableProfiling)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	prof := interp.Profile()
+	if prof == nil {
+		t.Fatal("no profile")
+	}
+	var found bool
+	for _, f := range prof.Funcs {
+		if f.Func.Name() == "fib" {
+			found = true
+			if f.Calls != 177 {
+				t.Fatalf("fib calls %v, want 177", f.Calls)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("fib not found in profile")
+	}
+	var buf bytes.Buffer
+	if err := prof.WritePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("empty pprof output")
+	}
+}
+
+func TestHotPath(t *testing.T) {
+	src := `package main
+
+func sum(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += i * i
+	}
+	return s
+}
+
+func spawn() {
+	done := make(chan int)
+	go func() { done <- sum(1000) }()
+	<-done
+}
+
+func main() {
+	for i := 0; i < 100; i++ {
+		sum(1000)
+	}
+	spawn()
+}
+`
+	ctx := gossa.NewContext(gossa.EnableProfiling)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	prof := interp.Profile()
+	// a tiny cost model makes any hot function a candidate
+	hots := prof.HotPath(gossa.CostModel{"BinOp": 0.001, "If": 0.001, "Jump": 0.001, "Phi": 0.001})
+	if len(hots) == 0 || hots[0].Func.Name() != "sum" {
+		t.Fatalf("sum is not the hottest function: %v", hots)
+	}
+	if h := hots[0]; !h.Candidate || h.Count == 0 || h.Slowdown < 10 || h.Share < 0.5 {
+		t.Fatalf("bad sum record %+v", h)
+	}
+	if prof.Overhead <= 0 {
+		t.Fatalf("bad timing overhead %v", prof.Overhead)
+	}
+	for _, h := range hots {
+		var self time.Duration
+		for _, r := range h.Instrs {
+			if r.Kind != "Call" && r.Kind != "RunDefers" {
+				self += r.Time
+			}
+		}
+		if self -= prof.Overhead * time.Duration(h.Count); self < 0 {
+			self = 0
+		}
+		if h.Self != self {
+			t.Fatalf("%v: self time %v, want %v less the overhead", h.Func, h.Self, self)
+		}
+	}
+	for _, h := range hots[1:] {
+		if h.Func.Name() == "spawn" && h.Candidate {
+			t.Fatal("spawn starting a goroutine is a candidate")
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := prof.WriteHotPath(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "main.sum") {
+		t.Fatalf("bad hot path report:\n%v", buf.String())
+	}
+}
+
+func TestLogger(t *testing.T) {
+	src := `package main
+
+import "gossa/log"
+
+func main() {
+	log.Info("hello", "n", 1)
+	log.Error("failed", "err", "bad")
+}
+`
+	var records []string
+	ctx := gossa.NewContext(0)
+	ctx.SetLogger(gossa.LoggerFunc(func(level gossa.LogLevel, msg string, keyvals ...interface{}) {
+		records = append(records, fmt.Sprint(level, msg, keyvals))
+	}))
+	_, err := ctx.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0] != "INFOhello[n 1]" || records[1] != "ERRORfailed[err bad]" {
+		t.Fatalf("bad records %q", records)
+	}
+}
+
+func TestDumpInstrLogger(t *testing.T) {
+	src := `package main
+
+func add(x, y int) int {
+	return x + y
+}
+
+func main() {
+	println(add(1, 2))
+}
+`
+	var records []string
+	ctx := gossa.NewContext(gossa.EnableDumpInstr)
+	ctx.SetLogger(gossa.LoggerFunc(func(level gossa.LogLevel, msg string, keyvals ...interface{}) {
+		if level != gossa.LogDebug || len(keyvals) != 2 {
+			t.Errorf("bad record %v %q %v", level, msg, keyvals)
+		}
+		if keyvals[0] == "func" {
+			records = append(records, fmt.Sprint(keyvals[1]))
+			if keyvals[1] == "main.add" && !strings.Contains(msg, "func add(x int, y int) int:") {
+				t.Errorf("bad dump of add %q", msg)
+			}
+		}
+	}))
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, err = ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	os.Stdout = stdout
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Fatalf("dump written to stdout %q", out)
+	}
+	if strings.Join(records, " ") != "main.init main.add main.main" {
+		t.Fatalf("bad dumped funcs %v", records)
+	}
+}
+
+func TestTracingLogger(t *testing.T) {
+	src := `package main
+
+func add(x, y int) int {
+	return x + y
+}
+
+func main() {
+	if add(1, 2) != 3 {
+		panic("bad add")
+	}
+}
+`
+	var calls []string
+	var traced int
+	ctx := gossa.NewContext(gossa.EnableTracing)
+	ctx.SetLogger(gossa.LoggerFunc(func(level gossa.LogLevel, msg string, keyvals ...interface{}) {
+		if len(keyvals) != 6 || keyvals[0] != "func" || keyvals[2] != "goroutine" || keyvals[4] != "pos" {
+			t.Errorf("bad fields %v", keyvals)
+			return
+		}
+		switch level {
+		case gossa.LogTrace:
+			traced++
+		case gossa.LogDebug:
+			if keyvals[1] == "main.add" {
+				calls = append(calls, fmt.Sprint(keyvals[5], " ", msg))
+			}
+		}
+	}))
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if traced == 0 {
+		t.Fatal("no traced instructions")
+	}
+	if len(calls) != 2 || calls[0] != "main.go:3:6 Entering main.add at main.go:3:6." ||
+		calls[1] != "main.go:4:2 Leaving main.add, resuming main.main call add(1:int, 2:int) at main.go:8:8." {
+		t.Fatalf("bad calls %q", calls)
+	}
+}
+
+// currentLabels returns the pprof labels of the calling goroutine, as
+// printed by the goroutine profile.
+func currentLabels() string {
+	var buf bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	for _, rec := range strings.Split(buf.String(), "\n\n") {
+		if !strings.Contains(rec, "runtime/pprof.writeGoroutine") {
+			continue
+		}
+		for _, line := range strings.Split(rec, "\n") {
+			if strings.HasPrefix(line, "# labels: ") {
+				return strings.TrimPrefix(line, "# labels: ")
+			}
+		}
+	}
+	return ""
+}
+
+func TestPprofLabels(t *testing.T) {
+	src := `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func Apply(f func()) int {
+	defer func() {
+		recover()
+	}()
+	f()
+	if fib(10) != 55 {
+		panic("bad fib")
+	}
+	panic("recover")
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(gossa.EnablePprofLabels)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	fn, _ := interp.GetFunc("Apply")
+	apply := fn.(func(func()) int)
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("host", "test")))
+		var during string
+		apply(func() {
+			during = currentLabels()
+		})
+		if want := `{"gossa.func":"main.Apply", "gossa.pos":"main.go:10"}`; during != want {
+			t.Errorf("labels during call %v, want %v", during, want)
+		}
+		if after, want := currentLabels(), `{"host":"test"}`; after != want {
+			t.Errorf("labels after call %v, want %v", after, want)
+		}
+	}()
+	<-done
+}
+
+func TestSliceGrow(t *testing.T) {
+	src := `package main
+
+func main() {
+	s := make([]int, 0, 2)
+	for i := 0; i < 3; i++ {
+		s = append(s, i)
+	}
+	_ = s
+}
+`
+	var infos []*gossa.SliceGrowInfo
+	ctx := gossa.NewContext(0)
+	ctx.SetSliceGrow(func(info *gossa.SliceGrowInfo) {
+		infos = append(infos, info)
+	})
+	_, err := ctx.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("grow events %v, want 1", len(infos))
+	}
+	info := infos[0]
+	if info.OldCap != 2 || info.NewCap <= 2 || info.Elem.Kind() != reflect.Int {
+		t.Fatalf("bad grow info %+v", info)
+	}
+	if pos := info.Position(); pos.Line != 6 {
+		t.Fatalf("bad grow position %v", pos)
+	}
+}
+
+func TestCheckOverflow(t *testing.T) {
+	src := `package main
+
+import "math"
+
+type Score int16
+
+func main() {
+	var a int8 = 127
+	a++
+	var b int64 = math.MinInt64
+	b = b - 1
+	c := Score(300)
+	c *= 200
+	var d int64 = -1
+	d *= math.MinInt64
+	var u uint8 = 255
+	u++
+	x := 1000
+	x = x*x + x - 5
+	if a != -128 || b != math.MaxInt64 || c != -5536 || d != math.MinInt64 || u != 0 || x != 1000995 {
+		panic("bad results")
+	}
+}
+`
+	var infos []*gossa.OverflowInfo
+	ctx := gossa.NewContext(gossa.CheckOverflow)
+	ctx.SetOverflow(func(info *gossa.OverflowInfo) {
+		infos = append(infos, info)
+	})
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"integer overflow: 127 + 1 (int8)",
+		"integer overflow: -9223372036854775808 - 1 (int64)",
+		"integer overflow: 300 * 200 (main.Score)",
+		"integer overflow: -1 * -9223372036854775808 (int64)",
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("overflows %v, want %v", infos, want)
+	}
+	for n, info := range infos {
+		if s := info.String(); s != want[n] {
+			t.Errorf("overflow %v = %v, want %v", n, s, want[n])
+		}
+	}
+	if pos := infos[0].Position(); pos.Line != 9 {
+		t.Fatalf("bad overflow position %v", pos)
+	}
+	if r := reflect.ValueOf(infos[2].Result); r.Type() != infos[2].Type || r.Int() != -5536 {
+		t.Fatalf("bad overflow result %v", infos[2].Result)
+	}
+
+	// without a func an overflow panics
+	_, err := gossa.RunFile("main.go", src, nil, gossa.CheckOverflow)
+	if err == nil || err.Error() != "runtime error: integer overflow: 127 + 1 (int8) at main.go:9:2" {
+		t.Fatalf("bad error %v", err)
+	}
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRuntimePos(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type T struct{ X int }
+
+func try(f func()) (s string) {
+	defer func() {
+		s = fmt.Sprint(recover())
+	}()
+	f()
+	return
+}
+
+func Errors() string {
+	s := []int{1, 2, 3}
+	i := 5
+	var p *T
+	z := 0
+	return strings.Join([]string{
+		try(func() { _ = s[i] }),
+		try(func() { _ = s[1:i] }),
+		try(func() { _ = p.X }),
+		try(func() { _ = 1 / z }),
+	}, "\n")
+}
+
+func Crash(i int) int {
+	s := []int{1, 2, 3}
+	return s[i]
+}
+
+func at(s []int, i int) int {
+	return s[i]
+}
+
+func CrashIn(i int) int {
+	s := []int{1, 2, 3}
+	return at(s, i) + 1
+}
+
+func main() {
+}
+`
+	errs := []string{
+		"runtime error: index out of range [5] with length 3",
+		"runtime error: slice bounds out of range [:5] with capacity 3",
+		"runtime error: invalid memory address or nil pointer dereference",
+		"runtime error: integer divide by zero",
+	}
+	pos := []string{"main.go:24:21", "main.go:25:21", "main.go:26:22", "main.go:27:22"}
+	for _, mode := range []gossa.Mode{0, gossa.EnableRuntimePos} {
+		ctx := gossa.NewContext(mode)
+		pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := append([]string(nil), errs...)
+		crash := "runtime error: index out of range [6] with length 3"
+		if mode == gossa.EnableRuntimePos {
+			for n := range want {
+				want[n] += " at " + pos[n]
+			}
+			crash += " at main.go:33:10"
+		}
+		crashIn := strings.Replace(crash, "33:10", "37:10", 1)
+		if r, err := interp.RunFunc("Errors"); err != nil || r != strings.Join(want, "\n") {
+			t.Fatalf("mode %v: errors %q, %v, want %q", mode, r, err, want)
+		}
+		if _, err := interp.RunFunc("Crash", 6); err == nil || err.Error() != crash {
+			t.Fatalf("mode %v: error %v, want %v", mode, err, crash)
+		}
+		if _, err := interp.RunFunc("CrashIn", 6); err == nil || err.Error() != crashIn {
+			t.Fatalf("mode %v: error %v, want %v", mode, err, crashIn)
+		}
+	}
+}
+
+func TestAppendCopy(t *testing.T) {
+	src := `package main
+
+type Bytes []byte
+
+func main() {
+	b := make([]byte, 3, 10)
+	if n := copy(b, "hello"); n != 3 || string(b) != "hel" {
+		panic(string(b))
+	}
+	b2 := append(b[:1], "ey"...)
+	if string(b) != "hey" || &b2[0] != &b[0] {
+		panic("append in place")
+	}
+	b = append(b, []byte(" there")...)
+	if string(b) != "hey there" {
+		panic(string(b))
+	}
+	if n := copy(b[1:], b); n != 8 || string(b) != "hhey ther" {
+		panic(string(b))
+	}
+	ints := append([]int{1, 2}, []int{3, 4}...)
+	if n := copy(ints, ints[2:]); n != 2 || ints[0] != 3 || ints[1] != 4 {
+		panic(ints)
+	}
+	strs := append([]string(nil), []string{"a", "b"}...)
+	if n := copy(strs, []string{"c"}); n != 1 || strs[0] != "c" || strs[1] != "b" {
+		panic(strs[0])
+	}
+	var nb Bytes
+	nb = append(nb, "named"...)
+	nb = append(nb, Bytes(" bytes")...)
+	if n := copy(nb, "N"); n != 1 || string(nb) != "Named bytes" {
+		panic(string(nb))
+	}
+	f := append([]float64{1}, 2)
+	if n := copy(f, []float64{3}); n != 1 || f[0] != 3 || f[1] != 2 {
+		panic(f)
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFuncAdapters(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/goplus/gossa/adapter"
+)
+
+func main() {
+	var n int
+	r := adapter.ReaderFunc(func(p []byte) (int, error) {
+		if n == 3 {
+			return 0, io.EOF
+		}
+		n++
+		p[0] = 'a'
+		return 1, nil
+	})
+	data, err := ioutil.ReadAll(r)
+	if err != nil || string(data) != "aaa" {
+		panic(fmt.Errorf("bad read %q %v", data, err))
+	}
+	var buf []byte
+	w := adapter.WriterFunc(func(p []byte) (int, error) {
+		buf = append(buf, p...)
+		return len(p), nil
+	})
+	fmt.Fprintf(w, "%v-%v", 1, 2)
+	if string(buf) != "1-2" {
+		panic(fmt.Errorf("bad write %q", buf))
+	}
+	s := fmt.Sprint(adapter.StringerFunc(func() string { return "str" }))
+	if s != "str" {
+		panic(s)
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	src := `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func main() {
+	if fib(5) != 5 {
+		panic("bad fib")
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	interp.SetTracer(&buf)
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("]")
+	var events []struct {
+		Name string
+		Ph   string
+	}
+	if err := json.Unmarshal(buf.Bytes(), &events); err != nil {
+		t.Fatal(err)
+	}
+	var begin, end int
+	for _, e := range events {
+		if e.Name != "main.fib" {
+			continue
+		}
+		switch e.Ph {
+		case "B":
+			begin++
+		case "E":
+			end++
+		}
+	}
+	if begin != 15 || end != 15 {
+		t.Fatalf("fib events %v/%v, want 15/15", begin, end)
+	}
+}
+
+func TestBlockInfos(t *testing.T) {
+	src := `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var elided int
+	for _, fn := range []string{"init", "main"} {
+		pfn, ok := interp.LookupFunction(pkg.Func(fn))
+		if !ok {
+			t.Fatalf("not found function %v", fn)
+		}
+		for _, info := range pfn.BlockInfos() {
+			if len(info.Instrs) != len(info.Block.Instrs) {
+				t.Fatalf("%v block %v: instrs %v, want %v", fn, info.Block.Index, len(info.Instrs), len(info.Block.Instrs))
+			}
+			for _, instr := range info.Instrs {
+				if instr.PC == -1 {
+					if instr.Elided == "" {
+						t.Fatalf("%v: no elided reason of %v", fn, instr.Instr)
+					}
+					elided++
+				} else if pfn.InstrForPC(instr.PC) != instr.Instr {
+					t.Fatalf("%v: bad pc %v of %v", fn, instr.PC, instr.Instr)
+				}
+			}
+		}
+	}
+	if elided == 0 {
+		t.Fatal("fmt.init call not elided")
+	}
+}
+
+func TestFunctionDump(t *testing.T) {
+	src := `package main
+
+var n int
+
+func add(x, y int) int {
+	if x > 0 {
+		n++
+		return x + y
+	}
+	return y
+}
+
+func main() {
+	add(1, 2)
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfn, ok := interp.LookupFunction(pkg.Func("add"))
+	if !ok {
+		t.Fatal("not found function add")
+	}
+	var b bytes.Buffer
+	if err := pfn.Dump(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `main.add func(x int, y int) int
+registers:
+	r0	x	int
+	r1	y	int
+	r2	t0	bool
+	r3	0:int	int	const 0
+	r4	t1	int
+	r5	n	*int	global main.n
+	r6	t2	int
+	r7	1:int	int	const 1
+	r8	t3	int
+0: entry
+	0	t0 = x > 0:int	r2 <- r0 r3
+	1	if t0 goto 1 else 2	r2
+1: if.then
+	2	t1 = *n	r4 <- r5
+	3	t2 = t1 + 1:int	r6 <- r4 r7
+	4	*n = t2	r5 r6
+	5	t3 = x + y	r8 <- r0 r1
+	6	return t3	r8
+2: if.done
+	7	return y	r1
+`
+	if b.String() != want {
+		t.Fatalf("bad dump\n%v", b.String())
+	}
+}
+
+func TestConstFold(t *testing.T) {
+	src := `package main
+
+const debug = false
+
+func div(x, y int) (r int, err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+	return x / y, nil
+}
+
+func main() {
+	n := 3
+	if m := n*4 + 1; m != 13 {
+		panic(m)
+	}
+	if debug {
+		println("debug")
+	}
+	var u uint8 = 200
+	if v := u + 100; v != 44 {
+		panic(v)
+	}
+	var i8 int8 = -128
+	if v := -i8; v != -128 {
+		panic(v)
+	}
+	var mask uint8 = 0x0f
+	if v := ^mask; v != 0xf0 {
+		panic(v)
+	}
+	one := 1
+	if v := one << 70; v != 0 {
+		panic(v)
+	}
+	if v := -7 / 2; v != -3 {
+		panic(v)
+	}
+	s := "con"
+	if s+"st" != "const" {
+		panic(s)
+	}
+	zero := 0
+	if _, err := div(1, zero); err == nil || err.Error() != "runtime error: integer divide by zero" {
+		panic(err)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfn, _ := interp.LookupFunction(pkg.Func("main"))
+	reasons := make(map[string]int)
+	for _, info := range pfn.BlockInfos() {
+		for _, instr := range info.Instrs {
+			reasons[instr.Elided]++
+		}
+	}
+	if reasons["constant folded"] == 0 || reasons["unreachable block"] == 0 {
+		t.Fatalf("bad elided instructions %v", reasons)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInline(t *testing.T) {
+	src := `package main
+
+type Counter int
+
+func (c *Counter) Get() int {
+	return int(*c)
+}
+
+func (c Counter) Pair() (int, int) {
+	return int(c) + 1, int(c)
+}
+
+func add(a, b int) int {
+	return a + b
+}
+
+func fill(s []int, v int) {
+	for i := range s {
+		s[i] = v
+	}
+}
+
+func get(c *Counter) (x int, err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+	return c.Get(), nil
+}
+
+func catch() (r interface{}) {
+	defer func() {
+		r = recover()
+	}()
+	panic("catch")
+}
+
+func main() {
+	c := Counter(1)
+	if x := c.Get(); x != 1 {
+		panic(x)
+	}
+	if x, y := c.Pair(); x != 2 || y != 1 {
+		panic(x)
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		sum = add(sum, add(i, 1))
+	}
+	if sum != 55 {
+		panic(sum)
+	}
+	s := make([]int, 3)
+	fill(s, 7)
+	if s[2] != 7 {
+		panic(s[2])
+	}
+	if _, err := get(nil); err == nil {
+		panic("must nil pointer error")
+	}
+	if r := catch(); r != "catch" {
+		panic(r)
+	}
+}
+`
+	for _, mode := range []gossa.Mode{0, gossa.DisableInline} {
+		_, err := gossa.RunFile("main.go", src, nil, mode)
+		if err != nil {
+			t.Fatalf("mode %v: %v", mode, err)
+		}
+	}
+}
+
+func TestDisableClosureCompiler(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Shape interface {
+	Area() int
+}
+
+type Rect [2]int
+
+func (r Rect) Area() int { return r[0] * r[1] }
+
+type Names []string
+
+const debug = false
+
+func div(x, y int) (q int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return x / y, nil
+}
+
+func main() {
+	if debug {
+		panic("debug")
+	}
+	var sb strings.Builder
+	for i, r := range "héllo" {
+		fmt.Fprint(&sb, i, string(r))
+	}
+	if s := sb.String(); s != "0h1é3l4l5o" {
+		panic(s)
+	}
+	m := map[interface{}]int{1: 1, "a": 2, Rect{1, 2}: 3}
+	m[Rect{1, 2}]++
+	if v, ok := m[Rect{1, 2}]; !ok || v != 4 || m["b"] != 0 {
+		panic(v)
+	}
+	var shapes []Shape
+	for i := 1; i <= 3; i++ {
+		shapes = append(shapes, Rect{i, i + 1})
+	}
+	sum := 0
+	for _, s := range shapes {
+		switch s := s.(type) {
+		case Rect:
+			sum += s.Area()
+		}
+	}
+	if sum != 20 {
+		panic(sum)
+	}
+	names := Names(strings.Fields("b a c"))[1:]
+	if len(names) != 2 || names[0] != "a" {
+		panic(names)
+	}
+	if s := string(rune(0x4e16)) + string([]byte{'o', 'k'}); s != "世ok" {
+		panic(s)
+	}
+	if f := 3.9; int8(f) != 3 || float32(16777217) != 16777216 {
+		panic(f)
+	}
+	if _, err := div(1, 0); err == nil || !strings.Contains(err.Error(), "divide by zero") {
+		panic(err)
+	}
+	ch := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := range ch {
+			sum += v
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case ch <- i:
+		case <-done:
+			panic("done")
+		}
+	}
+	close(ch)
+	<-done
+	if sum != 23 {
+		panic(sum)
+	}
+	counter := 0
+	inc := func() int {
+		counter++
+		return counter
+	}
+	inc()
+	if inc() != 2 || errors.New("x").Error() != "x" {
+		panic(counter)
+	}
+}
+`
+	for _, mode := range []gossa.Mode{0, gossa.DisableClosureCompiler} {
+		_, err := gossa.RunFile("main.go", src, nil, mode)
+		if err != nil {
+			t.Fatalf("mode %v: %v", mode, err)
+		}
+	}
+}
+
+func TestMaxCallDepth(t *testing.T) {
+	src := `package main
+
+import "runtime"
+
+func depth(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return depth(n-1) + 1
+}
+
+func forever(n int) int {
+	return forever(n+1) + 1
+}
+
+func main() {
+	if n := depth(500); n != 500 {
+		panic(n)
+	}
+	defer func() {
+		err, ok := recover().(runtime.Error)
+		if !ok || err.Error() != "runtime error: stack overflow" {
+			panic(err)
+		}
+	}()
+	forever(0)
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.SetMaxCallDepth(1000)
+	_, err := ctx.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the depth continues through the calls of the host into the program
+	src = `package main
+
+import (
+	"runtime"
+	"strings"
+)
+
+func mapper(n int) func(rune) rune {
+	return func(r rune) rune {
+		if n > 0 {
+			strings.Map(mapper(n-1), "a")
+		}
+		return r
+	}
+}
+
+func main() {
+	strings.Map(mapper(100), "a")
+	defer func() {
+		err, ok := recover().(runtime.Error)
+		if !ok || err.Error() != "runtime error: stack overflow" {
+			panic(err)
+		}
+	}()
+	strings.Map(mapper(1000), "a")
+}
+`
+	_, err = ctx.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxSteps(t *testing.T) {
+	src := `package main
+
+var n = count(1000)
+
+func count(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s++
+	}
+	return s
+}
+
+func main() {
+	defer func() {
+		recover()
+	}()
+	for {
+		n++
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.SetMaxSteps(5000)
+	_, err := ctx.RunFile("main.go", src, nil)
+	e, ok := err.(*gossa.TimeoutError)
+	if !ok || e.Steps != 5000 || e.Func.Name() != "main" || e.Pos.Line != 18 {
+		t.Fatalf("bad budget error %v", err)
+	}
+	if err.Error() != "step budget 5000 exceeded: main.main at main.go:18:3" {
+		t.Fatalf("bad budget message %v", err)
+	}
+	ctx.SetMaxSteps(500)
+	_, err = ctx.RunFile("main.go", src, nil)
+	if e, ok := errors.Unwrap(err).(*gossa.TimeoutError); !ok || e.Func.Name() != "count" {
+		t.Fatalf("bad init budget error %v", err)
+	}
+}
+
+func TestMaxStepsPerCall(t *testing.T) {
+	src := `package main
+
+func Count(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s++
+	}
+	return s
+}
+
+func Spin() {
+	go func() {
+		for {
+		}
+	}()
+	select {}
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.SetMaxSteps(1000)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each call has its own budget
+	for n := 0; n < 3; n++ {
+		if r, err := interp.RunFunc("Count", 800); err != nil || r != 800 {
+			t.Fatalf("Count = %v, %v", r, err)
+		}
+	}
+	_, err = interp.RunFuncTimeout(time.Minute, "Count", 2000)
+	if e, ok := err.(*gossa.TimeoutError); !ok || e.Steps != 1000 || e.Func.Name() != "Count" {
+		t.Fatalf("bad timeout budget error %v", err)
+	}
+	// shared by the goroutines of the call
+	_, err = interp.RunFunc("Spin")
+	if e, ok := err.(*gossa.TimeoutError); !ok || e.Steps != 1000 || e.Func.Name() != "Spin$1" {
+		t.Fatalf("bad goroutine budget error %v", err)
+	}
+}
+
+func TestFuzzProgram(t *testing.T) {
+	for _, src := range []string{
+		"package main\n\nfunc main() {\n\tprintln(1 << 3)\n}\n",
+		"package main\n\nfunc main() {\n\tvar s []int\n\tprintln(s[1])\n}\n",
+		"package main\n\nfunc main() {\n\tfor i := 0; ; i++ {\n\t}\n}\n",
+		"package main\n\nfunc f(n int) int {\n\treturn f(n + 1)\n}\n\nfunc main() {\n\tf(0)\n}\n",
+		"package main\n\nfunc main() {\n\tvar x int = \"s\"\n}\n",
+		"package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Exit(1)\n}\n",
+	} {
+		if err := gossa.FuzzProgram([]byte(src)); err != nil {
+			t.Fatalf("%v\n%v", err, src)
+		}
+	}
+}
+
+func TestPanicHandler(t *testing.T) {
+	src := `package main
+
+func index(s []int, i int) int {
+	v := s[i]
+	return v
+}
+
+func try() {
+	defer func() {
+		recover()
+	}()
+	index(nil, 1)
+}
+
+func boom() {
+	panic("boom")
+}
+
+func main() {
+	try()
+	boom()
+}
+`
+	for _, mode := range []gossa.Mode{0, gossa.DisableInline} {
+		testPanicHandler(t, src, mode)
+	}
+}
+
+func testPanicHandler(t *testing.T, src string, mode gossa.Mode) {
+	ctx := gossa.NewContext(mode)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var infos []*gossa.PanicInfo
+	interp.SetPanicHandler(func(info *gossa.PanicInfo) {
+		infos = append(infos, info)
+	})
+	_, err = interp.Run("main")
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("mode %v: bad error %v", mode, err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("mode %v: panics %v, want 2", mode, len(infos))
+	}
+	if _, ok := infos[0].Value.(runtime.Error); !ok || infos[0].Pos.Line != 4 {
+		t.Fatalf("mode %v: bad index panic %v at %v", mode, infos[0].Value, infos[0].Pos)
+	}
+	var funcs []string
+	for _, f := range infos[0].Stack {
+		funcs = append(funcs, f.Func.Name())
+	}
+	if want := []string{"index", "try", "main"}; !reflect.DeepEqual(funcs, want) {
+		t.Fatalf("mode %v: stack %v, want %v", mode, funcs, want)
+	}
+	if infos[1].Value != "boom" || infos[1].Pos.Line != 16 || len(infos[1].Stack) != 2 {
+		t.Fatalf("mode %v: bad panic %v at %v", mode, infos[1].Value, infos[1].Pos)
+	}
+	info, ok := gossa.TargetPanic(err)
+	if !ok || info.Pos.Line != 16 || len(info.Stack) != 2 || info.Stack[0].Func.Name() != "boom" {
+		t.Fatalf("mode %v: bad target panic %v", mode, info)
+	}
+}
+
+func TestExternCallHook(t *testing.T) {
+	src := `package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+func getenv() (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = r.(error).Error()
+		}
+	}()
+	return os.Getenv("HOME")
+}
+
+func main() {
+	var w io.Writer = &bytes.Buffer{}
+	w.Write([]byte(strings.ToUpper("a")))
+	defer strings.Repeat("b", 2)
+	if s := getenv(); s != "denied os.Getenv" {
+		panic(s)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	interp.SetExternCallHook(func(fn string, args []reflect.Value) error {
+		calls = append(calls, fmt.Sprintf("%v%v", fn, len(args)))
+		if fn == "os.Getenv" {
+			return errors.New("denied " + fn)
+		}
+		return nil
+	})
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"strings.ToUpper1", "(*bytes.Buffer).Write2", "os.Getenv1", "(*errors.errorString).Error1", "strings.Repeat2"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls %v, want %v", calls, want)
+	}
+}
+
+func TestProcEnv(t *testing.T) {
+	src := `package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var name = flag.String("name", "", "name")
+
+func run() string {
+	flag.Parse()
+	if err := os.Setenv("GREETING", "hi"); err != nil {
+		panic(err)
+	}
+	data, err := ioutil.ReadAll(must(os.Open("in.txt")))
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir("sub"); err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.WriteFile("out.txt", data, 0644); err != nil {
+		panic(err)
+	}
+	_, home := os.LookupEnv("HOME")
+	return fmt.Sprintf("%v %v %v %v %v %v", *name, len(os.Args), os.ExpandEnv("$GREETING $USER"), strings.HasSuffix(wd, "sub"), home, strings.Join(os.Environ(), ","))
+}
+
+func must(f *os.File, err error) *os.File {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outs []string
+	for _, user := range []string{"a", "b"} {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "in.txt"), []byte(user), 0644); err != nil {
+			t.Fatal(err)
+		}
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp.SetArgs([]string{"main", "-name", user})
+		interp.SetEnv(map[string]string{"USER": user})
+		if err := interp.SetWorkdir(dir); err != nil {
+			t.Fatal(err)
+		}
+		out, err := interp.RunFunc("run")
+		if err != nil {
+			t.Fatal(err)
+		}
+		outs = append(outs, out.(string))
+		if data, err := ioutil.ReadFile(filepath.Join(dir, "sub", "out.txt")); err != nil || string(data) != user {
+			t.Fatalf("bad out.txt %q %v", data, err)
+		}
+	}
+	want := []string{
+		"a 3 hi a true false GREETING=hi,USER=a",
+		"b 3 hi b true false GREETING=hi,USER=b",
+	}
+	if !reflect.DeepEqual(outs, want) {
+		t.Fatalf("got %q, want %q", outs, want)
+	}
+	if _, ok := os.LookupEnv("GREETING"); ok {
+		t.Fatal("host environment changed")
+	}
+}
+
+func TestProcFlags(t *testing.T) {
+	src := `package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	n     = flag.Int("n", 1, "count")
+	out   string
+	usage bool
+)
+
+func main() {
+	flag.Usage = func() {
+		usage = true
+	}
+	flag.CommandLine.SetOutput(ioutil.Discard)
+	flag.Parse()
+	out = fmt.Sprint(*n, flag.Args(), flag.Parsed())
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		args  []string
+		code  int
+		out   string
+		usage bool
+	}{
+		{[]string{"main", "-n", "3", "x"}, 0, "3 [x] true", false},
+		{[]string{"main"}, 0, "1 [] true", false},
+		{[]string{"main", "-bad"}, 2, "", true},
+		{[]string{"main", "-h"}, 0, "", true},
+	} {
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp.SetArgs(test.args)
+		code, err := interp.Run("main")
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, _ := interp.GetVarAddr("out")
+		usage, _ := interp.GetVarAddr("usage")
+		if code != test.code || *out.(*string) != test.out || *usage.(*bool) != test.usage {
+			t.Fatalf("%v: exit %v, out %q, usage %v", test.args, code, *out.(*string), *usage.(*bool))
+		}
+	}
+	if flag.Lookup("n") != nil {
+		t.Fatal("host flags changed")
+	}
+}
+
+func TestRunFileArgs(t *testing.T) {
+	src := `package main
+
+import (
+	"flag"
+	"os"
+)
+
+var (
+	args = os.Args
+	n    = flag.Int("n", 0, "exit code")
+)
+
+func main() {
+	flag.Parse()
+	if len(args) != 3 || args[0] != "main.go" || flag.CommandLine.Name() != "main.go" {
+		panic(args)
+	}
+	os.Exit(*n)
+}
+`
+	args, flags := os.Args, flag.CommandLine
+	var wg sync.WaitGroup
+	codes := make([]int, 8)
+	errs := make([]error, len(codes))
+	for k := range codes {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			ctx := gossa.NewContext(0)
+			codes[k], errs[k] = ctx.RunFile("main.go", src, []string{"-n", strconv.Itoa(k)})
+		}(k)
+	}
+	wg.Wait()
+	for k, code := range codes {
+		if code != k || errs[k] != nil {
+			t.Fatalf("run %v: exit %v, %v", k, code, errs[k])
+		}
+	}
+	if !reflect.DeepEqual(os.Args, args) || flag.CommandLine != flags {
+		t.Fatalf("host args changed to %v", os.Args)
+	}
+}
+
+func TestRunTests(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "calc.go"), []byte(`package calc
+
+func Add(a, b int) int {
+	return a + b
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	args := os.Args
+	if err := gossa.RunTests(dir, "Test[", 0); err == nil || !strings.Contains(err.Error(), "missing closing ]") {
+		t.Fatalf("bad pattern error %v", err)
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	err = gossa.RunTests(dir, "TestAdd", 0)
+	os.Stdout = stdout
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("no tests %v", err)
+	}
+	if !strings.HasPrefix(string(out), "testing: warning: no tests to run\nok\t"+dir) {
+		t.Fatalf("bad output %q", out)
+	}
+	if !reflect.DeepEqual(os.Args, args) {
+		t.Fatalf("host args changed to %v", os.Args)
+	}
+}
+
+func TestCreateTestMainPackage(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"calc.go": `package calc
+
+func Add(a, b int) int {
+	return a + b
+}
+`,
+		"calc_test.go": `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fatal("bad add")
+	}
+}
+
+func TestSub(t *testing.T) {
+	t.Fatal("no sub")
+}
+
+func BenchmarkAdd(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Add(1, 2)
+	}
+}
+`,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := gossa.NewContext(0)
+	pkgs, err := ctx.LoadDir(token.NewFileSet(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests, benchmarks, _, _ := gossa.FindTests(pkgs[0])
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Pos() < tests[j].Pos() })
+	if fmt.Sprint(tests, benchmarks) != "[calc.TestAdd calc.TestSub] [calc.BenchmarkAdd]" {
+		t.Fatalf("FindTests: %v %v", tests, benchmarks)
+	}
+	// the testmain type checks against the testing of the toolchain
+	pkg, err := gossa.CreateTestMainPackage(pkgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deps, ok := interp.GetVarAddr("deps"); ok && reflect.ValueOf(deps).Elem().IsNil() {
+		t.Fatal("deps of testing.MainStart not set")
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func run() string {
+	start := time.Now()
+	var log []string
+	add := func(s string) {
+		log = append(log, fmt.Sprintf("%v@%v", s, time.Since(start)))
+	}
+	time.Sleep(2 * time.Hour)
+	add("sleep")
+	timer := time.NewTimer(time.Hour)
+	stopped := time.NewTimer(time.Minute)
+	if !stopped.Stop() {
+		panic("timer not active")
+	}
+	ticker := time.NewTicker(25 * time.Minute)
+	for n := 0; n < 2; n++ {
+		time.Sleep(25 * time.Minute)
+		<-ticker.C
+		add("tick")
+	}
+	ticker.Stop()
+	time.Sleep(10 * time.Minute)
+	<-timer.C
+	add("timer")
+	done := make(chan bool)
+	time.AfterFunc(time.Minute, func() { done <- true })
+	after := time.After(30 * time.Second)
+	time.Sleep(30 * time.Second)
+	select {
+	case <-done:
+		panic("fired early")
+	case <-after:
+		add("after")
+	}
+	time.Sleep(30 * time.Second)
+	<-done
+	add("func")
+	return fmt.Sprintf("%v %v", log, time.Now().Format(time.Kitchen))
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.SetClock(gossa.NewFakeClock(time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)))
+	start := time.Now()
+	ret, err := interp.RunFunc("run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the timers are fired by the sleeps advancing the clock
+	want := "[sleep@2h0m0s tick@2h25m0s tick@2h50m0s timer@3h0m0s after@3h0m30s func@3h1m0s] 12:01PM"
+	if ret != want {
+		t.Fatalf("got %v, want %v", ret, want)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("fake clock run took %v", d)
+	}
+}
+
+func TestFakeClockAdvance(t *testing.T) {
+	start := time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := gossa.NewFakeClock(start)
+	c.AfterFunc(time.Second, func() {
+		c.Advance(10 * time.Second)
+	})
+	c.Advance(2 * time.Second)
+	if d := c.Now().Sub(start); d != 11*time.Second {
+		t.Fatalf("clock advanced by %v, want 11s", d)
+	}
+}
+
+func TestRandSeed(t *testing.T) {
+	src := `package main
+
+import "math/rand"
+
+func next() int {
+	return rand.Intn(1000)
+}
+
+func perm() []int {
+	rand.Seed(7)
+	return rand.Perm(5)
+}
+
+func read() []byte {
+	rand.Seed(7)
+	p := make([]byte, 10)
+	rand.Read(p[:3])
+	rand.Read(p[3:])
+	return p
+}
+
+func readAll() int {
+	done := make(chan int)
+	for n := 0; n < 4; n++ {
+		go func() {
+			p := make([]byte, 5)
+			for i := 0; i < 100; i++ {
+				rand.Read(p)
+			}
+			done <- 1
+		}()
+	}
+	n := 0
+	for i := 0; i < 4; i++ {
+		n += <-done
+	}
+	return n
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var interps []*gossa.Interp
+	for n := 0; n < 2; n++ {
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp.SetRandSeed(1)
+		interps = append(interps, interp)
+	}
+	// the calls of the interpreters interleaved see their own streams
+	want := rand.New(rand.NewSource(1))
+	for n := 0; n < 5; n++ {
+		w := want.Intn(1000)
+		for _, interp := range interps {
+			if v, err := interp.RunFunc("next"); err != nil || v != w {
+				t.Fatalf("got %v %v, want %v", v, err, w)
+			}
+		}
+	}
+	v, err := interps[0].RunFunc("perm")
+	if w := rand.New(rand.NewSource(7)).Perm(5); err != nil || !reflect.DeepEqual(v, w) {
+		t.Fatalf("got %v %v, want %v", v, err, w)
+	}
+	if v, err := interps[1].RunFunc("next"); err != nil || v != want.Intn(1000) {
+		t.Fatalf("seed of another interpreter changed the stream: %v %v", v, err)
+	}
+	w := make([]byte, 10)
+	rand.New(rand.NewSource(7)).Read(w)
+	if v, err := interps[0].RunFunc("read"); err != nil || !bytes.Equal(v.([]byte), w) {
+		t.Fatalf("got %v %v, want %v", v, err, w)
+	}
+	if v, err := interps[0].RunFunc("readAll"); err != nil || v != 4 {
+		t.Fatalf("got %v %v", v, err)
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type allowDialer map[string]bool
+
+func (d allowDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if !d[address] {
+		return nil, fmt.Errorf("dial %v denied", address)
+	}
+	c, s := net.Pipe()
+	go func() {
+		s.Write([]byte("hello " + address))
+		s.Close()
+	}()
+	return c, nil
+}
+
+func TestNetPolicy(t *testing.T) {
+	src := `package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/smtp"
+	"strings"
+)
+
+func get(url string) string {
+	_, err := http.Get(url)
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+func do(url string) string {
+	req, _ := http.NewRequest("GET", url, nil)
+	_, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+func dial(address string) string {
+	c, err := net.Dial("tcp", address)
+	if err != nil {
+		return err.Error()
+	}
+	defer c.Close()
+	data, _ := ioutil.ReadAll(c)
+	return string(data)
+}
+
+func listen() string {
+	_, err := net.Listen("tcp", "127.0.0.1:0")
+	return err.Error()
+}
+
+func roundTrip(url string) string {
+	req, _ := http.NewRequest("GET", url, nil)
+	var rt http.RoundTripper = &http.Transport{}
+	_, err := rt.RoundTrip(req)
+	f := rt.RoundTrip
+	_, err2 := f(req)
+	return err.Error() + "|" + err2.Error()
+}
+
+func proxy(p *httputil.ReverseProxy, h http.Handler) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/c", nil))
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/d", nil))
+	return w
+}
+
+func testServer() (s string) {
+	defer func() {
+		s = fmt.Sprint(recover())
+	}()
+	httptest.NewServer(nil)
+	return "ok"
+}
+
+func denied() string {
+	var errs []string
+	_, err := tls.Dial("tcp", "example.com:443", nil)
+	errs = append(errs, err.Error())
+	_, err = net.DialTCP("tcp", nil, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80})
+	errs = append(errs, err.Error())
+	_, err = smtp.Dial("example.com:25")
+	errs = append(errs, err.Error())
+	_, err = net.LookupHost("example.com")
+	errs = append(errs, err.Error())
+	_, err = net.DefaultResolver.LookupHost(context.Background(), "example.com")
+	errs = append(errs, err.Error())
+	var d interface {
+		Dial(network, address string) (net.Conn, error)
+	} = &net.Dialer{}
+	_, err = d.Dial("tcp", "example.com:22")
+	errs = append(errs, err.Error())
+	_, err = (&net.ListenConfig{}).Listen(context.Background(), "tcp", "127.0.0.1:0")
+	errs = append(errs, err.Error())
+	err = http.ListenAndServe("127.0.0.1:0", nil)
+	errs = append(errs, err.Error())
+	return strings.Join(errs, "|")
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []string
+	interp.SetHTTPTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.String())
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}))
+	interp.SetDialer(allowDialer{"example.com:80": true})
+	for _, test := range []struct {
+		fn   string
+		arg  []interface{}
+		want string
+	}{
+		{"get", []interface{}{"http://example.com/a"}, "ok"},
+		{"do", []interface{}{"http://example.com/b"}, "ok"},
+		{"dial", []interface{}{"example.com:80"}, "hello example.com:80"},
+		{"dial", []interface{}{"example.com:22"}, "dial example.com:22 denied"},
+		{"listen", nil, "listen tcp: " + gossa.ErrNetDenied.Error()},
+		{"testServer", nil, "httptest: failed to listen on a port: listen tcp: " + gossa.ErrNetDenied.Error()},
+		{"roundTrip", []interface{}{"http://example.com:22/"}, "dial example.com:22 denied|dial example.com:22 denied"},
+		{"denied", nil, "dial example.com:443 denied|dial: " + gossa.ErrNetDenied.Error() +
+			"|dial: " + gossa.ErrNetDenied.Error() + "|lookup: " + gossa.ErrNetDenied.Error() +
+			"|lookup: " + gossa.ErrNetDenied.Error() + "|dial example.com:22 denied|listen tcp: " +
+			gossa.ErrNetDenied.Error() + "|listen tcp: " + gossa.ErrNetDenied.Error()},
+	} {
+		if v, err := interp.RunFunc(test.fn, test.arg...); err != nil || v != test.want {
+			t.Fatalf("%v: got %v %v, want %v", test.fn, v, err, test.want)
+		}
+	}
+	// the reverse proxies served by the program use its transport
+	target := &url.URL{Scheme: "http", Host: "example.com"}
+	h := &httputil.ReverseProxy{Director: func(req *http.Request) {
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+	}}
+	if v, err := interp.RunFunc("proxy", httputil.NewSingleHostReverseProxy(target), h); err != nil || v.(*httptest.ResponseRecorder).Code != 200 {
+		t.Fatalf("proxy: got %v %v", v, err)
+	}
+	if want := []string{"GET http://example.com/a", "GET http://example.com/b",
+		"GET http://example.com/c", "GET http://example.com/d"}; !reflect.DeepEqual(requests, want) {
+		t.Fatalf("got requests %v, want %v", requests, want)
+	}
+}
+
+func TestFuncFormat(t *testing.T) {
+	src := `package main
+
+import "fmt"
+
+type T struct{}
+
+func (T) Name() string { return "T" }
+
+func myFunc() {}
+
+func funcs() string {
+	f := func() {}
+	return fmt.Sprint(myFunc, " ", T{}.Name) + fmt.Sprintf(" %v|%10s", f, "x")
+}
+
+func get() func() {
+	return myFunc
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := interp.RunFunc("funcs")
+	want := "main.myFunc (main.go:9:6) (main.T).Name$bound (main.go:7:10) main.funcs$1 (main.go:12:7)|         x"
+	if err != nil || v != want {
+		t.Fatalf("got %q %v, want %q", v, err, want)
+	}
+	f, err := interp.RunFunc("get")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fn, ok := interp.FuncOf(f); !ok || fn.String() != "main.myFunc" {
+		t.Fatalf("FuncOf: %v %v", fn, ok)
+	}
+	if _, ok := interp.FuncOf(fmt.Println); ok {
+		t.Fatal("FuncOf of a host func")
+	}
+	host := reflect.MakeFunc(reflect.TypeOf(func() {}), func([]reflect.Value) []reflect.Value { return nil })
+	if _, ok := interp.FuncOf(host.Interface()); ok {
+		t.Fatal("FuncOf of a host reflect.MakeFunc func")
+	}
+	other, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := other.FuncOf(f); ok {
+		t.Fatal("FuncOf of a func of another interp")
+	}
+}
+
+func TestFuncCompare(t *testing.T) {
+	src := `package main
+
+type T struct {
+	f func()
+}
+
+func isNil(f func()) bool {
+	return f == nil
+}
+
+func notNil(f func()) bool {
+	return nil != f
+}
+
+func named() {}
+
+func compare(x, y interface{}) (err string) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = e.(error).Error()
+		}
+	}()
+	_ = x == y
+	return "equal"
+}
+
+func hash(k interface{}) (err string) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = e.(error).Error()
+		}
+	}()
+	m := make(map[interface{}]int)
+	m[k] = 1
+	return "hashed"
+}
+
+func main() {
+	var f func()
+	if !isNil(f) || notNil(f) || isNil(named) || !notNil(func() {}) {
+		panic("bad nil comparison")
+	}
+	if compare(named, 1) != "equal" {
+		panic("compare of different types")
+	}
+	if e := compare(named, named); e != "runtime error: comparing uncomparable type func()" {
+		panic(e)
+	}
+	if e := compare(T{}, T{}); e != "runtime error: comparing uncomparable type main.T" {
+		panic(e)
+	}
+	if e := compare([]interface{}{named}, nil); e != "equal" {
+		panic(e)
+	}
+	if e := compare(struct{ x interface{} }{named}, struct{ x interface{} }{named}); e != "runtime error: comparing uncomparable type func()" {
+		panic(e)
+	}
+	if e := hash(T{}); e != "runtime error: hash of unhashable type main.T" {
+		panic(e)
+	}
+	if e := hash(struct{ x interface{} }{named}); e != "runtime error: hash of unhashable type func()" {
+		panic(e)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.RunFunc("main"); err != nil {
+		t.Fatal(err)
+	}
+	// a nil func argument is a nil interface
+	if v, err := interp.RunFunc("isNil", nil); err != nil || v != true {
+		t.Fatalf("isNil(nil): %v %v", v, err)
+	}
+	// comparing non-nil funcs is rejected by the type checker
+	_, err = gossa.NewContext(0).LoadFile(token.NewFileSet(), "main.go", `package main
+
+func main() {
+	f := func() {}
+	_ = f == main
+}
+`)
+	if err == nil || !strings.Contains(err.Error(), "can only be compared to nil") {
+		t.Fatalf("comparing funcs: %v", err)
+	}
+}
+
+func TestStructCompare(t *testing.T) {
+	// the fields and elements are compared in order like gc
+	src := `package main
+
+type T struct {
+	f func()
+}
+
+type I struct {
+	x interface{}
+}
+
+type N struct {
+	_ func()
+	i I
+}
+
+func named() {}
+
+func compare(x, y interface{}) (err string) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = e.(error).Error()
+		}
+	}()
+	if x == y {
+		return "equal"
+	}
+	return "different"
+}
+
+func check(x, y interface{}, want string) {
+	if got := compare(x, y); got != want {
+		panic(got + ", want " + want)
+	}
+}
+
+func main() {
+	check(T{}, T{}, "runtime error: comparing uncomparable type main.T")
+	check(T{}, 1, "different")
+	check(N{}, N{}, "runtime error: comparing uncomparable type main.N")
+	check(I{1}, I{1}, "equal")
+	check(I{1}, I{"1"}, "different")
+	check(I{named}, I{named}, "runtime error: comparing uncomparable type func()")
+	check(I{T{}}, I{T{}}, "runtime error: comparing uncomparable type main.T")
+	check(I{I{[]int{}}}, I{I{[]int{}}}, "runtime error: comparing uncomparable type []int")
+	check(I{I{[]int{}}}, I{I{nil}}, "different")
+	check([2]interface{}{1, named}, [2]interface{}{2, named}, "different")
+	check([2]interface{}{named, 1}, [2]interface{}{named, 2}, "runtime error: comparing uncomparable type func()")
+	check([2]interface{}{map[int]int{}, 1}, [2]interface{}{[]int{}, 1}, "different")
+	check([1]I{{1}}, [1]I{{1}}, "equal")
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValueSemantics(t *testing.T) {
+	// changing a copy of a struct or array does not change the original
+	src := `package main
+
+type Inner struct {
+	A [2]int
+}
+
+type Mid struct {
+	In Inner
+}
+
+type Outer struct {
+	Arr [2]Mid
+}
+
+func (o Outer) Get() int { return o.Arr[0].In.A[0] }
+
+func (o Outer) Mutate() Outer {
+	o.Arr[0].In.A[0] = -1
+	return o
+}
+
+func (o *Outer) Set(v int) { o.Arr[0].In.A[0] = v }
+
+func assert(ok bool, msg string) {
+	if !ok {
+		panic(msg)
+	}
+}
+
+func param(o Outer, g [2][2]int) {
+	o.Arr[0].In.A[0] = 100
+	g[0][0] = 100
+}
+
+func result(o *Outer) Outer {
+	return *o
+}
+
+func newOuter() Outer {
+	return Outer{Arr: [2]Mid{{In: Inner{A: [2]int{1, 2}}}, {In: Inner{A: [2]int{3, 4}}}}}
+}
+
+func main() {
+	o := newOuter()
+	g := [2][2]int{{5, 6}, {7, 8}}
+
+	// assignment
+	c := o
+	c.Arr[0].In.A[0] = 10
+	c.Arr[1].In = Inner{}
+	d := g
+	d[0][1] = 10
+	assert(o.Arr[0].In.A[0] == 1 && o.Arr[1].In.A[1] == 4 && g[0][1] == 6, "assignment")
+	mid := o.Arr[1]
+	mid.In.A[0] = 10
+	assert(o.Arr[1].In.A[0] == 3, "element assignment")
+
+	// parameters and results
+	param(o, g)
+	assert(o.Arr[0].In.A[0] == 1 && g[0][0] == 5, "parameter")
+	r := result(&o)
+	r.Arr[0].In.A[1] = 20
+	assert(o.Arr[0].In.A[1] == 2, "result")
+
+	// receivers
+	m := o.Mutate()
+	assert(o.Arr[0].In.A[0] == 1 && m.Arr[0].In.A[0] == -1, "value receiver")
+	get := o.Get
+	o.Set(30)
+	assert(get() == 1 && o.Get() == 30, "method value")
+	o.Set(1)
+
+	// phi
+	var p Outer
+	for i := 0; i < 2; i++ {
+		if i == 0 {
+			p = o
+		} else {
+			p.Arr[0].In.A[0] = 40
+		}
+	}
+	assert(o.Arr[0].In.A[0] == 1 && p.Arr[0].In.A[0] == 40, "phi")
+
+	// loop variables
+	var saved []Inner
+	for i := 0; i < 2; i++ {
+		var in Inner
+		in.A[0] += i
+		saved = append(saved, in)
+	}
+	assert(saved[0].A[0] == 0 && saved[1].A[0] == 1, "loop")
+
+	// range over an array copies the array
+	arr := [2]Inner{{A: [2]int{1}}, {A: [2]int{2}}}
+	for i, v := range arr {
+		arr[1].A[0] = 50
+		if i == 1 {
+			assert(v.A[0] == 2, "range array")
+		}
+		v.A[1] = 50
+	}
+	assert(arr[0].A[1] == 0, "range value")
+
+	// slices, maps and channels of values
+	s := []Outer{o}
+	e := s[0]
+	e.Arr[0].In.A[0] = 60
+	assert(s[0].Arr[0].In.A[0] == 1, "slice element")
+	s2 := append([]Outer(nil), s...)
+	s2[0].Arr[1].In.A[1] = 60
+	assert(s[0].Arr[1].In.A[1] == 4, "append")
+	s[0].Arr[0].In.A[1] = 60
+	copy(s, s2)
+	s[0].Arr[0].In.A[0] = 60
+	assert(s2[0].Arr[0].In.A[0] == 1 && s[0].Arr[0].In.A[1] == 2, "copy")
+	mp := map[int]Outer{0: o}
+	v := mp[0]
+	v.Arr[0].In.A[0] = 70
+	assert(mp[0].Arr[0].In.A[0] == 1, "map value")
+	mp[1] = v
+	v.Arr[0].In.A[0] = 71
+	assert(mp[1].Arr[0].In.A[0] == 70, "map store")
+	ch := make(chan [2][2]int, 1)
+	ch <- g
+	g[0][0] = 80
+	h := <-ch
+	assert(h[0][0] == 5, "channel")
+	g[0][0] = 5
+
+	// interfaces hold copies
+	var i interface{} = o
+	o.Set(90)
+	assert(i.(Outer).Arr[0].In.A[0] == 1, "interface")
+	j := i.(Outer)
+	j.Arr[0].In.A[0] = 91
+	assert(i.(Outer).Arr[0].In.A[0] == 1, "type assertion")
+	o.Set(1)
+
+	// closures capture variables, not values
+	f := func() int { return o.Arr[0].In.A[0] }
+	o.Set(95)
+	assert(f() == 95, "closure")
+	o.Set(1)
+
+	// go and defer evaluate their arguments when executed
+	done := make(chan int)
+	go func(o Outer) {
+		done <- o.Arr[0].In.A[0]
+	}(o)
+	o.Set(99)
+	assert(<-done == 1, "go")
+	func() {
+		defer func(o Outer) {
+			assert(o.Arr[0].In.A[0] == 99, "defer")
+		}(o)
+		o.Set(1)
+	}()
+
+	// arrays of arrays through pointers
+	pg := &g
+	row := pg[0]
+	row[0] = 110
+	assert(g[0][0] == 5, "array element")
+	pg[1] = row
+	row[1] = 110
+	assert(g[1][1] == 6, "array store")
+	pa := &o.Arr
+	pm := pa[0]
+	pm.In.A[0] = 120
+	assert(o.Arr[0].In.A[0] == 1, "struct element")
+	*pa = [2]Mid{pm}
+	pm.In.A[0] = 121
+	assert(o.Arr[0].In.A[0] == 120, "struct array store")
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStringADD(t *testing.T) {
+	src := `package main
+
+type Name string
+
+func (n Name) Upper() Name {
+	b := []byte(n)
+	for i, c := range b {
+		if c >= 'a' && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return Name(b)
+}
+
+func main() {
+	var s string
+	for i := 0; i < 3; i++ {
+		s += "ab"
+	}
+	if s != "ababab" {
+		panic(s)
+	}
+	var n Name = "go"
+	n += "+"
+	n = n + Name(s[:2])
+	if n != "go+ab" || n.Upper() != "GO+AB" {
+		panic(string(n))
+	}
+	var i interface{} = n + ""
+	if _, ok := i.(Name); !ok {
+		panic("type")
+	}
+	if i.(Name) != n {
+		panic("value")
+	}
+	var e Name
+	if e+e != "" || len(e+n) != 5 {
+		panic("empty")
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkStringConcat(b *testing.B) {
+	for _, typ := range []string{"string", "Name"} {
+		b.Run(typ, func(b *testing.B) {
+			src := `package main
+
+type Name string
+
+func concat(n int) int {
+	var s ` + typ + `
+	for i := 0; i < n; i++ {
+		s += "x"
+		if len(s) > 64 {
+			s = ""
+		}
+	}
+	return len(s)
+}
+`
+			ctx := gossa.NewContext(0)
+			pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+			if err != nil {
+				b.Fatal(err)
+			}
+			interp, err := ctx.NewInterp(pkg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			if _, err := interp.RunFunc("concat", b.N); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestMapFastPath(t *testing.T) {
+	src := `package main
+
+type Key string
+
+func assert(b bool, msg string) {
+	if !b {
+		panic(msg)
+	}
+}
+
+func main() {
+	si := map[string]int{"a": 1}
+	si["b"] += 2
+	assert(si["a"] == 1 && si["b"] == 2 && si["c"] == 0, "map[string]int")
+	ss := map[string]string{}
+	ss["k"] = "v"
+	assert(ss["k"] == "v" && ss["x"] == "", "map[string]string")
+	sb := map[string]bool{"t": true}
+	assert(sb["t"] && !sb["f"], "map[string]bool")
+	se := map[string]interface{}{"n": nil, "i": 1}
+	v, ok := se["n"]
+	assert(v == nil && ok && se["i"] == 1 && len(se) == 2, "map[string]interface{}")
+	_, ok = se["m"]
+	assert(!ok, "map[string]interface{} missing")
+
+	ii := map[int]int{1: 10}
+	ii[2]++
+	assert(ii[1] == 10 && ii[2] == 1 && ii[3] == 0, "map[int]int")
+	is := map[int]string{1: "one"}
+	assert(is[1] == "one" && is[2] == "", "map[int]string")
+	ib := map[int]bool{}
+	ib[3] = true
+	assert(ib[3] && !ib[4], "map[int]bool")
+	ie := map[int]interface{}{}
+	ie[0] = nil
+	ie[1] = "x"
+	_, ok = ie[0]
+	assert(ok && len(ie) == 2 && ie[1] == "x", "map[int]interface{}")
+
+	mk := map[Key]int{"x": 7}
+	mk["y"] = 8
+	assert(mk["x"] == 7 && mk["y"] == 8 && mk["z"] == 0, "named key")
+
+	var nm map[string]int
+	n, ok := nm["a"]
+	assert(n == 0 && !ok && len(nm) == 0, "nil map")
+	var ni map[int]interface{}
+	assert(ni[1] == nil, "nil map interface")
+	defer func() {
+		e := recover().(error)
+		assert(e.Error() == "assignment to entry in nil map", e.Error())
+	}()
+	nm["a"] = 1
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkMapAccess(b *testing.B) {
+	for _, bench := range []struct {
+		key  string
+		keys string
+	}{
+		{"string", `"alpha", "beta", "gamma", "delta"`},
+		{"int", "1, 2, 3, 4"},
+		{"Key", `"alpha", "beta", "gamma", "delta"`},
+	} {
+		b.Run("map["+bench.key+"]int", func(b *testing.B) {
+			src := `package main
+
+type Key string
+
+var keys = []` + bench.key + `{` + bench.keys + `}
+
+func access(n int) int {
+	m := make(map[` + bench.key + `]int)
+	var sum int
+	for i := 0; i < n; i++ {
+		k := keys[i&3]
+		m[k] += i
+		sum += m[k]
+	}
+	return sum
+}
+`
+			ctx := gossa.NewContext(0)
+			pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+			if err != nil {
+				b.Fatal(err)
+			}
+			interp, err := ctx.NewInterp(pkg)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			if _, err := interp.RunFunc("access", b.N); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
+func BenchmarkCall(b *testing.B) {
+	src := `package main
+
+func fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fib(n-1) + fib(n-2)
+}
+
+func ackermann(m, n int) int {
+	if m == 0 {
+		return n + 1
+	}
+	if n == 0 {
+		return ackermann(m-1, 1)
+	}
+	return ackermann(m-1, ackermann(m, n-1))
+}
+
+func sum(n int) int {
+	var s int
+	for i := 0; i < n; i++ {
+		s = add(s, i)
+	}
+	return s
+}
+
+func add(x, y int) int {
+	if x > y {
+		return x + y
+	}
+	return y + x
+}
+`
+	ctx := gossa.NewContext(gossa.DisableInline)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		b.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("fib", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			interp.RunFunc("fib", 15)
+		}
+	})
+	b.Run("ackermann", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			interp.RunFunc("ackermann", 2, 3)
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		interp.RunFunc("sum", b.N)
+	})
+}
+
+func TestFuncValueIdentity(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type T struct{ n int }
+
+func (t T) Get() int { return t.n }
+
+func worker() int { return 1 }
+
+func get() func() int { return worker }
+
+func method() func(T) int { return T.Get }
+
+func word(f func() int) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&f))
+}
+
+func main() {
+	m := map[string]func() int{}
+	m["a"] = worker
+	m["b"] = get()
+	if word(m["a"]) != word(m["b"]) || word(worker) != word(get()) {
+		panic("func identity")
+	}
+	var i interface{} = worker
+	if word(i.(func() int)) != word(worker) {
+		panic("interface identity")
+	}
+	g1, g2 := method(), method()
+	if *(*unsafe.Pointer)(unsafe.Pointer(&g1)) != *(*unsafe.Pointer)(unsafe.Pointer(&g2)) {
+		panic("method identity")
+	}
+	x, y := 1, 2
+	f := func() int { return x }
+	g := func() int { return y }
+	if word(f) == word(g) {
+		panic("closure identity")
+	}
+	if s := fmt.Sprintf("%T %v", worker, worker != nil); s != "func() int true" {
+		panic(s)
+	}
+	if s := fmt.Sprintf("%[1]T %[1]p", worker); s[:len("func() int 0x")] != "func() int 0x" {
+		panic(s)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	f1, _ := interp.GetFunc("worker")
+	f2, _ := interp.GetFunc("worker")
+	if (*[2]unsafe.Pointer)(unsafe.Pointer(&f1))[1] != (*[2]unsafe.Pointer)(unsafe.Pointer(&f2))[1] {
+		t.Fatal("GetFunc returns new func values")
+	}
+}
+
+func TestInterpOptions(t *testing.T) {
+	src := `package main
+
+func div(x, y int) (r int) {
+	defer func() {
+		if recover() != nil {
+			r = -1
+		}
+	}()
+	return x / y
+}
+
+func main() {
+	n := 3
+	println(div(6, n))
+}
+`
+	load := func(ctx *gossa.Context) *ssa.Package {
+		pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg
+	}
+
+	var trace bytes.Buffer
+	ctx := gossa.NewContext(0)
+	interp, err := ctx.NewInterp(load(ctx), gossa.WithTracing(&trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	if s := trace.String(); !strings.Contains(s, "Entering main.div") || !strings.Contains(s, "Leaving main.div") {
+		t.Fatalf("trace:\n%v", s)
+	}
+	if _, err := ctx.NewInterp(load(ctx), gossa.WithTracing(nil)); err == nil {
+		t.Fatal("tracing to nil writer")
+	}
+	if _, err := ctx.NewInterp(load(ctx), gossa.WithMode(gossa.EnableDumpInstr)); err == nil {
+		t.Fatal("EnableDumpInstr is a mode of the context")
+	}
+
+	interp, err = ctx.NewInterp(load(ctx), gossa.WithRecoverDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("recovered by the program")
+			}
+		}()
+		interp.RunFunc("div", 1, 0)
+	}()
+
+	if _, err := ctx.NewInterp(load(ctx), gossa.WithDebugger(func(*gossa.DebugInfo) {})); err == nil {
+		t.Fatal("debugger of program without debug information")
+	}
+	ctx = gossa.NewContext(0)
+	ctx.SetDebug(func(*gossa.DebugInfo) {
+		t.Fatal("debug func of the context")
+	})
+	var vars []string
+	interp, err = ctx.NewInterp(load(ctx), gossa.WithDebugger(func(info *gossa.DebugInfo) {
+		if v, _, ok := info.AsVar(); ok {
+			vars = append(vars, v.Name())
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.Join(vars, " "); !strings.Contains(s, "n") || !strings.Contains(s, "x") {
+		t.Fatalf("debugged vars %v", s)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	src := `package main
+
+type Point struct {
+	X, Y int
+}
+
+type Shape struct {
+	Name   string
+	Points []Point
+	Center *Point
+	Attrs  map[string]float64
+	id     int
+}
+
+func NewShape(name string) Shape {
+	return Shape{
+		Name:   name,
+		Points: []Point{{1, 2}, {3, 4}},
+		Center: &Point{2, 3},
+		Attrs:  map[string]float64{"area": 1.5},
+		id:     7,
+	}
+}
+
+func Move(p Point, dx int) Point {
+	p.X += dx
+	return p
+}
+
+func main() {
+}
+`
+	type point struct {
+		X, Y int
+	}
+	type shape struct {
+		Name   string
+		Points []point
+		Center *point
+		Attrs  map[string]float32
+		ID     int `gossa:"id"`
+		Extra  string
+	}
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := interp.RunFunc("NewShape", "box")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s shape
+	if err := gossa.Unmarshal(r, &s); err != nil {
+		t.Fatal(err)
+	}
+	want := shape{"box", []point{{1, 2}, {3, 4}}, &point{2, 3}, map[string]float32{"area": 1.5}, 7, ""}
+	if !reflect.DeepEqual(s, want) {
+		t.Fatalf("unmarshal %+v, want %+v", s, want)
+	}
+	typ, ok := interp.GetType("Point")
+	if !ok {
+		t.Fatal("not found type Point")
+	}
+	arg, err := gossa.Marshal(point{1, 2}, typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = interp.RunFunc("Move", arg, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p point
+	if err := gossa.Unmarshal(r, &p); err != nil || p != (point{11, 2}) {
+		t.Fatalf("Move = %v, %v", p, err)
+	}
+	var bad struct{ Name int }
+	if err := gossa.Unmarshal(r, &bad); err == nil {
+		t.Fatal("must type error")
+	}
+	if err := gossa.Unmarshal(r, p); err == nil {
+		t.Fatal("must pointer error")
+	}
+}
+
+func TestMarshalCycle(t *testing.T) {
+	src := `package main
+
+type Node struct {
+	Name string
+	Next *Node
+	List List
+}
+
+type List []List
+
+func main() {
+}
+`
+	type list []list
+	type node struct {
+		Name string
+		Next *node
+		List list
+	}
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, ok := interp.GetType("Node")
+	if !ok {
+		t.Fatal("not found type Node")
+	}
+	a := &node{Name: "a"}
+	b := &node{Name: "b", Next: a}
+	a.Next = b
+	a.List = make(list, 1)
+	a.List[0] = a.List
+	v, err := gossa.Marshal(a, reflect.PtrTo(typ))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out *node
+	if err := gossa.Unmarshal(v, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out == a || out.Name != "a" || out.Next.Name != "b" || out.Next.Next != out {
+		t.Fatalf("bad pointer cycle %+v", out)
+	}
+	if len(out.List) != 1 || &out.List[0][0] != &out.List[0] || &out.List[0] == &a.List[0] {
+		t.Fatal("bad slice cycle")
+	}
+	type tree map[string]tree
+	type hostTree map[string]hostTree
+	tr := make(tree)
+	tr["t"] = tr
+	var htr hostTree
+	if err := gossa.Unmarshal(tr, &htr); err != nil {
+		t.Fatal(err)
+	}
+	if reflect.ValueOf(htr["t"]).Pointer() != reflect.ValueOf(htr).Pointer() {
+		t.Fatal("bad map cycle")
+	}
+}
+
+func TestStrictPanicSeparation(t *testing.T) {
+	src := `package main
+
+func div(a, b int) (n int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	return a / b, nil
+}
+
+func main() {
+	if _, err := div(1, 0); err == nil {
+		panic("must runtime error")
+	}
+	panic("target")
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, gossa.StrictPanicSeparation)
+	if err == nil || err.Error() != "target" {
+		t.Fatalf("bad error %v", err)
+	}
+	if _, ok := err.(*gossa.InterpInternalError); ok {
+		t.Fatal("target panic reported as internal error")
+	}
+}
+
+func TestRunFuncArgs(t *testing.T) {
+	src := `package main
+
+type Int int
+
+func Add(a Int, b int) Int {
+	return a + Int(b)
+}
+
+func Len(s []int) int {
+	return len(s)
+}
+
+func Sum(base int, s ...int) int {
+	for _, v := range s {
+		base += v
+	}
+	return base
+}
+
+func Count(s ...interface{}) int {
+	return len(s)
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := interp.RunFunc("Add", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := reflect.ValueOf(r); v.Int() != 3 {
+		t.Fatalf("Add = %v, want 3", r)
+	}
+	if r, err := interp.RunFunc("Len", nil); err != nil || r != 0 {
+		t.Fatalf("Len(nil) = %v, %v", r, err)
+	}
+	if _, err := interp.RunFunc("Add", 1); err == nil {
+		t.Fatal("must arity error")
+	}
+	if _, err := interp.RunFunc("Add", 1, "2"); err == nil || err.Error() != "main.Add: cannot use string as int value in argument b" {
+		t.Fatalf("bad type error %v", err)
+	}
+	if _, err := interp.RunFunc("Add", nil, 2); err == nil {
+		t.Fatal("must nil error")
+	}
+	for _, test := range []struct {
+		name string
+		args []gossa.Value
+		want int
+	}{
+		{"Sum", []gossa.Value{1}, 1},
+		{"Sum", []gossa.Value{1, 2, 3}, 6},
+		{"Sum", []gossa.Value{1, []int{2, 3}}, 6},
+		{"Sum", []gossa.Value{1, nil}, 1},
+		{"Count", nil, 0},
+		{"Count", []gossa.Value{1, "a", nil}, 3},
+		{"Count", []gossa.Value{[]interface{}{1, 2}}, 2},
+		{"Count", []gossa.Value{[]int{1, 2}}, 1},
+	} {
+		if r, err := interp.RunFunc(test.name, test.args...); err != nil || r != test.want {
+			t.Fatalf("%v%v = %v, %v, want %v", test.name, test.args, r, err, test.want)
+		}
+	}
+	if _, err := interp.RunFunc("Sum"); err == nil || err.Error() != "main.Sum: got 0 arguments, want at least 1" {
+		t.Fatalf("bad variadic arity error %v", err)
+	}
+	if _, err := interp.RunFunc("Sum", 1, 2, "3"); err == nil || err.Error() != "main.Sum: cannot use string as int value in argument s" {
+		t.Fatalf("bad variadic type error %v", err)
+	}
+}
+
+func TestRunFuncTuple(t *testing.T) {
+	src := `package main
+
+import "errors"
+
+type Int int
+
+func Div(a, b int) (q Int, m int, err error) {
+	if b == 0 {
+		return 0, 0, errors.New("division by zero")
+	}
+	return Int(a / b), a % b, nil
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := interp.RunFunc("Div", 7, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, ok := r.(gossa.Tuple)
+	if !ok || res.Len() != 3 {
+		t.Fatalf("Div = %#v, want 3 results", r)
+	}
+	if v := res.Get(1); v != 1 {
+		t.Fatalf("Get(1) = %v, want 1", v)
+	}
+	if err := res.Err(); err != nil {
+		t.Fatalf("Err = %v", err)
+	}
+	var q int64
+	var m int
+	if err := res.Scan(&q, &m, nil); err != nil || q != 3 || m != 1 {
+		t.Fatalf("Scan = %v %v, %v", q, m, err)
+	}
+	if err := res.Scan(&q, &m); err == nil || err.Error() != "scan: 3 results into 2 values" {
+		t.Fatalf("bad count error %v", err)
+	}
+	if err := res.Scan(q, &m, nil); err == nil || err.Error() != "scan: non-pointer int64 for result 0" {
+		t.Fatalf("bad pointer error %v", err)
+	}
+	var s string
+	if err := res.Scan(&q, &s, nil); err == nil || err.Error() != "scan: result 1: cannot use int as string value" {
+		t.Fatalf("bad type error %v", err)
+	}
+
+	r, err = interp.RunFunc("Div", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.(gossa.Tuple).Err(); err == nil || err.Error() != "division by zero" {
+		t.Fatalf("Err = %v", err)
+	}
+	var e error
+	if err := r.(gossa.Tuple).Scan(nil, nil, &e); err != nil || e == nil {
+		t.Fatalf("Scan error = %v, %v", e, err)
+	}
+}
+
+func TestValueOf(t *testing.T) {
+	src := `package main
+
+type Point struct {
+	X, Y int
+}
+
+type Level int32
+
+func Scale(p *Point, f int32) int32 {
+	if p == nil {
+		return -f
+	}
+	return f
+}
+
+func Sum(a int8, b float32, c uint) float64 {
+	return float64(a) + float64(b) + float64(c)
+}
+
+func Up(l Level) Level {
+	return l + 1
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err := interp.RunFunc("Scale", nil, 2); err != nil || r != int32(-2) {
+		t.Fatalf("Scale(nil) = %v, %v", r, err)
+	}
+	point, _ := interp.GetType("Point")
+	if r, err := interp.RunFunc("Scale", reflect.New(point).Interface(), int64(3)); err != nil || r != int32(3) {
+		t.Fatalf("Scale = %v, %v", r, err)
+	}
+	p := gossa.Nil(reflect.PtrTo(point))
+	if v := reflect.ValueOf(p); v.Type() != reflect.PtrTo(point) || !v.IsNil() {
+		t.Fatalf("Nil = %#v", p)
+	}
+	if r, err := interp.RunFunc("Scale", p, 4); err != nil || r != int32(-4) {
+		t.Fatalf("Scale(Nil) = %v, %v", r, err)
+	}
+	if r, err := interp.RunFunc("Sum", 1, 2.5, 3); err != nil || r != 6.5 {
+		t.Fatalf("Sum = %v, %v", r, err)
+	}
+	if _, err := interp.RunFunc("Sum", 300, 0, 0); err == nil || err.Error() != "main.Sum: cannot use int value 300 as int8 value (overflows or truncated) in argument a" {
+		t.Fatalf("bad overflow error %v", err)
+	}
+	if _, err := interp.RunFunc("Sum", 0, 0, -1); err == nil || err.Error() != "main.Sum: cannot use int value -1 as uint value (overflows or truncated) in argument c" {
+		t.Fatalf("bad sign error %v", err)
+	}
+	if _, err := interp.RunFunc("Sum", 1.5, 0, 0); err == nil || err.Error() != "main.Sum: cannot use float64 value 1.5 as int8 value (overflows or truncated) in argument a" {
+		t.Fatalf("bad truncation error %v", err)
+	}
+	if _, err := interp.RunFunc("Scale", nil, "2"); err == nil || err.Error() != "main.Scale: cannot use string as int32 value in argument f" {
+		t.Fatalf("bad type error %v", err)
+	}
+
+	level, _ := interp.GetType("Level")
+	l, err := gossa.ValueOf(1, level)
+	if err != nil || reflect.TypeOf(l) != level {
+		t.Fatalf("ValueOf = %#v, %v", l, err)
+	}
+	if r, err := interp.RunFunc("Up", l); err != nil || reflect.ValueOf(r).Int() != 2 {
+		t.Fatalf("Up = %v, %v", r, err)
+	}
+	if _, err := gossa.ValueOf(nil, level); err == nil || err.Error() != "cannot use nil as main.Level value" {
+		t.Fatalf("bad nil error %v", err)
+	}
+	if v, err := gossa.ValueOf(nil, reflect.TypeOf((*error)(nil)).Elem()); err != nil || v != nil {
+		t.Fatalf("ValueOf(nil) = %v, %v", v, err)
+	}
+}
+
+func TestGetMethod(t *testing.T) {
+	src := `package main
+
+type Counter int
+
+func (c *Counter) Add(n int) int {
+	*c += Counter(n)
+	return int(*c)
+}
+
+func (c Counter) Double() int {
+	return int(c) * 2
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ, _ := interp.GetType("Counter")
+	ptr := reflect.PtrTo(typ)
+	add, ok := interp.GetMethod(ptr, "Add")
+	if !ok {
+		t.Fatal("not found method (*Counter).Add")
+	}
+	c := reflect.New(typ)
+	if r := reflect.ValueOf(add).Call([]reflect.Value{c, reflect.ValueOf(3)}); r[0].Int() != 3 || c.Elem().Int() != 3 {
+		t.Fatalf("Add = %v, counter %v", r[0], c.Elem())
+	}
+	double, ok := interp.GetMethod(typ, "Double")
+	if !ok {
+		t.Fatal("not found method Counter.Double")
+	}
+	if r := reflect.ValueOf(double).Call([]reflect.Value{c.Elem()}); r[0].Int() != 6 {
+		t.Fatalf("Double = %v", r[0])
+	}
+	if _, ok := interp.GetMethod(ptr, "Double"); !ok {
+		t.Fatal("not found method (*Counter).Double")
+	}
+	if _, ok := interp.GetMethod(typ, "Add"); ok {
+		t.Fatal("found pointer method Add of Counter")
+	}
+	if _, ok := interp.GetMethod(reflect.TypeOf(0), "Add"); ok {
+		t.Fatal("found method of host type")
+	}
+}
+
+func TestGetMethodConcurrent(t *testing.T) {
+	var buf strings.Builder
+	buf.WriteString("package main\n\n")
+	for n := 0; n < 50; n++ {
+		fmt.Fprintf(&buf, "type T%v int\n\nfunc (t T%v) Get() int { return int(t) }\n\n", n, n)
+	}
+	buf.WriteString(`type Getter interface {
+	Get() int
+}
+
+var g Getter = T0(1)
+
+func Loop(n int) int {
+	var s int
+	for i := 0; i < n; i++ {
+		s += g.Get()
+	}
+	return s
+}
+
+func main() {
+}
+`)
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if r, err := interp.RunFunc("Loop", 100); err != nil || r != 100 {
+				t.Errorf("Loop = %v, %v", r, err)
+				return
+			}
+		}
+	}()
+	// the wrappers (*Tn).Get are compiled on first use, while Loop runs
+	for n := 0; n < 50; n++ {
+		typ, _ := interp.GetType(fmt.Sprintf("T%v", n))
+		if _, ok := interp.GetMethod(reflect.PtrTo(typ), "Get"); !ok {
+			t.Errorf("not found method (*T%v).Get", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	<-done
+}
+
+func TestSymbols(t *testing.T) {
+	src := `package main
+
+const Version = "1.0"
+
+type Point struct {
+	X, Y int
+}
+
+var Origin Point
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func helper() {
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []string
+	for _, sym := range interp.Symbols("main") {
+		list = append(list, fmt.Sprintf("%v %v %v %v %v", sym.Kind, sym.Name, sym.Type, sym.RType.Kind(), sym.Pos.Line))
+	}
+	want := []string{
+		"func Add func(a int, b int) int func 11",
+		"var Origin main.Point struct 9",
+		"type Point main.Point struct 5",
+		"const Version string string 3",
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Fatalf("symbols %q, want %q", list, want)
+	}
+	if syms := interp.Symbols("strings"); syms != nil {
+		t.Fatalf("symbols of extern package %v", syms)
+	}
+}
+
+func TestBind(t *testing.T) {
+	src := `package main
+
+import "errors"
+
+type Celsius float64
+
+func ToFahrenheit(c Celsius) (float64, error) {
+	if c < -273.15 {
+		return 0, errors.New("below absolute zero")
+	}
+	return float64(c*9/5 + 32), nil
+}
+
+func Join(sep string, s ...string) string {
+	var r string
+	for i, v := range s {
+		if i > 0 {
+			r += sep
+		}
+		r += v
+	}
+	return r
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var convert func(float64) (float64, error)
+	if err := interp.Bind("ToFahrenheit", &convert); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := convert(100); err != nil || f != 212 {
+		t.Fatalf("convert = %v, %v", f, err)
+	}
+	if _, err := convert(-300); err == nil || err.Error() != "below absolute zero" {
+		t.Fatalf("bad error %v", err)
+	}
+	var join func(string, ...string) string
+	if err := interp.Bind("Join", &join); err != nil {
+		t.Fatal(err)
+	}
+	if s := join(",", "a", "b"); s != "a,b" {
+		t.Fatalf("join = %v", s)
+	}
+	var bad func(string) (float64, error)
+	if err := interp.Bind("ToFahrenheit", &bad); err == nil || err.Error() != "bind main.ToFahrenheit: cannot use string as main.Celsius value in argument c" {
+		t.Fatalf("bad bind error %v", err)
+	}
+	if err := interp.Bind("Missing", &bad); err == nil {
+		t.Fatal("must no function error")
+	}
+	if err := interp.Bind("Join", join); err == nil {
+		t.Fatal("must func pointer error")
+	}
+}
+
+func TestRunFuncTimeout(t *testing.T) {
+	src := `package main
+
+func Spin() (n int) {
+	defer func() {
+		recover()
+	}()
+	for {
+		n++
+	}
+}
+
+func wait(c chan int) int {
+	for v := range c {
+		return v
+	}
+	return 0
+}
+
+func Wait() int {
+	return wait(make(chan int))
+}
+
+func Add(a, b int) int {
+	return a + b
+}
+
+var done bool
+
+func Poll() {
+	for !done {
+	}
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err := interp.RunFuncTimeout(time.Second, "Add", 1, 2); err != nil || r != 3 {
+		t.Fatalf("Add = %v, %v", r, err)
+	}
+	_, err = interp.RunFuncTimeout(50*time.Millisecond, "Spin")
+	if e, ok := err.(*gossa.TimeoutError); !ok || e.Blocked || e.Func.Name() != "Spin" || e.Pos.Line != 8 {
+		t.Fatalf("bad spin timeout %v", err)
+	}
+	_, err = interp.RunFuncTimeout(50*time.Millisecond, "Wait")
+	if e, ok := err.(*gossa.TimeoutError); !ok || !e.Blocked || e.Func.Name() != "wait" {
+		t.Fatalf("bad wait timeout %v", err)
+	}
+	_, err = interp.RunFuncTimeout(50*time.Millisecond, "Poll")
+	if e, ok := err.(*gossa.TimeoutError); !ok || e.Blocked || e.Func.Name() != "Poll" {
+		t.Fatalf("bad poll timeout %v", err)
+	}
+}
+
+func TestSnapshotGlobals(t *testing.T) {
+	src := `package main
+
+import "strings"
+
+type state struct {
+	count int
+	names []string
+}
+
+var (
+	n     int
+	s     = new(state)
+	attrs = make(map[string]*int)
+	total = &n
+	arr   [4]int
+	elem  = &arr[1]
+	part  = arr[2:]
+	log   = new(strings.Builder)
+)
+
+func Action(name string, fail bool) int {
+	n++
+	s.count++
+	s.names = append(s.names, name)
+	attrs[name] = total
+	log.WriteString(name)
+	if fail {
+		panic("failed " + name)
+	}
+	return n
+}
+
+func Check() bool {
+	log.WriteString("check")
+	return total == &n && s.count == n && len(s.names) == n && len(attrs) == n && attrs["a"] == total &&
+		elem == &arr[1] && &part[0] == &arr[2]
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.RunFunc("Action", "a", false); err != nil {
+		t.Fatal(err)
+	}
+	snap := interp.SnapshotGlobals()
+	for i := 0; i < 2; i++ {
+		if _, err := interp.RunFunc("Action", "b", true); err == nil {
+			t.Fatal("must fail")
+		}
+		if err := interp.RestoreGlobals(snap); err != nil {
+			t.Fatal(err)
+		}
+		if r, err := interp.RunFunc("Check"); err != nil || r != true {
+			t.Fatalf("Check = %v, %v", r, err)
+		}
+	}
+	if r, err := interp.RunFunc("Action", "c", false); err != nil || r != 2 {
+		t.Fatalf("Action = %v, %v", r, err)
+	}
+	if r, err := interp.RunFunc("Check"); err != nil || r != true {
+		t.Fatalf("Check = %v, %v", r, err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	src := `package main
+
+var (
+	count int
+	names []string
+	ratio int32 = 2
+)
+
+func Add(name string) int {
+	count++
+	names = append(names, name)
+	return count
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.RunFunc("Add", "a")
+	interp.RunFunc("Add", "b")
+
+	// bad init keeps the old program
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", strings.Replace(src, "func main() {", "func init() {\n\tcount = 100\n\tpanic(\"bad\")\n}\n\nfunc main() {", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := interp.Reload(pkg); err == nil || err.Error() != "init error: bad" {
+		t.Fatalf("bad reload error %v", err)
+	}
+	if r, err := interp.RunFunc("Add", "c"); err != nil || r != 3 {
+		t.Fatalf("Add = %v, %v", r, err)
+	}
+
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+var (
+	count int
+	names []string
+	ratio int64 = 3
+	total = 10
+)
+
+func Add(name string) int {
+	count += 10
+	names = append(names, name)
+	return count + len(names) + int(ratio) + total
+}
+
+func main() {
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := interp.Reload(pkg); err != nil {
+		t.Fatal(err)
+	}
+	// count 13, 4 names, ratio converted from 2, total initialized
+	if r, err := interp.RunFunc("Add", "d"); err != nil || r != 13+4+2+10 {
+		t.Fatalf("Add = %v, %v", r, err)
+	}
+}
+
+func TestUnsafePointerConv(t *testing.T) {
+	src := `package main
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+func assert(ok bool, msg string) {
+	if !ok {
+		panic(msg)
+	}
+}
+
+func main() {
+	assert(unsafe.Sizeof(uintptr(0)) == reflect.TypeOf(uintptr(0)).Size(), "sizeof uintptr")
+	assert(unsafe.Sizeof(int64(0)) == 8 && unsafe.Alignof(int64(0)) == uintptr(reflect.TypeOf(int64(0)).Align()), "int64")
+	var arr [4]int32
+	assert(unsafe.Sizeof(arr) == reflect.TypeOf(arr).Size(), "sizeof array")
+	assert(unsafe.Alignof(arr) == uintptr(reflect.TypeOf(arr).Align()), "alignof array")
+	var s []string
+	assert(unsafe.Sizeof(s) == reflect.TypeOf(s).Size(), "sizeof slice")
+
+	for i := range arr {
+		arr[i] = int32(i + 1)
+	}
+	// uintptr round trip
+	p := (*int32)(unsafe.Pointer(uintptr(unsafe.Pointer(&arr[0])) + 2*unsafe.Sizeof(arr[0])))
+	assert(*p == 3, "uintptr arith")
+	*p = 30
+	assert(arr[2] == 30, "uintptr store")
+	pa := (*[4]int32)(unsafe.Pointer(&arr[0]))
+	assert(pa == &arr, "array pointer")
+
+	f := 1.5
+	bits := *(*uint64)(unsafe.Pointer(&f))
+	assert(bits == 0x3ff8000000000000, "float bits")
+	*(*uint64)(unsafe.Pointer(&f)) = 0x4000000000000000
+	assert(f == 2, "float store")
+
+	b := [8]byte{1}
+	n := *(*uint8)(unsafe.Pointer(&b))
+	assert(n == 1, "byte pointer")
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoroutineName(t *testing.T) {
+	src := `package main
+
+import "gossa/goroutine"
+
+func worker(ch chan string) {
+	goroutine.SetLabel("job", "1")
+	ch <- goroutine.Name()
+}
+
+func main() {
+	goroutine.SetName("main")
+	if goroutine.Name() != "main" {
+		panic("bad main name")
+	}
+	ch := make(chan string)
+	go worker(ch)
+	if name := <-ch; name != "worker main.worker" {
+		panic("bad worker name: " + name)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.SetGoroutineSpawn(func(info *gossa.GoroutineInfo) {
+		info.Name = "worker " + info.Func
+	})
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, g := range interp.Goroutines() {
+		if g.Name == "main" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("not found main goroutine")
+	}
+}
+
+func TestMethodValue(t *testing.T) {
+	src := `package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+type Rot int
+
+func (r Rot) Map(c rune) rune {
+	return c + rune(r)
+}
+
+type I interface {
+	Map(c rune) rune
+}
+
+func main() {
+	var buf bytes.Buffer
+	write := buf.WriteString
+	for i := 0; i < 3; i++ {
+		write("ab")
+	}
+	if buf.String() != "ababab" {
+		panic("bad extern method value: " + buf.String())
+	}
+	var r Rot = 1
+	if s := strings.Map(r.Map, "abc"); s != "bcd" {
+		panic("bad method value: " + s)
+	}
+	var i I = Rot(2)
+	if s := strings.Map(i.Map, "abc"); s != "cde" {
+		panic("bad interface method value: " + s)
+	}
+	f := Rot.Map
+	if f(3, 'a') != 'd' {
+		panic("bad method expression")
+	}
+	g := (*bytes.Buffer).Len
+	if g(&buf) != 6 {
+		panic("bad extern method expression")
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunResult(t *testing.T) {
+	src := `package main
+
+func fail(msg string) {
+	panic(msg)
+}
+
+func main() {
+	ch := make(chan int)
+	go func() {
+		<-ch
+	}()
+	fail("failed")
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := interp.RunResult("main")
+	if r.Err == nil || r.Panic != "failed" {
+		t.Fatalf("bad result panic %v, err %v", r.Panic, r.Err)
+	}
+	if len(r.Stack) != 2 || r.Stack[0].Func.Name() != "fail" || r.Stack[0].Pos.Line != 4 ||
+		r.Stack[1].Func.Name() != "main" || r.Stack[1].Pos.Line != 12 {
+		t.Fatalf("bad result stack %v", r.Stack)
+	}
+	if r.Goroutines != 1 {
+		t.Fatalf("bad result goroutines %v", r.Goroutines)
+	}
+}
+
+func TestPrintFloat(t *testing.T) {
+	src := `package main
+
+import "math"
+
+type F float64
+
+func main() {
+	println(1.0, -2.5, float32(0.1), 0.0, math.Inf(1), math.Inf(-1), math.NaN(), 1e100, 123456789.0, F(3))
+	println(complex(1, -2), complex64(complex(0.5, 0)))
+	println(true, false, 1, -3, uint8(7), "s")
+}
+`
+	var buf bytes.Buffer
+	gossa.CapturedOutput = &buf
+	defer func() {
+		gossa.CapturedOutput = nil
+	}()
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := printFloatOutput + "true false 1 -3 7 s\n"
+	if buf.String() != want {
+		t.Fatalf("print output:\n%v\nwant:\n%v", buf.String(), want)
+	}
+}
+
+func TestPrintValues(t *testing.T) {
+	src := `package main
+
+type S string
+
+func (s S) String() string { return "S" }
+
+type N int
+
+func (n N) String() string { return "N" }
+
+type B bool
+
+func (b B) String() string { return "B" }
+
+func main() {
+	var p *int
+	var m map[int]int
+	var s []int
+	var f func()
+	var c chan int
+	println(S("s"), N(-2), B(true), uintptr(12), 'a')
+	println(p, m, s, f, c)
+	println(make([]int, 2, 5), new(int), make(map[int]int), make(chan int), main)
+}
+`
+	var buf bytes.Buffer
+	gossa.CapturedOutput = &buf
+	defer func() {
+		gossa.CapturedOutput = nil
+	}()
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := regexp.MustCompile(`^s -2 true 12 97
+0x0 0x0 \[0/0\]0x0 0x0 0x0
+\[2/5\]0x[0-9a-f]+ 0x[0-9a-f]+ 0x[0-9a-f]+ 0x[0-9a-f]+ 0x[0-9a-f]+
+$`)
+	if !want.MatchString(buf.String()) {
+		t.Fatalf("print output:\n%v\nwant:\n%v", buf.String(), want)
+	}
+}
+
+func TestCapturedOutput(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	fmt.Print("a", 1, "\n")
+	fmt.Println("b")
+	fmt.Printf("%v\n", "c")
+	fmt.Fprintln(os.Stderr, "d")
+	fmt.Fprintln(new(strings.Builder), "e")
+	println("f")
+}
+`
+	var buf bytes.Buffer
+	gossa.CapturedOutput = &buf
+	defer func() {
+		gossa.CapturedOutput = nil
+	}()
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a1\nb\nc\nd\nf\n"; buf.String() != want {
+		t.Fatalf("captured output %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParallelCompile(t *testing.T) {
+	src := `package main
+
+func depth(n int) (r int) {
+	defer func() {
+		if recover() != nil {
+			r = -n
+		}
+	}()
+	if n == 0 {
+		panic("bottom")
+	}
+	return depth(n - 1)
+}
+
+func f0() int { return 1 }
+func f1() int { return f0() + 1 }
+func f2() int { return f1() + 1 }
+func f3() int { return f2() + 1 }
+func f4() int { return f3() + 1 }
+func f5() int { return f4() + 1 }
+func f6() int { return f5() + 1 }
+func f7() int { return f6() + 1 }
+
+func main() {
+	if n := depth(3); n != 0 {
+		panic(n)
+	}
+	fs := []func() int{f0, f1, f2, f3, f4, f5, f6, f7}
+	for i, f := range fs {
+		if f() != i+1 {
+			panic(i)
+		}
+	}
+}
+`
+	for i := 0; i < 10; i++ {
+		_, err := gossa.RunFile("main.go", src, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := gossa.RunFile("main.go", `package main
+
+func g()
+
+func main() { g() }
+`, nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "missing function body") {
+		t.Fatalf("bad compile error %v", err)
+	}
+}
+
+func TestSelectLoop(t *testing.T) {
+	src := `package main
+
+func main() {
+	in := make(chan int)
+	out := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+	sum := 0
+	for in != nil {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			sum += v
+		case <-done:
+			panic("done")
+		}
+	}
+	if sum != 4950 {
+		panic(sum)
+	}
+	select {
+	case out <- nil:
+	default:
+		panic("default")
+	}
+	select {
+	case out <- nil:
+		panic("full")
+	default:
+	}
+	if err := <-out; err != nil {
+		panic(err)
+	}
+	close(done)
+	select {
+	case v, ok := <-done:
+		if ok || v != struct{}{} {
+			panic("closed")
+		}
+	}
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSelectNilChan(t *testing.T) {
+	src := `package main
+
+type C chan string
+
+func mustPanic(msg string, f func()) {
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || err.Error() != msg {
+			panic(r)
+		}
+	}()
+	f()
+}
+
+func main() {
+	var nc chan int
+	var named C
+	c := make(chan int, 1)
+	select {
+	case nc <- 1:
+		panic("send on nil")
+	case <-nc:
+		panic("recv from nil")
+	case named <- "x":
+		panic("send on named nil")
+	case <-(chan int)(nil):
+		panic("recv from const nil")
+	case c <- 2:
+	}
+	select {
+	case nc <- 1:
+		panic("send on nil")
+	default:
+	}
+	if v, ok := <-c; v != 2 || !ok {
+		panic(v)
+	}
+	close(c)
+	for i := 0; i < 2; i++ {
+		select {
+		case v, ok := <-c:
+			if v != 0 || ok {
+				panic("recv from closed")
+			}
+		case <-nc:
+			panic("recv from nil")
+		}
+	}
+	if v, ok := <-c; v != 0 || ok {
+		panic("recv from closed")
+	}
+	for range c {
+		panic("range over closed")
+	}
+	mustPanic("send on closed channel", func() {
+		select {
+		case c <- 1:
+		case nc <- 1:
+		}
+	})
+	mustPanic("send on closed channel", func() {
+		c <- 1
+	})
+	mustPanic("close of closed channel", func() {
+		close(c)
+	})
+	mustPanic("close of nil channel", func() {
+		close(nc)
+	})
+	done := make(chan bool)
+	go func() {
+		select {
+		case <-nc:
+		case nc <- 1:
+		case done <- true:
+		}
+	}()
+	<-done
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoModLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gossa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.14\n",
+		"greet/greet.go": `package greet
+
+import "example.com/m/greet/internal/names"
+
+type Greeter struct {
+	Name string
+}
+
+func (g *Greeter) Greet() string {
+	return prefix + names.Upper(g.Name)
+}
+`,
+		"greet/fancy.go": `//go:build fancy
+// +build fancy
+
+package greet
+
+const prefix = "ahoy "
+`,
+		"greet/plain.go": `//go:build !fancy
+// +build !fancy
+
+package greet
+
+const prefix = "hello "
+`,
+		"greet/internal/names/names.go": `package names
+
+import "strings"
+
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := `package main
+
+import "example.com/m/greet"
+
+func main() {
+	g := &greet.Greeter{Name: "gossa"}
+	if s := g.Greet(); s != want {
+		panic(s)
+	}
+}
+`
+	for _, tags := range [][]string{nil, {"fancy"}} {
+		want := "hello GOSSA"
+		if tags != nil {
+			want = "ahoy GOSSA"
+		}
+		ctx := gossa.NewContext(0)
+		ctx.Loader = gossa.NewGoModLoader(dir, tags)
+		code, err := ctx.RunFile("main.go", strings.Replace(src, "want", strconv.Quote(want), 1), nil)
+		if err != nil || code != 0 {
+			t.Fatalf("tags %v: exit %v, %v", tags, code, err)
+		}
+	}
+}
+
+func TestRunIn(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gossa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.14\n",
+		"jobs/jobs.go": `package jobs
+
+const Name = "jobs"
+
+type Job struct {
+	ID int
+}
+
+var Count int
+
+func Main() {
+	Count++
+}
+
+func Add(n int) int {
+	Count += n
+	return Count
+}
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := `package main
+
+import _ "example.com/m/jobs"
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.Loader = gossa.NewGoModLoader(dir, nil)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const jobs = "example.com/m/jobs"
+	if code, err := interp.RunIn(jobs, "Main"); err != nil || code != 0 {
+		t.Fatalf("RunIn = %v, %v", code, err)
+	}
+	if r, err := interp.RunFuncIn(jobs, "Add", 2); err != nil || r != 3 {
+		t.Fatalf("RunFuncIn = %v, %v", r, err)
+	}
+	if p, ok := interp.GetVarAddrIn(jobs, "Count"); !ok || *p.(*int) != 3 {
+		t.Fatalf("GetVarAddrIn = %v, %v", p, ok)
+	}
+	if fn, ok := interp.GetFuncIn(jobs, "Add"); !ok || fn.(func(int) int)(1) != 4 {
+		t.Fatal("bad GetFuncIn")
+	}
+	if c, ok := interp.GetConstIn(jobs, "Name"); !ok || c.String() != strconv.Quote("jobs") {
+		t.Fatalf("GetConstIn = %v, %v", c, ok)
+	}
+	if typ, ok := interp.GetTypeIn(jobs, "Job"); !ok || typ.Name() != "Job" {
+		t.Fatalf("GetTypeIn = %v, %v", typ, ok)
+	}
+	if _, ok := interp.GetFunc("Add"); ok {
+		t.Fatal("main package lookup found jobs.Add")
+	}
+	if _, err := interp.RunIn("example.com/m/none", "Main"); err == nil || err.Error() != "no package example.com/m/none" {
+		t.Fatalf("bad error %v", err)
+	}
+	if _, err := interp.RunFuncIn("strings", "ToUpper", "a"); err == nil {
+		t.Fatal("must no package error")
+	}
+}
+
+func TestInitCycle(t *testing.T) {
+	src := `package main
+
+var a = f()
+
+func f() int { return b }
+
+var b = a + 1
+
+func main() {
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	want := `main.go:3:5: initialization cycle for a
+	main.go:3:5: a refers to f
+	main.go:5:6: f refers to b
+	main.go:7:5: b refers to a`
+	if err == nil || err.Error() != want {
+		t.Fatalf("error %v, want %v", err, want)
+	}
+
+	// variables are initialized in dependency order, also by functions
+	src = `package main
+
+var x = f()
+
+func f() int { return y * 2 }
+
+var y = g()
+
+func g() int { return z + 1 }
+
+var z = 10
+
+var order []string
+
+var p = trace("p", q)
+var q = trace("q", 1)
+
+func trace(s string, n int) int {
+	order = append(order, s)
+	return n
+}
+
+func main() {
+	if x != 22 || y != 11 || p != 1 {
+		panic("bad init")
+	}
+	if len(order) != 2 || order[0] != "q" || order[1] != "p" {
+		panic(order)
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	var diags []*gossa.Diagnostic
+	ctx := gossa.NewContext(0)
+	ctx.SetDiagnostic(func(d *gossa.Diagnostic) {
+		diags = append(diags, d)
+	})
+	src := `package main
+
+import "unsafe"
+
+func asm() int
+
+func main() {
+	var n int
+	p := unsafe.Pointer(&n)
+	*(*int)(p) = asm()
+}
+`
+	if _, err := ctx.LoadFile(token.NewFileSet(), "main.go", src); err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 3 {
+		t.Fatalf("bad diagnostics %v", diags)
+	}
+	for i, want := range []struct {
+		kind gossa.DiagnosticKind
+		line int
+	}{{gossa.DiagNoBody, 5}, {gossa.DiagUnsafe, 9}, {gossa.DiagUnsafe, 10}} {
+		if d := diags[i]; d.Kind != want.kind || d.Pos.Line != want.line {
+			t.Fatalf("bad diagnostic %v: %v %v", i, d.Kind, d)
+		}
+	}
+
+	for _, src := range []string{`package main
+
+func Max[T int | float64](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func main() {
+	println(Max(1, 2))
+}
+`, `package main
+
+import "C"
+
+func main() {
+}
+`} {
+		diags = nil
+		_, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+		if err == nil || len(diags) != 1 || err.Error() != diags[0].String() {
+			t.Fatalf("bad load error %v, diagnostics %v", err, diags)
+		}
+	}
+}
+
+func TestContextRegisterPackage(t *testing.T) {
+	api := func(version string) *gossa.Package {
+		return &gossa.Package{
+			Name: "api",
+			Path: "example.com/host/api",
+			Deps: map[string]string{},
+			Funcs: map[string]reflect.Value{
+				"Version": reflect.ValueOf(func() string { return version }),
+			},
+		}
+	}
+	src := `package main
+
+import "example.com/host/api"
+
+func main() {
+	if v := api.Version(); v != "VERSION" {
+		panic(v)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	if err := ctx.RegisterPackage(api("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := gossa.LookupPackage("example.com/host/api"); ok {
+		t.Fatal("context package registered globally")
+	}
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", strings.Replace(src, "VERSION", "v1", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.RegisterPackage(api("v2"))
+	if _, err := ctx.RunFile("main.go", strings.Replace(src, "VERSION", "v2", 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+
+	// packages are registered while programs load
+	var wg sync.WaitGroup
+	for n := 0; n < 4; n++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			ctx.RegisterPackage(api(fmt.Sprint("v", n)))
+		}(n)
+		go func() {
+			defer wg.Done()
+			if _, err := ctx.LoadFile(token.NewFileSet(), "main.go", src); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// type records created before a replacement keep the replaced types
+	loader := gossa.NewTypesLoader(0).(*gossa.TypesLoader)
+	adapterPkg, _ := gossa.LookupPackage("github.com/goplus/gossa/adapter")
+	if _, err := loader.Import(adapterPkg.Path); err != nil {
+		t.Fatal(err)
+	}
+	rt := reflect.TypeOf(adapter.ReaderFunc(nil))
+	record := gossa.NewTypesRecord(loader, nil)
+	t1, _ := record.LookupTypes(rt)
+	loader.RegisterPackage(adapterPkg)
+	if _, err := loader.Import(adapterPkg.Path); err != nil {
+		t.Fatal(err)
+	}
+	t2, ok := loader.LookupTypes(rt)
+	if !ok || t1 == nil || t1 == t2 {
+		t.Fatalf("replaced package types not reinstalled: %v %v", t1, t2)
+	}
+	if typ, _ := record.LookupTypes(rt); typ != t1 {
+		t.Fatalf("type record lost replaced type %v", typ)
+	}
+	if typ, _ := gossa.NewTypesRecord(loader, nil).LookupTypes(rt); typ != t2 {
+		t.Fatalf("new type record got replaced type %v", typ)
+	}
+}
+
+func TestStore(t *testing.T) {
+	src := `package main
+
+import (
+	"strconv"
+
+	"gossa/store"
+)
+
+func main() {
+	data, ok, err := store.Get("runs")
+	if err != nil {
+		panic(err)
+	}
+	n := 0
+	if ok {
+		n, _ = strconv.Atoi(string(data))
+	}
+	if err := store.Set("runs", []byte(strconv.Itoa(n+1))); err != nil {
+		panic(err)
+	}
+	store.Set("tmp", nil)
+	store.Delete("tmp")
+	if keys, _ := store.Keys(""); len(keys) != 1 || keys[0] != "runs" {
+		panic("bad keys")
+	}
+}
+`
+	filename := filepath.Join(t.TempDir(), "store.json")
+	for i := 1; i <= 2; i++ {
+		s, err := gossa.NewFileStore(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := gossa.NewContext(0)
+		ctx.SetStore(s)
+		if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+			t.Fatal(err)
+		}
+		if data, _, _ := s.Get("runs"); string(data) != strconv.Itoa(i) {
+			t.Fatalf("bad runs %q, want %v", data, i)
+		}
+	}
+
+	// interpreters without a store do not share state
+	ctx := gossa.NewContext(0)
+	for i := 0; i < 2; i++ {
+		pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := interp.Run("main"); err != nil {
+			t.Fatal(err)
+		}
+		s := gossa.NewMemStore()
+		interp.SetStore(s)
+		if _, err := interp.Run("main"); err != nil {
+			t.Fatal(err)
+		}
+		if data, _, _ := s.Get("runs"); string(data) != "1" {
+			t.Fatalf("bad runs %q", data)
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"os"
+
+	"gossa/event"
+)
+
+type Tick int
+
+var sum int
+var names []string
+
+func init() {
+	event.On("tick", func(n Tick) {
+		sum += int(n)
+	})
+	event.On("tick", func(n Tick) {
+		if n < 0 {
+			panic("negative tick")
+		}
+		sum += int(n) * 10
+	})
+	event.On("name", func(s ...string) error {
+		if len(s) == 0 {
+			return errors.New("no names")
+		}
+		names = append(names, s...)
+		return nil
+	})
+	event.On("exit", func() {
+		os.Exit(3)
+	})
+	event.On("exit", func() {
+		sum = -1
+	})
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := interp.Handlers("tick"); n != 2 {
+		t.Fatalf("tick handlers %v", n)
+	}
+	if err := interp.Emit("tick", 2); err != nil {
+		t.Fatal(err)
+	}
+	// the second handler panics, the first one runs
+	err = interp.Emit("tick", -1)
+	errs, ok := err.(gossa.EmitError)
+	if !ok || len(errs) != 1 || errs[0].Index != 1 || !strings.Contains(err.Error(), "negative tick") {
+		t.Fatalf("bad error %v", err)
+	}
+	if err := interp.Emit("tick", "x"); err == nil {
+		t.Fatal("must bad argument")
+	}
+	if err := interp.Emit("name", "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := interp.Emit("name"); err == nil || !strings.Contains(err.Error(), "no names") {
+		t.Fatalf("bad error %v", err)
+	}
+	if err := interp.Emit("none"); err != nil {
+		t.Fatal(err)
+	}
+	// os.Exit stops the handlers
+	err = interp.Emit("exit")
+	if errs, ok := err.(gossa.EmitError); !ok || len(errs) != 1 {
+		t.Fatalf("bad exit error %v", err)
+	} else if e, ok := errs[0].Err.(*gossa.ExitError); !ok || e.Code != 3 {
+		t.Fatalf("bad exit error %v", err)
+	}
+	sum, _ := interp.GetVarAddr("sum")
+	if v := *sum.(*int); v != 21 {
+		t.Fatalf("sum %v, want 21", v)
+	}
+	names, _ := interp.GetVarAddr("names")
+	if v := *names.(*[]string); len(v) != 2 || v[0] != "a" || v[1] != "b" {
+		t.Fatalf("names %v", v)
+	}
+}
+
+func TestRunner(t *testing.T) {
+	src := `package main
+
+func Fib(n int) int {
+	if n < 2 {
+		return n
+	}
+	return Fib(n-1) + Fib(n-2)
+}
+
+func Check(n int) int {
+	defer func() {
+		recover()
+	}()
+	if n%5 == 0 {
+		var m map[int]int
+		m[n] = n
+	}
+	return n
+}
+
+func Fail(n int) int {
+	if n%5 == 0 {
+		panic("bad n")
+	}
+	return n
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := interp.NewRunner(4)
+	for n := 0; n < 20; n++ {
+		r.Go("Fib", n)
+		r.Go("Check", n)
+	}
+	results, err := r.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fib := []int{0, 1}
+	for n := 2; n < 20; n++ {
+		fib = append(fib, fib[n-1]+fib[n-2])
+	}
+	for n := 0; n < 20; n++ {
+		if v := results[2*n].Result; v != fib[n] {
+			t.Fatalf("Fib(%v) = %v, want %v", n, v, fib[n])
+		}
+		if v := results[2*n+1].Result; n%5 != 0 && v != n {
+			t.Fatalf("Check(%v) = %v", n, v)
+		}
+	}
+
+	// a panic fails its call only
+	for n := 1; n <= 10; n++ {
+		r.Go("Fail", n)
+	}
+	results, err = r.Wait()
+	if err == nil || err.Error() != "bad n" || len(results) != 10 {
+		t.Fatalf("bad error %v", err)
+	}
+	for n, res := range results {
+		if (res.Err != nil) != ((n+1)%5 == 0) {
+			t.Fatalf("Fail(%v) = %v, %v", n+1, res.Result, res.Err)
+		}
+	}
+}
+
+func TestRunExit(t *testing.T) {
+	src := `package main
+
+import "os"
+
+var release = make(chan int)
+
+func Exit(code int) {
+	os.Exit(code)
+}
+
+func Release() {
+	close(release)
+}
+
+func main() {
+	go func() {
+		<-release
+		os.Exit(4)
+	}()
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, err := interp.Run("main"); code != 0 || err != nil {
+		t.Fatalf("main: %v %v", code, err)
+	}
+	// os.Exit of calls concurrent with or after Run ends the calls only
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			interp.RunFunc("Exit", 3)
+		}()
+		go func() {
+			defer wg.Done()
+			interp.Run("main")
+		}()
+	}
+	wg.Wait()
+	if _, err := interp.RunFunc("Release"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestConcurrentRunFunc(t *testing.T) {
+	src := `package main
+
+import "sync"
+
+var (
+	mu    sync.Mutex
+	count int
+)
+
+func Add(n int) (r int) {
+	defer func() {
+		if recover() != nil {
+			r = -n
+		}
+	}()
+	mu.Lock()
+	count += n
+	mu.Unlock()
+	if n%3 == 0 {
+		panic(n)
+	}
+	return n
+}
+
+func Count() int {
+	mu.Lock()
+	defer mu.Unlock()
+	return count
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var add func(int) int
+	if err := interp.Bind("Add", &add); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 1; n <= 100; n++ {
+				want := n
+				if n%3 == 0 {
+					want = -n
+				}
+				var r interface{} = add(n)
+				if g%2 == 0 {
+					var err error
+					r, err = interp.RunFunc("Add", n)
+					if err != nil {
+						errs <- err
+						return
+					}
+				}
+				if r != want {
+					errs <- fmt.Errorf("Add(%v) = %v, want %v", n, r, want)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	// 8 goroutines bind Add, 4 of them RunFunc it too
+	if r, _ := interp.RunFunc("Count"); r != 12*5050 {
+		t.Fatalf("count %v, want %v", r, 12*5050)
+	}
+}
+
+func TestGoroutineRecover(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"sort"
+)
+
+func safe(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New(r.(string))
+		}
+	}()
+	f()
+	return nil
+}
+
+func main() {
+	done := make(chan error)
+	go func() {
+		done <- safe(func() { panic("g1") })
+	}()
+	if err := <-done; err == nil || err.Error() != "g1" {
+		panic("bad g1")
+	}
+	// recover in a goroutine from a panic of a host callback
+	go func() {
+		done <- safe(func() {
+			s := []int{3, 1, 2}
+			sort.Slice(s, func(i, j int) bool { panic("less") })
+		})
+	}()
+	if err := <-done; err == nil || err.Error() != "less" {
+		panic("bad less")
+	}
+	// recover in a defer of a goroutine started from a defer
+	func() {
+		defer func() {
+			go func() {
+				done <- safe(func() { panic("nested") })
+			}()
+		}()
+	}()
+	if err := <-done; err == nil || err.Error() != "nested" {
+		panic("bad nested")
+	}
+	// recover in a goroutine started while main panics
+	func() {
+		defer func() {
+			recover()
+		}()
+		defer func() {
+			go func() {
+				defer func() {
+					done <- errors.New(recover().(string))
+				}()
+				panic("inner")
+			}()
+			if err := <-done; err.Error() != "inner" {
+				panic("bad inner")
+			}
+		}()
+		panic("outer")
+	}()
+	// a goroutine does not recover the panic of its creator
+	func() {
+		defer func() {
+			if r := recover(); r != "creator" {
+				panic("bad creator")
+			}
+		}()
+		defer func() {
+			go func() {
+				done <- errors.New(toString(recover()))
+			}()
+			if err := <-done; err.Error() != "<nil>" {
+				panic("recovered creator panic: " + err.Error())
+			}
+		}()
+		panic("creator")
+	}()
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return v.(string)
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAbandonedCall(t *testing.T) {
+	src := `package main
+
+import (
+	"os"
+	"time"
+)
+
+var N int
+
+func main() {
+	go func() {
+		os.Exit(3)
+	}()
+	for i := 0; i < 100; i++ {
+		N = i + 1
+		time.Sleep(time.Millisecond)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, err := interp.Run("main"); code != 3 || err != nil {
+		t.Fatalf("exit code %v, %v", code, err)
+	}
+	p, _ := interp.GetVarAddr("N")
+	n := *p.(*int)
+	// the abandoned main stops at its next safepoint
+	time.Sleep(20 * time.Millisecond)
+	if m := *p.(*int); m > n+1 || m == 100 {
+		t.Fatalf("abandoned call still running: N %v after %v", m, n)
+	}
+}
+
+func TestGoroutinePanic(t *testing.T) {
+	tests := []struct {
+		stmt string
+		code int
+		out  []string
+	}{
+		{`panic("boom")`, 2, []string{"panic: boom\n", "goroutine ", "main.main$2(...)\n\tmain.go:"}},
+		{`panic(errors.New("bad"))`, 2, []string{"panic: bad\n"}},
+		{`panic(Code(7))`, 2, []string{"panic: main.Code(7)\n"}},
+		{`panic(worker)`, 2, []string{"panic: (func()) main.worker (main.go:13:6)\n"}},
+		{`var m map[int]int; m[0] = 1`, 2, []string{"panic: assignment to entry in nil map"}},
+		{`os.Exit(3)`, 3, nil},
+	}
+	for _, test := range tests {
+		src := `package main
+
+import (
+	"errors"
+	"os"
+)
+
+var _ = errors.New
+var _ = os.Exit
+
+type Code int
+
+func worker() {
+	defer func() {
+		// recovers the panics of its own goroutine only
+		recover()
+	}()
+	func() {
+		` + test.stmt + `
+	}()
+}
+
+func Crash() {
+	go func() {
+		` + test.stmt + `
+	}()
+	select {}
+}
+
+func main() {
+	go func() {
+		defer func() {
+			recover()
+		}()
+		go worker()
+	}()
+	go func() {
+		func() {
+			` + test.stmt + `
+		}()
+	}()
+	select {}
+}
+`
+		ctx := gossa.NewContext(0)
+		pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interp, err := ctx.NewInterp(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the crash ends the blocked call, not the host
+		code, err := interp.Run("main")
+		if code != test.code {
+			t.Fatalf("%v: exit code %v, want %v: %v", test.stmt, code, test.code, err)
+		}
+		if test.out == nil && err != nil {
+			t.Fatalf("%v: error %v", test.stmt, err)
+		}
+		for _, out := range test.out {
+			if _, ok := err.(*gossa.GoroutinePanic); !ok || !strings.Contains(err.Error(), out) {
+				t.Fatalf("%v: error %q, want %q", test.stmt, err, out)
+			}
+		}
+		_, err = interp.RunFunc("Crash")
+		if _, ok := err.(*gossa.GoroutinePanic); ok != (test.out != nil) {
+			t.Fatalf("%v: RunFunc error %v", test.stmt, err)
+		}
+	}
+}
+
+func TestMapInterfaceKeys(t *testing.T) {
+	src := `package main
+
+import (
+	"math"
+	"runtime"
+)
+
+type K struct {
+	A interface{}
+}
+
+func unhashable(want string, f func()) {
+	defer func() {
+		r := recover()
+		if _, ok := r.(runtime.Error); !ok {
+			panic("not a runtime error")
+		}
+		if msg := r.(error).Error(); msg != "runtime error: hash of unhashable type "+want {
+			panic(msg)
+		}
+	}()
+	f()
+}
+
+func main() {
+	m := map[interface{}]int{}
+	unhashable("[]int", func() { m[[]int{1}] = 1 })
+	unhashable("[]int", func() { _ = m[[]int{1}] })
+	unhashable("[]int", func() { _, _ = m[[]int{1}] })
+	unhashable("[]int", func() { delete(m, []int{1}) })
+	unhashable("func()", func() { m[func() {}] = 1 })
+	unhashable("map[int]int", func() { _ = m[map[int]int{}] })
+	unhashable("[]int", func() {
+		var nilmap map[interface{}]int
+		_ = nilmap[[]int{}]
+	})
+	unhashable("[]int", func() {
+		mk := map[K]int{}
+		_ = mk[K{[]int{}}]
+	})
+	unhashable("func()", func() {
+		mk := map[[1]interface{}]int{}
+		delete(mk, [1]interface{}{func() {}})
+	})
+
+	// nil keys and values
+	m[nil] = 5
+	if v, ok := m[nil]; v != 5 || !ok {
+		panic("bad nil key")
+	}
+	delete(m, nil)
+	if _, ok := m[nil]; ok || len(m) != 0 {
+		panic("bad delete nil key")
+	}
+	var err error
+	me := map[error]interface{}{err: nil}
+	if v, ok := me[nil]; v != nil || !ok || len(me) != 1 {
+		panic("bad nil value")
+	}
+	values := map[string]interface{}{"a": nil, "b": 1}
+	values["c"] = nil
+	if len(values) != 3 {
+		panic("bad nil values")
+	}
+
+	// NaN keys are never equal
+	nan := math.NaN()
+	f := map[float64]int{}
+	f[nan] = 1
+	f[nan] = 2
+	f[1] = 3
+	if _, ok := f[nan]; ok {
+		panic("bad NaN lookup")
+	}
+	delete(f, nan)
+	n := 0
+	for k, v := range f {
+		if k != k {
+			n += v
+		}
+	}
+	if len(f) != 3 || n != 3 {
+		panic("bad NaN keys")
+	}
+	i := map[interface{}]int{nan: 1}
+	i[nan] = 2
+	if _, ok := i[nan]; ok || len(i) != 2 {
+		panic("bad NaN interface keys")
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStringConversions(t *testing.T) {
+	src := `package main
+
+type MyByte byte
+type MyRune rune
+type MyString string
+type Runes []rune
+type MyBytes []MyByte
+type MyRunes []MyRune
+type Int int
+type Uint64 uint64
+
+func (r MyRune) String() string { return "r" }
+
+func check(name string, got, want interface{}) {
+	if got != want {
+		panic(name + ": got " + got.(string) + ", want " + want.(string))
+	}
+}
+
+func main() {
+	bad := "a\xffb\xc0\x80c\xed\xa0\x80"
+	rs := []rune(bad)
+	if len(rs) != 9 || rs[1] != 0xFFFD || rs[3] != 0xFFFD || rs[8] != 0xFFFD {
+		panic("bad []rune")
+	}
+	if mrs := MyRunes(MyString(bad)); len(mrs) != 9 || mrs[1] != 0xFFFD {
+		panic("bad MyRunes")
+	}
+	if rs := Runes(bad); len(rs) != 9 || rs[4] != 0xFFFD {
+		panic("bad Runes")
+	}
+	if bs := []MyByte(bad); len(bs) != len(bad) || bs[1] != 0xff {
+		panic("bad []MyByte")
+	}
+	check("[]byte", string([]byte(bad)), bad)
+
+	check("string([]rune)", string([]rune{'a', -1, 0xD800, 0x10FFFF, 0x110000, 'z'}), "a��\U0010FFFF�z")
+	check("string([]MyRune)", string([]MyRune{'a', -1}), "a�")
+	check("MyString(MyRunes)", string(MyString(MyRunes{'q', 0xD800})), "q�")
+	check("string(MyBytes)", string(MyBytes{'a', 0xff}), "a\xff")
+
+	for _, n := range []int64{-1, 0xD800, 0x110000, 1 << 40} {
+		check("string(Int)", string(Int(n)), "�")
+	}
+	var n int64 = 1 << 40
+	check("string(rune)", string(rune(n)), "\x00")
+	var u uint64 = 1<<63 + 'A'
+	check("string(Uint64)", string(MyString(Uint64(u))), "�")
+	check("string(uint32)", string(rune(uint32(u))), "A")
+	var b MyByte = 200
+	var r MyRune = 'é'
+	check("string(MyByte)", string(b), "È")
+	check("MyString(MyRune)", string(MyString(r)), "é")
+	var i8 int8 = -1
+	check("string(int8)", string(rune(i8)), "�")
+	if []byte("") == nil || []rune("") == nil {
+		panic("nil conversion of empty string")
+	}
+	if v, ok := interface{}(MyString([]byte("ab"))).(MyString); !ok || v != "ab" {
+		panic("bad MyString([]byte)")
+	}
+	if v, ok := interface{}(MyBytes("ab")).(MyBytes); !ok || len(v) != 2 || v[1] != 'b' {
+		panic("bad MyBytes(string)")
+	}
+	if v, ok := interface{}(MyRunes(MyString("é"))).(MyRunes); !ok || len(v) != 1 || v[0] != 'é' {
+		panic("bad MyRunes(MyString)")
+	}
+	if v, ok := interface{}(MyString(MyByte('x'))).(MyString); !ok || v != "x" {
+		panic("bad MyString(MyByte)")
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFloatConversions(t *testing.T) {
+	src := `package main
+
+import "fmt"
+
+type Small int8
+type Single float32
+
+func Floats(f float64) string {
+	f32 := float32(f)
+	return fmt.Sprint(int(f), int8(f), int16(f), int32(f), int64(f),
+		uint(f), uint8(f), uint16(f), uint32(f), uint64(f), Small(f),
+		int32(f32), uint16(f32), int64(f32))
+}
+
+func Ints(i int64, u uint64) string {
+	return fmt.Sprint(float32(i), float32(u), Single(i), float32(int32(i)), float64(i))
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []float64{0, -1, 1.5, -1.5, 127.9, 300, -300, 70000, 3e9, -3e9, 5e9,
+		1e19, -1e19, 2e19, math.MaxInt64, math.MinInt64, math.Inf(1), math.Inf(-1), math.NaN()} {
+		f32 := float32(f)
+		want := fmt.Sprint(int(f), int8(f), int16(f), int32(f), int64(f),
+			uint(f), uint8(f), uint16(f), uint32(f), uint64(f), int8(f),
+			int32(f32), uint16(f32), int64(f32))
+		if r, err := interp.RunFunc("Floats", f); err != nil || r != want {
+			t.Errorf("Floats(%v) = %v, %v, want %v", f, r, err, want)
+		}
+	}
+	for _, i := range []int64{0, -1, 1<<24 + 1, 1<<60 + 1<<36 + 1, -(1<<60 + 1<<36 + 1), math.MaxInt64, math.MinInt64} {
+		u := uint64(i)
+		want := fmt.Sprint(float32(i), float32(u), float32(i), float32(int32(i)), float64(i))
+		if r, err := interp.RunFunc("Ints", i, u); err != nil || r != want {
+			t.Errorf("Ints(%v) = %v, %v, want %v", i, r, err, want)
+		}
+	}
+}
+
+type bindPoint struct {
+	X, Y int
+}
+
+type bindHost struct {
+	Prefix string
+	Hook   func(string) string
+	calls  int
+}
+
+func (h *bindHost) Greet(name string) string {
+	h.calls++
+	return h.Prefix + name
+}
+
+func TestNewPackageFromMap(t *testing.T) {
+	count := 1
+	pkg, err := gossa.NewPackageFromMap("example.com/host/geo", map[string]interface{}{
+		"Point": reflect.TypeOf(bindPoint{}),
+		"Add": func(a, b bindPoint) bindPoint {
+			return bindPoint{a.X + b.X, a.Y + b.Y}
+		},
+		"Count":   &count,
+		"Max":     10,
+		"Name":    "geo",
+		"Epsilon": 0.5,
+		"Second":  time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pkg.UntypedConsts["Max"]; !ok {
+		t.Fatal("Max is not an untyped const")
+	}
+	if _, ok := pkg.TypedConsts["Second"]; !ok {
+		t.Fatal("Second is not a typed const")
+	}
+	if _, ok := pkg.Deps["time"]; !ok {
+		t.Fatalf("missing time dep: %v", pkg.Deps)
+	}
+	src := `package main
+
+import "example.com/host/geo"
+
+func main() {
+	var p geo.Point = geo.Add(geo.Point{1, 2}, geo.Point{X: 3, Y: 4})
+	if p.X != 4 || p.Y != 6 {
+		panic("bad point")
+	}
+	var n int8 = geo.Max
+	if n != 10 || geo.Name != "geo" || geo.Epsilon*2 != 1 || geo.Second.Seconds() != 1 {
+		panic("bad consts")
+	}
+	geo.Count++
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.RegisterPackage(pkg)
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("bad count %v", count)
+	}
+
+	if _, err := gossa.NewPackageFromMap("example.com/host/bad", map[string]interface{}{"C": make(chan int)}); err == nil {
+		t.Fatal("chan item must fail")
+	}
+}
+
+func TestNewPackageFromStruct(t *testing.T) {
+	host := &bindHost{Prefix: "hello ", Hook: strings.ToUpper}
+	pkg, err := gossa.NewPackageFromStruct("example.com/host/api", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `package main
+
+import "example.com/host/api"
+
+func main() {
+	if s := api.Greet("world"); s != "hello world" {
+		panic(s)
+	}
+	api.Prefix = "hi "
+	if s := api.Hook(api.Greet("gossa")); s != "HI GOSSA" {
+		panic(s)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.RegisterPackage(pkg)
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if host.calls != 2 || host.Prefix != "hi " {
+		t.Fatalf("bad host state %+v", host)
+	}
+	if _, err := gossa.NewPackageFromStruct("example.com/host/bad", 1); err == nil {
+		t.Fatal("int must fail")
+	}
+}
+
+func TestExternPackage(t *testing.T) {
+	gossa.RegisterExternal("hash/adler32.Checksum", adler32.Checksum)
+	gossa.RegisterExternal("hash/adler32.Size", adler32.Size)
+	src := `package main
+
+import "hash/adler32"
+
+func main() {
+	if adler32.Checksum([]byte("gossa")) != CHECKSUM || adler32.Size != 4 {
+		panic("bad checksum")
+	}
+}
+`
+	src = strings.Replace(src, "CHECKSUM", strconv.Itoa(int(adler32.Checksum([]byte("gossa")))), 1)
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	// symbols missing from the table are reported by name
+	src = `package main
+
+import "hash/adler32"
+
+func main() {
+	adler32.New()
+}
+`
+	_, err := gossa.RunFile("main.go", src, nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "adler32.New") {
+		t.Fatalf("bad missing symbol error %v", err)
+	}
+}
+
+type missingImporter struct{}
+
+func (missingImporter) Import(path string) (*types.Package, error) {
+	if path != "example.com/missing" {
+		return nil, fmt.Errorf("no package %v", path)
+	}
+	pkg := types.NewPackage(path, "missing")
+	sig := types.NewSignature(nil, nil, nil, false)
+	pkg.Scope().Insert(types.NewFunc(token.NoPos, pkg, "F", sig))
+	pkg.Scope().Insert(types.NewFunc(token.NoPos, pkg, "G", sig))
+	pkg.Scope().Insert(types.NewVar(token.NoPos, pkg, "V", types.Typ[types.Int]))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+func TestMissingSymbols(t *testing.T) {
+	src := `package main
+
+import "example.com/missing"
+
+func main() {
+	missing.G()
+	missing.F()
+	missing.V++
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.External = missingImporter{}
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ctx.NewInterp(pkg)
+	missing, ok := err.(gossa.MissingSymbolsError)
+	if !ok {
+		t.Fatalf("bad error %T %v", err, err)
+	}
+	var names []string
+	for _, m := range missing {
+		if m.Pkg != "example.com/missing" {
+			t.Fatalf("bad missing package %v", m.Pkg)
+		}
+		names = append(names, m.Kind+" "+m.Name)
+	}
+	if want := []string{"func F", "func G", "var V"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("missing %v, want %v", names, want)
+	}
+	if !strings.Contains(err.Error(), `gossa.RegisterExternal("example.com/missing.F", value)`) {
+		t.Fatalf("no remediation in %v", err)
+	}
+}
+
+func TestDisableUnsafe(t *testing.T) {
+	src := `package main
+
+import (
+	"reflect"
+	"syscall"
+	"unsafe"
+)
+
+func main() {
+	var n int
+	p := unsafe.Pointer(&n)
+	_ = p
+	reflect.ValueOf(&p).Elem().SetPointer(nil)
+	println(syscall.Getpid())
+}
+`
+	var diags []*gossa.Diagnostic
+	ctx := gossa.NewContext(gossa.DisableUnsafe)
+	ctx.SetDiagnostic(func(d *gossa.Diagnostic) {
+		if d.Kind == gossa.DiagDenied {
+			diags = append(diags, d)
+		}
+	})
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ctx.NewInterp(pkg)
+	denied, ok := err.(gossa.DeniedError)
+	if !ok {
+		t.Fatalf("bad error %T %v", err, err)
+	}
+	var lines []int
+	for _, d := range denied {
+		lines = append(lines, d.Pos.Line)
+	}
+	if want := []int{5, 6, 11, 13, 14}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("denied lines %v, want %v: %v", lines, want, err)
+	}
+	if len(diags) != len(denied) {
+		t.Fatalf("bad diagnostics %v", diags)
+	}
+
+	ctx = gossa.NewContext(gossa.DisableUnsafe)
+	ctx.SetDenylist("os.Exit")
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ctx.NewInterp(pkg); err != nil {
+		t.Fatalf("denied by custom list: %v", err)
+	}
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import "os"
+
+func main() {
+	exit := os.Exit
+	exit(0)
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ctx.NewInterp(pkg); err == nil || !strings.Contains(err.Error(), "main.go:7:6: use of denied symbol os.Exit") {
+		t.Fatalf("bad error %v", err)
+	}
+}
+
+func TestImportFilter(t *testing.T) {
+	ctx := gossa.NewContext(0)
+	ctx.RegisterPackage(&gossa.Package{
+		Name: "strconv",
+		Path: "example.com/stub/strconv",
+		Deps: map[string]string{},
+		Funcs: map[string]reflect.Value{
+			"Itoa": reflect.ValueOf(func(int) string { return "stub" }),
+		},
+	})
+	errNoExec := errors.New("no processes")
+	err := ctx.SetImportFilter(func(path string) error {
+		switch path {
+		case "os/exec", "strings.Repeat", "(*strings.Builder).WriteString":
+			return errNoExec
+		case "strconv":
+			return gossa.RewriteImport("example.com/stub/strconv")
+		case "errors", "example.com/cycle":
+			return gossa.RewriteImport(path)
+		case "example.com/a":
+			return gossa.RewriteImport("example.com/b")
+		case "example.com/b":
+			return gossa.RewriteImport("example.com/a")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import "os/exec"
+
+func main() {
+	exec.Command("ls").Run()
+}
+`)
+	if err == nil || !strings.Contains(err.Error(), "os/exec denied: no processes") {
+		t.Fatalf("bad import error %v", err)
+	}
+
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import "strings"
+
+func main() {
+	println(strings.Repeat("a", 2))
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ctx.NewInterp(pkg)
+	if err == nil || err.Error() != "main.go:6:24: strings.Repeat denied: no processes" {
+		t.Fatalf("bad symbol error %v", err)
+	}
+
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import "strconv"
+
+func main() {
+	if s := strconv.Itoa(1); s != "stub" {
+		panic(s)
+	}
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = interp.Run("main"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import _ "example.com/a"
+`)
+	if err == nil || !strings.Contains(err.Error(), "example.com/b denied: rewrite cycle to example.com/a") {
+		t.Fatalf("bad rewrite cycle error %v", err)
+	}
+
+	// methods called through interfaces are filtered
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+var err = errors.New("rewritten to itself")
+
+func call() {
+	var w io.StringWriter = &strings.Builder{}
+	w.WriteString("a")
+}
+
+func value() {
+	var w io.StringWriter = &strings.Builder{}
+	f := w.WriteString
+	f("a")
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err = ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"call", "value"} {
+		if _, err := interp.RunFunc(fn); err == nil || !strings.Contains(err.Error(), "(*strings.Builder).WriteString denied: no processes") {
+			t.Fatalf("%v: bad method error %v", fn, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	src := `package main
+
+import "example.com/missing"
+
+func main() {
+	missing.F()
+	println(missing.V)
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.External = missingImporter{}
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var diags []gossa.Diagnostic = gossa.Verify(pkg, ctx.Loader)
+	var kinds []gossa.DiagnosticKind
+	for _, d := range diags {
+		if d.Kind == gossa.DiagExtern && d.Pos.Filename != "main.go" {
+			t.Fatalf("bad position %v", d)
+		}
+		kinds = append(kinds, d.Kind)
+	}
+	want := []gossa.DiagnosticKind{gossa.DiagImport, gossa.DiagExtern, gossa.DiagExtern}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("diagnostics %v, want %v", kinds, want)
+	}
+
+	ctx = gossa.NewContext(0)
+	pkg, err = ctx.LoadFile(token.NewFileSet(), "main.go", `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := gossa.Verify(pkg, ctx.Loader); len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestInterfaceBridge(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+)
+
+type byLen []string
+
+func (s byLen) Len() int           { return len(s) }
+func (s byLen) Less(i, j int) bool { return len(s[i]) < len(s[j]) }
+func (s byLen) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type ints []int
+
+func (s *ints) Len() int           { return len(*s) }
+func (s *ints) Less(i, j int) bool { return (*s)[i] < (*s)[j] }
+func (s *ints) Swap(i, j int)      { (*s)[i], (*s)[j] = (*s)[j], (*s)[i] }
+
+type reverse struct {
+	sort.Interface
+}
+
+func (r reverse) Less(i, j int) bool { return r.Interface.Less(j, i) }
+
+type codeError int
+
+func (e *codeError) Error() string { return fmt.Sprintf("code %d", int(*e)) }
+
+type hello string
+
+func (h hello) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "%v %v", h, r.URL.Path)
+}
+
+type reader struct {
+	io.Reader
+}
+
+func main() {
+	s := byLen{"ccc", "a", "bb"}
+	sort.Sort(s)
+	if fmt.Sprint(s) != "[a bb ccc]" {
+		panic(fmt.Sprint("byLen ", s))
+	}
+	n := ints{3, 1, 2}
+	sort.Sort(&n)
+	if fmt.Sprint(n) != "[1 2 3]" {
+		panic(fmt.Sprint("ints ", n))
+	}
+	sort.Sort(reverse{&n})
+	if fmt.Sprint(n) != "[3 2 1]" {
+		panic(fmt.Sprint("reverse ", n))
+	}
+
+	code := codeError(404)
+	err := fmt.Errorf("wrap: %w", &code)
+	if err.Error() != "wrap: code 404" {
+		panic(err)
+	}
+	var e *codeError
+	if !errors.As(err, &e) || *e != 404 {
+		panic("errors.As")
+	}
+
+	rec := httptest.NewRecorder()
+	h := http.StripPrefix("/api", hello("hello"))
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/api/x", nil))
+	if rec.Body.String() != "hello /x" {
+		panic(rec.Body.String())
+	}
+
+	data, _ := io.ReadAll(reader{strings.NewReader("data")})
+	if string(data) != "data" {
+		panic(string(data))
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmbedHostType(t *testing.T) {
+	src := `package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+type counter struct {
+	sync.Mutex
+	n int
+}
+
+type buffer struct {
+	*bytes.Buffer
+}
+
+type writer struct {
+	io.Writer
+}
+
+type group struct {
+	*sync.WaitGroup
+}
+
+type outer struct {
+	group
+}
+
+func main() {
+	var c counter
+	c.Lock()
+	c.n++
+	c.Unlock()
+	var l sync.Locker = &c
+	l.Lock()
+	l.Unlock()
+	lock := (*counter).Lock
+	lock(&c)
+	var x interface{} = &c
+	x.(sync.Locker).Unlock()
+
+	b := buffer{new(bytes.Buffer)}
+	b.WriteString("a")
+	var w io.Writer = b
+	w.Write([]byte("b"))
+	if b.String() != "ab" {
+		panic(b.String())
+	}
+
+	var sb strings.Builder
+	w = writer{&sb}
+	w.Write([]byte("c"))
+	if sb.String() != "c" {
+		panic(sb.String())
+	}
+
+	o := outer{group{&sync.WaitGroup{}}}
+	o.Add(1)
+	go o.Done()
+	o.Wait()
+	add := o.Add
+	add(0)
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChanDir(t *testing.T) {
+	pkg, err := gossa.NewPackageFromMap("example.com/host/chans", map[string]interface{}{
+		"Sum": func(c <-chan int) (n int) {
+			for v := range c {
+				n += v
+			}
+			return
+		},
+		"Fill": func(c chan<- int, n int) {
+			for i := 1; i <= n; i++ {
+				c <- i
+			}
+			close(c)
+		},
+		"Recv": func() <-chan chan int {
+			c := make(chan chan int, 1)
+			c <- make(chan int, 1)
+			return c
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `package main
+
+import (
+	"example.com/host/chans"
+	"reflect"
+)
+
+type ID int
+
+type IDs chan ID
+
+func send(c chan<- ID, id ID) { c <- id }
+
+func recv(c <-chan ID) ID { return <-c }
+
+func main() {
+	c := make(chan int, 3)
+	go chans.Fill(c, 3)
+	if n := chans.Sum(c); n != 6 {
+		panic(n)
+	}
+
+	ids := make(IDs, 1)
+	send(ids, 7)
+	var r <-chan ID = ids
+	if id := recv(r); id != 7 {
+		panic(id)
+	}
+	var x interface{} = r
+	if x == interface{}(ids) {
+		panic("different channel types are equal")
+	}
+	if reflect.TypeOf(x).ChanDir() != reflect.RecvDir {
+		panic(reflect.TypeOf(x))
+	}
+
+	cc := make(chan chan<- ID, 1)
+	cc <- ids
+	var rc <-chan chan<- ID = cc
+	send(<-rc, 8)
+	if id := <-ids; id != 8 {
+		panic(id)
+	}
+
+	in := <-chans.Recv()
+	var s chan<- int = in
+	s <- 1
+	if v := <-in; v != 1 {
+		panic(v)
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.RegisterPackage(pkg)
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReflectScriptTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gossa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.14\n",
+		"greet/v2/greet.go": `package greet
+
+type Name string
+
+func (n Name) String() string {
+	return "hello " + string(n)
+}
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := `package main
+
+import (
+	"example.com/m/greet/v2"
+	"fmt"
+	"reflect"
+)
+
+type ID int
+
+func check(v interface{}, s string) {
+	if t := reflect.TypeOf(v); t.String() != s {
+		panic(fmt.Sprintf("%v, want %v", t.String(), s))
+	}
+}
+
+func main() {
+	check(greet.Name(""), "greet.Name")
+	check([]*greet.Name{}, "[]*greet.Name")
+	check(map[greet.Name][2]ID{}, "map[greet.Name][2]main.ID")
+	check(make(chan (<-chan greet.Name)), "chan (<-chan greet.Name)")
+	check(make(chan<- ID), "chan<- main.ID")
+
+	t := reflect.TypeOf(greet.Name(""))
+	if t.Name() != "Name" || t.PkgPath() != "example.com/m/greet/v2" {
+		panic(t.PkgPath())
+	}
+	if !t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		panic("greet.Name is not a fmt.Stringer")
+	}
+	v := reflect.New(t).Elem()
+	v.SetString("gossa")
+	if s := v.Interface().(fmt.Stringer).String(); s != "hello gossa" {
+		panic(s)
+	}
+	if !reflect.DeepEqual(map[ID][]greet.Name{1: {"a"}}, map[ID][]greet.Name{1: {"a"}}) {
+		panic("DeepEqual")
+	}
+}
+`
+	ctx := gossa.NewContext(0)
+	ctx.Loader = gossa.NewGoModLoader(dir, nil)
+	if _, err := ctx.RunFile("main.go", src, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReflectMethods(t *testing.T) {
+	src := `package main
+
+import "reflect"
+
+type T struct {
+	Name string
+}
+
+func (t T) String() string { return "T:" + t.Name }
+
+func (t *T) Set(s string) { t.Name = s }
+
+func newT(s string) *T { return &T{s} }
+
+func method(v interface{}, i int) reflect.Method {
+	return reflect.TypeOf(v).Method(i)
+}
+
+func methodByName(v interface{}, name string) reflect.Method {
+	m, _ := reflect.TypeOf(v).MethodByName(name)
+	return m
+}
+
+func numMethod(v interface{}) int {
+	return reflect.TypeOf(v).NumMethod()*10 + reflect.ValueOf(v).NumMethod()
+}
+
+func call(v interface{}) string {
+	reflect.ValueOf(v).MethodByName("Set").Call([]reflect.Value{reflect.ValueOf("k")})
+	return reflect.ValueOf(v).Method(1).Call(nil)[0].String()
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := interp.RunFunc("newT", "w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := interp.RunFunc("method", p, 0)
+	if m := v.(reflect.Method); err != nil || m.Name != "Set" || m.Index != 0 || m.Type.String() != "func(*main.T, string)" {
+		t.Fatalf("bad Method %+v %v", v, err)
+	}
+	v, err = interp.RunFunc("methodByName", p, "String")
+	m := v.(reflect.Method)
+	if err != nil || m.Name != "String" || m.Index != 1 || m.Type.String() != "func(*main.T) string" {
+		t.Fatalf("bad MethodByName %+v %v", v, err)
+	}
+	if out := m.Func.Call([]reflect.Value{reflect.ValueOf(p)}); out[0].String() != "T:w" {
+		t.Fatalf("bad method call %v", out[0])
+	}
+	if v, err := interp.RunFunc("numMethod", reflect.ValueOf(p).Elem().Interface()); err != nil || v != 11 {
+		t.Fatalf("bad T NumMethod %v %v", v, err)
+	}
+	if v, err := interp.RunFunc("numMethod", p); err != nil || v != 22 {
+		t.Fatalf("bad *T NumMethod %v %v", v, err)
+	}
+	if v, err := interp.RunFunc("call", p); err != nil || v != "T:k" {
+		t.Fatalf("bad Value.Method call %v %v", v, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	src := `package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type Level int
+
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Repeat("*", int(l)))
+}
+
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = Level(len(s))
+	return nil
+}
+
+type Base struct {
+	ID int 'json:"id"'
+}
+
+type Item struct {
+	Base
+	Name   string            'json:"name"'
+	Note   string            'json:"note,omitempty"'
+	Level  Level             'json:"level"'
+	Tags   []string          'json:"tags,omitempty"'
+	Attrs  map[string]Level  'json:"attrs,omitempty"'
+	Next   *Item             'json:"next,omitempty"'
+	Skip   int               'json:"-"'
+	hidden int
+}
+
+var _ json.Marshaler = Level(0)
+var _ json.Unmarshaler = (*Level)(nil)
+
+func main() {
+	item := Item{Base: Base{1}, Name: "a", Level: 2, Next: &Item{Name: "b"}, Skip: 3, hidden: 4}
+	data, err := json.Marshal(item)
+	if err != nil {
+		panic(err)
+	}
+	want := '{"id":1,"name":"a","level":"**","next":{"id":0,"name":"b","level":""}}'
+	if string(data) != want {
+		panic(string(data))
+	}
+
+	var out Item
+	if err := json.Unmarshal([]byte('{"id":2,"name":"c","level":"***","tags":["x"],"attrs":{"k":"*"},"next":{"name":"d"},"Skip":5}'), &out); err != nil {
+		panic(err)
+	}
+	if out.ID != 2 || out.Name != "c" || out.Level != 3 || len(out.Tags) != 1 || out.Attrs["k"] != 1 || out.Next.Name != "d" || out.Skip != 0 {
+		panic("bad unmarshal")
+	}
+
+	p := new(Item)
+	if err := json.Unmarshal(data, p); err != nil || p.Next == nil || p.Level != 2 {
+		panic("bad unmarshal of pointer")
+	}
+}
+`
+	// struct tags and raw strings are quoted by ' in src
+	src = strings.Replace(src, "'", "`", -1)
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFmtMethods(t *testing.T) {
+	src := `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type T int
+
+func (t T) String() string { return fmt.Sprintf("T(%d)", int(t)) }
+
+type P int
+
+func (p *P) String() string { return "P" }
+
+type E string
+
+func (e E) Error() string  { return "E:" + string(e) }
+func (e E) String() string { return "unused" }
+
+type G int
+
+func (g G) GoString() string { return "G!" }
+
+type F int
+
+func (f F) Format(s fmt.State, c rune) { fmt.Fprintf(s, "F%c", c) }
+
+type S string
+
+func (s S) String() string { return "<" + string(s) + ">" }
+
+func check(got, want string) {
+	if got != want {
+		panic(fmt.Sprintf("got %q, want %q", got, want))
+	}
+}
+
+func main() {
+	check(fmt.Sprint(T(1), " ", T(2)), "T(1) T(2)")
+	check(fmt.Sprint(S("a"), S("b")), "<a><b>")
+	check(fmt.Sprint(S("a"), 1, T(2), "x", T(3)), "<a>1 T(2)xT(3)")
+	check(fmt.Sprintf("%v|%s|%d|%+v|%q|%6s|%-6v|%x", T(1), T(1), T(1), T(1), T(1), T(1), T(1), T(1)),
+		"T(1)|T(1)|1|T(1)|\"T(1)\"|  T(1)|T(1)  |54283129")
+	p := P(3)
+	check(fmt.Sprint(p, &p), "3 P")
+	var err error = E("x")
+	check(fmt.Sprintf("%v %s %+v", err, err, E("y")), "E:x E:x E:y")
+	check(fmt.Sprintf("%#v %#v %v %d", G(1), T(1), F(1), F(1)), "G! 1 Fv Fd")
+	var b strings.Builder
+	fmt.Fprintln(&b, T(4), err)
+	check(b.String(), "T(4) E:x\n")
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDisableMethodSynthesis(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Shape interface {
+	Area() int
+}
+
+type Square int
+
+func (s Square) Area() int { return int(s * s) }
+
+type Rect [2]int
+
+func (r Rect) Area() int { return r[0] * r[1] }
+
+type myError string
+
+func (e myError) Error() string { return "my " + string(e) }
+
+type Name string
+
+func (n Name) String() string { return "<" + string(n) + ">" }
+
+type Ints []int
+
+func (p Ints) Len() int           { return len(p) }
+func (p Ints) Less(i, j int) bool { return p[i] < p[j] }
+func (p Ints) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+type counter int
+
+func (c *counter) Read(p []byte) (int, error) {
+	if *c == 0 {
+		return 0, io.EOF
+	}
+	*c--
+	p[0] = 'x'
+	return 1, nil
+}
+
+func check(err error) error {
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func main() {
+	shapes := []Shape{Square(3), Rect{2, 5}}
+	sum := 0
+	for _, s := range shapes {
+		switch v := s.(type) {
+		case Square:
+			sum += v.Area()
+		case Rect:
+			sum += v.Area() * 10
+		}
+	}
+	if sum != 109 {
+		panic(sum)
+	}
+	var x interface{} = Square(2)
+	if s, ok := x.(Shape); !ok || s.Area() != 4 {
+		panic("must Shape")
+	}
+	if _, ok := x.(fmt.Stringer); ok {
+		panic("must not Stringer")
+	}
+	err := check(myError("error"))
+	if err.Error() != "my error" || fmt.Sprint(err) != "my error" {
+		panic(err)
+	}
+	if err := fmt.Errorf("wrap: %w", err); errors.Unwrap(err).Error() != "my error" {
+		panic(err)
+	}
+	if s := fmt.Sprintf("%v %s", Name("a"), Name("b")); s != "<a> <b>" {
+		panic(s)
+	}
+	p := Ints{3, 1, 2}
+	sort.Sort(p)
+	if fmt.Sprint([]int(p)) != "[1 2 3]" {
+		panic(p)
+	}
+	c := counter(3)
+	data, err := io.ReadAll(&c)
+	if err != nil || string(data) != "xxx" {
+		panic(string(data))
+	}
+	var b strings.Builder
+	fmt.Fprint(&b, Name("c"))
+	if b.String() != "<c>" {
+		panic(b.String())
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, gossa.DisableMethodSynthesis); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,251 @@
+package gossa
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/petermattis/goid"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// LogPkgPath is the import path of the script logging package.
+// Scripts log through the Logger of their Interp:
+//
+//	import "gossa/log"
+//
+//	log.Info("request done", "status", 200)
+const LogPkgPath = "gossa/log"
+
+// LogLevel is the level of a gossa/log record or of an interpreter
+// diagnostic record.
+type LogLevel int
+
+const (
+	LogTrace LogLevel = iota - 2 // instructions traced in EnableTracing mode
+	LogDebug
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogTrace:
+		return "TRACE"
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// Logger is the host backend of the gossa/log package.
+// keyvals are alternating keys and values as passed by the script.
+//
+// The diagnostics of EnableTracing and EnableDumpInstr modes are logged by
+// the Logger set by SetLogger too, instead of the standard log package and
+// stdout. Tracing records have the fields "func", "goroutine" and "pos",
+// instructions are logged at LogTrace and calls and returns at LogDebug.
+// Dumped packages are logged at LogDebug with the field "package", and the
+// SSA code of their functions with the field "func".
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
+// LoggerFunc is an adapter to allow the use of ordinary functions as Logger.
+type LoggerFunc func(level LogLevel, msg string, keyvals ...interface{})
+
+func (f LoggerFunc) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	f(level, msg, keyvals...)
+}
+
+// NewTestingLogger returns a Logger writing to a testing.TB.
+func NewTestingLogger(t interface{ Log(args ...interface{}) }) Logger {
+	return LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		t.Log(formatLog(level, msg, keyvals))
+	})
+}
+
+// NewSugaredLogger returns a Logger writing to a zap.SugaredLogger
+// or any logger with the same key-value methods.
+func NewSugaredLogger(l interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}) Logger {
+	return LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		switch {
+		case level <= LogDebug:
+			l.Debugw(msg, keyvals...)
+		case level == LogInfo:
+			l.Infow(msg, keyvals...)
+		case level == LogWarn:
+			l.Warnw(msg, keyvals...)
+		default:
+			l.Errorw(msg, keyvals...)
+		}
+	})
+}
+
+// stdLogger is the default Logger, writing to the standard log package.
+var stdLogger = LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+	log.Print(formatLog(level, msg, keyvals))
+})
+
+func formatLog(level LogLevel, msg string, keyvals []interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(level.String())
+	sb.WriteByte(' ')
+	sb.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&sb, " %v=%v", keyvals[i], keyvals[i+1])
+		} else {
+			fmt.Fprintf(&sb, " %v=(MISSING)", keyvals[i])
+		}
+	}
+	return sb.String()
+}
+
+// SetLogger sets the backend of the gossa/log package and of the
+// diagnostics for interpreters created by the context. A nil l restores
+// the standard log package.
+func (c *Context) SetLogger(l Logger) {
+	c.logger = l
+}
+
+// SetLogger sets the backend of the gossa/log package and of the tracing
+// for the interpreter, overriding the context logger.
+func (i *Interp) SetLogger(l Logger) {
+	i.logger = l
+}
+
+// tracef logs a tracing record of the frame fr at the position pos, to
+// the output of WithTracing if any, else by the standard log package if no
+// Logger is set.
+func (i *Interp) tracef(fr *frame, level LogLevel, pos token.Pos, format string, args ...interface{}) {
+	if i.traceOut != nil {
+		fmt.Fprintf(i.traceOut, format+"\n", args...)
+		return
+	}
+	l := i.logger
+	if l == nil {
+		l = i.ctx.logger
+	}
+	if l == nil {
+		log.Printf(format, args...)
+		return
+	}
+	l.Log(level, fmt.Sprintf(format, args...),
+		"func", fr.pfn.Fn.String(), "goroutine", goid.Get(), "pos", i.fset.Position(pos))
+}
+
+// dumpPackage logs the package p created as kind in EnableDumpInstr mode,
+// by stdout if no Logger is set.
+func (c *Context) dumpPackage(kind string, p *types.Package) {
+	if c.logger == nil {
+		fmt.Println("#", kind, p)
+		return
+	}
+	c.logger.Log(LogDebug, kind, "package", p.Path())
+}
+
+// dumpFunctions logs the SSA code of the functions of pkgs in
+// EnableDumpInstr mode, by stdout if no Logger is set.
+func (c *Context) dumpFunctions(pkgs []*ssa.Package) {
+	dump := make(map[*ssa.Package]bool, len(pkgs))
+	for _, p := range pkgs {
+		dump[p] = true
+	}
+	var fns []*ssa.Function
+	for fn := range ssautil.AllFunctions(pkgs[0].Prog) {
+		if dump[fn.Pkg] && fn.Blocks != nil {
+			fns = append(fns, fn)
+		}
+	}
+	sort.Slice(fns, func(i, j int) bool {
+		if fns[i].Pos() != fns[j].Pos() {
+			return fns[i].Pos() < fns[j].Pos()
+		}
+		return fns[i].String() < fns[j].String()
+	})
+	for _, fn := range fns {
+		var buf bytes.Buffer
+		ssa.WriteFunction(&buf, fn)
+		if c.logger == nil {
+			os.Stdout.Write(buf.Bytes())
+			continue
+		}
+		c.logger.Log(LogDebug, buf.String(), "func", fn.String())
+	}
+}
+
+func (i *Interp) getLogger() Logger {
+	if i.logger != nil {
+		return i.logger
+	}
+	if i.ctx.logger != nil {
+		return i.ctx.logger
+	}
+	return stdLogger
+}
+
+// findLogFunc returns the gossa/log function name bound to the interp logger.
+func findLogFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	var level LogLevel
+	switch name {
+	case "Debug":
+		level = LogDebug
+	case "Info":
+		level = LogInfo
+	case "Warn":
+		level = LogWarn
+	case "Error":
+		level = LogError
+	default:
+		return
+	}
+	return reflect.ValueOf(func(msg string, keyvals ...interface{}) {
+		interp.getLogger().Log(level, msg, keyvals...)
+	}), true
+}
+
+func logFunc(level LogLevel) func(msg string, keyvals ...interface{}) {
+	return func(msg string, keyvals ...interface{}) {
+		stdLogger.Log(level, msg, keyvals...)
+	}
+}
+
+func init() {
+	RegisterPackage(&Package{
+		Name:       "log",
+		Path:       LogPkgPath,
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Debug": reflect.ValueOf(logFunc(LogDebug)),
+			"Info":  reflect.ValueOf(logFunc(LogInfo)),
+			"Warn":  reflect.ValueOf(logFunc(LogWarn)),
+			"Error": reflect.ValueOf(logFunc(LogError)),
+		},
+		TypedConsts:   map[string]TypedConst{},
+		UntypedConsts: map[string]UntypedConst{},
+	})
+}
@@ -0,0 +1,17 @@
+//go:build go1.21
+// +build go1.21
+
+package gossa
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger returns a Logger writing to a slog.Logger,
+// LogLevel n maps to slog.Level n*4.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(level LogLevel, msg string, keyvals ...interface{}) {
+		l.Log(context.Background(), slog.Level(level*4), msg, keyvals...)
+	})
+}
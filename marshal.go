@@ -0,0 +1,262 @@
+package gossa
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Unmarshal stores the interpreter value result, eg. a RunFunc result of a
+// struct type declared by the script, in the host value pointed to by out.
+//
+// Struct fields are matched by name, or by the name of the `gossa:"name"`
+// tag of the host field, which may name an unexported script field. A tag
+// "-" skips the field, fields missing in result are left unchanged.
+// Pointers, slices, arrays and maps are converted element by element,
+// numbers are converted to other number types and other values are
+// converted if they have the same kind. Shared and cyclic pointers, slices
+// and maps convert to shared and cyclic values.
+func Unmarshal(result Value, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("unmarshal: non-pointer %T", out)
+	}
+	return marshalValue(rv.Elem(), reflect.ValueOf(result), true, "")
+}
+
+// Marshal returns the host value in converted to the interpreter type typ,
+// eg. a type of the script found by Interp.GetType, as a RunFunc argument.
+// Fields are matched like Unmarshal, by the tags of in.
+func Marshal(in interface{}, typ reflect.Type) (Value, error) {
+	v := reflect.New(typ).Elem()
+	if err := marshalValue(v, reflect.ValueOf(in), false, ""); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// ValueOf returns x converted to the interpreter type typ like a RunFunc
+// argument, eg. an int to a named int32 type of the script found by
+// Interp.GetType. A nil x is the nil value of typ.
+func ValueOf(x interface{}, typ reflect.Type) (Value, error) {
+	return convertArg(x, typ, typ.String())
+}
+
+// Nil returns the nil value of the pointer, slice, map, chan, func or
+// interface type typ, eg. a typed nil pointer of a script type as a
+// RunFunc argument. It panics if typ has no nil value.
+func Nil(typ reflect.Type) Value {
+	v, err := convertArg(nil, typ, typ.String())
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// marshaler converts the values of Marshal and Unmarshal, toHost reports
+// whether the converted values are host values. The pointers, slices and
+// maps converted are reused, so shared and cyclic values convert to shared
+// and cyclic values.
+type marshaler struct {
+	toHost bool
+	seen   map[visit]reflect.Value
+}
+
+// visit is a pointer, slice or map converted to the type typ.
+type visit struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// marshalValue sets dst to the converted src, toHost reports whether dst
+// is the host value.
+func marshalValue(dst, src reflect.Value, toHost bool, path string) error {
+	m := &marshaler{toHost: toHost, seen: make(map[visit]reflect.Value)}
+	return m.value(dst, src, path)
+}
+
+// value sets dst to the converted src. Path is the field path of dst for
+// errors.
+func (m *marshaler) value(dst, src reflect.Value, path string) error {
+	for src.IsValid() && src.Kind() == reflect.Interface {
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		v := reflect.New(dst.Type().Elem())
+		if src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				dst.Set(reflect.Zero(dst.Type()))
+				return nil
+			}
+			key := visit{ptr: src.Pointer(), typ: dst.Type()}
+			if seen, ok := m.seen[key]; ok {
+				dst.Set(seen)
+				return nil
+			}
+			m.seen[key] = v
+			src = src.Elem()
+		}
+		if err := m.value(v.Elem(), src, path); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Struct:
+		if src.Kind() == reflect.Ptr && !src.IsNil() {
+			src = src.Elem()
+		}
+		if src.Kind() != reflect.Struct {
+			break
+		}
+		return m.structFields(dst, src, path)
+	case reflect.Slice:
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		v := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		if src.Kind() == reflect.Slice {
+			key := visit{ptr: src.Pointer(), len: src.Len(), typ: dst.Type()}
+			if seen, ok := m.seen[key]; ok {
+				dst.Set(seen)
+				return nil
+			}
+			m.seen[key] = v
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := m.value(v.Index(i), src.Index(i), fmt.Sprintf("%v[%v]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Array:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+		for i := 0; i < src.Len() && i < dst.Len(); i++ {
+			if err := m.value(dst.Index(i), src.Index(i), fmt.Sprintf("%v[%v]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if src.Kind() != reflect.Map {
+			break
+		}
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		key := visit{ptr: src.Pointer(), typ: dst.Type()}
+		if seen, ok := m.seen[key]; ok {
+			dst.Set(seen)
+			return nil
+		}
+		v := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		m.seen[key] = v
+		kt, et := dst.Type().Key(), dst.Type().Elem()
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(kt).Elem()
+			if err := m.value(k, iter.Key(), path); err != nil {
+				return err
+			}
+			e := reflect.New(et).Elem()
+			if err := m.value(e, iter.Value(), fmt.Sprintf("%v[%v]", path, k)); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, e)
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Interface:
+		if src.Type().Implements(dst.Type()) {
+			dst.Set(src)
+			return nil
+		}
+	default:
+		if sameKindClass(src.Kind(), dst.Kind()) && src.Type().ConvertibleTo(dst.Type()) {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("cannot use %v as %v value", src.Type(), dst.Type())
+	}
+	return fmt.Errorf("cannot use %v as %v value in field %v", src.Type(), dst.Type(), path[1:])
+}
+
+// structFields sets the fields of the struct dst matching the fields of
+// src by the names and tags of the host struct.
+func (m *marshaler) structFields(dst, src reflect.Value, path string) error {
+	if !src.CanAddr() {
+		// unexported fields are accessed by address
+		v := reflect.New(src.Type()).Elem()
+		v.Set(src)
+		src = v
+	}
+	host, script := dst, src
+	if !m.toHost {
+		host, script = src, dst
+	}
+	for i := 0; i < host.NumField(); i++ {
+		f := host.Type().Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("gossa"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		sf, ok := script.Type().FieldByName(name)
+		if !ok || len(sf.Index) != 1 {
+			continue
+		}
+		hv, sv := host.Field(i), accessField(script.Field(sf.Index[0]))
+		var err error
+		if m.toHost {
+			err = m.value(hv, sv, path+"."+f.Name)
+		} else {
+			err = m.value(sv, hv, path+"."+sf.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accessField returns the field v, made settable if unexported.
+func accessField(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// sameKindClass reports whether a value of kind x converts to kind y without
+// changing of meaning, eg. numbers but not a number to a string.
+func sameKindClass(x, y reflect.Kind) bool {
+	return x == y || isNumberKind(x) && isNumberKind(y)
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,224 @@
+package gossa
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// SourceLoader is a Loader that loads imported packages from source for
+// interpretation. Files of source packages are parsed into FileSet, which
+// Context uses for the packages it loads itself.
+type SourceLoader interface {
+	Loader
+	FileSet() *token.FileSet
+	Source(pkg *types.Package) (files []*ast.File, info *types.Info, ok bool)
+}
+
+// GoModLoader is a SourceLoader resolving imports from the module of a
+// directory with go/packages, respecting GOFLAGS, build tags and vendor
+// directories. Registered packages, including the standard library, are
+// imported from the embedded Loader.
+type GoModLoader struct {
+	Loader                 // registered packages
+	Dir     string         // module directory
+	Tags    []string       // build tags
+	Sizes   types.Sizes    // types size of source packages
+	fset    *token.FileSet // source files
+	listed  map[string]*packages.Package
+	sources map[string]*sourcePackage // by import path
+	srcpkgs map[*types.Package]*sourcePackage
+}
+
+type sourcePackage struct {
+	pkg   *types.Package
+	files []*ast.File
+	info  *types.Info
+}
+
+// NewGoModLoader returns a GoModLoader for the module of dir, building
+// source packages with the build tags.
+func NewGoModLoader(dir string, tags []string) *GoModLoader {
+	return &GoModLoader{
+		Loader:  NewTypesLoader(0),
+		Dir:     dir,
+		Tags:    tags,
+		Sizes:   types.SizesFor("gc", runtime.GOARCH),
+		fset:    token.NewFileSet(),
+		listed:  make(map[string]*packages.Package),
+		sources: make(map[string]*sourcePackage),
+		srcpkgs: make(map[*types.Package]*sourcePackage),
+	}
+}
+
+// RegisterPackage registers or replaces pkg, see TypesLoader.RegisterPackage.
+// Source packages are type checked again on next import.
+func (l *GoModLoader) RegisterPackage(pkg *Package) {
+	if r, ok := l.Loader.(interface{ RegisterPackage(*Package) }); ok {
+		r.RegisterPackage(pkg)
+	}
+	l.sources = make(map[string]*sourcePackage)
+	l.srcpkgs = make(map[*types.Package]*sourcePackage)
+}
+
+func (l *GoModLoader) generation() int {
+	if r, ok := l.Loader.(generationLoader); ok {
+		return r.generation()
+	}
+	return 0
+}
+
+func (l *GoModLoader) lookupTypesAt(rt reflect.Type, gen int) (types.Type, bool) {
+	if r, ok := l.Loader.(generationLoader); ok {
+		return r.lookupTypesAt(rt, gen)
+	}
+	return l.Loader.LookupTypes(rt)
+}
+
+func (l *GoModLoader) FileSet() *token.FileSet {
+	return l.fset
+}
+
+func (l *GoModLoader) Source(pkg *types.Package) (files []*ast.File, info *types.Info, ok bool) {
+	if sp, ok := l.srcpkgs[pkg]; ok {
+		return sp.files, sp.info, true
+	}
+	return nil, nil, false
+}
+
+func (l *GoModLoader) Packages() []*types.Package {
+	pkgs := l.Loader.Packages()
+	for pkg := range l.srcpkgs {
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+func (l *GoModLoader) Import(path string) (*types.Package, error) {
+	if sp, ok := l.sources[path]; ok {
+		return sp.pkg, nil
+	}
+	if pkg, err := l.Loader.Import(path); err == nil {
+		return pkg, nil
+	} else if isStdPath(path) {
+		return nil, err
+	}
+	lp, err := l.list(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.check(path, lp)
+}
+
+// isStdPath reports whether path is an import path of the standard
+// library, whose first element has no dot.
+func isStdPath(path string) bool {
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	return !strings.Contains(path, ".")
+}
+
+// list lists the package of path and its dependencies with go/packages.
+func (l *GoModLoader) list(path string) (*packages.Package, error) {
+	if lp, ok := l.listed[path]; ok {
+		return lp, nil
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps,
+		Dir:  l.Dir,
+	}
+	if len(l.Tags) != 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(l.Tags, ",")}
+	}
+	lps, err := packages.Load(cfg, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(lps) != 1 {
+		return nil, fmt.Errorf("Not found package %v", path)
+	}
+	packages.Visit(lps, nil, func(lp *packages.Package) {
+		l.listed[lp.PkgPath] = lp
+	})
+	lp := lps[0]
+	l.listed[path] = lp
+	if len(lp.Errors) != 0 {
+		return nil, lp.Errors[0]
+	}
+	return lp, nil
+}
+
+// check parses and type checks the listed package lp imported as path.
+func (l *GoModLoader) check(path string, lp *packages.Package) (*types.Package, error) {
+	if sp, ok := l.sources[lp.PkgPath]; ok {
+		l.sources[path] = sp
+		return sp.pkg, nil
+	}
+	if len(lp.Errors) != 0 {
+		return nil, lp.Errors[0]
+	}
+	var files []*ast.File
+	for _, filename := range lp.GoFiles {
+		f, err := parser.ParseFile(l.fset, filename, nil, parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if diags := cgoDiagnostics(l.fset, files); len(diags) != 0 {
+		return nil, fmt.Errorf("%v", diags[0])
+	}
+	sp := &sourcePackage{
+		pkg:   types.NewPackage(lp.PkgPath, lp.Name),
+		files: files,
+		info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Scopes:     make(map[ast.Node]*types.Scope),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+	}
+	recordInstances(sp.info)
+	tc := &types.Config{
+		Importer: importerFunc(func(ipath string) (*types.Package, error) {
+			// resolve vendored imports
+			if ip, ok := lp.Imports[ipath]; ok {
+				if sp, ok := l.sources[ip.PkgPath]; ok {
+					return sp.pkg, nil
+				}
+				if pkg, err := l.Loader.Import(ip.PkgPath); err == nil {
+					return pkg, nil
+				}
+				return l.check(ip.PkgPath, ip)
+			}
+			return l.Import(ipath)
+		}),
+		Sizes: l.Sizes,
+	}
+	if err := checkFiles(tc, l.fset, sp.pkg, sp.info, files); err != nil {
+		return nil, err
+	}
+	if diags := genericDiagnostics(l.fset, sp.pkg, files, sp.info, l.Loader); len(diags) != 0 {
+		return nil, fmt.Errorf("%v", diags[0])
+	}
+	l.sources[path] = sp
+	l.sources[lp.PkgPath] = sp
+	l.srcpkgs[sp.pkg] = sp
+	return sp.pkg, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
@@ -0,0 +1,437 @@
+package gossa
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Dialer dials the network connections of a program, see SetDialer. A
+// Dialer with the Listen method of net.ListenConfig also listens for
+// net.Listen of the program.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+type netListener interface {
+	Listen(ctx context.Context, network, address string) (net.Listener, error)
+}
+
+// ErrNetDenied is the error of the network calls of a program denied by
+// its Dialer, see SetDialer.
+var ErrNetDenied = errors.New("denied by the network policy")
+
+// SetDialer sets the dialer of net.Dial, net.DialTimeout, the Dial and
+// DialContext methods of net.Dialer, tls.Dial, tls.DialWithDialer and the
+// HTTP transports of the program, for allowlists, proxies or logging of
+// the connections of untrusted scripts. net.Listen, the Listen method of
+// net.ListenConfig, the ListenAndServe of net/http and the servers of
+// httptest listen by d if it listens. The other dial, listen, lookup and
+// resolve functions and methods of net, net/http, crypto/tls, net/smtp,
+// net/rpc, net/rpc/jsonrpc, net/textproto and log/syslog fail with
+// ErrNetDenied, also when called through interfaces and method values. A
+// nil d restores the host network.
+//
+// Host code run by the program keeps dialing by the host network, eg. a
+// httputil.ReverseProxy with a nil Transport served by net/http. The
+// proxies of httputil.NewSingleHostReverseProxy, and the proxies served
+// by the program calling ServeHTTP, use the transport of the program.
+func (i *Interp) SetDialer(d Dialer) {
+	i.dialer = d
+	i.transports.Range(func(k, v interface{}) bool {
+		i.transports.Delete(k)
+		return true
+	})
+}
+
+// SetHTTPTransport sets the round tripper of http.Get, http.Head,
+// http.Post, http.PostForm and the http.Client of the program with a nil
+// Transport or http.DefaultTransport, for filtering or logging the
+// requests of untrusted scripts. A nil t restores http.DefaultTransport,
+// dialing by the dialer of SetDialer.
+func (i *Interp) SetHTTPTransport(t http.RoundTripper) {
+	i.transport = t
+}
+
+// dialContext dials by the dialer of i, with the timeout of d if any.
+func (i *Interp) dialContext(d *net.Dialer, ctx context.Context, network, address string) (net.Conn, error) {
+	if d != nil && d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	return i.dialer.DialContext(ctx, network, address)
+}
+
+// listen listens by the dialer of i if it listens.
+func (i *Interp) listen(ctx context.Context, network, address string) (net.Listener, error) {
+	if l, ok := i.dialer.(netListener); ok {
+		return l.Listen(ctx, network, address)
+	}
+	return nil, &net.OpError{Op: "listen", Net: network, Err: ErrNetDenied}
+}
+
+// dialTLS dials by the dialer of i like tls.DialWithDialer.
+func (i *Interp) dialTLS(d *net.Dialer, network, address string, config *tls.Config) (*tls.Conn, error) {
+	ctx := context.Background()
+	if d != nil && d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+	conn, err := i.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil || config.ServerName == "" {
+		host := address
+		if k := strings.LastIndex(host, ":"); k >= 0 {
+			host = host[:k]
+		}
+		if config == nil {
+			config = &tls.Config{}
+		} else {
+			config = config.Clone()
+		}
+		config.ServerName = host
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(dl)
+	}
+	c := tls.Client(conn, config)
+	if err := c.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// netDenied returns ext failing with ErrNetDenied while the program has a
+// Dialer, by a *net.OpError of op as the error result, or as the panic of
+// funcs without an error result.
+func (i *Interp) netDenied(op string, ext reflect.Value) reflect.Value {
+	typ := ext.Type()
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		if i.dialer == nil {
+			if typ.IsVariadic() {
+				return ext.CallSlice(args)
+			}
+			return ext.Call(args)
+		}
+		var err error = &net.OpError{Op: op, Err: ErrNetDenied}
+		n := typ.NumOut()
+		if n == 0 || typ.Out(n-1) != tyErrorInterface {
+			panic(err)
+		}
+		out := make([]reflect.Value, n)
+		for k := 0; k < n-1; k++ {
+			out[k] = reflect.Zero(typ.Out(k))
+		}
+		out[n-1] = reflect.ValueOf(&err).Elem()
+		return out
+	})
+}
+
+// roundTripper returns the round tripper of the program for rt, the
+// Transport of a client. The host transports dial by the dialer of i with
+// their settings kept.
+func (i *Interp) roundTripper(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil || rt == http.DefaultTransport {
+		if i.transport != nil {
+			return i.transport
+		}
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok || i.dialer == nil {
+		return rt
+	}
+	if v, ok := i.transports.Load(t); ok {
+		return v.(*http.Transport)
+	}
+	c := t.Clone()
+	c.Dial = nil
+	c.DialContext = i.dialer.DialContext
+	c.DialTLS = nil
+	c.DialTLSContext = nil
+	v, _ := i.transports.LoadOrStore(t, c)
+	return v.(*http.Transport)
+}
+
+// serve is the ListenAndServe of srv listening by the dialer of i.
+func (i *Interp) serve(srv *http.Server) error {
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	l, err := i.listen(context.Background(), "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(l)
+}
+
+// proxyTransport is the Transport of the reverse proxies of the program,
+// the round tripper of the program for rt at each request.
+type proxyTransport struct {
+	interp *Interp
+	rt     http.RoundTripper
+}
+
+func (t *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.interp.roundTripper(t.rt).RoundTrip(req)
+}
+
+// reverseProxy returns p, or a copy of p, using the round tripper of the
+// program.
+func (i *Interp) reverseProxy(p *httputil.ReverseProxy) *httputil.ReverseProxy {
+	if t, ok := p.Transport.(*proxyTransport); ok && t.interp == i {
+		return p
+	}
+	c := *p
+	c.Transport = &proxyTransport{interp: i, rt: p.Transport}
+	return &c
+}
+
+// testServer is httptest.NewUnstartedServer listening by the dialer of i.
+func (i *Interp) testServer(handler http.Handler) *httptest.Server {
+	l, err := i.listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("httptest: failed to listen on a port: %v", err))
+	}
+	return &httptest.Server{Listener: l, Config: &http.Server{Handler: handler}}
+}
+
+// client returns a copy of c using the round tripper of the program.
+func (i *Interp) client(c *http.Client) *http.Client {
+	cc := *c
+	cc.Transport = i.roundTripper(c.Transport)
+	return &cc
+}
+
+// isNetPkg reports whether path is a package of the network policy.
+func isNetPkg(path string) bool {
+	switch path {
+	case "net", "net/http", "net/http/httptest", "net/http/httputil", "crypto/tls",
+		"net/smtp", "net/rpc", "net/rpc/jsonrpc", "net/textproto", "log/syslog":
+		return true
+	}
+	return false
+}
+
+// isNetFunc reports whether fn is a function or method of a package of
+// the network policy, see findNetFunc.
+func isNetFunc(fn *ssa.Function) bool {
+	return fn.Pkg != nil && isNetPkg(fn.Pkg.Pkg.Path())
+}
+
+// netAccess returns the op of the errors of the function or method name
+// of the network package pkgPath, if it dials, listens or resolves names.
+func netAccess(pkgPath, name string) (op string, ok bool) {
+	switch {
+	case strings.HasPrefix(name, "Dial"), name == "SendMail":
+		return "dial", true
+	case pkgPath == "log/syslog" && (name == "New" || name == "NewLogger"):
+		// the local syslog server
+		return "dial", true
+	case strings.HasPrefix(name, "Listen"):
+		return "listen", true
+	case strings.HasPrefix(name, "Lookup"), strings.HasPrefix(name, "Resolve"):
+		return "lookup", true
+	}
+	return
+}
+
+// netMethod returns the full name of the method name of the host type
+// rtype, if rtype is a named type of a network package.
+func netMethod(rtype reflect.Type, name string) (pkgPath, fullName string, ok bool) {
+	pkgPath, fullName, ok = externMethodName(rtype, name)
+	if !ok || !isNetPkg(pkgPath) {
+		return "", "", false
+	}
+	return
+}
+
+// findNetMethod returns ext, the method name of the host type rtype,
+// bound to the network policy of i, for the calls of the method through
+// interfaces.
+func (i *Interp) findNetMethod(rtype reflect.Type, name string, ext reflect.Value) reflect.Value {
+	pkgPath, fullName, ok := netMethod(rtype, name)
+	if !ok {
+		return ext
+	}
+	if fn, ok := findNetFunc(i, fullName); ok {
+		return fn
+	}
+	if op, ok := netAccess(pkgPath, name); ok {
+		return i.netDenied(op, ext)
+	}
+	return ext
+}
+
+// findNetFunc returns the function name of a network package bound to the
+// network policy of interp, see SetDialer and SetHTTPTransport.
+func findNetFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	var fn interface{}
+	switch name {
+	case "net.Dial":
+		fn = func(network, address string) (net.Conn, error) {
+			if interp.dialer == nil {
+				return net.Dial(network, address)
+			}
+			return interp.dialContext(nil, context.Background(), network, address)
+		}
+	case "net.DialTimeout":
+		fn = func(network, address string, timeout time.Duration) (net.Conn, error) {
+			if interp.dialer == nil {
+				return net.DialTimeout(network, address, timeout)
+			}
+			return interp.dialContext(&net.Dialer{Timeout: timeout}, context.Background(), network, address)
+		}
+	case "(*net.Dialer).Dial":
+		fn = func(d *net.Dialer, network, address string) (net.Conn, error) {
+			if interp.dialer == nil {
+				return d.Dial(network, address)
+			}
+			return interp.dialContext(d, context.Background(), network, address)
+		}
+	case "(*net.Dialer).DialContext":
+		fn = func(d *net.Dialer, ctx context.Context, network, address string) (net.Conn, error) {
+			if interp.dialer == nil {
+				return d.DialContext(ctx, network, address)
+			}
+			return interp.dialContext(d, ctx, network, address)
+		}
+	case "net.Listen":
+		fn = func(network, address string) (net.Listener, error) {
+			if interp.dialer == nil {
+				return net.Listen(network, address)
+			}
+			return interp.listen(context.Background(), network, address)
+		}
+	case "(*net.ListenConfig).Listen":
+		fn = func(lc *net.ListenConfig, ctx context.Context, network, address string) (net.Listener, error) {
+			if interp.dialer == nil {
+				return lc.Listen(ctx, network, address)
+			}
+			return interp.listen(ctx, network, address)
+		}
+	case "crypto/tls.Dial":
+		fn = func(network, address string, config *tls.Config) (*tls.Conn, error) {
+			if interp.dialer == nil {
+				return tls.Dial(network, address, config)
+			}
+			return interp.dialTLS(nil, network, address, config)
+		}
+	case "crypto/tls.DialWithDialer":
+		fn = func(d *net.Dialer, network, address string, config *tls.Config) (*tls.Conn, error) {
+			if interp.dialer == nil {
+				return tls.DialWithDialer(d, network, address, config)
+			}
+			return interp.dialTLS(d, network, address, config)
+		}
+	case "net/http.ListenAndServe":
+		fn = func(addr string, handler http.Handler) error {
+			if interp.dialer == nil {
+				return http.ListenAndServe(addr, handler)
+			}
+			return interp.serve(&http.Server{Addr: addr, Handler: handler})
+		}
+	case "(*net/http.Server).ListenAndServe":
+		fn = func(srv *http.Server) error {
+			if interp.dialer == nil {
+				return srv.ListenAndServe()
+			}
+			return interp.serve(srv)
+		}
+	case "net/http/httptest.NewServer":
+		fn = func(handler http.Handler) *httptest.Server {
+			if interp.dialer == nil {
+				return httptest.NewServer(handler)
+			}
+			s := interp.testServer(handler)
+			s.Start()
+			return s
+		}
+	case "net/http/httptest.NewTLSServer":
+		fn = func(handler http.Handler) *httptest.Server {
+			if interp.dialer == nil {
+				return httptest.NewTLSServer(handler)
+			}
+			s := interp.testServer(handler)
+			s.StartTLS()
+			return s
+		}
+	case "net/http/httptest.NewUnstartedServer":
+		fn = func(handler http.Handler) *httptest.Server {
+			if interp.dialer == nil {
+				return httptest.NewUnstartedServer(handler)
+			}
+			return interp.testServer(handler)
+		}
+	case "net/http/httputil.NewSingleHostReverseProxy":
+		fn = func(target *url.URL) *httputil.ReverseProxy {
+			return interp.reverseProxy(httputil.NewSingleHostReverseProxy(target))
+		}
+	case "(*net/http/httputil.ReverseProxy).ServeHTTP":
+		fn = func(p *httputil.ReverseProxy, w http.ResponseWriter, req *http.Request) {
+			interp.reverseProxy(p).ServeHTTP(w, req)
+		}
+	case "net/http.Get":
+		fn = func(url string) (*http.Response, error) {
+			return interp.client(http.DefaultClient).Get(url)
+		}
+	case "net/http.Head":
+		fn = func(url string) (*http.Response, error) {
+			return interp.client(http.DefaultClient).Head(url)
+		}
+	case "net/http.Post":
+		fn = func(url, contentType string, body io.Reader) (*http.Response, error) {
+			return interp.client(http.DefaultClient).Post(url, contentType, body)
+		}
+	case "net/http.PostForm":
+		fn = func(url string, data url.Values) (*http.Response, error) {
+			return interp.client(http.DefaultClient).PostForm(url, data)
+		}
+	case "(*net/http.Client).Do":
+		fn = func(c *http.Client, req *http.Request) (*http.Response, error) {
+			return interp.client(c).Do(req)
+		}
+	case "(*net/http.Client).Get":
+		fn = func(c *http.Client, url string) (*http.Response, error) {
+			return interp.client(c).Get(url)
+		}
+	case "(*net/http.Client).Head":
+		fn = func(c *http.Client, url string) (*http.Response, error) {
+			return interp.client(c).Head(url)
+		}
+	case "(*net/http.Client).Post":
+		fn = func(c *http.Client, url, contentType string, body io.Reader) (*http.Response, error) {
+			return interp.client(c).Post(url, contentType, body)
+		}
+	case "(*net/http.Client).PostForm":
+		fn = func(c *http.Client, url string, data url.Values) (*http.Response, error) {
+			return interp.client(c).PostForm(url, data)
+		}
+	case "(*net/http.Transport).RoundTrip":
+		fn = func(t *http.Transport, req *http.Request) (*http.Response, error) {
+			return interp.roundTripper(t).RoundTrip(req)
+		}
+	default:
+		return
+	}
+	return reflect.ValueOf(fn), true
+}
@@ -1,13 +1,16 @@
 package gossa
 
 import (
+	"context"
 	"fmt"
 	"go/token"
 	"go/types"
-	"os"
 	"reflect"
+	"runtime/pprof"
 	"strings"
 	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
 
 	"github.com/goplus/reflectx"
@@ -75,15 +78,21 @@ const (
 
 type Function struct {
 	Interp           *Interp
-	Fn               *ssa.Function        // ssa function
-	Main             *ssa.BasicBlock      // Fn.Blocks[0]
-	Instrs           []func(fr *frame)    // main instrs
-	Recover          []func(fr *frame)    // recover instrs
-	Blocks           []int                // block offset
-	stack            []value              // stack
-	ssaInstrs        []ssa.Instruction    // org ssa instr
-	index            map[ssa.Value]uint32 // stack index
-	mapUnderscoreKey map[types.Type]bool
+	Fn               *ssa.Function          // ssa function
+	Main             *ssa.BasicBlock        // Fn.Blocks[0]
+	Instrs           []func(fr *frame)      // main instrs
+	Recover          []func(fr *frame)      // recover instrs
+	Blocks           []int                  // block offset
+	stack            []value                // stack
+	ssaInstrs        []ssa.Instruction      // org ssa instr
+	pos              []token.Pos            // position of Instrs, see PosForPC
+	index            map[ssa.Value]uint32   // stack index
+	mapUnderscoreKey map[types.Type]bool    // set at compile time, read by calls
+	labels           context.Context        // pprof labels for EnablePprofLabels
+	labelSet         pprof.LabelSet         // pprof label set of labels
+	blockInfos       []*BlockInfo           // compiled block infos
+	inlining         map[*ssa.Function]bool // functions being inlined, see makeInlineCall
+	site             *inlineSite            // inlined call being compiled, see makeInlineCall
 }
 
 func (p *Function) InstrForPC(pc int) ssa.Instruction {
@@ -93,13 +102,21 @@ func (p *Function) InstrForPC(pc int) ssa.Instruction {
 	return nil
 }
 
+// PosForPC returns the position of the instruction at pc, or of the
+// nearest preceding instruction with a position, eg. for implicit loads.
 func (p *Function) PosForPC(pc int) token.Pos {
-	if instr := p.InstrForPC(pc); instr != nil {
-		return instr.Pos()
+	if pc >= 0 && pc < len(p.pos) {
+		return p.pos[pc]
 	}
 	return token.NoPos
 }
 
+// hasRecover reports whether p has a recover block. It is decided from the
+// SSA function, as p may still be compiling in another worker.
+func (p *Function) hasRecover() bool {
+	return p.Fn.Recover != nil && p.Interp.mode&DisableRecover == 0
+}
+
 func (p *Function) regIndex(v ssa.Value) int {
 	instr := p.regInstr(v)
 	return int(instr & 0xffffff)
@@ -145,22 +162,81 @@ func (p *Function) regInstr(v ssa.Value) uint32 {
 }
 
 func findExternFunc(interp *Interp, fn *ssa.Function) (ext reflect.Value, ok bool) {
+	if fn.Synthetic == instanceSynthetic {
+		return findInstanceFunc(interp, fn)
+	}
 	fnName := fn.String()
 	if fnName == "os.Exit" {
 		return reflect.ValueOf(func(code int) {
-			if interp.exited {
-				os.Exit(code)
-			} else {
-				panic(exitPanic(code))
-			}
+			panic(exitPanic(code))
 		}), true
 	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == LogPkgPath {
+		if ext, ok = findLogFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == StorePkgPath {
+		if ext, ok = findStoreFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == GoroutinePkgPath {
+		if ext, ok = findGoroutineFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == EventPkgPath {
+		if ext, ok = findEventFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
 	// check override func
 	ext, ok = interp.ctx.override[fnName]
 	if ok {
 		return
 	}
-	// check extern func
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == "reflect" {
+		if ext, ok = findReflectFunc(interp, fnName); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == "fmt" {
+		if ext, ok = findFmtFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == "time" {
+		if ext, ok = findClockFunc(interp, fnName); ok {
+			return
+		}
+	}
+	if fn.Pkg != nil && fn.Pkg.Pkg.Path() == "math/rand" && fn.Signature.Recv() == nil {
+		if ext, ok = findRandFunc(interp, fn.Name()); ok {
+			return
+		}
+	}
+	if isNetFunc(fn) {
+		if ext, ok = findNetFunc(interp, fnName); ok {
+			return
+		}
+		if op, access := netAccess(fn.Pkg.Pkg.Path(), fn.Name()); access {
+			if ext, ok = findExternValue(interp, fn, fnName); ok {
+				ext = interp.netDenied(op, ext)
+			}
+			return
+		}
+	}
+	if isProcFunc(fn) {
+		if ext, ok = findProcFunc(interp, fnName); ok {
+			return
+		}
+	}
+	return findExternValue(interp, fn, fnName)
+}
+
+// findExternValue returns the extern func or method fn of full name fnName.
+func findExternValue(interp *Interp, fn *ssa.Function, fnName string) (ext reflect.Value, ok bool) {
 	ext, ok = externValues[fnName]
 	if ok {
 		return
@@ -220,8 +296,16 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		ir := pfn.regIndex(instr)
 		ix := pfn.regIndex(instr.X)
 		iy := pfn.regIndex(instr.Y)
+		if interp.mode&CheckOverflow != 0 {
+			if fn := makeOverflowCheck(interp, instr, ir, ix, iy); fn != nil {
+				return fn
+			}
+		}
 		switch instr.Op {
 		case token.ADD:
+			if t, ok := instr.Type().Underlying().(*types.Basic); ok && t.Info()&types.IsString != 0 {
+				return makeStringADD(interp.preToType(instr.Type()), ir, ix, iy)
+			}
 			return func(fr *frame) {
 				fr.setReg(ir, opADD(fr.reg(ix), fr.reg(iy)))
 			}
@@ -311,6 +395,8 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		if kx.isStatic() {
 			if vx == nil {
 				vx = reflect.New(typ).Elem().Interface()
+			} else {
+				vx = changeType(reflect.ValueOf(vx), typ).Interface()
 			}
 			return func(fr *frame) {
 				fr.setReg(ir, vx)
@@ -321,7 +407,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 			if x == nil {
 				fr.setReg(ir, reflect.New(typ).Elem().Interface())
 			} else {
-				fr.setReg(ir, reflect.ValueOf(x).Convert(typ).Interface())
+				fr.setReg(ir, changeType(reflect.ValueOf(x), typ).Interface())
 			}
 		}
 	case *ssa.Convert:
@@ -330,6 +416,17 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		typ := interp.preToType(instr.Type())
 		ir := pfn.regIndex(instr)
 		ix, kx, vx := pfn.regIndex3(instr.X)
+		if interp.mode&DisableMethodSynthesis != 0 && typ != tyEmptyInterface {
+			if kx.isStatic() {
+				vx = interp.wrapIface(typ, vx)
+				return func(fr *frame) {
+					fr.setReg(ir, vx)
+				}
+			}
+			return func(fr *frame) {
+				fr.setReg(ir, interp.wrapIface(typ, fr.reg(ix)))
+			}
+		}
 		if kx.isStatic() {
 			if typ == tyEmptyInterface {
 				return func(fr *frame) {
@@ -366,6 +463,20 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 			ib[i] = pfn.regIndex(v)
 		}
 		pfn := interp.loadFunction(fn)
+		if obj, ok := fn.Object().(*types.Func); ok && obj.Exported() &&
+			strings.HasPrefix(fn.Synthetic, "bound method wrapper") {
+			name := obj.Name()
+			return func(fr *frame) {
+				recv := fr.reg(ib[0])
+				if recv != nil {
+					if index := interp.boundMethod(reflect.TypeOf(recv), name, typ); index >= 0 {
+						fr.setReg(ir, reflect.ValueOf(recv).Method(index).Interface())
+						return
+					}
+				}
+				fr.setReg(ir, interp.makeFunc(typ, pfn, []value{recv}).Interface())
+			}
+		}
 		return func(fr *frame) {
 			var bindings []value
 			for i, _ := range instr.Bindings {
@@ -377,11 +488,22 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		typ := interp.preToType(instr.Type())
 		ir := pfn.regIndex(instr)
 		is := pfn.regIndex(instr.Size)
+		if typ.ChanDir() != reflect.BothDir {
+			// make(chan<- T) makes a channel of chan T
+			ctyp := reflect.ChanOf(reflect.BothDir, typ.Elem())
+			return func(fr *frame) {
+				buffer := asInt(fr.reg(is))
+				if buffer < 0 {
+					panic(runtimeError{msg: "makechan: size out of range"})
+				}
+				fr.setReg(ir, convertChan(reflect.MakeChan(ctyp, buffer), typ).Interface())
+			}
+		}
 		return func(fr *frame) {
 			size := fr.reg(is)
 			buffer := asInt(size)
 			if buffer < 0 {
-				panic(runtimeError("makechan: size out of range"))
+				panic(runtimeError{msg: "makechan: size out of range"})
 			}
 			fr.setReg(ir, reflect.MakeChan(typ, buffer).Interface())
 		}
@@ -412,11 +534,11 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		return func(fr *frame) {
 			Len := asInt(fr.reg(il))
 			if Len < 0 || Len >= maxMemLen {
-				panic(runtimeError("makeslice: len out of range"))
+				panic(runtimeError{msg: "makeslice: len out of range"})
 			}
 			Cap := asInt(fr.reg(ic))
 			if Cap < 0 || Cap >= maxMemLen {
-				panic(runtimeError("makeslice: cap out of range"))
+				panic(runtimeError{msg: "makeslice: cap out of range"})
 			}
 			fr.setReg(ir, reflect.MakeSlice(typ, Len, Cap).Interface())
 		}
@@ -444,7 +566,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		return func(fr *frame) {
 			v, err := FieldAddr(fr.reg(ix), instr.Field)
 			if err != nil {
-				panic(runtimeError(err.Error()))
+				panic(runtimeError{msg: err.Error()})
 			}
 			fr.setReg(ir, v)
 		}
@@ -454,7 +576,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		return func(fr *frame) {
 			v, err := Field(fr.reg(ix), instr.Field)
 			if err != nil {
-				panic(runtimeError(err.Error()))
+				panic(runtimeError{msg: err.Error()})
 			}
 			fr.setReg(ir, v)
 		}
@@ -473,15 +595,15 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 			case reflect.Slice:
 			case reflect.Array:
 			case reflect.Invalid:
-				panic(runtimeError("invalid memory address or nil pointer dereference"))
+				panic(runtimeError{msg: "invalid memory address or nil pointer dereference"})
 			default:
 				panic(fmt.Sprintf("unexpected x type in IndexAddr: %T", x))
 			}
 			index := asInt(idx)
 			if index < 0 {
-				panic(runtimeError(fmt.Sprintf("index out of range [%v]", index)))
+				panic(runtimeError{msg: fmt.Sprintf("index out of range [%v]", index)})
 			} else if length := v.Len(); index >= length {
-				panic(runtimeError(fmt.Sprintf("index out of range [%v] with length %v", index, length)))
+				panic(runtimeError{msg: fmt.Sprintf("index out of range [%v] with length %v", index, length)})
 			}
 			fr.setReg(ir, v.Index(index).Addr().Interface())
 		}
@@ -508,12 +630,16 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 				fr.setReg(ir, reflect.ValueOf(v).String()[asInt(idx)])
 			}
 		case reflect.Map:
+			if fn := makeMapLookup(typ, instr.CommaOk, ir, ix, ii); fn != nil {
+				return fn
+			}
+			ktyp := typ.Key()
 			if pfn.mapUnderscoreKey[instr.X.Type()] {
 				return func(fr *frame) {
 					m := fr.reg(ix)
 					idx := fr.reg(ii)
 					vm := reflect.ValueOf(m)
-					vk := reflect.ValueOf(idx)
+					vk := mapKey(ktyp, idx)
 					for _, vv := range vm.MapKeys() {
 						if equalStruct(vk, vv) {
 							vk = vv
@@ -535,6 +661,26 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 					}
 				}
 			}
+			if hasInterface(ktyp) {
+				return func(fr *frame) {
+					m := fr.reg(ix)
+					idx := fr.reg(ii)
+					vm := reflect.ValueOf(m)
+					v := vm.MapIndex(mapKey(ktyp, idx))
+					ok := v.IsValid()
+					var rv value
+					if ok {
+						rv = v.Interface()
+					} else {
+						rv = reflect.New(typ.Elem()).Elem().Interface()
+					}
+					if instr.CommaOk {
+						fr.setReg(ir, tuple{rv, ok})
+					} else {
+						fr.setReg(ir, rv)
+					}
+				}
+			}
 			return func(fr *frame) {
 				m := fr.reg(ix)
 				idx := fr.reg(ii)
@@ -560,46 +706,59 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		ir := pfn.regIndex(instr)
 		ic := make([]int, len(instr.States))
 		is := make([]int, len(instr.States))
+		dirs := make([]reflect.SelectDir, len(instr.States))
+		zeros := make([]reflect.Value, len(instr.States))
 		for i, state := range instr.States {
 			ic[i] = pfn.regIndex(state.Chan)
+			if state.Dir == types.RecvOnly {
+				dirs[i] = reflect.SelectRecv
+			} else {
+				dirs[i] = reflect.SelectSend
+			}
 			if state.Send != nil {
 				is[i] = pfn.regIndex(state.Send)
 			}
+			zeros[i] = reflect.Zero(interp.preToType(state.Chan.Type()).Elem())
+		}
+		var offset int
+		if !instr.Blocking {
+			offset = 1
 		}
 		return func(fr *frame) {
-			var cases []reflect.SelectCase
-			if !instr.Blocking {
-				cases = append(cases, reflect.SelectCase{
-					Dir: reflect.SelectDefault,
-				})
+			// reuse the frame scratch cases, select in loop is common
+			n := len(instr.States) + offset
+			if cap(fr.cases) < n {
+				fr.cases = make([]reflect.SelectCase, n)
+			}
+			cases := fr.cases[:n]
+			if offset == 1 {
+				cases[0] = reflect.SelectCase{Dir: reflect.SelectDefault}
 			}
 			for i, state := range instr.States {
-				var dir reflect.SelectDir
-				if state.Dir == types.RecvOnly {
-					dir = reflect.SelectRecv
-				} else {
-					dir = reflect.SelectSend
+				c := &cases[i+offset]
+				c.Dir = dirs[i]
+				c.Send = reflect.Value{}
+				c.Chan = reflect.ValueOf(fr.reg(ic[i]))
+				if !c.Chan.IsValid() || c.Chan.IsNil() {
+					// a nil channel is never ready, reflect.Select
+					// ignores the case of a zero Chan
+					c.Chan = reflect.Value{}
+					continue
 				}
-				ch := reflect.ValueOf(fr.reg(ic[i]))
-				var send reflect.Value
 				if state.Send != nil {
-					v := fr.reg(is[i])
-					if v == nil {
-						send = reflect.New(ch.Type().Elem()).Elem()
+					if v := fr.reg(is[i]); v == nil {
+						c.Send = zeros[i]
 					} else {
-						send = reflect.ValueOf(v)
+						c.Send = reflect.ValueOf(v)
 					}
 				}
-				cases = append(cases, reflect.SelectCase{
-					Dir:  dir,
-					Chan: ch,
-					Send: send,
-				})
 			}
 			chosen, recv, recvOk := reflect.Select(cases)
-			if !instr.Blocking {
-				chosen-- // default case should have index -1.
+			for i := range cases {
+				// drop references to channels and values
+				cases[i] = reflect.SelectCase{}
 			}
+			chosen -= offset // default case should have index -1.
 			r := tuple{chosen, recvOk}
 			for n, st := range instr.States {
 				if st.Dir == types.RecvOnly {
@@ -608,8 +767,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 						// No need to copy since send makes an unaliased copy.
 						v = recv.Interface()
 					} else {
-						typ := interp.toType(st.Chan.Type())
-						v = reflect.New(typ.Elem()).Elem().Interface()
+						v = zeros[n].Interface()
 					}
 					r = append(r, v)
 				}
@@ -626,7 +784,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 			vLen := v.Len()
 			tLen := typ.Elem().Len()
 			if tLen > vLen {
-				panic(runtimeError(fmt.Sprintf("cannot convert slice with length %v to pointer to array with length %v", vLen, tLen)))
+				panic(runtimeError{msg: fmt.Sprintf("cannot convert slice with length %v to pointer to array with length %v", vLen, tLen)})
 			}
 			fr.setReg(ir, v.Convert(typ).Interface())
 		}
@@ -645,13 +803,25 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 				v := fr.reg(ix)
 				fr.setReg(ir, &mapIter{iter: reflect.ValueOf(v).MapRange()})
 			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return func(fr *frame) {
+				v := fr.reg(ix)
+				fr.setReg(ir, newIntIter(reflect.ValueOf(v)))
+			}
 		default:
-			panic("unreachable")
+			panic(fmt.Errorf("range over %v is not supported", instr.X.Type()))
 		}
 	case *ssa.Next:
 		ir := pfn.regIndex(instr)
 		ii := pfn.regIndex(instr.Iter)
 		if instr.IsString {
+			// the builder emits a range over an integer as over a string
+			if t, ok := instr.Iter.(*ssa.Range).X.Type().Underlying().(*types.Basic); ok && t.Info()&types.IsInteger != 0 {
+				return func(fr *frame) {
+					fr.setReg(ir, fr.reg(ii).(*intIter).next())
+				}
+			}
 			return func(fr *frame) {
 				fr.setReg(ir, fr.reg(ii).(*stringIter).next())
 			}
@@ -684,12 +854,37 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		}
 	// Instructions executed for effect
 	case *ssa.Jump:
+		if b := instr.Block(); b.Succs[0].Index <= b.Index {
+			// loop back edge, a safepoint of RunFuncTimeout
+			return func(fr *frame) {
+				if fr.deadline != nil {
+					fr.deadline.check(fr)
+				}
+				fr.pred, fr.block = fr.block.Index, fr.block.Succs[0]
+				fr.pc = fr.pfn.Blocks[fr.block.Index]
+			}
+		}
 		return func(fr *frame) {
 			fr.pred, fr.block = fr.block.Index, fr.block.Succs[0]
 			fr.pc = fr.pfn.Blocks[fr.block.Index]
 		}
 	case *ssa.If:
 		ic := pfn.regIndex(instr.Cond)
+		if b := instr.Block(); b.Succs[0].Index <= b.Index || b.Succs[1].Index <= b.Index {
+			// loop back edge, a safepoint of RunFuncTimeout
+			return func(fr *frame) {
+				if fr.deadline != nil {
+					fr.deadline.check(fr)
+				}
+				fr.pred = fr.block.Index
+				if reflect.ValueOf(fr.reg(ic)).Bool() {
+					fr.block = fr.block.Succs[0]
+				} else {
+					fr.block = fr.block.Succs[1]
+				}
+				fr.pc = fr.pfn.Blocks[fr.block.Index]
+			}
+		}
 		switch instr.Cond.Type().(type) {
 		case *types.Basic:
 			return func(fr *frame) {
@@ -741,17 +936,17 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 	case *ssa.Panic:
 		ix := pfn.regIndex(instr.X)
 		return func(fr *frame) {
-			panic(targetPanic{fr.reg(ix)})
+			panic(targetPanic{fr.reg(ix), fr})
 		}
 	case *ssa.Go:
 		iv, ia, ib := getCallIndex(pfn, &instr.Call)
 		return func(fr *frame) {
 			fn, args := interp.prepareCall(fr, &instr.Call, iv, ia, ib)
 			atomic.AddInt32(&interp.goroutines, 1)
-			go func() {
+			go interp.spawn(instr, interp.goroutineRun(), func() {
 				interp.callDiscardsResult(nil, fn, args, instr.Call.Args)
 				atomic.AddInt32(&interp.goroutines, -1)
-			}()
+			})
 		}
 	case *ssa.Defer:
 		iv, ia, ib := getCallIndex(pfn, &instr.Call)
@@ -768,12 +963,15 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 	case *ssa.Send:
 		ic := pfn.regIndex(instr.Chan)
 		ix := pfn.regIndex(instr.X)
+		zero := reflect.Zero(interp.preToType(instr.Chan.Type()).Elem())
 		return func(fr *frame) {
-			c := fr.reg(ic)
-			x := fr.reg(ix)
-			ch := reflect.ValueOf(c)
-			if x == nil {
-				ch.Send(reflect.New(ch.Type().Elem()).Elem())
+			ch := reflect.ValueOf(fr.reg(ic))
+			if !ch.IsValid() {
+				// send on nil channel blocks forever
+				select {}
+			}
+			if x := fr.reg(ix); x == nil {
+				ch.Send(zero)
 			} else {
 				ch.Send(reflect.ValueOf(x))
 			}
@@ -815,23 +1013,36 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 		im := pfn.regIndex(instr.Map)
 		ik := pfn.regIndex(instr.Key)
 		iv, kv, vv := pfn.regIndex3(instr.Value)
+		typ := interp.preToType(instr.Map.Type())
+		if fn := makeMapUpdate(typ, im, ik, iv); fn != nil {
+			return fn
+		}
+		ktyp := typ.Key()
+		// nil interface values are stored, not deleted
+		zero := reflect.Zero(typ.Elem())
+		elem := func(v value) reflect.Value {
+			if v == nil {
+				return zero
+			}
+			return reflect.ValueOf(v)
+		}
 		if pfn.mapUnderscoreKey[instr.Map.Type()] {
 			if kv.isStatic() {
 				return func(fr *frame) {
 					vm := reflect.ValueOf(fr.reg(im))
-					vk := reflect.ValueOf(fr.reg(ik))
+					vk := mapKey(ktyp, fr.reg(ik))
 					for _, v := range vm.MapKeys() {
 						if equalStruct(vk, v) {
 							vk = v
 							break
 						}
 					}
-					vm.SetMapIndex(vk, reflect.ValueOf(vv))
+					vm.SetMapIndex(vk, elem(vv))
 				}
 			}
 			return func(fr *frame) {
 				vm := reflect.ValueOf(fr.reg(im))
-				vk := reflect.ValueOf(fr.reg(ik))
+				vk := mapKey(ktyp, fr.reg(ik))
 				v := fr.reg(iv)
 				for _, vv := range vm.MapKeys() {
 					if equalStruct(vk, vv) {
@@ -839,14 +1050,27 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 						break
 					}
 				}
-				vm.SetMapIndex(vk, reflect.ValueOf(v))
+				vm.SetMapIndex(vk, elem(v))
+			}
+		}
+		if hasInterface(ktyp) {
+			return func(fr *frame) {
+				vm := reflect.ValueOf(fr.reg(im))
+				vm.SetMapIndex(mapKey(ktyp, fr.reg(ik)), elem(fr.reg(iv)))
 			}
 		}
 		if kv.isStatic() {
+			ve := elem(vv)
 			return func(fr *frame) {
 				vm := reflect.ValueOf(fr.reg(im))
 				vk := reflect.ValueOf(fr.reg(ik))
-				vm.SetMapIndex(vk, reflect.ValueOf(vv))
+				vm.SetMapIndex(vk, ve)
+			}
+		} else if typ.Elem().Kind() == reflect.Interface {
+			return func(fr *frame) {
+				vm := reflect.ValueOf(fr.reg(im))
+				vk := reflect.ValueOf(fr.reg(ik))
+				vm.SetMapIndex(vk, elem(fr.reg(iv)))
 			}
 		} else {
 			return func(fr *frame) {
@@ -864,7 +1088,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 				ref.toValue = func() (*types.Var, interface{}, bool) {
 					return v, fr.reg(ix), true
 				}
-				interp.ctx.debugFunc(ref)
+				interp.debugFunc(ref)
 			}
 		}
 		return func(fr *frame) {
@@ -872,7 +1096,7 @@ func makeInstr(interp *Interp, pfn *Function, instr ssa.Instruction) func(fr *fr
 			ref.toValue = func() (*types.Var, interface{}, bool) {
 				return nil, nil, false
 			}
-			interp.ctx.debugFunc(ref)
+			interp.debugFunc(ref)
 		}
 	default:
 		panic(fmt.Errorf("unreachable %T", instr))
@@ -900,6 +1124,12 @@ func makeConvertInstr(pfn *Function, interp *Interp, instr *ssa.Convert) func(fr
 	vk := xtyp.Kind()
 	ir := pfn.regIndex(instr)
 	ix := pfn.regIndex(instr.X)
+	if fn := makeStringConvert(typ, xtyp, ir, ix); fn != nil {
+		return fn
+	}
+	if fn := makeFloatConvert(typ, xtyp, ir, ix); fn != nil {
+		return fn
+	}
 	switch typ.Kind() {
 	case reflect.UnsafePointer:
 		if vk == reflect.Uintptr {
@@ -980,6 +1210,144 @@ func makeConvertInstr(pfn *Function, interp *Interp, instr *ssa.Convert) func(fr
 	}
 }
 
+// makeStringConvert returns the conversion of xtyp to a string, byte slice
+// or rune slice type, or nil for other types. Named types and slices of
+// named element types are boxed by the type word of typ, like
+// makeStringADD. Strings are decoded like the Go runtime, invalid UTF-8
+// bytes are utf8.RuneError, and runes and integers of invalid code points
+// are encoded as "\uFFFD".
+func makeStringConvert(typ reflect.Type, xtyp reflect.Type, ir int, ix int) func(fr *frame) {
+	var conv func(x value) value
+	switch typ.Kind() {
+	case reflect.Slice:
+		if xtyp.Kind() != reflect.String {
+			return nil
+		}
+		switch typ.Elem().Kind() {
+		case reflect.Uint8:
+			conv = func(x value) value {
+				return []byte(*(*string)(valueWord(x)))
+			}
+		case reflect.Int32:
+			conv = func(x value) value {
+				return []rune(*(*string)(valueWord(x)))
+			}
+		}
+	case reflect.String:
+		switch xtyp.Kind() {
+		case reflect.Slice:
+			switch xtyp.Elem().Kind() {
+			case reflect.Uint8:
+				conv = func(x value) value {
+					return string(*(*[]byte)(valueWord(x)))
+				}
+			case reflect.Int32:
+				conv = func(x value) value {
+					return string(*(*[]rune)(valueWord(x)))
+				}
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			conv = func(x value) value {
+				n := reflect.ValueOf(x).Int()
+				if int64(rune(n)) != n {
+					n = utf8.RuneError
+				}
+				return string(rune(n))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			conv = func(x value) value {
+				n := reflect.ValueOf(x).Uint()
+				if n > unicode.MaxRune {
+					n = utf8.RuneError
+				}
+				return string(rune(n))
+			}
+		}
+	}
+	if conv == nil {
+		return nil
+	}
+	if typ == tyString || typ == tyBytes || typ == tyRunes {
+		return func(fr *frame) {
+			fr.setReg(ir, conv(fr.reg(ix)))
+		}
+	}
+	z := reflect.Zero(typ).Interface()
+	rtyp := (*emptyInterface)(unsafe.Pointer(&z)).typ
+	return func(fr *frame) {
+		v := conv(fr.reg(ix))
+		(*emptyInterface)(unsafe.Pointer(&v)).typ = rtyp
+		fr.setReg(ir, v)
+	}
+}
+
+// valueWord returns the data word of x, a pointer to the string or slice
+// boxed by x.
+func valueWord(x value) unsafe.Pointer {
+	return (*emptyInterface)(unsafe.Pointer(&x)).word
+}
+
+// floatToInt is the compiled Go conversions of floats to the integer kinds.
+var floatToInt = map[reflect.Kind]func(float64) value{
+	reflect.Int:     func(f float64) value { return int(f) },
+	reflect.Int8:    func(f float64) value { return int8(f) },
+	reflect.Int16:   func(f float64) value { return int16(f) },
+	reflect.Int32:   func(f float64) value { return int32(f) },
+	reflect.Int64:   func(f float64) value { return int64(f) },
+	reflect.Uint:    func(f float64) value { return uint(f) },
+	reflect.Uint8:   func(f float64) value { return uint8(f) },
+	reflect.Uint16:  func(f float64) value { return uint16(f) },
+	reflect.Uint32:  func(f float64) value { return uint32(f) },
+	reflect.Uint64:  func(f float64) value { return uint64(f) },
+	reflect.Uintptr: func(f float64) value { return uintptr(f) },
+}
+
+// makeFloatConvert returns the conversion of xtyp to typ for floats to
+// integers and integers to float32, or nil for other types. Unlike
+// compiled Go, reflect.Value.Convert converts floats by int64, wrapping
+// out-of-range values, and integers to float32 by float64, rounding twice.
+// The conversions of the exact types are compiled, out-of-range values
+// convert like gc on the host architecture.
+func makeFloatConvert(typ reflect.Type, xtyp reflect.Type, ir int, ix int) func(fr *frame) {
+	var conv func(v reflect.Value) value
+	switch xtyp.Kind() {
+	case reflect.Float32, reflect.Float64:
+		fn, ok := floatToInt[typ.Kind()]
+		if !ok {
+			return nil
+		}
+		conv = func(v reflect.Value) value {
+			return fn(v.Float())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typ.Kind() != reflect.Float32 {
+			return nil
+		}
+		conv = func(v reflect.Value) value {
+			return float32(v.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if typ.Kind() != reflect.Float32 {
+			return nil
+		}
+		conv = func(v reflect.Value) value {
+			return float32(v.Uint())
+		}
+	default:
+		return nil
+	}
+	if reflect.TypeOf(conv(reflect.Zero(xtyp))) == typ {
+		return func(fr *frame) {
+			fr.setReg(ir, conv(reflect.ValueOf(fr.reg(ix))))
+		}
+	}
+	// named types convert exactly from the basic type of their kind
+	return func(fr *frame) {
+		v := reflect.ValueOf(conv(reflect.ValueOf(fr.reg(ix))))
+		fr.setReg(ir, v.Convert(typ).Interface())
+	}
+}
+
 func makeCallInstr(pfn *Function, interp *Interp, instr ssa.Value, call *ssa.CallCommon) func(fr *frame) {
 	ir := pfn.regIndex(instr)
 	iv, ia, ib := getCallIndex(pfn, call)
@@ -992,7 +1360,7 @@ func makeCallInstr(pfn *Function, interp *Interp, instr ssa.Value, call *ssa.Cal
 	case *ssa.MakeClosure:
 		ifn := interp.loadFunction(fn.Fn.(*ssa.Function))
 		ia = append(ia, ib...)
-		if ifn.Recover == nil {
+		if !ifn.hasRecover() {
 			return func(fr *frame) {
 				interp.callFunctionByStackNoRecover(fr, ifn, ir, ia)
 			}
@@ -1009,14 +1377,18 @@ func makeCallInstr(pfn *Function, interp *Interp, instr ssa.Value, call *ssa.Cal
 				if fn.Pkg != nil && fn.Name() == "init" {
 					return nil
 				}
-				panic(fmt.Errorf("no code for function: %v", fn))
+				panic(missingFunc(fn))
 			}
+			name := fn.String()
 			return func(fr *frame) {
-				interp.callExternalByStack(fr, ext, ir, ia)
+				interp.callExternalByStack(fr, name, ext, ir, ia)
 			}
 		}
+		if canInline(interp, pfn, fn) {
+			return makeInlineCall(pfn, interp, fn, ir, ia)
+		}
 		ifn := interp.loadFunction(fn)
-		if ifn.Recover == nil {
+		if !ifn.hasRecover() {
 			return func(fr *frame) {
 				interp.callFunctionByStackNoRecover(fr, ifn, ir, ia)
 			}
@@ -1039,23 +1411,17 @@ func makeCallInstr(pfn *Function, interp *Interp, instr ssa.Value, call *ssa.Cal
 		fn := fr.reg(iv)
 		switch fn := fn.(type) {
 		case *ssa.Function:
-			interp.callFunctionByStack(fr, interp.funcs[fn], ir, ia)
+			interp.callFunctionByStack(fr, interp.lookupFunc(fn), ir, ia)
 		case *closure:
-			interp.callFunctionByStack(fr, interp.funcs[fn.Fn], ir, ia)
+			interp.callFunctionByStack(fr, interp.lookupFunc(fn.Fn), ir, ia)
 		case *ssa.Builtin:
 			interp.callBuiltinByStack(fr, fn.Name(), call.Args, ir, ia)
 		default:
-			interp.callExternalByStack(fr, reflect.ValueOf(fn), ir, ia)
+			interp.callExternalByStack(fr, "", reflect.ValueOf(fn), ir, ia)
 		}
 	}
 }
 
-var (
-	typeOfType      = reflect.TypeOf(reflect.TypeOf(0))
-	vfnMethod       = reflect.ValueOf(reflectx.MethodByIndex)
-	vfnMethodByName = reflect.ValueOf(reflectx.MethodByName)
-)
-
 func findUserMethod(typ reflect.Type, name string) (ext reflect.Value, ok bool) {
 	if m, ok := reflectx.MethodByName(typ, name); ok {
 		return m.Func, true
@@ -1063,15 +1429,46 @@ func findUserMethod(typ reflect.Type, name string) (ext reflect.Value, ok bool)
 	return
 }
 
-func findExternMethod(typ reflect.Type, name string) (ext reflect.Value, ok bool) {
-	if typ == typeOfType {
-		switch name {
-		case "Method":
-			return vfnMethod, true
-		case "MethodByName":
-			return vfnMethodByName, true
+// externMethodName returns the package path of the named host type rtype,
+// or of the type rtype points to, and the full name of its method name,
+// eg. "(*net.Dialer).Dial".
+func externMethodName(rtype reflect.Type, name string) (pkgPath, fullName string, ok bool) {
+	typ, star := rtype, ""
+	if typ.Kind() == reflect.Ptr {
+		typ, star = typ.Elem(), "*"
+	}
+	if typ.Name() == "" || typ.PkgPath() == "" {
+		return "", "", false
+	}
+	return typ.PkgPath(), "(" + star + typ.PkgPath() + "." + typ.Name() + ")." + name, true
+}
+
+// filterMethod checks the method name of the host type rtype, called
+// through an interface, by the import filter of the loader like the
+// symbols used by the program. The methods of unexported types, not named
+// by programs, are not filtered.
+func (i *Interp) filterMethod(rtype reflect.Type, name string) {
+	l, ok := i.loader.(symbolFilter)
+	if !ok {
+		return
+	}
+	typ := rtype
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if !token.IsExported(typ.Name()) {
+		return
+	}
+	if _, fullName, ok := externMethodName(rtype, name); ok {
+		if err := l.filterSymbol(fullName); err != nil {
+			if _, ok := err.(RewriteImport); !ok {
+				panic(err)
+			}
 		}
 	}
+}
+
+func findExternMethod(typ reflect.Type, name string) (ext reflect.Value, ok bool) {
 	if m, ok := typ.MethodByName(name); ok {
 		return m.Func, true
 	}
@@ -1085,27 +1482,96 @@ func (i *Interp) findMethod(typ reflect.Type, mname string) (fn *ssa.Function, o
 	return
 }
 
+// methodKey is the key of Interp.methods, a dynamic method call resolved
+// for a receiver of type typ.
+type methodKey struct {
+	typ reflect.Type
+	fn  *types.Func
+}
+
+// methodValue is a resolved dynamic method call: fn for user defined
+// type methods, ext for extern methods of full name name.
+type methodValue struct {
+	fn   *ssa.Function
+	pfn  *Function // compiled fn
+	ext  reflect.Value
+	name string
+}
+
+// resolveMethod returns the callee of method fn for receiver type rtype,
+// caching the result in i.methods.
+func (i *Interp) resolveMethod(rtype reflect.Type, fn *types.Func) *methodValue {
+	key := methodKey{rtype, fn}
+	if v, ok := i.methods.Load(key); ok {
+		return v.(*methodValue)
+	}
+	mname := fn.Name()
+	var found bool
+	m := &methodValue{}
+	// find user type method *ssa.Function
+	if mset, ok := i.msets[rtype]; ok {
+		if f, ok := mset[mname]; ok {
+			m.fn, m.pfn, found = f, i.lookupFunc(f), true
+		} else {
+			m.ext, found = findUserMethod(rtype, mname)
+		}
+	} else if rtype == typeOfType {
+		m.ext, found = i.reflectTypeMethod(mname)
+	}
+	if !found {
+		i.filterMethod(rtype, mname)
+		m.ext, found = findExternMethod(rtype, mname)
+	}
+	if !found {
+		panic(fmt.Errorf("no code for method: %v.%v", rtype, mname))
+	}
+	if m.fn == nil {
+		m.name = "(" + rtype.String() + ")." + mname
+		m.ext = i.findNetMethod(rtype, mname, m.ext)
+	}
+	v, _ := i.methods.LoadOrStore(key, m)
+	return v.(*methodValue)
+}
+
+// boundKey is the cache key of bound method values.
+type boundKey struct {
+	typ  reflect.Type
+	name string
+}
+
+// boundMethod returns the method index of extern type rtype for bound
+// method values of name with func type typ, caching the result in
+// i.bounds. It returns -1 for user defined types, and for the types of the
+// network packages called by the network policy, which are bound by the
+// interpreted wrapper.
+func (i *Interp) boundMethod(rtype reflect.Type, name string, typ reflect.Type) int {
+	key := boundKey{rtype, name}
+	if v, ok := i.bounds.Load(key); ok {
+		return v.(int)
+	}
+	index := -1
+	_, user := i.msets[rtype]
+	if !user {
+		i.filterMethod(rtype, name)
+	}
+	if _, _, network := netMethod(rtype, name); !user && !network {
+		if m, ok := rtype.MethodByName(name); ok && reflect.Zero(rtype).Method(m.Index).Type() == typ {
+			index = m.Index
+		}
+	}
+	i.bounds.Store(key, index)
+	return index
+}
+
 func makeCallMethodInstr(interp *Interp, instr ssa.Value, call *ssa.CallCommon, ir int, iv int, ia []int) func(fr *frame) {
-	mname := call.Method.Name()
 	ia = append([]int{iv}, ia...)
-	var found bool
-	var ext reflect.Value
 	return func(fr *frame) {
 		v := fr.reg(iv)
-		rtype := reflect.TypeOf(v)
-		// find user type method *ssa.Function
-		if mset, ok := interp.msets[rtype]; ok {
-			if fn, ok := mset[mname]; ok {
-				interp.callFunctionByStack(fr, interp.funcs[fn], ir, ia)
-				return
-			}
-			ext, found = findUserMethod(rtype, mname)
-		} else {
-			ext, found = findExternMethod(rtype, mname)
-		}
-		if !found {
-			panic(fmt.Errorf("no code for method: %v.%v", rtype, mname))
+		m := interp.resolveMethod(reflect.TypeOf(v), call.Method)
+		if m.fn != nil {
+			interp.callFunctionByStack(fr, m.pfn, ir, ia)
+			return
 		}
-		interp.callExternalByStack(fr, ext, ir, ia)
+		interp.callExternalByStack(fr, m.name, m.ext, ir, ia)
 	}
 }
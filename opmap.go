@@ -0,0 +1,135 @@
+package gossa
+
+import (
+	"reflect"
+)
+
+// Maps of string and int keys are by far the most common in programs, their
+// Lookup and MapUpdate index the map natively rather than by reflect.
+var (
+	tyMapStringInt       = reflect.TypeOf(map[string]int(nil))
+	tyMapStringString    = reflect.TypeOf(map[string]string(nil))
+	tyMapStringBool      = reflect.TypeOf(map[string]bool(nil))
+	tyMapStringInterface = reflect.TypeOf(map[string]interface{}(nil))
+	tyMapIntInt          = reflect.TypeOf(map[int]int(nil))
+	tyMapIntString       = reflect.TypeOf(map[int]string(nil))
+	tyMapIntBool         = reflect.TypeOf(map[int]bool(nil))
+	tyMapIntInterface    = reflect.TypeOf(map[int]interface{}(nil))
+)
+
+// makeMapLookup returns the Lookup of the map of type typ in register ix by
+// the key in register ii, or nil if typ has no native lookup.
+func makeMapLookup(typ reflect.Type, commaOk bool, ir, ix, ii int) func(fr *frame) {
+	var lookup func(fr *frame) (value, bool)
+	switch typ {
+	case tyMapStringInt:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[string]int)
+			v, ok := m[fr.reg(ii).(string)]
+			return v, ok
+		}
+	case tyMapStringString:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[string]string)
+			v, ok := m[fr.reg(ii).(string)]
+			return v, ok
+		}
+	case tyMapStringBool:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[string]bool)
+			v, ok := m[fr.reg(ii).(string)]
+			return v, ok
+		}
+	case tyMapStringInterface:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[string]interface{})
+			v, ok := m[fr.reg(ii).(string)]
+			return v, ok
+		}
+	case tyMapIntInt:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[int]int)
+			v, ok := m[fr.reg(ii).(int)]
+			return v, ok
+		}
+	case tyMapIntString:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[int]string)
+			v, ok := m[fr.reg(ii).(int)]
+			return v, ok
+		}
+	case tyMapIntBool:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[int]bool)
+			v, ok := m[fr.reg(ii).(int)]
+			return v, ok
+		}
+	case tyMapIntInterface:
+		lookup = func(fr *frame) (value, bool) {
+			m, _ := fr.reg(ix).(map[int]interface{})
+			v, ok := m[fr.reg(ii).(int)]
+			return v, ok
+		}
+	default:
+		return nil
+	}
+	if commaOk {
+		return func(fr *frame) {
+			v, ok := lookup(fr)
+			fr.setReg(ir, tuple{v, ok})
+		}
+	}
+	return func(fr *frame) {
+		v, _ := lookup(fr)
+		fr.setReg(ir, v)
+	}
+}
+
+// makeMapUpdate returns the MapUpdate of the map of type typ in register im
+// by the key in register ik and the value in register iv, or nil if typ has
+// no native update. Updates of nil maps panic like gc.
+func makeMapUpdate(typ reflect.Type, im, ik, iv int) func(fr *frame) {
+	switch typ {
+	case tyMapStringInt:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[string]int)
+			m[fr.reg(ik).(string)] = fr.reg(iv).(int)
+		}
+	case tyMapStringString:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[string]string)
+			m[fr.reg(ik).(string)] = fr.reg(iv).(string)
+		}
+	case tyMapStringBool:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[string]bool)
+			m[fr.reg(ik).(string)] = fr.reg(iv).(bool)
+		}
+	case tyMapStringInterface:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[string]interface{})
+			m[fr.reg(ik).(string)] = fr.reg(iv)
+		}
+	case tyMapIntInt:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[int]int)
+			m[fr.reg(ik).(int)] = fr.reg(iv).(int)
+		}
+	case tyMapIntString:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[int]string)
+			m[fr.reg(ik).(int)] = fr.reg(iv).(string)
+		}
+	case tyMapIntBool:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[int]bool)
+			m[fr.reg(ik).(int)] = fr.reg(iv).(bool)
+		}
+	case tyMapIntInterface:
+		return func(fr *frame) {
+			m, _ := fr.reg(im).(map[int]interface{})
+			m[fr.reg(ik).(int)] = fr.reg(iv)
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"go/constant"
 	"go/token"
 	"go/types"
+	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -22,7 +23,8 @@ import (
 
 // If the target program panics, the interpreter panics with this type.
 type targetPanic struct {
-	v value
+	v  value
+	fr *frame // panicking frame
 }
 
 func (p targetPanic) Error() string {
@@ -104,6 +106,9 @@ func constToValue(i *Interp, c *ssa.Const) value {
 func globalToValue(i *Interp, key *ssa.Global) (interface{}, bool) {
 	if key.Pkg != nil {
 		pkgpath := key.Pkg.Pkg.Path()
+		if v, ok := procGlobal(i, pkgpath, key.Name()); ok {
+			return v, true
+		}
 		if pkg, ok := i.installed(pkgpath); ok {
 			if ext, ok := pkg.Vars[key.Name()]; ok {
 				return ext.Interface(), true
@@ -164,8 +169,10 @@ func asInt(x value) int {
 	panic(fmt.Sprintf("cannot convert %T to int", x))
 }
 
-// asUint64 converts x, which must be an unsigned integer, to a uint64
-// suitable for use as a bitwise shift count.
+// asUint64 converts x, an integer of any kind, to a uint64 suitable for
+// use as a bitwise shift count. A negative x panics like gc. Counts of the
+// width of the shifted value or larger need no care, the shift of the
+// int64 or uint64 value truncated to its type is 0 or -1 like the spec.
 func asUint64(x value) uint64 {
 	switch x := x.(type) {
 	case int:
@@ -214,7 +221,7 @@ func asUint64(x value) uint64 {
 			panic(fmt.Sprintf("cannot convert %T to uint64", x))
 		}
 	}
-	panic(runtimeError("negative shift amount"))
+	panic(runtimeError{msg: "negative shift amount"})
 }
 
 // slice returns x[lo:hi:max].  Any of lo, hi and max may be nil.
@@ -257,42 +264,42 @@ func slice(fr *frame, instr *ssa.Slice, makesliceCheck bool, ix, ih, il, im int)
 
 	if makesliceCheck {
 		if hi < 0 {
-			panic(runtimeError("makeslice: len out of range"))
+			panic(runtimeError{msg: "makeslice: len out of range"})
 		} else if hi > max {
-			panic(runtimeError("makeslice: cap out of range"))
+			panic(runtimeError{msg: "makeslice: cap out of range"})
 		}
 	} else {
 		if slice3 {
 			if max < 0 {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [::%v]", max)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [::%v]", max)})
 			} else if max > Cap {
 				if kind == reflect.Slice {
-					panic(runtimeError(fmt.Sprintf("slice bounds out of range [::%v] with capacity %v", max, Cap)))
+					panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [::%v] with capacity %v", max, Cap)})
 				} else {
-					panic(runtimeError(fmt.Sprintf("slice bounds out of range [::%v] with length %v", max, Cap)))
+					panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [::%v] with length %v", max, Cap)})
 				}
 			} else if hi < 0 {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [:%v:]", hi)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [:%v:]", hi)})
 			} else if hi > max {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [:%v:%v]", hi, max)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [:%v:%v]", hi, max)})
 			} else if lo < 0 {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [%v::]", lo)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [%v::]", lo)})
 			} else if lo > hi {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [%v:%v:]", lo, hi)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [%v:%v:]", lo, hi)})
 			}
 		} else {
 			if hi < 0 {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [:%v]", hi)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [:%v]", hi)})
 			} else if hi > Cap {
 				if kind == reflect.Slice {
-					panic(runtimeError(fmt.Sprintf("slice bounds out of range [:%v] with capacity %v", hi, Cap)))
+					panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [:%v] with capacity %v", hi, Cap)})
 				} else {
-					panic(runtimeError(fmt.Sprintf("slice bounds out of range [:%v] with length %v", hi, Cap)))
+					panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [:%v] with length %v", hi, Cap)})
 				}
 			} else if lo < 0 {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [%v:]", lo)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [%v:]", lo)})
 			} else if lo > hi {
-				panic(runtimeError(fmt.Sprintf("slice bounds out of range [%v:%v]", lo, hi)))
+				panic(runtimeError{msg: fmt.Sprintf("slice bounds out of range [%v:%v]", lo, hi)})
 			}
 		}
 	}
@@ -369,6 +376,32 @@ failed:
 	panic(fmt.Sprintf("invalid binary op: %T + %T", x, y))
 }
 
+// makeStringADD returns the concatenation of the strings of type typ, or nil
+// for other types. Named string types are boxed by the type word of typ
+// rather than by the reflect fallback of opADD.
+func makeStringADD(typ reflect.Type, ir, ix, iy int) func(fr *frame) {
+	if typ.Kind() != reflect.String {
+		return nil
+	}
+	if typ == tyString {
+		return func(fr *frame) {
+			fr.setReg(ir, fr.reg(ix).(string)+fr.reg(iy).(string))
+		}
+	}
+	z := reflect.Zero(typ).Interface()
+	rtyp := (*emptyInterface)(unsafe.Pointer(&z)).typ
+	return func(fr *frame) {
+		x, y := fr.reg(ix), fr.reg(iy)
+		s := *(*string)((*emptyInterface)(unsafe.Pointer(&x)).word) +
+			*(*string)((*emptyInterface)(unsafe.Pointer(&y)).word)
+		var r value
+		e := (*emptyInterface)(unsafe.Pointer(&r))
+		e.typ = rtyp
+		e.word = unsafe.Pointer(&s)
+		fr.setReg(ir, r)
+	}
+}
+
 func opSUB(x, y value) value {
 	switch x.(type) {
 	case int:
@@ -526,6 +559,7 @@ func opQuo(x, y value) value {
 			case reflect.Float32, reflect.Float64:
 				r.SetFloat(vx.Float() / vy.Float())
 			case reflect.Complex64, reflect.Complex128:
+				// complex64 divides as complex128 rounded once, like gc
 				r.SetComplex(vx.Complex() / vy.Complex())
 			default:
 				goto failed
@@ -1117,14 +1151,16 @@ func IsNil(v reflect.Value) bool {
 func opEQL(instr *ssa.BinOp, x, y interface{}) bool {
 	vx := reflect.ValueOf(x)
 	vy := reflect.ValueOf(y)
-	if vx.Kind() != vy.Kind() {
-		return false
-	}
+	// a nil func, map or slice may be held by a nil interface, eg. a
+	// RunFunc argument
 	if IsConstNil(instr.X) {
 		return IsNil(vy)
 	} else if IsConstNil(instr.Y) {
 		return IsNil(vx)
 	}
+	if vx.Kind() != vy.Kind() {
+		return false
+	}
 	return equalValue(vx, vy)
 }
 
@@ -1137,36 +1173,63 @@ func equalNil(vx, vy reflect.Value) bool {
 	return equalValue(vx, vy)
 }
 
+// equalValue compares vx and vy like the == of gc on interface values: the
+// values of different types are not equal, those of the same uncomparable
+// type panic. The func, map and slice values compared to nil are checked
+// by opEQL.
 func equalValue(vx, vy reflect.Value) bool {
 	if kind := vx.Kind(); kind == vy.Kind() {
 		switch kind {
 		case reflect.Invalid:
 			return true
-		case reflect.Chan:
-			dirx := vx.Type().ChanDir()
-			diry := vy.Type().ChanDir()
-			if dirx != diry {
-				if dirx == reflect.BothDir {
-					return vy.Interface() == vx.Convert(vy.Type()).Interface()
-				} else if diry == reflect.BothDir {
-					return vx.Interface() == vy.Convert(vx.Type()).Interface()
-				}
-			} else {
-				return vx.Interface() == vy.Interface()
-			}
 		case reflect.Ptr:
 			return vx.Pointer() == vy.Pointer()
 		case reflect.Struct:
 			return equalStruct(vx, vy)
 		case reflect.Array:
 			return equalArray(vx, vy)
-		default:
-			return vx.Interface() == vy.Interface()
+		case reflect.Interface:
+			if !sameDynamicType(vx, vy) {
+				return false
+			}
+			return vx.IsNil() || equalValue(vx.Elem(), vy.Elem())
+		case reflect.Func, reflect.Map, reflect.Slice:
+			if vx.Type() != vy.Type() {
+				return false
+			}
+			panic(uncomparableError(vx.Type()))
 		}
+		if vx.Type() != vy.Type() {
+			return false
+		}
+		// compare by kind without boxing the values by Interface
+		switch kind {
+		case reflect.Bool:
+			return vx.Bool() == vy.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return vx.Int() == vy.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return vx.Uint() == vy.Uint()
+		case reflect.Float32, reflect.Float64:
+			return vx.Float() == vy.Float()
+		case reflect.Complex64, reflect.Complex128:
+			return vx.Complex() == vy.Complex()
+		case reflect.String:
+			return vx.String() == vy.String()
+		case reflect.Chan, reflect.UnsafePointer:
+			return vx.Pointer() == vy.Pointer()
+		}
+		return vx.Interface() == vy.Interface()
 	}
 	return false
 }
 
+// uncomparableError is the runtime error of comparing the values of the
+// uncomparable type typ.
+func uncomparableError(typ reflect.Type) runtimeError {
+	return runtimeError{msg: "comparing uncomparable type " + typ.String()}
+}
+
 func equalArray(vx, vy reflect.Value) bool {
 	xlen := vx.Len()
 	if xlen != vy.Len() {
@@ -1175,6 +1238,9 @@ func equalArray(vx, vy reflect.Value) bool {
 	if vx.Type().Elem() != vy.Type().Elem() {
 		return false
 	}
+	if !vx.Type().Comparable() {
+		panic(uncomparableError(vx.Type()))
+	}
 	for i := 0; i < xlen; i++ {
 		fx := vx.Index(i)
 		fy := vy.Index(i)
@@ -1185,11 +1251,17 @@ func equalArray(vx, vy reflect.Value) bool {
 	return true
 }
 
+// equalStruct compares the fields of the structs vx and vy in order like
+// gc, an interface field holding values of an uncomparable type panics
+// unless a field before differs.
 func equalStruct(vx, vy reflect.Value) bool {
 	typ := vx.Type()
 	if typ != vy.Type() {
 		return false
 	}
+	if !typ.Comparable() {
+		panic(uncomparableError(typ))
+	}
 	n := typ.NumField()
 	for i := 0; i < n; i++ {
 		f := typ.Field(i)
@@ -1198,13 +1270,6 @@ func equalStruct(vx, vy reflect.Value) bool {
 		}
 		fx := reflectx.FieldByIndexX(vx, f.Index)
 		fy := reflectx.FieldByIndexX(vy, f.Index)
-		// check uncomparable
-		switch f.Type.Kind() {
-		case reflect.Slice, reflect.Map, reflect.Func:
-			if fx.Interface() != fy.Interface() {
-				return false
-			}
-		}
 		if !equalNil(fx, fy) {
 			return false
 		}
@@ -1212,9 +1277,94 @@ func equalStruct(vx, vy reflect.Value) bool {
 	return true
 }
 
+// sameDynamicType reports whether the interface values vx and vy are both
+// nil or hold values of the same type.
+func sameDynamicType(vx, vy reflect.Value) bool {
+	if vx.IsNil() || vy.IsNil() {
+		return vx.IsNil() && vy.IsNil()
+	}
+	return vx.Elem().Type() == vy.Elem().Type()
+}
+
+// hasInterface reports whether values of typ may hold interface values,
+// whose dynamic types are checked by map operations.
+func hasInterface(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return hasInterface(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if hasInterface(typ.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mapKey returns the key k of a map with key type typ, which may hold
+// interface values. A nil k is the nil interface, interface values of
+// unhashable types panic like the Go runtime.
+func mapKey(typ reflect.Type, k value) reflect.Value {
+	if k == nil {
+		return reflect.Zero(typ)
+	}
+	v := reflect.ValueOf(k)
+	checkHashable(v)
+	return v
+}
+
+// checkHashable panics if the map key v, or an interface value it holds,
+// is of unhashable type, reported like the Go runtime by the dynamic type
+// of the interface, eg. a struct with a func field.
+func checkHashable(v reflect.Value) {
+	if !v.Type().Comparable() {
+		panic(runtimeError{msg: "hash of unhashable type " + v.Type().String()})
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			checkHashable(v.Elem())
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			checkHashable(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			checkHashable(v.Field(i))
+		}
+	}
+}
+
+// mapDelete implements the delete built-in.
+func mapDelete(m value, k value) {
+	vm := reflect.ValueOf(m)
+	if typ := vm.Type().Key(); hasInterface(typ) {
+		vm.SetMapIndex(mapKey(typ, k), reflect.Value{})
+		return
+	}
+	vm.SetMapIndex(reflect.ValueOf(k), reflect.Value{})
+}
+
+// closeChan closes the channel ch, which is nil for a nil channel passed
+// through an interface, eg. a RunFunc argument.
+func closeChan(ch value) {
+	if ch == nil {
+		panic(plainError("close of nil channel"))
+	}
+	reflect.ValueOf(ch).Close()
+}
+
 func unop(instr *ssa.UnOp, x value) value {
 	switch instr.Op {
 	case token.ARROW: // receive
+		if x == nil {
+			// receive from nil channel blocks forever
+			select {}
+		}
 		vx := reflect.ValueOf(x)
 		v, ok := vx.Recv()
 		if !ok {
@@ -1275,13 +1425,15 @@ func unop(instr *ssa.UnOp, x value) value {
 				r.SetFloat(-v.Float())
 			case reflect.Complex64, reflect.Complex128:
 				r.SetComplex(-v.Complex())
+			default:
+				goto failed
 			}
 			return r.Interface()
 		}
 	case token.MUL:
 		v := reflect.ValueOf(x).Elem()
 		if !v.IsValid() {
-			panic(runtimeError("invalid memory address or nil pointer dereference"))
+			panic(runtimeError{msg: "invalid memory address or nil pointer dereference"})
 		}
 		return v.Interface()
 		//return load(deref(instr.X.Type()), x.(*value))
@@ -1293,10 +1445,7 @@ func unop(instr *ssa.UnOp, x value) value {
 			v := reflect.ValueOf(x)
 			if v.Kind() == reflect.Bool {
 				r := reflect.New(v.Type()).Elem()
-				if v.Bool() {
-					return v.Interface()
-				}
-				r.SetBool(true)
+				r.SetBool(!v.Bool())
 				return r.Interface()
 			}
 		}
@@ -1330,9 +1479,9 @@ func unop(instr *ssa.UnOp, x value) value {
 			r := reflect.New(vx.Type()).Elem()
 			switch vx.Kind() {
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				r.SetInt(^r.Int())
+				r.SetInt(^vx.Int())
 			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				r.SetUint(^r.Uint())
+				r.SetUint(^vx.Uint())
 			default:
 				goto failed
 			}
@@ -1343,6 +1492,21 @@ failed:
 	panic(fmt.Sprintf("invalid unary op %s %T", instr.Op, x))
 }
 
+// typeAssertResult returns the result v of the type assertion instr, or
+// panics with err if it fails without comma ok.
+func typeAssertResult(instr *ssa.TypeAssert, typ reflect.Type, v value, err error) value {
+	if err != nil {
+		if !instr.CommaOk {
+			panic(err)
+		}
+		return tuple{reflect.New(typ).Elem().Interface(), false}
+	}
+	if instr.CommaOk {
+		return tuple{v, true}
+	}
+	return v
+}
+
 // typeAssert checks whether dynamic type of itf is instr.AssertedType.
 // It returns the extracted value on success, and panics on failure,
 // unless instr.CommaOk, in which case it always returns a "value,ok" tuple.
@@ -1350,6 +1514,18 @@ failed:
 func typeAssert(i *Interp, instr *ssa.TypeAssert, typ reflect.Type, iv interface{}) value {
 	var v value
 	var err error
+	if iv != nil && i.mode&DisableMethodSynthesis != 0 {
+		// the types have no methods, check the method sets of the script
+		iv = unwrapIface(iv)
+		if _, ok := instr.AssertedType.Underlying().(*types.Interface); ok {
+			if i.implements(iv, instr.AssertedType) {
+				v = i.wrapIface(typ, iv)
+			} else {
+				err = runtimeError{msg: fmt.Sprintf("interface conversion: %v is %v, not %v", instr.X.Type(), reflect.TypeOf(iv), instr.AssertedType)}
+			}
+			return typeAssertResult(instr, typ, v, err)
+		}
+	}
 	if iv == nil {
 		err = plainError(fmt.Sprintf("interface conversion: interface is nil, not %v", typ))
 	} else {
@@ -1359,12 +1535,12 @@ func typeAssert(i *Interp, instr *ssa.TypeAssert, typ reflect.Type, iv interface
 			v = iv
 		} else {
 			if !rt.AssignableTo(typ) {
-				err = runtimeError(fmt.Sprintf("interface conversion: %v is %v, not %v", instr.X.Type(), rt, typ))
+				err = runtimeError{msg: fmt.Sprintf("interface conversion: %v is %v, not %v", instr.X.Type(), rt, typ)}
 				if itype, ok := instr.AssertedType.Underlying().(*types.Interface); ok {
 					if it, ok := i.findType(rt, false); ok {
 						if meth, _ := types.MissingMethod(it, itype, true); meth != nil {
-							err = runtimeError(fmt.Sprintf("interface conversion: %v is not %v: missing method %s",
-								rt, instr.AssertedType, meth.Name()))
+							err = runtimeError{msg: fmt.Sprintf("interface conversion: %v is not %v: missing method %s",
+								rt, instr.AssertedType, meth.Name())}
 						}
 					}
 				} else if typ.PkgPath() == rt.PkgPath() && typ.Name() == rt.Name() {
@@ -1374,7 +1550,7 @@ func typeAssert(i *Interp, instr *ssa.TypeAssert, typ reflect.Type, iv interface
 						n1, ok1 := t1.(*types.Named)
 						n2, ok2 := t2.(*types.Named)
 						if ok1 && ok2 && n1.Obj().Parent() != n2.Obj().Parent() {
-							err = runtimeError(fmt.Sprintf("interface conversion: %v is %v, not %v (types from different scopes)", instr.X.Type(), rt, typ))
+							err = runtimeError{msg: fmt.Sprintf("interface conversion: %v is %v, not %v (types from different scopes)", instr.X.Type(), rt, typ)}
 						}
 					}
 				}
@@ -1383,16 +1559,7 @@ func typeAssert(i *Interp, instr *ssa.TypeAssert, typ reflect.Type, iv interface
 			}
 		}
 	}
-	if err != nil {
-		if !instr.CommaOk {
-			panic(err)
-		}
-		return tuple{reflect.New(typ).Elem().Interface(), false}
-	}
-	if instr.CommaOk {
-		return tuple{v, true}
-	}
-	return v
+	return typeAssertResult(instr, typ, v, err)
 	// err := ""
 	// if itf.t == nil {
 	// 	err = fmt.Sprintf("interface conversion: interface is nil, not %s", instr.AssertedType)
@@ -1421,7 +1588,8 @@ func typeAssert(i *Interp, instr *ssa.TypeAssert, typ reflect.Type, iv interface
 }
 
 // If CapturedOutput is non-nil, all writes by the interpreted program
-// to file descriptors 1 and 2 will also be written to CapturedOutput.
+// to file descriptors 1 and 2 by the print and println built-ins and the
+// print functions of package fmt will also be written to CapturedOutput.
 //
 // (The $GOROOT/test system requires that the test be considered a
 // failure if "BUG" appears in the combined stdout/stderr output, even
@@ -1435,12 +1603,123 @@ var capturedOutputMu sync.Mutex
 // The print/println built-ins and the write() system call funnel
 // through here so they can be captured by the test driver.
 func print(b []byte) (int, error) {
+	capture(b)
+	return os.Stdout.Write(b)
+}
+
+func capture(b []byte) {
 	if CapturedOutput != nil {
 		capturedOutputMu.Lock()
 		CapturedOutput.Write(b) // ignore errors
 		capturedOutputMu.Unlock()
 	}
-	return os.Stdout.Write(b)
+}
+
+// capturedWriter writes to os.Stdout or os.Stderr and CapturedOutput.
+type capturedWriter struct {
+	w io.Writer
+}
+
+func (c capturedWriter) Write(b []byte) (int, error) {
+	capture(b)
+	return c.w.Write(b)
+}
+
+// captured returns w, writing also to CapturedOutput if w is os.Stdout or
+// os.Stderr. The print functions of package fmt write by captured.
+func captured(w io.Writer) io.Writer {
+	if CapturedOutput != nil && (w == io.Writer(os.Stdout) || w == io.Writer(os.Stderr)) {
+		return capturedWriter{w}
+	}
+	return w
+}
+
+// appendSlice appends v1 to v0, reporting reallocation to the context
+// slice growth func.
+func (inter *Interp) appendSlice(caller *frame, v0, v1 reflect.Value) reflect.Value {
+	r := reflect.AppendSlice(v0, v1)
+	if r.Cap() != v0.Cap() {
+		inter.sliceGrow(caller, v0.Cap(), r.Cap(), v0.Type().Elem())
+	}
+	return r
+}
+
+func (inter *Interp) sliceGrow(caller *frame, oldCap, newCap int, elem reflect.Type) {
+	if fn := inter.ctx.growFunc; fn != nil {
+		fn(&SliceGrowInfo{
+			OldCap: oldCap,
+			NewCap: newCap,
+			Elem:   elem,
+			Pos:    caller.pfn.PosForPC(caller.pc - 1),
+			fset:   inter.fset,
+		})
+	}
+}
+
+// appendFast appends s1 to s0 without reflect for []byte, []int and
+// []string, and append([]byte, string...). It returns false for other
+// slice types, including named ones.
+func (inter *Interp) appendFast(caller *frame, s0, s1 value) (value, bool) {
+	var r value
+	var oldCap, newCap int
+	var elem reflect.Type
+	switch x := s0.(type) {
+	case []byte:
+		switch y := s1.(type) {
+		case []byte:
+			x2 := append(x, y...)
+			r, newCap = x2, cap(x2)
+		case string:
+			x2 := append(x, y...)
+			r, newCap = x2, cap(x2)
+		default:
+			return nil, false
+		}
+		oldCap, elem = cap(x), basicTypes[types.Byte]
+	case []int:
+		y, ok := s1.([]int)
+		if !ok {
+			return nil, false
+		}
+		x2 := append(x, y...)
+		r, oldCap, newCap, elem = x2, cap(x), cap(x2), basicTypes[types.Int]
+	case []string:
+		y, ok := s1.([]string)
+		if !ok {
+			return nil, false
+		}
+		x2 := append(x, y...)
+		r, oldCap, newCap, elem = x2, cap(x), cap(x2), basicTypes[types.String]
+	default:
+		return nil, false
+	}
+	if newCap != oldCap {
+		inter.sliceGrow(caller, oldCap, newCap, elem)
+	}
+	return r, true
+}
+
+// copySlice implements copy(dst, src), without reflect for []byte, []int
+// and []string, and copy([]byte, string).
+func copySlice(dst, src value) int {
+	switch d := dst.(type) {
+	case []byte:
+		switch s := src.(type) {
+		case []byte:
+			return copy(d, s)
+		case string:
+			return copy(d, s)
+		}
+	case []int:
+		if s, ok := src.([]int); ok {
+			return copy(d, s)
+		}
+	case []string:
+		if s, ok := src.([]string); ok {
+			return copy(d, s)
+		}
+	}
+	return reflect.Copy(reflect.ValueOf(dst), reflect.ValueOf(src))
 }
 
 // callBuiltin interprets a call to builtin fn with arguments args,
@@ -1451,6 +1730,9 @@ func (inter *Interp) callBuiltin(caller *frame, fn *ssa.Builtin, args []value, s
 		if len(args) == 1 {
 			return args[0]
 		}
+		if r, ok := inter.appendFast(caller, args[0], args[1]); ok {
+			return r
+		}
 		if s, ok := args[1].(string); ok {
 			// append([]byte, ...string) []byte
 			args[1] = []byte(s)
@@ -1460,19 +1742,19 @@ func (inter *Interp) callBuiltin(caller *frame, fn *ssa.Builtin, args []value, s
 		i0 := v0.Len()
 		i1 := v1.Len()
 		if i0+i1 < i0 {
-			panic(runtimeError("growslice: cap out of range"))
+			panic(runtimeError{msg: "growslice: cap out of range"})
 		}
-		return reflect.AppendSlice(v0, v1).Interface()
+		return inter.appendSlice(caller, v0, v1).Interface()
 
 	case "copy": // copy([]T, []T) int or copy([]byte, string) int
-		return reflect.Copy(reflect.ValueOf(args[0]), reflect.ValueOf(args[1]))
+		return copySlice(args[0], args[1])
 
 	case "close": // close(chan T)
-		reflect.ValueOf(args[0]).Close()
+		closeChan(args[0])
 		return nil
 
 	case "delete": // delete(map[K]value, K)
-		reflect.ValueOf(args[0]).SetMapIndex(reflect.ValueOf(args[1]), reflect.Value{})
+		mapDelete(args[0], args[1])
 		return nil
 
 	case "print", "println": // print(any, ...)
@@ -1540,7 +1822,7 @@ func (inter *Interp) callBuiltin(caller *frame, fn *ssa.Builtin, args []value, s
 	case "panic":
 		// ssa.Panic handles most cases; this is only for "go
 		// panic" or "defer panic".
-		panic(targetPanic{args[0]})
+		panic(targetPanic{args[0], caller})
 
 	case "recover":
 		return doRecover(caller)
@@ -1574,7 +1856,7 @@ func (inter *Interp) callBuiltin(caller *frame, fn *ssa.Builtin, args []value, s
 			if length == 0 {
 				return reflect.New(reflect.SliceOf(ptr.Type().Elem())).Elem().Interface()
 			}
-			panic(runtimeError("unsafe.Slice: ptr is nil and len is not zero"))
+			panic(runtimeError{msg: "unsafe.Slice: ptr is nil and len is not zero"})
 		}
 		typ := reflect.ArrayOf(length, ptr.Type().Elem())
 		v := reflect.NewAt(typ, unsafe.Pointer(ptr.Pointer()))
@@ -1592,13 +1874,13 @@ func (inter *Interp) callBuiltinDiscardsResult(caller *frame, fn *ssa.Builtin, a
 		panic("discards result of " + fnName)
 
 	case "copy": // copy([]T, []T) int or copy([]byte, string) int
-		reflect.Copy(reflect.ValueOf(args[0]), reflect.ValueOf(args[1]))
+		copySlice(args[0], args[1])
 
 	case "close": // close(chan T)
-		reflect.ValueOf(args[0]).Close()
+		closeChan(args[0])
 
 	case "delete": // delete(map[K]value, K)
-		reflect.ValueOf(args[0]).SetMapIndex(reflect.ValueOf(args[1]), reflect.Value{})
+		mapDelete(args[0], args[1])
 
 	case "print", "println": // print(any, ...)
 		ln := fn.Name() == "println"
@@ -1639,7 +1921,7 @@ func (inter *Interp) callBuiltinDiscardsResult(caller *frame, fn *ssa.Builtin, a
 	case "panic":
 		// ssa.Panic handles most cases; this is only for "go
 		// panic" or "defer panic".
-		panic(targetPanic{args[0]})
+		panic(targetPanic{args[0], caller})
 
 	case "recover":
 		doRecover(caller)
@@ -1683,6 +1965,10 @@ func (inter *Interp) callBuiltinByStack(caller *frame, fn string, ssaArgs []ssa.
 		}
 		arg0 := caller.reg(ia[0])
 		arg1 := caller.reg(ia[1])
+		if r, ok := inter.appendFast(caller, arg0, arg1); ok {
+			caller.setReg(ir, r)
+			return
+		}
 		if s, ok := arg1.(string); ok {
 			// append([]byte, ...string) []byte
 			arg1 = []byte(s)
@@ -1692,23 +1978,20 @@ func (inter *Interp) callBuiltinByStack(caller *frame, fn string, ssaArgs []ssa.
 		i0 := v0.Len()
 		i1 := v1.Len()
 		if i0+i1 < i0 {
-			panic(runtimeError("growslice: cap out of range"))
+			panic(runtimeError{msg: "growslice: cap out of range"})
 		}
-		caller.setReg(ir, reflect.AppendSlice(v0, v1).Interface())
+		caller.setReg(ir, inter.appendSlice(caller, v0, v1).Interface())
 
 	case "copy": // copy([]T, []T) int or copy([]byte, string) int
 		arg0 := caller.reg(ia[0])
 		arg1 := caller.reg(ia[1])
-		caller.setReg(ir, reflect.Copy(reflect.ValueOf(arg0), reflect.ValueOf(arg1)))
+		caller.setReg(ir, copySlice(arg0, arg1))
 
 	case "close": // close(chan T)
-		arg0 := caller.reg(ia[0])
-		reflect.ValueOf(arg0).Close()
+		closeChan(caller.reg(ia[0]))
 
 	case "delete": // delete(map[K]value, K)
-		arg0 := caller.reg(ia[0])
-		arg1 := caller.reg(ia[1])
-		reflect.ValueOf(arg0).SetMapIndex(reflect.ValueOf(arg1), reflect.Value{})
+		mapDelete(caller.reg(ia[0]), caller.reg(ia[1]))
 
 	case "print", "println": // print(any, ...)
 		ln := fn == "println"
@@ -1782,7 +2065,7 @@ func (inter *Interp) callBuiltinByStack(caller *frame, fn string, ssaArgs []ssa.
 		// ssa.Panic handles most cases; this is only for "go
 		// panic" or "defer panic".
 		arg0 := caller.reg(ia[0])
-		panic(targetPanic{arg0})
+		panic(targetPanic{arg0, caller})
 
 	case "recover":
 		caller.setReg(ir, doRecover(caller))
@@ -1821,7 +2104,7 @@ func (inter *Interp) callBuiltinByStack(caller *frame, fn string, ssaArgs []ssa.
 				caller.setReg(ir, reflect.New(reflect.SliceOf(ptr.Type().Elem())).Elem().Interface())
 				return
 			}
-			panic(runtimeError("unsafe.Slice: ptr is nil and len is not zero"))
+			panic(runtimeError{msg: "unsafe.Slice: ptr is nil and len is not zero"})
 		}
 		typ := reflect.ArrayOf(length, ptr.Type().Elem())
 		v := reflect.NewAt(typ, unsafe.Pointer(ptr.Pointer()))
@@ -1896,6 +2179,27 @@ type reflectValue struct {
 	flag uintptr
 }
 
+// changeType returns v converted to typ, a type of the same underlying
+// type as v but for channel directions, by a ChangeType instruction.
+func changeType(v reflect.Value, typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Chan && v.Type() != typ {
+		return convertChan(v, typ)
+	}
+	return v.Convert(typ)
+}
+
+// convertChan returns the channel v as the channel type typ, which may
+// differ from its type by name and direction, eg. chan T to <-chan T.
+// The element types are identical, interpreter defined or not.
+func convertChan(v reflect.Value, typ reflect.Type) reflect.Value {
+	if v.Type().ConvertibleTo(typ) {
+		return v.Convert(typ)
+	}
+	c := reflect.New(typ).Elem()
+	*(*unsafe.Pointer)(unsafe.Pointer(c.UnsafeAddr())) = unsafe.Pointer(v.Pointer())
+	return c
+}
+
 func convert(x interface{}, typ reflect.Type) interface{} {
 	v := reflect.ValueOf(x)
 	vk := v.Kind()
@@ -0,0 +1,78 @@
+package gossa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Option configures an interpreter created by NewInterp. Options are
+// applied in order before the program is compiled, so they override the
+// mode and the callbacks of the context for this interpreter only. An
+// option returns an error for an invalid configuration, failing NewInterp.
+type Option func(i *Interp) error
+
+// WithMode adds the flags of mode to the mode of the context. The flags of
+// the loader and packages of the context, DisableUnexportMethods and
+// EnableDumpInstr, are rejected.
+func WithMode(mode Mode) Option {
+	return func(i *Interp) error {
+		if m := mode & contextModes; m != 0 {
+			return fmt.Errorf("mode %#x is set by NewContext", uint(m))
+		}
+		i.mode |= mode
+		return nil
+	}
+}
+
+// WithArgs sets the os.Args of the program like SetArgs, before the
+// package initializers run. RunFile passes the file name and the run
+// arguments, leaving os.Args and flag.CommandLine of the host alone.
+func WithArgs(args []string) Option {
+	return func(i *Interp) error {
+		args := append([]string(nil), args...)
+		i.proc.args = &args
+		return nil
+	}
+}
+
+// WithTracing traces the instructions, calls and returns of the program to
+// w, one record a line, like the EnableTracing mode does to the Logger.
+// Inlining is disabled so every call is traced.
+func WithTracing(w io.Writer) Option {
+	return func(i *Interp) error {
+		if w == nil {
+			return errors.New("tracing to nil writer")
+		}
+		i.mode |= EnableTracing
+		i.traceOut = w
+		return nil
+	}
+}
+
+// WithRecoverDisabled disables recover in the program like the
+// DisableRecover mode: panics are not recovered by the deferred calls of
+// the program, they crash the host with the interpreter stack.
+func WithRecoverDisabled() Option {
+	return WithMode(DisableRecover)
+}
+
+// WithDebugger calls d for the DebugRef instructions of the program, the
+// references of its source variables and expressions, overriding the
+// func of Context.SetDebug. The program must be built with debug
+// information, by a context with SetDebug called or ssa.GlobalDebug set
+// in its BuilderMode.
+func WithDebugger(d func(*DebugInfo)) Option {
+	return func(i *Interp) error {
+		if d == nil {
+			return errors.New("nil debugger")
+		}
+		if i.ctx.BuilderMode&ssa.GlobalDebug == 0 {
+			return errors.New("debugger of program built without ssa.GlobalDebug")
+		}
+		i.debugFunc = d
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package gossa
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"math"
+	"reflect"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// OverflowInfo describes a signed integer add, sub or mul overflowing in
+// CheckOverflow mode.
+type OverflowInfo struct {
+	Op     token.Token  // token.ADD, token.SUB or token.MUL
+	X, Y   Value        // operands
+	Result Value        // wrapped around result
+	Type   reflect.Type // operand type
+	Pos    token.Pos    // operation position
+	fset   *token.FileSet
+}
+
+func (i *OverflowInfo) Position() token.Position {
+	return i.fset.Position(i.Pos)
+}
+
+func (i *OverflowInfo) String() string {
+	return fmt.Sprintf("integer overflow: %v %v %v (%v)", i.X, i.Op, i.Y, i.Type)
+}
+
+// SetOverflow sets the func called at a signed integer overflow of an add,
+// sub or mul in CheckOverflow mode, the operation result wraps around like
+// in Go. Without a func the overflow panics with a runtime error.
+func (c *Context) SetOverflow(fn func(*OverflowInfo)) {
+	c.overflow = fn
+}
+
+// makeOverflowCheck returns the signed integer operation instr checked for
+// overflow, or nil for other operations.
+func makeOverflowCheck(interp *Interp, instr *ssa.BinOp, ir, ix, iy int) func(fr *frame) {
+	switch instr.Op {
+	case token.ADD, token.SUB, token.MUL:
+	default:
+		return nil
+	}
+	t, ok := instr.X.Type().Underlying().(*types.Basic)
+	if !ok || t.Info()&types.IsInteger == 0 || t.Info()&types.IsUnsigned != 0 {
+		return nil
+	}
+	typ := interp.preToType(instr.X.Type())
+	size := typ.Bits()
+	op := instr.Op
+	return func(fr *frame) {
+		x, y := fr.reg(ix), fr.reg(iy)
+		r := binop(instr, nil, x, y)
+		if overflows(op, reflect.ValueOf(x).Int(), reflect.ValueOf(y).Int(), reflect.ValueOf(r).Int(), size) {
+			info := &OverflowInfo{Op: op, X: x, Y: y, Result: r, Type: typ, Pos: instr.Pos(), fset: interp.fset}
+			fn := interp.ctx.overflow
+			if fn == nil {
+				panic(runtimeError{msg: info.String(), Pos: info.Position()})
+			}
+			fn(info)
+		}
+		fr.setReg(ir, r)
+	}
+}
+
+// overflows reports whether x op y overflows to r in signed integers of
+// size bits.
+func overflows(op token.Token, x, y, r int64, size int) bool {
+	if size < 64 {
+		// exact in int64
+		switch op {
+		case token.ADD:
+			return x+y != r
+		case token.SUB:
+			return x-y != r
+		default:
+			return x*y != r
+		}
+	}
+	switch op {
+	case token.ADD:
+		return (x^r)&(y^r) < 0
+	case token.SUB:
+		return (x^y)&(x^r) < 0
+	default:
+		return x != 0 && (r/x != y || x == -1 && y == math.MinInt64)
+	}
+}
@@ -3,6 +3,8 @@ package gossa
 import (
 	"go/constant"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 var (
@@ -15,30 +17,90 @@ func LookupPackage(name string) (pkg *Package, ok bool) {
 	return
 }
 
-// register pkg
+// PackageList returns the sorted paths of the registered packages, the
+// packages compiled in by importing their gossa/pkg or gossa/stdlib
+// packages.
+func PackageList() []string {
+	list := make([]string, 0, len(registerPkgs))
+	for path := range registerPkgs {
+		list = append(list, path)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// register pkg, the symbols of a package registered again are added to it,
+// eg. the symbols of a Go release added by its version tagged export file.
 func RegisterPackage(pkg *Package) {
 	if p, ok := registerPkgs[pkg.Path]; ok {
 		for k, v := range pkg.Interfaces {
 			p.Interfaces[k] = v
 		}
 		for k, v := range pkg.NamedTypes {
+			if t, ok := p.NamedTypes[k]; ok && t.Typ == v.Typ {
+				v.Methods = joinMethods(t.Methods, v.Methods)
+				v.PtrMethods = joinMethods(t.PtrMethods, v.PtrMethods)
+			}
 			p.NamedTypes[k] = v
 		}
+		for k, v := range pkg.AliasTypes {
+			p.AliasTypes[k] = v
+		}
 		for k, v := range pkg.Vars {
 			p.Vars[k] = v
 		}
 		for k, v := range pkg.Funcs {
 			p.Funcs[k] = v
 		}
+		for k, v := range pkg.TypedConsts {
+			p.TypedConsts[k] = v
+		}
 		for k, v := range pkg.UntypedConsts {
 			p.UntypedConsts[k] = v
 		}
+		for k, v := range pkg.Deps {
+			p.Deps[k] = v
+		}
+		if len(pkg.GenericTypes) != 0 && p.GenericTypes == nil {
+			p.GenericTypes = make(map[string]string)
+		}
+		for k, v := range pkg.GenericTypes {
+			p.GenericTypes[k] = v
+		}
+		if len(pkg.GenericFuncs) != 0 && p.GenericFuncs == nil {
+			p.GenericFuncs = make(map[string]GenericFunc)
+		}
+		for k, v := range pkg.GenericFuncs {
+			if f, ok := p.GenericFuncs[k]; ok && f.Decl == v.Decl {
+				v.Instances = append(f.Instances[:len(f.Instances):len(f.Instances)], v.Instances...)
+			}
+			p.GenericFuncs[k] = v
+		}
 		return
 	}
 	registerPkgs[pkg.Path] = pkg
 	//	externPackages[pkg.Path] = true
 }
 
+// joinMethods returns the comma separated method names of a and b.
+func joinMethods(a, b string) string {
+	if a == "" || a == b {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range strings.Split(a+","+b, ",") {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
 type TypedConst struct {
 	Typ   reflect.Type
 	Value constant.Value
@@ -55,6 +117,16 @@ type NamedType struct {
 	PtrMethods string
 }
 
+// GenericFunc is a generic function of a package, declared by its Go
+// source without body, eg. "func Max[T cmp.Ordered](x T, y ...T) T", and
+// implemented by the instantiations registered, eg. slices.Max[[]int].
+// Scripts call it with the type arguments inferred, a call with other
+// type arguments fails to load as a missing instance.
+type GenericFunc struct {
+	Decl      string
+	Instances []reflect.Value
+}
+
 type Package struct {
 	Name          string
 	Path          string
@@ -66,6 +138,8 @@ type Package struct {
 	TypedConsts   map[string]TypedConst
 	UntypedConsts map[string]UntypedConst
 	Deps          map[string]string
+	GenericTypes  map[string]string        // constraint declarations, eg. "type Ordered interface{ ~int | ~string }"
+	GenericFuncs  map[string]GenericFunc   // generic functions, Go 1.18 and later
 	methods       map[string]reflect.Value // methods cached
 }
 
@@ -77,3 +151,27 @@ var (
 func RegisterExternal(key string, i interface{}) {
 	externValues[key] = reflect.ValueOf(i)
 }
+
+// externPackage synthesizes the package path from the external values
+// registered as "path.Name", for packages without export file. Values are
+// items of NewPackageFromMap, so a reflect.Type registers a type, other
+// values are skipped. Scripts using other symbols of the package fail to
+// type check with the missing names.
+func externPackage(path string) (*Package, bool) {
+	pkg := newBindPackage(path)
+	prefix := path + "."
+	var found bool
+	for key, v := range externValues {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		name := key[len(prefix):]
+		if strings.ContainsAny(name, "./") || !v.IsValid() {
+			continue
+		}
+		if pkg.bind(name, v.Interface()) == nil {
+			found = true
+		}
+	}
+	return pkg.Package, found
+}
@@ -0,0 +1,156 @@
+package gossa
+
+import (
+	"go/token"
+	"runtime"
+	"strings"
+)
+
+// PanicInfo describes a panic of the target program.
+type PanicInfo struct {
+	Value interface{}    // panic() value, or runtime error
+	Pos   token.Position // position of the panicking instruction
+	Stack []StackFrame   // interpreted call chain, innermost first
+}
+
+// SetPanicHandler sets fn called when the target program panics, before
+// the deferred calls run, whether the panic is recovered or not. A nil fn
+// removes the handler. SetPanicHandler should be called before running the
+// interpreter.
+func (i *Interp) SetPanicHandler(fn func(info *PanicInfo)) {
+	i.panicFunc = fn
+}
+
+// TargetPanic returns the PanicInfo of p, a panic of the target program
+// recovered by the host, eg. in a host function calling a func of the
+// script, or the error of a panicking RunFunc call.
+func TargetPanic(p interface{}) (*PanicInfo, bool) {
+	var info *PanicInfo
+	switch p := p.(type) {
+	case targetPanic:
+		info = &PanicInfo{Value: p.v, Stack: p.stack()}
+	case runtimePanic:
+		info = &PanicInfo{Value: p.err, Stack: frameStack(p.fr)}
+	default:
+		return nil, false
+	}
+	if len(info.Stack) > 0 {
+		info.Pos = info.Stack[0].Pos
+	}
+	return info, true
+}
+
+// reportPanic reports the panic unwinding fr to the panic handler, once
+// by the innermost frame.
+func (fr *frame) reportPanic() {
+	if fr.pc == -1 || fr.panicked {
+		return
+	}
+	p := recover()
+	if p == nil {
+		// runtime.Goexit
+		return
+	}
+	for caller := fr; caller != nil; caller = caller.caller {
+		caller.panicked = true
+	}
+	info := &PanicInfo{Value: p, Stack: frameStack(fr)}
+	switch p := p.(type) {
+	case targetPanic:
+		info.Value = p.v
+	case runtimePanic:
+		info.Value = p.err
+	}
+	if len(info.Stack) > 0 {
+		info.Pos = info.Stack[0].Pos
+	}
+	fr.interp.panicFunc(info)
+	panic(p)
+}
+
+// addPanicPos adds the position of the failed operation of fr to the
+// runtime error unwinding fr in EnableRuntimePos mode, once by the
+// innermost frame. Runtime errors of the host, eg. integer divide by
+// zero, become runtime errors of the interpreter.
+func (fr *frame) addPanicPos() {
+	if fr.pc == -1 {
+		return
+	}
+	p := recover()
+	if p == nil {
+		// runtime.Goexit
+		return
+	}
+	pos := fr.interp.fset.Position(fr.pfn.PosForPC(fr.pc - 1))
+	switch e := p.(type) {
+	case runtimePanic:
+		// added by the innermost frame
+	case runtimeError:
+		if !e.Pos.IsValid() {
+			e.Pos = pos
+			p = e
+		}
+	case runtime.Error:
+		if msg := e.Error(); strings.HasPrefix(msg, "runtime error: ") {
+			p = runtimeError{msg: strings.TrimPrefix(msg, "runtime error: "), Pos: pos}
+		}
+	}
+	panic(p)
+}
+
+// runtimePanic is a runtime error of the interpreted program with the frame
+// where it occurred, whose stack is reported by TargetPanic.
+type runtimePanic struct {
+	err runtime.Error
+	fr  *frame
+}
+
+func (p runtimePanic) RuntimeError() {}
+
+func (p runtimePanic) Error() string {
+	return p.err.Error()
+}
+
+// stackPanic adds fr to the runtime error unwinding fr, once by the
+// innermost frame.
+func (fr *frame) stackPanic() {
+	if fr.pc == -1 {
+		return
+	}
+	p := recover()
+	if p == nil {
+		// runtime.Goexit
+		return
+	}
+	switch e := p.(type) {
+	case runtimePanic:
+	case runtime.Error:
+		p = runtimePanic{e, fr}
+	}
+	panic(p)
+}
+
+// recovered clears the reported panic of fr and its callers.
+func (fr *frame) recovered() {
+	for ; fr != nil && fr.panicked; fr = fr.caller {
+		fr.panicked = false
+	}
+}
+
+// frameStack returns the interpreted call stack of fr, innermost first.
+// The calls inlined in a frame are listed before it.
+func frameStack(fr *frame) (frames []StackFrame) {
+	for ; fr != nil; fr = fr.caller {
+		for site, pc := fr.site, fr.sitePC; site != nil; site, pc = site.caller, site.pc {
+			frames = append(frames, StackFrame{
+				Func: site.fn,
+				Pos:  fr.interp.fset.Position(site.pos[pc]),
+			})
+		}
+		frames = append(frames, StackFrame{
+			Func: fr.pfn.Fn,
+			Pos:  fr.interp.fset.Position(fr.pfn.PosForPC(fr.pc - 1)),
+		})
+	}
+	return
+}
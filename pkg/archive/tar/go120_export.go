@@ -0,0 +1,51 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package tar
+
+import (
+	q "archive/tar"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "tar",
+		Path: "archive/tar",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/godebug": "godebug",
+			"io":               "io",
+			"io/fs":            "fs",
+			"maps":             "maps",
+			"math":             "math",
+			"os/user":          "user",
+			"path":             "path",
+			"path/filepath":    "filepath",
+			"reflect":          "reflect",
+			"runtime":          "runtime",
+			"slices":           "slices",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"sync":             "sync",
+			"syscall":          "syscall",
+			"time":             "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"ErrInsecurePath": reflect.ValueOf(&q.ErrInsecurePath),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
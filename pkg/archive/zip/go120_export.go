@@ -0,0 +1,50 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package zip
+
+import (
+	q "archive/zip"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "zip",
+		Path: "archive/zip",
+		Deps: map[string]string{
+			"bufio":            "bufio",
+			"compress/flate":   "flate",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"hash":             "hash",
+			"hash/crc32":       "crc32",
+			"internal/godebug": "godebug",
+			"io":               "io",
+			"io/fs":            "fs",
+			"os":               "os",
+			"path":             "path",
+			"path/filepath":    "filepath",
+			"slices":           "slices",
+			"strings":          "strings",
+			"sync":             "sync",
+			"time":             "time",
+			"unicode/utf8":     "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"ErrInsecurePath": reflect.ValueOf(&q.ErrInsecurePath),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,42 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package bytes
+
+import (
+	q "bytes"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "bytes",
+		Path: "bytes",
+		Deps: map[string]string{
+			"errors":           "errors",
+			"internal/bytealg": "bytealg",
+			"io":               "io",
+			"iter":             "iter",
+			"math/bits":        "bits",
+			"unicode":          "unicode",
+			"unicode/utf8":     "utf8",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Clone":     reflect.ValueOf(q.Clone),
+			"CutPrefix": reflect.ValueOf(q.CutPrefix),
+			"CutSuffix": reflect.ValueOf(q.CutSuffix),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
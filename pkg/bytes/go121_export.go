@@ -0,0 +1,42 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package bytes
+
+import (
+	q "bytes"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "bytes",
+		Path: "bytes",
+		Deps: map[string]string{
+			"errors":           "errors",
+			"internal/bytealg": "bytealg",
+			"io":               "io",
+			"iter":             "iter",
+			"math/bits":        "bits",
+			"unicode":          "unicode",
+			"unicode/utf8":     "utf8",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Buffer": {reflect.TypeOf((*q.Buffer)(nil)).Elem(), "", "Available,AvailableBuffer,Bytes,Cap,Grow,Len,Next,Peek,Read,ReadByte,ReadBytes,ReadFrom,ReadRune,ReadString,Reset,String,Truncate,UnreadByte,UnreadRune,Write,WriteByte,WriteRune,WriteString,WriteTo,empty,grow,readSlice,tryGrowByReslice"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"ContainsFunc": reflect.ValueOf(q.ContainsFunc),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,29 @@
+//go:build go1.21
+// +build go1.21
+
+// The generic functions of cmp are instantiated for int, float64 and
+// string.
+package cmp
+
+import (
+	q "cmp"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "cmp",
+		Path: "cmp",
+		GenericTypes: map[string]string{
+			"Ordered": "type Ordered interface{ ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr | ~float32 | ~float64 | ~string }",
+		},
+		GenericFuncs: map[string]gossa.GenericFunc{
+			"Compare": {"func Compare[T Ordered](x, y T) int", []reflect.Value{reflect.ValueOf(q.Compare[int]), reflect.ValueOf(q.Compare[float64]), reflect.ValueOf(q.Compare[string])}},
+			"Less":    {"func Less[T Ordered](x, y T) bool", []reflect.Value{reflect.ValueOf(q.Less[int]), reflect.ValueOf(q.Less[float64]), reflect.ValueOf(q.Less[string])}},
+			"Or":      {"func Or[T comparable](vals ...T) T", []reflect.Value{reflect.ValueOf(q.Or[int]), reflect.ValueOf(q.Or[float64]), reflect.ValueOf(q.Or[string])}},
+		},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package context
+
+import (
+	q "context"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "context",
+		Path: "context",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/reflectlite": "reflectlite",
+			"sync":                 "sync",
+			"sync/atomic":          "atomic",
+			"time":                 "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"CancelCauseFunc": {reflect.TypeOf((*q.CancelCauseFunc)(nil)).Elem(), "", ""},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Cause":           reflect.ValueOf(q.Cause),
+			"WithCancelCause": reflect.ValueOf(q.WithCancelCause),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package context
+
+import (
+	q "context"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "context",
+		Path: "context",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/reflectlite": "reflectlite",
+			"sync":                 "sync",
+			"sync/atomic":          "atomic",
+			"time":                 "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"AfterFunc":         reflect.ValueOf(q.AfterFunc),
+			"WithDeadlineCause": reflect.ValueOf(q.WithDeadlineCause),
+			"WithTimeoutCause":  reflect.ValueOf(q.WithTimeoutCause),
+			"WithoutCancel":     reflect.ValueOf(q.WithoutCancel),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
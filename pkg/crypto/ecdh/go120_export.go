@@ -0,0 +1,50 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package ecdh
+
+import (
+	q "crypto/ecdh"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "ecdh",
+		Path: "crypto/ecdh",
+		Deps: map[string]string{
+			"bytes":                        "bytes",
+			"crypto":                       "crypto",
+			"crypto/internal/boring":       "boring",
+			"crypto/internal/fips140/ecdh": "ecdh",
+			"crypto/internal/fips140/edwards25519/field": "field",
+			"crypto/internal/fips140only":                "fips140only",
+			"crypto/internal/rand":                       "rand",
+			"crypto/subtle":                              "subtle",
+			"errors":                                     "errors",
+			"io":                                         "io",
+		},
+		Interfaces: map[string]reflect.Type{
+			"Curve": reflect.TypeOf((*q.Curve)(nil)).Elem(),
+		},
+		NamedTypes: map[string]gossa.NamedType{
+			"PrivateKey": {reflect.TypeOf((*q.PrivateKey)(nil)).Elem(), "", "Bytes,Curve,ECDH,Equal,Public,PublicKey"},
+			"PublicKey":  {reflect.TypeOf((*q.PublicKey)(nil)).Elem(), "", "Bytes,Curve,Equal"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"P256":   reflect.ValueOf(q.P256),
+			"P384":   reflect.ValueOf(q.P384),
+			"P521":   reflect.ValueOf(q.P521),
+			"X25519": reflect.ValueOf(q.X25519),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
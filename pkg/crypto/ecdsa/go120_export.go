@@ -0,0 +1,52 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package ecdsa
+
+import (
+	q "crypto/ecdsa"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "ecdsa",
+		Path: "crypto/ecdsa",
+		Deps: map[string]string{
+			"crypto":                                "crypto",
+			"crypto/ecdh":                           "ecdh",
+			"crypto/elliptic":                       "elliptic",
+			"crypto/internal/boring":                "boring",
+			"crypto/internal/boring/bbig":           "bbig",
+			"crypto/internal/fips140/ecdsa":         "ecdsa",
+			"crypto/internal/fips140/nistec":        "nistec",
+			"crypto/internal/fips140cache":          "fips140cache",
+			"crypto/internal/fips140hash":           "fips140hash",
+			"crypto/internal/fips140only":           "fips140only",
+			"crypto/internal/rand":                  "rand",
+			"crypto/sha512":                         "sha512",
+			"crypto/subtle":                         "subtle",
+			"errors":                                "errors",
+			"io":                                    "io",
+			"math/big":                              "big",
+			"math/rand/v2":                          "rand",
+			"vendor/golang.org/x/crypto/cryptobyte": "cryptobyte",
+			"vendor/golang.org/x/crypto/cryptobyte/asn1": "asn1",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"PrivateKey": {reflect.TypeOf((*q.PrivateKey)(nil)).Elem(), "", "Bytes,ECDH,Equal,Public,Sign"},
+			"PublicKey":  {reflect.TypeOf((*q.PublicKey)(nil)).Elem(), "", "Bytes,ECDH,Equal"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,45 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package ed25519
+
+import (
+	q "crypto/ed25519"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "ed25519",
+		Path: "crypto/ed25519",
+		Deps: map[string]string{
+			"crypto":                          "crypto",
+			"crypto/internal/fips140/ed25519": "ed25519",
+			"crypto/internal/fips140cache":    "fips140cache",
+			"crypto/internal/fips140only":     "fips140only",
+			"crypto/internal/rand":            "rand",
+			"crypto/rand":                     "rand",
+			"crypto/subtle":                   "subtle",
+			"errors":                          "errors",
+			"internal/godebug":                "godebug",
+			"io":                              "io",
+			"strconv":                         "strconv",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Options": {reflect.TypeOf((*q.Options)(nil)).Elem(), "", "HashFunc"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"VerifyWithOptions": reflect.ValueOf(q.VerifyWithOptions),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,43 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package elliptic
+
+import (
+	q "crypto/elliptic"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "elliptic",
+		Path: "crypto/elliptic",
+		Deps: map[string]string{
+			"crypto/internal/fips140/nistec": "nistec",
+			"errors":                         "errors",
+			"io":                             "io",
+			"math/big":                       "big",
+			"sync":                           "sync",
+		},
+		Interfaces: map[string]reflect.Type{
+			"Curve": reflect.TypeOf((*q.Curve)(nil)).Elem(),
+		},
+		NamedTypes: map[string]gossa.NamedType{
+			"CurveParams": {reflect.TypeOf((*q.CurveParams)(nil)).Elem(), "", "Add,Double,IsOnCurve,Params,ScalarBaseMult,ScalarMult,addJacobian,affineFromJacobian,doubleJacobian,polynomial"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"GenerateKey": reflect.ValueOf(q.GenerateKey),
+			"Marshal":     reflect.ValueOf(q.Marshal),
+			"Unmarshal":   reflect.ValueOf(q.Unmarshal),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,49 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package rsa
+
+import (
+	q "crypto/rsa"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "rsa",
+		Path: "crypto/rsa",
+		Deps: map[string]string{
+			"crypto":                         "crypto",
+			"crypto/internal/boring":         "boring",
+			"crypto/internal/boring/bbig":    "bbig",
+			"crypto/internal/fips140/bigmod": "bigmod",
+			"crypto/internal/fips140/rsa":    "rsa",
+			"crypto/internal/fips140hash":    "fips140hash",
+			"crypto/internal/fips140only":    "fips140only",
+			"crypto/internal/rand":           "rand",
+			"crypto/rand":                    "rand",
+			"crypto/subtle":                  "subtle",
+			"errors":                         "errors",
+			"fmt":                            "fmt",
+			"hash":                           "hash",
+			"internal/godebug":               "godebug",
+			"io":                             "io",
+			"math":                           "math",
+			"math/big":                       "big",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"GenerateMultiPrimeKey": reflect.ValueOf(q.GenerateMultiPrimeKey),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
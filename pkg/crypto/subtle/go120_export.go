@@ -0,0 +1,36 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package subtle
+
+import (
+	q "crypto/subtle"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "subtle",
+		Path: "crypto/subtle",
+		Deps: map[string]string{
+			"crypto/internal/constanttime":   "constanttime",
+			"crypto/internal/fips140/subtle": "subtle",
+			"internal/runtime/sys":           "sys",
+			"unsafe":                         "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"XORBytes": reflect.ValueOf(q.XORBytes),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,86 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package tls
+
+import (
+	q "crypto/tls"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "tls",
+		Path: "crypto/tls",
+		Deps: map[string]string{
+			"bytes":                           "bytes",
+			"container/list":                  "list",
+			"context":                         "context",
+			"crypto":                          "crypto",
+			"crypto/aes":                      "aes",
+			"crypto/cipher":                   "cipher",
+			"crypto/des":                      "des",
+			"crypto/ecdh":                     "ecdh",
+			"crypto/ecdsa":                    "ecdsa",
+			"crypto/ed25519":                  "ed25519",
+			"crypto/elliptic":                 "elliptic",
+			"crypto/fips140":                  "fips140",
+			"crypto/hkdf":                     "hkdf",
+			"crypto/hmac":                     "hmac",
+			"crypto/hpke":                     "hpke",
+			"crypto/internal/boring":          "boring",
+			"crypto/internal/fips140/aes":     "aes",
+			"crypto/internal/fips140/aes/gcm": "gcm",
+			"crypto/internal/fips140/tls12":   "tls12",
+			"crypto/internal/fips140/tls13":   "tls13",
+			"crypto/md5":                      "md5",
+			"crypto/mldsa":                    "mldsa",
+			"crypto/mlkem":                    "mlkem",
+			"crypto/rand":                     "rand",
+			"crypto/rc4":                      "rc4",
+			"crypto/rsa":                      "rsa",
+			"crypto/sha1":                     "sha1",
+			"crypto/sha256":                   "sha256",
+			"crypto/sha512":                   "sha512",
+			"crypto/subtle":                   "subtle",
+			"crypto/tls/internal/fips140tls":  "fips140tls",
+			"crypto/x509":                     "x509",
+			"encoding/pem":                    "pem",
+			"errors":                          "errors",
+			"fmt":                             "fmt",
+			"hash":                            "hash",
+			"internal/byteorder":              "byteorder",
+			"internal/cpu":                    "cpu",
+			"internal/godebug":                "godebug",
+			"io":                              "io",
+			"net":                             "net",
+			"os":                              "os",
+			"runtime":                         "runtime",
+			"slices":                          "slices",
+			"sort":                            "sort",
+			"strconv":                         "strconv",
+			"strings":                         "strings",
+			"sync":                            "sync",
+			"sync/atomic":                     "atomic",
+			"time":                            "time",
+			"unsafe":                          "unsafe",
+			"vendor/golang.org/x/crypto/chacha20poly1305": "chacha20poly1305",
+			"vendor/golang.org/x/crypto/cryptobyte":       "cryptobyte",
+			"weak":                                        "weak",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"CertificateVerificationError": {reflect.TypeOf((*q.CertificateVerificationError)(nil)).Elem(), "", "Error,Unwrap"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
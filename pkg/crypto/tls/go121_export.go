@@ -0,0 +1,115 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package tls
+
+import (
+	q "crypto/tls"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "tls",
+		Path: "crypto/tls",
+		Deps: map[string]string{
+			"bytes":                           "bytes",
+			"container/list":                  "list",
+			"context":                         "context",
+			"crypto":                          "crypto",
+			"crypto/aes":                      "aes",
+			"crypto/cipher":                   "cipher",
+			"crypto/des":                      "des",
+			"crypto/ecdh":                     "ecdh",
+			"crypto/ecdsa":                    "ecdsa",
+			"crypto/ed25519":                  "ed25519",
+			"crypto/elliptic":                 "elliptic",
+			"crypto/fips140":                  "fips140",
+			"crypto/hkdf":                     "hkdf",
+			"crypto/hmac":                     "hmac",
+			"crypto/hpke":                     "hpke",
+			"crypto/internal/boring":          "boring",
+			"crypto/internal/fips140/aes":     "aes",
+			"crypto/internal/fips140/aes/gcm": "gcm",
+			"crypto/internal/fips140/tls12":   "tls12",
+			"crypto/internal/fips140/tls13":   "tls13",
+			"crypto/md5":                      "md5",
+			"crypto/mldsa":                    "mldsa",
+			"crypto/mlkem":                    "mlkem",
+			"crypto/rand":                     "rand",
+			"crypto/rc4":                      "rc4",
+			"crypto/rsa":                      "rsa",
+			"crypto/sha1":                     "sha1",
+			"crypto/sha256":                   "sha256",
+			"crypto/sha512":                   "sha512",
+			"crypto/subtle":                   "subtle",
+			"crypto/tls/internal/fips140tls":  "fips140tls",
+			"crypto/x509":                     "x509",
+			"encoding/pem":                    "pem",
+			"errors":                          "errors",
+			"fmt":                             "fmt",
+			"hash":                            "hash",
+			"internal/byteorder":              "byteorder",
+			"internal/cpu":                    "cpu",
+			"internal/godebug":                "godebug",
+			"io":                              "io",
+			"net":                             "net",
+			"os":                              "os",
+			"runtime":                         "runtime",
+			"slices":                          "slices",
+			"sort":                            "sort",
+			"strconv":                         "strconv",
+			"strings":                         "strings",
+			"sync":                            "sync",
+			"sync/atomic":                     "atomic",
+			"time":                            "time",
+			"unsafe":                          "unsafe",
+			"vendor/golang.org/x/crypto/chacha20poly1305": "chacha20poly1305",
+			"vendor/golang.org/x/crypto/cryptobyte":       "cryptobyte",
+			"weak":                                        "weak",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"AlertError":               {reflect.TypeOf((*q.AlertError)(nil)).Elem(), "Error", ""},
+			"ClientSessionState":       {reflect.TypeOf((*q.ClientSessionState)(nil)).Elem(), "", "ResumptionState"},
+			"Config":                   {reflect.TypeOf((*q.Config)(nil)).Elem(), "", "BuildNameToCertificate,Clone,DecryptTicket,EncryptTicket,SetSessionTicketKeys,cipherSuites,curvePreferences,decryptTicket,encryptTicket,getCertificate,initLegacySessionTicketKeyRLocked,maxSupportedVersion,mutualVersion,rand,supportedCipherSuites,supportedVersions,supportsCurve,ticketKeyFromBytes,ticketKeys,time,writeKeyLog"},
+			"QUICConfig":               {reflect.TypeOf((*q.QUICConfig)(nil)).Elem(), "", ""},
+			"QUICConn":                 {reflect.TypeOf((*q.QUICConn)(nil)).Elem(), "", "Close,ConnectionState,HandleData,NextEvent,SendSessionTicket,SetTransportParameters,Start,StoreSession"},
+			"QUICEncryptionLevel":      {reflect.TypeOf((*q.QUICEncryptionLevel)(nil)).Elem(), "String", ""},
+			"QUICEvent":                {reflect.TypeOf((*q.QUICEvent)(nil)).Elem(), "", ""},
+			"QUICEventKind":            {reflect.TypeOf((*q.QUICEventKind)(nil)).Elem(), "", ""},
+			"QUICSessionTicketOptions": {reflect.TypeOf((*q.QUICSessionTicketOptions)(nil)).Elem(), "", ""},
+			"SessionState":             {reflect.TypeOf((*q.SessionState)(nil)).Elem(), "", "Bytes"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"NewResumptionState": reflect.ValueOf(q.NewResumptionState),
+			"ParseSessionState":  reflect.ValueOf(q.ParseSessionState),
+			"QUICClient":         reflect.ValueOf(q.QUICClient),
+			"QUICServer":         reflect.ValueOf(q.QUICServer),
+			"VersionName":        reflect.ValueOf(q.VersionName),
+		},
+		TypedConsts: map[string]gossa.TypedConst{
+			"QUICEncryptionLevelApplication":  {reflect.TypeOf(q.QUICEncryptionLevelApplication), constant.MakeInt64(int64(q.QUICEncryptionLevelApplication))},
+			"QUICEncryptionLevelEarly":        {reflect.TypeOf(q.QUICEncryptionLevelEarly), constant.MakeInt64(int64(q.QUICEncryptionLevelEarly))},
+			"QUICEncryptionLevelHandshake":    {reflect.TypeOf(q.QUICEncryptionLevelHandshake), constant.MakeInt64(int64(q.QUICEncryptionLevelHandshake))},
+			"QUICEncryptionLevelInitial":      {reflect.TypeOf(q.QUICEncryptionLevelInitial), constant.MakeInt64(int64(q.QUICEncryptionLevelInitial))},
+			"QUICHandshakeDone":               {reflect.TypeOf(q.QUICHandshakeDone), constant.MakeInt64(int64(q.QUICHandshakeDone))},
+			"QUICNoEvent":                     {reflect.TypeOf(q.QUICNoEvent), constant.MakeInt64(int64(q.QUICNoEvent))},
+			"QUICRejectedEarlyData":           {reflect.TypeOf(q.QUICRejectedEarlyData), constant.MakeInt64(int64(q.QUICRejectedEarlyData))},
+			"QUICSetReadSecret":               {reflect.TypeOf(q.QUICSetReadSecret), constant.MakeInt64(int64(q.QUICSetReadSecret))},
+			"QUICSetWriteSecret":              {reflect.TypeOf(q.QUICSetWriteSecret), constant.MakeInt64(int64(q.QUICSetWriteSecret))},
+			"QUICTransportParameters":         {reflect.TypeOf(q.QUICTransportParameters), constant.MakeInt64(int64(q.QUICTransportParameters))},
+			"QUICTransportParametersRequired": {reflect.TypeOf(q.QUICTransportParametersRequired), constant.MakeInt64(int64(q.QUICTransportParametersRequired))},
+			"QUICWriteData":                   {reflect.TypeOf(q.QUICWriteData), constant.MakeInt64(int64(q.QUICWriteData))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,81 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package x509
+
+import (
+	q "crypto/x509"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "x509",
+		Path: "crypto/x509",
+		Deps: map[string]string{
+			"bytes":                                 "bytes",
+			"crypto":                                "crypto",
+			"crypto/aes":                            "aes",
+			"crypto/cipher":                         "cipher",
+			"crypto/des":                            "des",
+			"crypto/dsa":                            "dsa",
+			"crypto/ecdh":                           "ecdh",
+			"crypto/ecdsa":                          "ecdsa",
+			"crypto/ed25519":                        "ed25519",
+			"crypto/elliptic":                       "elliptic",
+			"crypto/fips140":                        "fips140",
+			"crypto/md5":                            "md5",
+			"crypto/mldsa":                          "mldsa",
+			"crypto/rsa":                            "rsa",
+			"crypto/sha1":                           "sha1",
+			"crypto/sha256":                         "sha256",
+			"crypto/sha512":                         "sha512",
+			"crypto/x509/pkix":                      "pkix",
+			"encoding/asn1":                         "asn1",
+			"encoding/hex":                          "hex",
+			"encoding/pem":                          "pem",
+			"errors":                                "errors",
+			"fmt":                                   "fmt",
+			"internal/godebug":                      "godebug",
+			"internal/goos":                         "goos",
+			"io":                                    "io",
+			"io/fs":                                 "fs",
+			"iter":                                  "iter",
+			"maps":                                  "maps",
+			"math":                                  "math",
+			"math/big":                              "big",
+			"math/bits":                             "bits",
+			"net":                                   "net",
+			"net/netip":                             "netip",
+			"net/url":                               "url",
+			"os":                                    "os",
+			"path/filepath":                         "filepath",
+			"runtime":                               "runtime",
+			"slices":                                "slices",
+			"strconv":                               "strconv",
+			"strings":                               "strings",
+			"sync":                                  "sync",
+			"time":                                  "time",
+			"unicode":                               "unicode",
+			"unicode/utf16":                         "utf16",
+			"unicode/utf8":                          "utf8",
+			"unsafe":                                "unsafe",
+			"vendor/golang.org/x/crypto/cryptobyte": "cryptobyte",
+			"vendor/golang.org/x/crypto/cryptobyte/asn1": "asn1",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"RevocationListEntry": {reflect.TypeOf((*q.RevocationListEntry)(nil)).Elem(), "", ""},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,39 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package pkix
+
+import (
+	q "crypto/x509/pkix"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "pkix",
+		Path: "crypto/x509/pkix",
+		Deps: map[string]string{
+			"encoding/asn1": "asn1",
+			"encoding/hex":  "hex",
+			"fmt":           "fmt",
+			"math/big":      "big",
+			"strings":       "strings",
+			"time":          "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"CertificateList":    {reflect.TypeOf((*q.CertificateList)(nil)).Elem(), "", "HasExpired"},
+			"TBSCertificateList": {reflect.TypeOf((*q.TBSCertificateList)(nil)).Elem(), "", ""},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
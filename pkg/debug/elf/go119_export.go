@@ -0,0 +1,99 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package elf
+
+import (
+	q "debug/elf"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "elf",
+		Path: "debug/elf",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"internal/zstd":    "zstd",
+			"io":               "io",
+			"math":             "math",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"R_LARCH": {reflect.TypeOf((*q.R_LARCH)(nil)).Elem(), "GoString,String", ""},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"EM_LOONGARCH":                       {reflect.TypeOf(q.EM_LOONGARCH), constant.MakeInt64(int64(q.EM_LOONGARCH))},
+			"R_LARCH_32":                         {reflect.TypeOf(q.R_LARCH_32), constant.MakeInt64(int64(q.R_LARCH_32))},
+			"R_LARCH_64":                         {reflect.TypeOf(q.R_LARCH_64), constant.MakeInt64(int64(q.R_LARCH_64))},
+			"R_LARCH_ADD16":                      {reflect.TypeOf(q.R_LARCH_ADD16), constant.MakeInt64(int64(q.R_LARCH_ADD16))},
+			"R_LARCH_ADD24":                      {reflect.TypeOf(q.R_LARCH_ADD24), constant.MakeInt64(int64(q.R_LARCH_ADD24))},
+			"R_LARCH_ADD32":                      {reflect.TypeOf(q.R_LARCH_ADD32), constant.MakeInt64(int64(q.R_LARCH_ADD32))},
+			"R_LARCH_ADD64":                      {reflect.TypeOf(q.R_LARCH_ADD64), constant.MakeInt64(int64(q.R_LARCH_ADD64))},
+			"R_LARCH_ADD8":                       {reflect.TypeOf(q.R_LARCH_ADD8), constant.MakeInt64(int64(q.R_LARCH_ADD8))},
+			"R_LARCH_COPY":                       {reflect.TypeOf(q.R_LARCH_COPY), constant.MakeInt64(int64(q.R_LARCH_COPY))},
+			"R_LARCH_IRELATIVE":                  {reflect.TypeOf(q.R_LARCH_IRELATIVE), constant.MakeInt64(int64(q.R_LARCH_IRELATIVE))},
+			"R_LARCH_JUMP_SLOT":                  {reflect.TypeOf(q.R_LARCH_JUMP_SLOT), constant.MakeInt64(int64(q.R_LARCH_JUMP_SLOT))},
+			"R_LARCH_MARK_LA":                    {reflect.TypeOf(q.R_LARCH_MARK_LA), constant.MakeInt64(int64(q.R_LARCH_MARK_LA))},
+			"R_LARCH_MARK_PCREL":                 {reflect.TypeOf(q.R_LARCH_MARK_PCREL), constant.MakeInt64(int64(q.R_LARCH_MARK_PCREL))},
+			"R_LARCH_NONE":                       {reflect.TypeOf(q.R_LARCH_NONE), constant.MakeInt64(int64(q.R_LARCH_NONE))},
+			"R_LARCH_RELATIVE":                   {reflect.TypeOf(q.R_LARCH_RELATIVE), constant.MakeInt64(int64(q.R_LARCH_RELATIVE))},
+			"R_LARCH_SOP_ADD":                    {reflect.TypeOf(q.R_LARCH_SOP_ADD), constant.MakeInt64(int64(q.R_LARCH_SOP_ADD))},
+			"R_LARCH_SOP_AND":                    {reflect.TypeOf(q.R_LARCH_SOP_AND), constant.MakeInt64(int64(q.R_LARCH_SOP_AND))},
+			"R_LARCH_SOP_ASSERT":                 {reflect.TypeOf(q.R_LARCH_SOP_ASSERT), constant.MakeInt64(int64(q.R_LARCH_SOP_ASSERT))},
+			"R_LARCH_SOP_IF_ELSE":                {reflect.TypeOf(q.R_LARCH_SOP_IF_ELSE), constant.MakeInt64(int64(q.R_LARCH_SOP_IF_ELSE))},
+			"R_LARCH_SOP_NOT":                    {reflect.TypeOf(q.R_LARCH_SOP_NOT), constant.MakeInt64(int64(q.R_LARCH_SOP_NOT))},
+			"R_LARCH_SOP_POP_32_S_0_10_10_16_S2": {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_0_10_10_16_S2), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_0_10_10_16_S2))},
+			"R_LARCH_SOP_POP_32_S_0_5_10_16_S2":  {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_0_5_10_16_S2), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_0_5_10_16_S2))},
+			"R_LARCH_SOP_POP_32_S_10_12":         {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_10_12), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_10_12))},
+			"R_LARCH_SOP_POP_32_S_10_16":         {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_10_16), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_10_16))},
+			"R_LARCH_SOP_POP_32_S_10_16_S2":      {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_10_16_S2), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_10_16_S2))},
+			"R_LARCH_SOP_POP_32_S_10_5":          {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_10_5), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_10_5))},
+			"R_LARCH_SOP_POP_32_S_5_20":          {reflect.TypeOf(q.R_LARCH_SOP_POP_32_S_5_20), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_S_5_20))},
+			"R_LARCH_SOP_POP_32_U":               {reflect.TypeOf(q.R_LARCH_SOP_POP_32_U), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_U))},
+			"R_LARCH_SOP_POP_32_U_10_12":         {reflect.TypeOf(q.R_LARCH_SOP_POP_32_U_10_12), constant.MakeInt64(int64(q.R_LARCH_SOP_POP_32_U_10_12))},
+			"R_LARCH_SOP_PUSH_ABSOLUTE":          {reflect.TypeOf(q.R_LARCH_SOP_PUSH_ABSOLUTE), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_ABSOLUTE))},
+			"R_LARCH_SOP_PUSH_DUP":               {reflect.TypeOf(q.R_LARCH_SOP_PUSH_DUP), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_DUP))},
+			"R_LARCH_SOP_PUSH_GPREL":             {reflect.TypeOf(q.R_LARCH_SOP_PUSH_GPREL), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_GPREL))},
+			"R_LARCH_SOP_PUSH_PCREL":             {reflect.TypeOf(q.R_LARCH_SOP_PUSH_PCREL), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_PCREL))},
+			"R_LARCH_SOP_PUSH_PLT_PCREL":         {reflect.TypeOf(q.R_LARCH_SOP_PUSH_PLT_PCREL), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_PLT_PCREL))},
+			"R_LARCH_SOP_PUSH_TLS_GD":            {reflect.TypeOf(q.R_LARCH_SOP_PUSH_TLS_GD), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_TLS_GD))},
+			"R_LARCH_SOP_PUSH_TLS_GOT":           {reflect.TypeOf(q.R_LARCH_SOP_PUSH_TLS_GOT), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_TLS_GOT))},
+			"R_LARCH_SOP_PUSH_TLS_TPREL":         {reflect.TypeOf(q.R_LARCH_SOP_PUSH_TLS_TPREL), constant.MakeInt64(int64(q.R_LARCH_SOP_PUSH_TLS_TPREL))},
+			"R_LARCH_SOP_SL":                     {reflect.TypeOf(q.R_LARCH_SOP_SL), constant.MakeInt64(int64(q.R_LARCH_SOP_SL))},
+			"R_LARCH_SOP_SR":                     {reflect.TypeOf(q.R_LARCH_SOP_SR), constant.MakeInt64(int64(q.R_LARCH_SOP_SR))},
+			"R_LARCH_SOP_SUB":                    {reflect.TypeOf(q.R_LARCH_SOP_SUB), constant.MakeInt64(int64(q.R_LARCH_SOP_SUB))},
+			"R_LARCH_SUB16":                      {reflect.TypeOf(q.R_LARCH_SUB16), constant.MakeInt64(int64(q.R_LARCH_SUB16))},
+			"R_LARCH_SUB24":                      {reflect.TypeOf(q.R_LARCH_SUB24), constant.MakeInt64(int64(q.R_LARCH_SUB24))},
+			"R_LARCH_SUB32":                      {reflect.TypeOf(q.R_LARCH_SUB32), constant.MakeInt64(int64(q.R_LARCH_SUB32))},
+			"R_LARCH_SUB64":                      {reflect.TypeOf(q.R_LARCH_SUB64), constant.MakeInt64(int64(q.R_LARCH_SUB64))},
+			"R_LARCH_SUB8":                       {reflect.TypeOf(q.R_LARCH_SUB8), constant.MakeInt64(int64(q.R_LARCH_SUB8))},
+			"R_LARCH_TLS_DTPMOD32":               {reflect.TypeOf(q.R_LARCH_TLS_DTPMOD32), constant.MakeInt64(int64(q.R_LARCH_TLS_DTPMOD32))},
+			"R_LARCH_TLS_DTPMOD64":               {reflect.TypeOf(q.R_LARCH_TLS_DTPMOD64), constant.MakeInt64(int64(q.R_LARCH_TLS_DTPMOD64))},
+			"R_LARCH_TLS_DTPREL32":               {reflect.TypeOf(q.R_LARCH_TLS_DTPREL32), constant.MakeInt64(int64(q.R_LARCH_TLS_DTPREL32))},
+			"R_LARCH_TLS_DTPREL64":               {reflect.TypeOf(q.R_LARCH_TLS_DTPREL64), constant.MakeInt64(int64(q.R_LARCH_TLS_DTPREL64))},
+			"R_LARCH_TLS_TPREL32":                {reflect.TypeOf(q.R_LARCH_TLS_TPREL32), constant.MakeInt64(int64(q.R_LARCH_TLS_TPREL32))},
+			"R_LARCH_TLS_TPREL64":                {reflect.TypeOf(q.R_LARCH_TLS_TPREL64), constant.MakeInt64(int64(q.R_LARCH_TLS_TPREL64))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
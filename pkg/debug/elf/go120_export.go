@@ -0,0 +1,140 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package elf
+
+import (
+	q "debug/elf"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "elf",
+		Path: "debug/elf",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"internal/zstd":    "zstd",
+			"io":               "io",
+			"math":             "math",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"R_LARCH_32_PCREL":           {reflect.TypeOf(q.R_LARCH_32_PCREL), constant.MakeInt64(int64(q.R_LARCH_32_PCREL))},
+			"R_LARCH_ABS64_HI12":         {reflect.TypeOf(q.R_LARCH_ABS64_HI12), constant.MakeInt64(int64(q.R_LARCH_ABS64_HI12))},
+			"R_LARCH_ABS64_LO20":         {reflect.TypeOf(q.R_LARCH_ABS64_LO20), constant.MakeInt64(int64(q.R_LARCH_ABS64_LO20))},
+			"R_LARCH_ABS_HI20":           {reflect.TypeOf(q.R_LARCH_ABS_HI20), constant.MakeInt64(int64(q.R_LARCH_ABS_HI20))},
+			"R_LARCH_ABS_LO12":           {reflect.TypeOf(q.R_LARCH_ABS_LO12), constant.MakeInt64(int64(q.R_LARCH_ABS_LO12))},
+			"R_LARCH_B16":                {reflect.TypeOf(q.R_LARCH_B16), constant.MakeInt64(int64(q.R_LARCH_B16))},
+			"R_LARCH_B21":                {reflect.TypeOf(q.R_LARCH_B21), constant.MakeInt64(int64(q.R_LARCH_B21))},
+			"R_LARCH_B26":                {reflect.TypeOf(q.R_LARCH_B26), constant.MakeInt64(int64(q.R_LARCH_B26))},
+			"R_LARCH_GNU_VTENTRY":        {reflect.TypeOf(q.R_LARCH_GNU_VTENTRY), constant.MakeInt64(int64(q.R_LARCH_GNU_VTENTRY))},
+			"R_LARCH_GNU_VTINHERIT":      {reflect.TypeOf(q.R_LARCH_GNU_VTINHERIT), constant.MakeInt64(int64(q.R_LARCH_GNU_VTINHERIT))},
+			"R_LARCH_GOT64_HI12":         {reflect.TypeOf(q.R_LARCH_GOT64_HI12), constant.MakeInt64(int64(q.R_LARCH_GOT64_HI12))},
+			"R_LARCH_GOT64_LO20":         {reflect.TypeOf(q.R_LARCH_GOT64_LO20), constant.MakeInt64(int64(q.R_LARCH_GOT64_LO20))},
+			"R_LARCH_GOT64_PC_HI12":      {reflect.TypeOf(q.R_LARCH_GOT64_PC_HI12), constant.MakeInt64(int64(q.R_LARCH_GOT64_PC_HI12))},
+			"R_LARCH_GOT64_PC_LO20":      {reflect.TypeOf(q.R_LARCH_GOT64_PC_LO20), constant.MakeInt64(int64(q.R_LARCH_GOT64_PC_LO20))},
+			"R_LARCH_GOT_HI20":           {reflect.TypeOf(q.R_LARCH_GOT_HI20), constant.MakeInt64(int64(q.R_LARCH_GOT_HI20))},
+			"R_LARCH_GOT_LO12":           {reflect.TypeOf(q.R_LARCH_GOT_LO12), constant.MakeInt64(int64(q.R_LARCH_GOT_LO12))},
+			"R_LARCH_GOT_PC_HI20":        {reflect.TypeOf(q.R_LARCH_GOT_PC_HI20), constant.MakeInt64(int64(q.R_LARCH_GOT_PC_HI20))},
+			"R_LARCH_GOT_PC_LO12":        {reflect.TypeOf(q.R_LARCH_GOT_PC_LO12), constant.MakeInt64(int64(q.R_LARCH_GOT_PC_LO12))},
+			"R_LARCH_PCALA64_HI12":       {reflect.TypeOf(q.R_LARCH_PCALA64_HI12), constant.MakeInt64(int64(q.R_LARCH_PCALA64_HI12))},
+			"R_LARCH_PCALA64_LO20":       {reflect.TypeOf(q.R_LARCH_PCALA64_LO20), constant.MakeInt64(int64(q.R_LARCH_PCALA64_LO20))},
+			"R_LARCH_PCALA_HI20":         {reflect.TypeOf(q.R_LARCH_PCALA_HI20), constant.MakeInt64(int64(q.R_LARCH_PCALA_HI20))},
+			"R_LARCH_PCALA_LO12":         {reflect.TypeOf(q.R_LARCH_PCALA_LO12), constant.MakeInt64(int64(q.R_LARCH_PCALA_LO12))},
+			"R_LARCH_RELAX":              {reflect.TypeOf(q.R_LARCH_RELAX), constant.MakeInt64(int64(q.R_LARCH_RELAX))},
+			"R_LARCH_TLS_GD_HI20":        {reflect.TypeOf(q.R_LARCH_TLS_GD_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_GD_HI20))},
+			"R_LARCH_TLS_GD_PC_HI20":     {reflect.TypeOf(q.R_LARCH_TLS_GD_PC_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_GD_PC_HI20))},
+			"R_LARCH_TLS_IE64_HI12":      {reflect.TypeOf(q.R_LARCH_TLS_IE64_HI12), constant.MakeInt64(int64(q.R_LARCH_TLS_IE64_HI12))},
+			"R_LARCH_TLS_IE64_LO20":      {reflect.TypeOf(q.R_LARCH_TLS_IE64_LO20), constant.MakeInt64(int64(q.R_LARCH_TLS_IE64_LO20))},
+			"R_LARCH_TLS_IE64_PC_HI12":   {reflect.TypeOf(q.R_LARCH_TLS_IE64_PC_HI12), constant.MakeInt64(int64(q.R_LARCH_TLS_IE64_PC_HI12))},
+			"R_LARCH_TLS_IE64_PC_LO20":   {reflect.TypeOf(q.R_LARCH_TLS_IE64_PC_LO20), constant.MakeInt64(int64(q.R_LARCH_TLS_IE64_PC_LO20))},
+			"R_LARCH_TLS_IE_HI20":        {reflect.TypeOf(q.R_LARCH_TLS_IE_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_IE_HI20))},
+			"R_LARCH_TLS_IE_LO12":        {reflect.TypeOf(q.R_LARCH_TLS_IE_LO12), constant.MakeInt64(int64(q.R_LARCH_TLS_IE_LO12))},
+			"R_LARCH_TLS_IE_PC_HI20":     {reflect.TypeOf(q.R_LARCH_TLS_IE_PC_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_IE_PC_HI20))},
+			"R_LARCH_TLS_IE_PC_LO12":     {reflect.TypeOf(q.R_LARCH_TLS_IE_PC_LO12), constant.MakeInt64(int64(q.R_LARCH_TLS_IE_PC_LO12))},
+			"R_LARCH_TLS_LD_HI20":        {reflect.TypeOf(q.R_LARCH_TLS_LD_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_LD_HI20))},
+			"R_LARCH_TLS_LD_PC_HI20":     {reflect.TypeOf(q.R_LARCH_TLS_LD_PC_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_LD_PC_HI20))},
+			"R_LARCH_TLS_LE64_HI12":      {reflect.TypeOf(q.R_LARCH_TLS_LE64_HI12), constant.MakeInt64(int64(q.R_LARCH_TLS_LE64_HI12))},
+			"R_LARCH_TLS_LE64_LO20":      {reflect.TypeOf(q.R_LARCH_TLS_LE64_LO20), constant.MakeInt64(int64(q.R_LARCH_TLS_LE64_LO20))},
+			"R_LARCH_TLS_LE_HI20":        {reflect.TypeOf(q.R_LARCH_TLS_LE_HI20), constant.MakeInt64(int64(q.R_LARCH_TLS_LE_HI20))},
+			"R_LARCH_TLS_LE_LO12":        {reflect.TypeOf(q.R_LARCH_TLS_LE_LO12), constant.MakeInt64(int64(q.R_LARCH_TLS_LE_LO12))},
+			"R_PPC64_ADDR16_HIGHER34":    {reflect.TypeOf(q.R_PPC64_ADDR16_HIGHER34), constant.MakeInt64(int64(q.R_PPC64_ADDR16_HIGHER34))},
+			"R_PPC64_ADDR16_HIGHERA34":   {reflect.TypeOf(q.R_PPC64_ADDR16_HIGHERA34), constant.MakeInt64(int64(q.R_PPC64_ADDR16_HIGHERA34))},
+			"R_PPC64_ADDR16_HIGHEST34":   {reflect.TypeOf(q.R_PPC64_ADDR16_HIGHEST34), constant.MakeInt64(int64(q.R_PPC64_ADDR16_HIGHEST34))},
+			"R_PPC64_ADDR16_HIGHESTA34":  {reflect.TypeOf(q.R_PPC64_ADDR16_HIGHESTA34), constant.MakeInt64(int64(q.R_PPC64_ADDR16_HIGHESTA34))},
+			"R_PPC64_COPY":               {reflect.TypeOf(q.R_PPC64_COPY), constant.MakeInt64(int64(q.R_PPC64_COPY))},
+			"R_PPC64_D28":                {reflect.TypeOf(q.R_PPC64_D28), constant.MakeInt64(int64(q.R_PPC64_D28))},
+			"R_PPC64_D34":                {reflect.TypeOf(q.R_PPC64_D34), constant.MakeInt64(int64(q.R_PPC64_D34))},
+			"R_PPC64_D34_HA30":           {reflect.TypeOf(q.R_PPC64_D34_HA30), constant.MakeInt64(int64(q.R_PPC64_D34_HA30))},
+			"R_PPC64_D34_HI30":           {reflect.TypeOf(q.R_PPC64_D34_HI30), constant.MakeInt64(int64(q.R_PPC64_D34_HI30))},
+			"R_PPC64_D34_LO":             {reflect.TypeOf(q.R_PPC64_D34_LO), constant.MakeInt64(int64(q.R_PPC64_D34_LO))},
+			"R_PPC64_DTPREL34":           {reflect.TypeOf(q.R_PPC64_DTPREL34), constant.MakeInt64(int64(q.R_PPC64_DTPREL34))},
+			"R_PPC64_GLOB_DAT":           {reflect.TypeOf(q.R_PPC64_GLOB_DAT), constant.MakeInt64(int64(q.R_PPC64_GLOB_DAT))},
+			"R_PPC64_GNU_VTENTRY":        {reflect.TypeOf(q.R_PPC64_GNU_VTENTRY), constant.MakeInt64(int64(q.R_PPC64_GNU_VTENTRY))},
+			"R_PPC64_GNU_VTINHERIT":      {reflect.TypeOf(q.R_PPC64_GNU_VTINHERIT), constant.MakeInt64(int64(q.R_PPC64_GNU_VTINHERIT))},
+			"R_PPC64_GOT_DTPREL_PCREL34": {reflect.TypeOf(q.R_PPC64_GOT_DTPREL_PCREL34), constant.MakeInt64(int64(q.R_PPC64_GOT_DTPREL_PCREL34))},
+			"R_PPC64_GOT_PCREL34":        {reflect.TypeOf(q.R_PPC64_GOT_PCREL34), constant.MakeInt64(int64(q.R_PPC64_GOT_PCREL34))},
+			"R_PPC64_GOT_TLSGD_PCREL34":  {reflect.TypeOf(q.R_PPC64_GOT_TLSGD_PCREL34), constant.MakeInt64(int64(q.R_PPC64_GOT_TLSGD_PCREL34))},
+			"R_PPC64_GOT_TLSLD_PCREL34":  {reflect.TypeOf(q.R_PPC64_GOT_TLSLD_PCREL34), constant.MakeInt64(int64(q.R_PPC64_GOT_TLSLD_PCREL34))},
+			"R_PPC64_GOT_TPREL_PCREL34":  {reflect.TypeOf(q.R_PPC64_GOT_TPREL_PCREL34), constant.MakeInt64(int64(q.R_PPC64_GOT_TPREL_PCREL34))},
+			"R_PPC64_PCREL28":            {reflect.TypeOf(q.R_PPC64_PCREL28), constant.MakeInt64(int64(q.R_PPC64_PCREL28))},
+			"R_PPC64_PCREL34":            {reflect.TypeOf(q.R_PPC64_PCREL34), constant.MakeInt64(int64(q.R_PPC64_PCREL34))},
+			"R_PPC64_PCREL_OPT":          {reflect.TypeOf(q.R_PPC64_PCREL_OPT), constant.MakeInt64(int64(q.R_PPC64_PCREL_OPT))},
+			"R_PPC64_PLT16_HA":           {reflect.TypeOf(q.R_PPC64_PLT16_HA), constant.MakeInt64(int64(q.R_PPC64_PLT16_HA))},
+			"R_PPC64_PLT16_HI":           {reflect.TypeOf(q.R_PPC64_PLT16_HI), constant.MakeInt64(int64(q.R_PPC64_PLT16_HI))},
+			"R_PPC64_PLT16_LO":           {reflect.TypeOf(q.R_PPC64_PLT16_LO), constant.MakeInt64(int64(q.R_PPC64_PLT16_LO))},
+			"R_PPC64_PLT32":              {reflect.TypeOf(q.R_PPC64_PLT32), constant.MakeInt64(int64(q.R_PPC64_PLT32))},
+			"R_PPC64_PLT64":              {reflect.TypeOf(q.R_PPC64_PLT64), constant.MakeInt64(int64(q.R_PPC64_PLT64))},
+			"R_PPC64_PLTCALL":            {reflect.TypeOf(q.R_PPC64_PLTCALL), constant.MakeInt64(int64(q.R_PPC64_PLTCALL))},
+			"R_PPC64_PLTCALL_NOTOC":      {reflect.TypeOf(q.R_PPC64_PLTCALL_NOTOC), constant.MakeInt64(int64(q.R_PPC64_PLTCALL_NOTOC))},
+			"R_PPC64_PLTREL32":           {reflect.TypeOf(q.R_PPC64_PLTREL32), constant.MakeInt64(int64(q.R_PPC64_PLTREL32))},
+			"R_PPC64_PLTREL64":           {reflect.TypeOf(q.R_PPC64_PLTREL64), constant.MakeInt64(int64(q.R_PPC64_PLTREL64))},
+			"R_PPC64_PLTSEQ":             {reflect.TypeOf(q.R_PPC64_PLTSEQ), constant.MakeInt64(int64(q.R_PPC64_PLTSEQ))},
+			"R_PPC64_PLTSEQ_NOTOC":       {reflect.TypeOf(q.R_PPC64_PLTSEQ_NOTOC), constant.MakeInt64(int64(q.R_PPC64_PLTSEQ_NOTOC))},
+			"R_PPC64_PLT_PCREL34":        {reflect.TypeOf(q.R_PPC64_PLT_PCREL34), constant.MakeInt64(int64(q.R_PPC64_PLT_PCREL34))},
+			"R_PPC64_PLT_PCREL34_NOTOC":  {reflect.TypeOf(q.R_PPC64_PLT_PCREL34_NOTOC), constant.MakeInt64(int64(q.R_PPC64_PLT_PCREL34_NOTOC))},
+			"R_PPC64_REL16_HIGH":         {reflect.TypeOf(q.R_PPC64_REL16_HIGH), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGH))},
+			"R_PPC64_REL16_HIGHA":        {reflect.TypeOf(q.R_PPC64_REL16_HIGHA), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHA))},
+			"R_PPC64_REL16_HIGHER":       {reflect.TypeOf(q.R_PPC64_REL16_HIGHER), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHER))},
+			"R_PPC64_REL16_HIGHER34":     {reflect.TypeOf(q.R_PPC64_REL16_HIGHER34), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHER34))},
+			"R_PPC64_REL16_HIGHERA":      {reflect.TypeOf(q.R_PPC64_REL16_HIGHERA), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHERA))},
+			"R_PPC64_REL16_HIGHERA34":    {reflect.TypeOf(q.R_PPC64_REL16_HIGHERA34), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHERA34))},
+			"R_PPC64_REL16_HIGHEST":      {reflect.TypeOf(q.R_PPC64_REL16_HIGHEST), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHEST))},
+			"R_PPC64_REL16_HIGHEST34":    {reflect.TypeOf(q.R_PPC64_REL16_HIGHEST34), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHEST34))},
+			"R_PPC64_REL16_HIGHESTA":     {reflect.TypeOf(q.R_PPC64_REL16_HIGHESTA), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHESTA))},
+			"R_PPC64_REL16_HIGHESTA34":   {reflect.TypeOf(q.R_PPC64_REL16_HIGHESTA34), constant.MakeInt64(int64(q.R_PPC64_REL16_HIGHESTA34))},
+			"R_PPC64_REL30":              {reflect.TypeOf(q.R_PPC64_REL30), constant.MakeInt64(int64(q.R_PPC64_REL30))},
+			"R_PPC64_SECTOFF":            {reflect.TypeOf(q.R_PPC64_SECTOFF), constant.MakeInt64(int64(q.R_PPC64_SECTOFF))},
+			"R_PPC64_SECTOFF_HA":         {reflect.TypeOf(q.R_PPC64_SECTOFF_HA), constant.MakeInt64(int64(q.R_PPC64_SECTOFF_HA))},
+			"R_PPC64_SECTOFF_HI":         {reflect.TypeOf(q.R_PPC64_SECTOFF_HI), constant.MakeInt64(int64(q.R_PPC64_SECTOFF_HI))},
+			"R_PPC64_SECTOFF_LO":         {reflect.TypeOf(q.R_PPC64_SECTOFF_LO), constant.MakeInt64(int64(q.R_PPC64_SECTOFF_LO))},
+			"R_PPC64_SECTOFF_LO_DS":      {reflect.TypeOf(q.R_PPC64_SECTOFF_LO_DS), constant.MakeInt64(int64(q.R_PPC64_SECTOFF_LO_DS))},
+			"R_PPC64_TPREL34":            {reflect.TypeOf(q.R_PPC64_TPREL34), constant.MakeInt64(int64(q.R_PPC64_TPREL34))},
+			"R_PPC64_UADDR16":            {reflect.TypeOf(q.R_PPC64_UADDR16), constant.MakeInt64(int64(q.R_PPC64_UADDR16))},
+			"R_PPC64_UADDR32":            {reflect.TypeOf(q.R_PPC64_UADDR32), constant.MakeInt64(int64(q.R_PPC64_UADDR32))},
+			"R_PPC64_UADDR64":            {reflect.TypeOf(q.R_PPC64_UADDR64), constant.MakeInt64(int64(q.R_PPC64_UADDR64))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
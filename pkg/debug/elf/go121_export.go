@@ -0,0 +1,82 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package elf
+
+import (
+	q "debug/elf"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "elf",
+		Path: "debug/elf",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"internal/zstd":    "zstd",
+			"io":               "io",
+			"math":             "math",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"DynFlag1": {reflect.TypeOf((*q.DynFlag1)(nil)).Elem(), "GoString,String", ""},
+			"File":     {reflect.TypeOf((*q.File)(nil)).Elem(), "", "Close,DWARF,DynString,DynValue,DynamicSymbols,DynamicVersionNeeds,DynamicVersions,ImportedLibraries,ImportedSymbols,Section,SectionByType,Symbols,applyRelocations,applyRelocations386,applyRelocationsAMD64,applyRelocationsARM,applyRelocationsARM64,applyRelocationsLOONG64,applyRelocationsMIPS,applyRelocationsMIPS64,applyRelocationsPPC,applyRelocationsPPC64,applyRelocationsRISCV64,applyRelocationsSPARC64,applyRelocationss390x,dynamicVersionNeeds,dynamicVersions,getSymbols,getSymbols32,getSymbols64,gnuVersion,gnuVersionInit,stringTable"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"COMPRESS_ZSTD":         {reflect.TypeOf(q.COMPRESS_ZSTD), constant.MakeInt64(int64(q.COMPRESS_ZSTD))},
+			"DF_1_CONFALT":          {reflect.TypeOf(q.DF_1_CONFALT), constant.MakeInt64(int64(q.DF_1_CONFALT))},
+			"DF_1_DIRECT":           {reflect.TypeOf(q.DF_1_DIRECT), constant.MakeInt64(int64(q.DF_1_DIRECT))},
+			"DF_1_DISPRELDNE":       {reflect.TypeOf(q.DF_1_DISPRELDNE), constant.MakeInt64(int64(q.DF_1_DISPRELDNE))},
+			"DF_1_DISPRELPND":       {reflect.TypeOf(q.DF_1_DISPRELPND), constant.MakeInt64(int64(q.DF_1_DISPRELPND))},
+			"DF_1_EDITED":           {reflect.TypeOf(q.DF_1_EDITED), constant.MakeInt64(int64(q.DF_1_EDITED))},
+			"DF_1_ENDFILTEE":        {reflect.TypeOf(q.DF_1_ENDFILTEE), constant.MakeInt64(int64(q.DF_1_ENDFILTEE))},
+			"DF_1_GLOBAL":           {reflect.TypeOf(q.DF_1_GLOBAL), constant.MakeInt64(int64(q.DF_1_GLOBAL))},
+			"DF_1_GLOBAUDIT":        {reflect.TypeOf(q.DF_1_GLOBAUDIT), constant.MakeInt64(int64(q.DF_1_GLOBAUDIT))},
+			"DF_1_GROUP":            {reflect.TypeOf(q.DF_1_GROUP), constant.MakeInt64(int64(q.DF_1_GROUP))},
+			"DF_1_IGNMULDEF":        {reflect.TypeOf(q.DF_1_IGNMULDEF), constant.MakeInt64(int64(q.DF_1_IGNMULDEF))},
+			"DF_1_INITFIRST":        {reflect.TypeOf(q.DF_1_INITFIRST), constant.MakeInt64(int64(q.DF_1_INITFIRST))},
+			"DF_1_INTERPOSE":        {reflect.TypeOf(q.DF_1_INTERPOSE), constant.MakeInt64(int64(q.DF_1_INTERPOSE))},
+			"DF_1_KMOD":             {reflect.TypeOf(q.DF_1_KMOD), constant.MakeInt64(int64(q.DF_1_KMOD))},
+			"DF_1_LOADFLTR":         {reflect.TypeOf(q.DF_1_LOADFLTR), constant.MakeInt64(int64(q.DF_1_LOADFLTR))},
+			"DF_1_NOCOMMON":         {reflect.TypeOf(q.DF_1_NOCOMMON), constant.MakeInt64(int64(q.DF_1_NOCOMMON))},
+			"DF_1_NODEFLIB":         {reflect.TypeOf(q.DF_1_NODEFLIB), constant.MakeInt64(int64(q.DF_1_NODEFLIB))},
+			"DF_1_NODELETE":         {reflect.TypeOf(q.DF_1_NODELETE), constant.MakeInt64(int64(q.DF_1_NODELETE))},
+			"DF_1_NODIRECT":         {reflect.TypeOf(q.DF_1_NODIRECT), constant.MakeInt64(int64(q.DF_1_NODIRECT))},
+			"DF_1_NODUMP":           {reflect.TypeOf(q.DF_1_NODUMP), constant.MakeInt64(int64(q.DF_1_NODUMP))},
+			"DF_1_NOHDR":            {reflect.TypeOf(q.DF_1_NOHDR), constant.MakeInt64(int64(q.DF_1_NOHDR))},
+			"DF_1_NOKSYMS":          {reflect.TypeOf(q.DF_1_NOKSYMS), constant.MakeInt64(int64(q.DF_1_NOKSYMS))},
+			"DF_1_NOOPEN":           {reflect.TypeOf(q.DF_1_NOOPEN), constant.MakeInt64(int64(q.DF_1_NOOPEN))},
+			"DF_1_NORELOC":          {reflect.TypeOf(q.DF_1_NORELOC), constant.MakeInt64(int64(q.DF_1_NORELOC))},
+			"DF_1_NOW":              {reflect.TypeOf(q.DF_1_NOW), constant.MakeInt64(int64(q.DF_1_NOW))},
+			"DF_1_ORIGIN":           {reflect.TypeOf(q.DF_1_ORIGIN), constant.MakeInt64(int64(q.DF_1_ORIGIN))},
+			"DF_1_PIE":              {reflect.TypeOf(q.DF_1_PIE), constant.MakeInt64(int64(q.DF_1_PIE))},
+			"DF_1_SINGLETON":        {reflect.TypeOf(q.DF_1_SINGLETON), constant.MakeInt64(int64(q.DF_1_SINGLETON))},
+			"DF_1_STUB":             {reflect.TypeOf(q.DF_1_STUB), constant.MakeInt64(int64(q.DF_1_STUB))},
+			"DF_1_SYMINTPOSE":       {reflect.TypeOf(q.DF_1_SYMINTPOSE), constant.MakeInt64(int64(q.DF_1_SYMINTPOSE))},
+			"DF_1_TRANS":            {reflect.TypeOf(q.DF_1_TRANS), constant.MakeInt64(int64(q.DF_1_TRANS))},
+			"DF_1_WEAKFILTER":       {reflect.TypeOf(q.DF_1_WEAKFILTER), constant.MakeInt64(int64(q.DF_1_WEAKFILTER))},
+			"R_PPC64_REL24_P9NOTOC": {reflect.TypeOf(q.R_PPC64_REL24_P9NOTOC), constant.MakeInt64(int64(q.R_PPC64_REL24_P9NOTOC))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
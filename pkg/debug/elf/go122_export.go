@@ -0,0 +1,56 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package elf
+
+import (
+	q "debug/elf"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "elf",
+		Path: "debug/elf",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"internal/zstd":    "zstd",
+			"io":               "io",
+			"math":             "math",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"R_LARCH_64_PCREL":    {reflect.TypeOf(q.R_LARCH_64_PCREL), constant.MakeInt64(int64(q.R_LARCH_64_PCREL))},
+			"R_LARCH_ADD6":        {reflect.TypeOf(q.R_LARCH_ADD6), constant.MakeInt64(int64(q.R_LARCH_ADD6))},
+			"R_LARCH_ADD_ULEB128": {reflect.TypeOf(q.R_LARCH_ADD_ULEB128), constant.MakeInt64(int64(q.R_LARCH_ADD_ULEB128))},
+			"R_LARCH_ALIGN":       {reflect.TypeOf(q.R_LARCH_ALIGN), constant.MakeInt64(int64(q.R_LARCH_ALIGN))},
+			"R_LARCH_CFA":         {reflect.TypeOf(q.R_LARCH_CFA), constant.MakeInt64(int64(q.R_LARCH_CFA))},
+			"R_LARCH_DELETE":      {reflect.TypeOf(q.R_LARCH_DELETE), constant.MakeInt64(int64(q.R_LARCH_DELETE))},
+			"R_LARCH_PCREL20_S2":  {reflect.TypeOf(q.R_LARCH_PCREL20_S2), constant.MakeInt64(int64(q.R_LARCH_PCREL20_S2))},
+			"R_LARCH_SUB6":        {reflect.TypeOf(q.R_LARCH_SUB6), constant.MakeInt64(int64(q.R_LARCH_SUB6))},
+			"R_LARCH_SUB_ULEB128": {reflect.TypeOf(q.R_LARCH_SUB_ULEB128), constant.MakeInt64(int64(q.R_LARCH_SUB_ULEB128))},
+			"R_MIPS_PC32":         {reflect.TypeOf(q.R_MIPS_PC32), constant.MakeInt64(int64(q.R_MIPS_PC32))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,63 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package pe
+
+import (
+	q "debug/pe"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "pe",
+		Path: "debug/pe",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"io":               "io",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"COFFSymbolAuxFormat5": {reflect.TypeOf((*q.COFFSymbolAuxFormat5)(nil)).Elem(), "", ""},
+			"File":                 {reflect.TypeOf((*q.File)(nil)).Elem(), "", "COFFSymbolReadSectionDefAux,Close,DWARF,ImportedLibraries,ImportedSymbols,Section"},
+		},
+		AliasTypes:  map[string]reflect.Type{},
+		Vars:        map[string]reflect.Value{},
+		Funcs:       map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{
+			"IMAGE_COMDAT_SELECT_ANY":          {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_ANY))},
+			"IMAGE_COMDAT_SELECT_ASSOCIATIVE":  {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_ASSOCIATIVE))},
+			"IMAGE_COMDAT_SELECT_EXACT_MATCH":  {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_EXACT_MATCH))},
+			"IMAGE_COMDAT_SELECT_LARGEST":      {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_LARGEST))},
+			"IMAGE_COMDAT_SELECT_NODUPLICATES": {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_NODUPLICATES))},
+			"IMAGE_COMDAT_SELECT_SAME_SIZE":    {"untyped int", constant.MakeInt64(int64(q.IMAGE_COMDAT_SELECT_SAME_SIZE))},
+			"IMAGE_FILE_MACHINE_LOONGARCH32":   {"untyped int", constant.MakeInt64(int64(q.IMAGE_FILE_MACHINE_LOONGARCH32))},
+			"IMAGE_FILE_MACHINE_LOONGARCH64":   {"untyped int", constant.MakeInt64(int64(q.IMAGE_FILE_MACHINE_LOONGARCH64))},
+			"IMAGE_SCN_CNT_CODE":               {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_CNT_CODE))},
+			"IMAGE_SCN_CNT_INITIALIZED_DATA":   {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_CNT_INITIALIZED_DATA))},
+			"IMAGE_SCN_CNT_UNINITIALIZED_DATA": {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_CNT_UNINITIALIZED_DATA))},
+			"IMAGE_SCN_LNK_COMDAT":             {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_LNK_COMDAT))},
+			"IMAGE_SCN_MEM_DISCARDABLE":        {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_MEM_DISCARDABLE))},
+			"IMAGE_SCN_MEM_EXECUTE":            {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_MEM_EXECUTE))},
+			"IMAGE_SCN_MEM_READ":               {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_MEM_READ))},
+			"IMAGE_SCN_MEM_WRITE":              {"untyped int", constant.MakeInt64(int64(q.IMAGE_SCN_MEM_WRITE))},
+		},
+	})
+}
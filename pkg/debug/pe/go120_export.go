@@ -0,0 +1,47 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package pe
+
+import (
+	q "debug/pe"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "pe",
+		Path: "debug/pe",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"compress/zlib":    "zlib",
+			"debug/dwarf":      "dwarf",
+			"encoding/binary":  "binary",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/saferio": "saferio",
+			"io":               "io",
+			"os":               "os",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces:  map[string]reflect.Type{},
+		NamedTypes:  map[string]gossa.NamedType{},
+		AliasTypes:  map[string]reflect.Type{},
+		Vars:        map[string]reflect.Value{},
+		Funcs:       map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{
+			"IMAGE_FILE_MACHINE_RISCV128": {"untyped int", constant.MakeInt64(int64(q.IMAGE_FILE_MACHINE_RISCV128))},
+			"IMAGE_FILE_MACHINE_RISCV32":  {"untyped int", constant.MakeInt64(int64(q.IMAGE_FILE_MACHINE_RISCV32))},
+			"IMAGE_FILE_MACHINE_RISCV64":  {"untyped int", constant.MakeInt64(int64(q.IMAGE_FILE_MACHINE_RISCV64))},
+		},
+	})
+}
@@ -0,0 +1,35 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package base32
+
+import (
+	q "encoding/base32"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "base32",
+		Path: "encoding/base32",
+		Deps: map[string]string{
+			"io":      "io",
+			"slices":  "slices",
+			"strconv": "strconv",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Encoding": {reflect.TypeOf((*q.Encoding)(nil)).Elem(), "WithPadding", "AppendDecode,AppendEncode,Decode,DecodeString,DecodedLen,Encode,EncodeToString,EncodedLen,decode"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
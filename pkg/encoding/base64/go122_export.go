@@ -0,0 +1,36 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package base64
+
+import (
+	q "encoding/base64"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "base64",
+		Path: "encoding/base64",
+		Deps: map[string]string{
+			"internal/byteorder": "byteorder",
+			"io":                 "io",
+			"slices":             "slices",
+			"strconv":            "strconv",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Encoding": {reflect.TypeOf((*q.Encoding)(nil)).Elem(), "Strict,WithPadding", "AppendDecode,AppendEncode,Decode,DecodeString,DecodedLen,Encode,EncodeToString,EncodedLen,decodeQuantum"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,41 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package binary
+
+import (
+	q "encoding/binary"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "binary",
+		Path: "encoding/binary",
+		Deps: map[string]string{
+			"errors":  "errors",
+			"io":      "io",
+			"math":    "math",
+			"reflect": "reflect",
+			"slices":  "slices",
+			"sync":    "sync",
+		},
+		Interfaces: map[string]reflect.Type{
+			"AppendByteOrder": reflect.TypeOf((*q.AppendByteOrder)(nil)).Elem(),
+		},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"AppendUvarint": reflect.ValueOf(q.AppendUvarint),
+			"AppendVarint":  reflect.ValueOf(q.AppendVarint),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,38 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package binary
+
+import (
+	q "encoding/binary"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "binary",
+		Path: "encoding/binary",
+		Deps: map[string]string{
+			"errors":  "errors",
+			"io":      "io",
+			"math":    "math",
+			"reflect": "reflect",
+			"slices":  "slices",
+			"sync":    "sync",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"NativeEndian": reflect.ValueOf(&q.NativeEndian),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package csv
+
+import (
+	q "encoding/csv"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "csv",
+		Path: "encoding/csv",
+		Deps: map[string]string{
+			"bufio":        "bufio",
+			"bytes":        "bytes",
+			"errors":       "errors",
+			"fmt":          "fmt",
+			"io":           "io",
+			"strings":      "strings",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Reader": {reflect.TypeOf((*q.Reader)(nil)).Elem(), "", "FieldPos,InputOffset,Read,ReadAll,readLine,readRecord"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
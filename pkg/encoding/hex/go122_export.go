@@ -0,0 +1,38 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package hex
+
+import (
+	q "encoding/hex"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "hex",
+		Path: "encoding/hex",
+		Deps: map[string]string{
+			"errors":  "errors",
+			"fmt":     "fmt",
+			"io":      "io",
+			"slices":  "slices",
+			"strings": "strings",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"AppendDecode": reflect.ValueOf(q.AppendDecode),
+			"AppendEncode": reflect.ValueOf(q.AppendEncode),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,45 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package xml
+
+import (
+	q "encoding/xml"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "xml",
+		Path: "encoding/xml",
+		Deps: map[string]string{
+			"bufio":        "bufio",
+			"bytes":        "bytes",
+			"encoding":     "encoding",
+			"errors":       "errors",
+			"fmt":          "fmt",
+			"io":           "io",
+			"reflect":      "reflect",
+			"runtime":      "runtime",
+			"strconv":      "strconv",
+			"strings":      "strings",
+			"sync":         "sync",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Encoder": {reflect.TypeOf((*q.Encoder)(nil)).Elem(), "", "Close,Encode,EncodeElement,EncodeToken,Flush,Indent"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
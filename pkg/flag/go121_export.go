@@ -0,0 +1,45 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package flag
+
+import (
+	q "flag"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "flag",
+		Path: "flag",
+		Deps: map[string]string{
+			"encoding": "encoding",
+			"errors":   "errors",
+			"fmt":      "fmt",
+			"io":       "io",
+			"os":       "os",
+			"reflect":  "reflect",
+			"runtime":  "runtime",
+			"slices":   "slices",
+			"strconv":  "strconv",
+			"strings":  "strings",
+			"time":     "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"FlagSet": {reflect.TypeOf((*q.FlagSet)(nil)).Elem(), "", "Arg,Args,Bool,BoolFunc,BoolVar,Duration,DurationVar,ErrorHandling,Float64,Float64Var,Func,Init,Int,Int64,Int64Var,IntVar,Lookup,NArg,NFlag,Name,Output,Parse,Parsed,PrintDefaults,Set,SetOutput,String,StringVar,TextVar,Uint,Uint64,Uint64Var,UintVar,Var,Visit,VisitAll,defaultUsage,failf,parseOne,set,sprintf,usage"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"BoolFunc": reflect.ValueOf(q.BoolFunc),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,45 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package fmt
+
+import (
+	q "fmt"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "fmt",
+		Path: "fmt",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/fmtsort":     "fmtsort",
+			"internal/stringslite": "stringslite",
+			"io":                   "io",
+			"math":                 "math",
+			"os":                   "os",
+			"reflect":              "reflect",
+			"slices":               "slices",
+			"strconv":              "strconv",
+			"sync":                 "sync",
+			"unicode/utf8":         "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Append":   reflect.ValueOf(q.Append),
+			"Appendf":  reflect.ValueOf(q.Appendf),
+			"Appendln": reflect.ValueOf(q.Appendln),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -14,8 +14,17 @@ import (
 	"strings"
 )
 
+// sinceVersions are the Go releases after go1.18 whose added symbols are
+// exported by version tagged files, generated by any later toolchain from
+// $GOROOT/api. The go1.18 files are the base of these releases.
+var sinceVersions = []string{"go1.19", "go1.20", "go1.21", "go1.22"}
+
 func main() {
 	ver := runtime.Version()[:6]
+	if len(os.Args) > 1 && os.Args[1] == "-since" {
+		exportSince()
+		return
+	}
 	var tags string
 	var name string
 	var fname string
@@ -81,6 +90,73 @@ func main() {
 	}
 }
 
+// exportSince exports the symbols added in sinceVersions, and imports the
+// packages added in each release by its go1NN_pkgs.go file.
+func exportSince() {
+	pkgs := stdList()
+	for _, ver := range sinceVersions {
+		name := "go1" + ver[4:] + "_export"
+		tags := "//+build " + ver
+		log.Println(ver, name, tags)
+		cmd := exec.Command("go", "run", "../cmd/qexp", "-outdir", ".", "-since", ver, "-addtags", tags, "-filename", name)
+		cmd.Args = append(cmd.Args, pkgs...)
+		cmd.Stderr = os.Stderr
+		cmd.Stdout = os.Stdout
+		if err := cmd.Run(); err != nil {
+			panic(err)
+		}
+		var added []string
+		for _, pkg := range pkgs {
+			if strings.HasPrefix(pkg, "testing") {
+				continue
+			}
+			if exists(pkg+"/"+name+".go") && !exists(pkg+"/go118_export.go") && !addedBefore(pkg, ver) {
+				added = append(added, pkg)
+			}
+		}
+		if len(added) == 0 {
+			continue
+		}
+		err := makepkgs("go1"+ver[4:]+"_pkgs.go", []string{tags}, added)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// addedBefore reports whether pkg has the export file of a since version
+// before ver.
+func addedBefore(pkg string, ver string) bool {
+	for _, v := range sinceVersions {
+		if v == ver {
+			return false
+		}
+		if exists(pkg + "/go1" + v[4:] + "_export.go") {
+			return true
+		}
+	}
+	return false
+}
+
+func exists(fname string) bool {
+	_, err := os.Stat(fname)
+	return err == nil
+}
+
+// makepkgs writes the file fname importing the packages pkgs.
+func makepkgs(fname string, tags []string, pkgs []string) error {
+	var imports []string
+	for _, v := range pkgs {
+		imports = append(imports, fmt.Sprintf(`_ "github.com/goplus/gossa/pkg/%v"`, v))
+	}
+	r := strings.NewReplacer("$TAGS", strings.Join(tags, "\n"), "$PKGS", strings.Join(imports, "\t\n"))
+	data, err := format.Source([]byte(r.Replace(tmpl)))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, data, 0644)
+}
+
 func makepkg(fname string, tags []string, std []string) error {
 	//_ github.com/goplus/gossa/pkg
 	var pkgs []string
@@ -0,0 +1,10 @@
+//go:build go1.21
+// +build go1.21
+
+package pkg
+
+// the packages of generic functions, registered with their instantiations
+import (
+	_ "github.com/goplus/gossa/pkg/cmp"
+	_ "github.com/goplus/gossa/pkg/slices"
+)
@@ -0,0 +1,52 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package ast
+
+import (
+	q "go/ast"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "ast",
+		Path: "go/ast",
+		Deps: map[string]string{
+			"bytes":        "bytes",
+			"cmp":          "cmp",
+			"fmt":          "fmt",
+			"go/scanner":   "scanner",
+			"go/token":     "token",
+			"io":           "io",
+			"iter":         "iter",
+			"os":           "os",
+			"reflect":      "reflect",
+			"slices":       "slices",
+			"strconv":      "strconv",
+			"strings":      "strings",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Importer": {reflect.TypeOf((*q.Importer)(nil)).Elem(), "", ""},
+			"Object":   {reflect.TypeOf((*q.Object)(nil)).Elem(), "", "Pos"},
+			"Package":  {reflect.TypeOf((*q.Package)(nil)).Elem(), "", "End,Pos"},
+			"Scope":    {reflect.TypeOf((*q.Scope)(nil)).Elem(), "", "Insert,Lookup,String"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"IsGenerated": reflect.ValueOf(q.IsGenerated),
+			"NewPackage":  reflect.ValueOf(q.NewPackage),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,61 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package build
+
+import (
+	q "go/build"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "build",
+		Path: "go/build",
+		Deps: map[string]string{
+			"bufio":               "bufio",
+			"bytes":               "bytes",
+			"errors":              "errors",
+			"fmt":                 "fmt",
+			"go/ast":              "ast",
+			"go/build/constraint": "constraint",
+			"go/doc":              "doc",
+			"go/parser":           "parser",
+			"go/scanner":          "scanner",
+			"go/token":            "token",
+			"internal/buildcfg":   "buildcfg",
+			"internal/godebug":    "godebug",
+			"internal/goroot":     "goroot",
+			"internal/goversion":  "goversion",
+			"internal/platform":   "platform",
+			"internal/syslist":    "syslist",
+			"io":                  "io",
+			"io/fs":               "fs",
+			"os":                  "os",
+			"os/exec":             "exec",
+			"path":                "path",
+			"path/filepath":       "filepath",
+			"runtime":             "runtime",
+			"slices":              "slices",
+			"strconv":             "strconv",
+			"strings":             "strings",
+			"unicode":             "unicode",
+			"unicode/utf8":        "utf8",
+			"unsafe":              "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Directive": {reflect.TypeOf((*q.Directive)(nil)).Elem(), "", ""},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
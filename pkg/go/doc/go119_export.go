@@ -0,0 +1,49 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package doc
+
+import (
+	q "go/doc"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "doc",
+		Path: "go/doc",
+		Deps: map[string]string{
+			"cmp":                 "cmp",
+			"fmt":                 "fmt",
+			"go/ast":              "ast",
+			"go/doc/comment":      "comment",
+			"go/token":            "token",
+			"internal/lazyregexp": "lazyregexp",
+			"io":                  "io",
+			"path":                "path",
+			"slices":              "slices",
+			"strconv":             "strconv",
+			"strings":             "strings",
+			"unicode":             "unicode",
+			"unicode/utf8":        "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Package": {reflect.TypeOf((*q.Package)(nil)).Elem(), "", "Filter,HTML,Markdown,Parser,Printer,Synopsis,Text,collectFuncs,collectInterfaceMethods,collectStructFields,collectTypes,collectValues,lookupPackage,lookupSym"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Synopsis": reflect.ValueOf(q.Synopsis),
+			"ToHTML":   reflect.ValueOf(q.ToHTML),
+			"ToText":   reflect.ValueOf(q.ToText),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,41 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package token
+
+import (
+	q "go/token"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "token",
+		Path: "go/token",
+		Deps: map[string]string{
+			"cmp":          "cmp",
+			"fmt":          "fmt",
+			"iter":         "iter",
+			"slices":       "slices",
+			"strconv":      "strconv",
+			"sync":         "sync",
+			"sync/atomic":  "atomic",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"FileSet": {reflect.TypeOf((*q.FileSet)(nil)).Elem(), "", "AddExistingFiles,AddFile,Base,File,Iterate,Position,PositionFor,Read,RemoveFile,Write,file"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
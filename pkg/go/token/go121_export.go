@@ -0,0 +1,41 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package token
+
+import (
+	q "go/token"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "token",
+		Path: "go/token",
+		Deps: map[string]string{
+			"cmp":          "cmp",
+			"fmt":          "fmt",
+			"iter":         "iter",
+			"slices":       "slices",
+			"strconv":      "strconv",
+			"sync":         "sync",
+			"sync/atomic":  "atomic",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"File": {reflect.TypeOf((*q.File)(nil)).Elem(), "", "AddLine,AddLineColumnInfo,AddLineInfo,Base,End,Line,LineCount,LineStart,Lines,MergeLine,Name,Offset,Pos,Position,PositionFor,SetLines,SetLinesForContent,Size,String,fixOffset,key,position,unpack"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
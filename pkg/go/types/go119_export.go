@@ -0,0 +1,61 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package types
+
+import (
+	q "go/types"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "types",
+		Path: "go/types",
+		Deps: map[string]string{
+			"bytes":                 "bytes",
+			"cmp":                   "cmp",
+			"container/heap":        "heap",
+			"errors":                "errors",
+			"fmt":                   "fmt",
+			"go/ast":                "ast",
+			"go/constant":           "constant",
+			"go/parser":             "parser",
+			"go/token":              "token",
+			"go/version":            "version",
+			"hash/maphash":          "maphash",
+			"internal/goversion":    "goversion",
+			"internal/types/errors": "errors",
+			"io":                    "io",
+			"iter":                  "iter",
+			"math":                  "math",
+			"os":                    "os",
+			"path/filepath":         "filepath",
+			"runtime":               "runtime",
+			"slices":                "slices",
+			"sort":                  "sort",
+			"strconv":               "strconv",
+			"strings":               "strings",
+			"sync":                  "sync",
+			"sync/atomic":           "atomic",
+			"unicode":               "unicode",
+			"unicode/utf8":          "utf8",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Func": {reflect.TypeOf((*q.Func)(nil)).Elem(), "", "FullName,Origin,Pkg,Scope,Signature,String,hasPtrRecv,isDependency"},
+			"Var":  {reflect.TypeOf((*q.Var)(nil)).Elem(), "", "Anonymous,Embedded,IsField,Kind,Origin,SetKind,String,isDependency"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
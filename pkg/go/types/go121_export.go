@@ -0,0 +1,60 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package types
+
+import (
+	q "go/types"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "types",
+		Path: "go/types",
+		Deps: map[string]string{
+			"bytes":                 "bytes",
+			"cmp":                   "cmp",
+			"container/heap":        "heap",
+			"errors":                "errors",
+			"fmt":                   "fmt",
+			"go/ast":                "ast",
+			"go/constant":           "constant",
+			"go/parser":             "parser",
+			"go/token":              "token",
+			"go/version":            "version",
+			"hash/maphash":          "maphash",
+			"internal/goversion":    "goversion",
+			"internal/types/errors": "errors",
+			"io":                    "io",
+			"iter":                  "iter",
+			"math":                  "math",
+			"os":                    "os",
+			"path/filepath":         "filepath",
+			"runtime":               "runtime",
+			"slices":                "slices",
+			"sort":                  "sort",
+			"strconv":               "strconv",
+			"strings":               "strings",
+			"sync":                  "sync",
+			"sync/atomic":           "atomic",
+			"unicode":               "unicode",
+			"unicode/utf8":          "utf8",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Package": {reflect.TypeOf((*q.Package)(nil)).Elem(), "", "Complete,GoVersion,Imports,MarkComplete,Name,Path,Scope,SetImports,SetName,String"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
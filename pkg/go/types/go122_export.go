@@ -0,0 +1,65 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package types
+
+import (
+	q "go/types"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "types",
+		Path: "go/types",
+		Deps: map[string]string{
+			"bytes":                 "bytes",
+			"cmp":                   "cmp",
+			"container/heap":        "heap",
+			"errors":                "errors",
+			"fmt":                   "fmt",
+			"go/ast":                "ast",
+			"go/constant":           "constant",
+			"go/parser":             "parser",
+			"go/token":              "token",
+			"go/version":            "version",
+			"hash/maphash":          "maphash",
+			"internal/goversion":    "goversion",
+			"internal/types/errors": "errors",
+			"io":                    "io",
+			"iter":                  "iter",
+			"math":                  "math",
+			"os":                    "os",
+			"path/filepath":         "filepath",
+			"runtime":               "runtime",
+			"slices":                "slices",
+			"sort":                  "sort",
+			"strconv":               "strconv",
+			"strings":               "strings",
+			"sync":                  "sync",
+			"sync/atomic":           "atomic",
+			"unicode":               "unicode",
+			"unicode/utf8":          "utf8",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Alias":   {reflect.TypeOf((*q.Alias)(nil)).Elem(), "", "Obj,Origin,Rhs,SetTypeParams,String,TypeArgs,TypeParams,Underlying,cleanup"},
+			"Checker": {reflect.TypeOf((*q.Checker)(nil)).Elem(), "", "Files,addDeclDep,allowVersion,applyTypeFunc,arguments,arityMatch,arrayLength,assertableTo,assignError,assignVar,assignVars,assignment,basicLit,binary,blockBranches,bound,builtin,callExpr,caseTypes,caseTypes_currently_unused,caseValues,chanElem,checkFieldUniqueness,checkFiles,cleanup,closeScope,collectMethods,collectObjects,collectParams,collectRecv,collectTypeParams,comparison,compositeLit,constDecl,context,conversion,convertUntyped,cycleError,declStmt,declare,declareInSet,declareParams,declarePkgObj,declareTypeParam,declaredType,directCycle,directCycles,dump,error,errorUnusedPkg,errorf,exclude,expr,exprInternal,exprList,exprOrType,filename,funcBody,funcDecl,funcInst,funcLit,funcString,funcType,genericExpr,genericExprList,genericType,handleBailout,handleError,hasAllMethods,hasVarSize,ident,implements,implicitTypeAndValue,importPackage,incomparableCause,index,indexExpr,indexedElts,infer,initConst,initFiles,initOrder,initVar,initVars,instance,instantiateSignature,instantiatedType,interfacePtrError,interfaceType,invalidConversion,isComplete,isImportedConstraint,isNil,isTerminating,isTerminatingList,isTerminatingSwitch,isValidIndex,labels,langCompat,later,lhsVar,lookupError,markImports,matchTypes,missingMethod,monomorph,multiExpr,multipleDefaults,needsCleanup,newAlias,newAliasInstance,newAssertableTo,newError,newInterface,newNamed,newNamedInstance,newTypeParam,nonGeneric,objDecl,op,openScope,overflow,packageObjects,pop,popPos,processDelayed,push,pushPos,qualifier,rangeStmt,rawExpr,record,recordBuiltinType,recordCommaOkTypes,recordCommaOkTypesInSyntax,recordDef,recordImplicit,recordInstance,recordParenthesizedRecvTypes,recordScope,recordSelection,recordTypeAndValue,recordTypeAndValueInSyntax,recordUntyped,recordUse,rememberUntyped,renameTParams,reportCycle,reportInstanceLoop,representable,representation,resolveBaseTypeName,returnError,selector,shift,shortVarDecl,simpleStmt,singleIndex,singleValue,sliceExpr,softErrorf,sortObjects,sprintf,stmt,stmtList,structType,subst,suspendedCall,tag,trace,typ,typInternal,typeAssertion,typeDecl,typeList,typesSummary,unary,unpackRecv,unusedImports,updateExprType,updateExprVal,usage,use,use1,useLHS,useN,validCycle,validRecv,validType,validType0,validVarType,validateTArgLen,varDecl,varType,verify,verifyVersionf,versionErrorf,walkDecl,walkDecls"},
+			"Info":    {reflect.TypeOf((*q.Info)(nil)).Elem(), "", "ObjectOf,PkgNameOf,TypeOf,recordTypes"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"NewAlias": reflect.ValueOf(q.NewAlias),
+			"Unalias":  reflect.ValueOf(q.Unalias),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,8 @@
+//go:build go1.19
+// +build go1.19
+
+package pkg
+
+import (
+	_ "github.com/goplus/gossa/pkg/go/doc/comment"
+)
@@ -0,0 +1,9 @@
+//go:build go1.20
+// +build go1.20
+
+package pkg
+
+import (
+	_ "github.com/goplus/gossa/pkg/crypto/ecdh"
+	_ "github.com/goplus/gossa/pkg/runtime/coverage"
+)
@@ -0,0 +1,8 @@
+//go:build go1.21
+// +build go1.21
+
+package pkg
+
+import (
+	_ "github.com/goplus/gossa/pkg/log/slog"
+)
@@ -0,0 +1,9 @@
+//go:build go1.22
+// +build go1.22
+
+package pkg
+
+import (
+	_ "github.com/goplus/gossa/pkg/go/version"
+	_ "github.com/goplus/gossa/pkg/math/rand/v2"
+)
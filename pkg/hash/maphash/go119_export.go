@@ -0,0 +1,37 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package maphash
+
+import (
+	q "hash/maphash"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "maphash",
+		Path: "hash/maphash",
+		Deps: map[string]string{
+			"hash":                  "hash",
+			"internal/abi":          "abi",
+			"internal/runtime/maps": "maps",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Bytes":  reflect.ValueOf(q.Bytes),
+			"String": reflect.ValueOf(q.String),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
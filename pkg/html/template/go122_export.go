@@ -0,0 +1,54 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package template
+
+import (
+	q "html/template"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "template",
+		Path: "html/template",
+		Deps: map[string]string{
+			"bytes":               "bytes",
+			"encoding/json":       "json",
+			"fmt":                 "fmt",
+			"html":                "html",
+			"internal/godebug":    "godebug",
+			"io":                  "io",
+			"io/fs":               "fs",
+			"maps":                "maps",
+			"os":                  "os",
+			"path":                "path",
+			"path/filepath":       "filepath",
+			"reflect":             "reflect",
+			"regexp":              "regexp",
+			"slices":              "slices",
+			"strconv":             "strconv",
+			"strings":             "strings",
+			"sync":                "sync",
+			"text/template":       "template",
+			"text/template/parse": "parse",
+			"unicode":             "unicode",
+			"unicode/utf8":        "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"ErrJSTemplate": {reflect.TypeOf(q.ErrJSTemplate), constant.MakeInt64(int64(q.ErrJSTemplate))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package fs
+
+import (
+	q "io/fs"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "fs",
+		Path: "io/fs",
+		Deps: map[string]string{
+			"errors":           "errors",
+			"internal/bytealg": "bytealg",
+			"internal/oserror": "oserror",
+			"io":               "io",
+			"path":             "path",
+			"slices":           "slices",
+			"time":             "time",
+			"unicode/utf8":     "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"SkipAll": reflect.ValueOf(&q.SkipAll),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,36 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package io
+
+import (
+	q "io"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "io",
+		Path: "io",
+		Deps: map[string]string{
+			"errors": "errors",
+			"sync":   "sync",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"OffsetWriter": {reflect.TypeOf((*q.OffsetWriter)(nil)).Elem(), "", "Seek,Write,WriteAt"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"NewOffsetWriter": reflect.ValueOf(q.NewOffsetWriter),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
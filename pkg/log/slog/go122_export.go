@@ -0,0 +1,56 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package slog
+
+import (
+	q "log/slog"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "slog",
+		Path: "log/slog",
+		Deps: map[string]string{
+			"bytes":                    "bytes",
+			"context":                  "context",
+			"encoding":                 "encoding",
+			"encoding/json":            "json",
+			"errors":                   "errors",
+			"fmt":                      "fmt",
+			"internal/goexperiment":    "goexperiment",
+			"io":                       "io",
+			"log":                      "log",
+			"log/internal":             "internal",
+			"log/slog/internal":        "internal",
+			"log/slog/internal/buffer": "buffer",
+			"math":                     "math",
+			"reflect":                  "reflect",
+			"runtime":                  "runtime",
+			"slices":                   "slices",
+			"strconv":                  "strconv",
+			"strings":                  "strings",
+			"sync":                     "sync",
+			"sync/atomic":              "atomic",
+			"time":                     "time",
+			"unicode":                  "unicode",
+			"unicode/utf8":             "utf8",
+			"unsafe":                   "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"SetLogLoggerLevel": reflect.ValueOf(q.SetLogLoggerLevel),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,46 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package big
+
+import (
+	q "math/big"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "big",
+		Path: "math/big",
+		Deps: map[string]string{
+			"bytes":              "bytes",
+			"errors":             "errors",
+			"fmt":                "fmt",
+			"internal/byteorder": "byteorder",
+			"internal/cpu":       "cpu",
+			"io":                 "io",
+			"math":               "math",
+			"math/bits":          "bits",
+			"math/rand":          "rand",
+			"slices":             "slices",
+			"strconv":            "strconv",
+			"strings":            "strings",
+			"sync":               "sync",
+			"unsafe":             "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Int": {reflect.TypeOf((*q.Int)(nil)).Elem(), "", "Abs,Add,And,AndNot,Append,AppendText,Binomial,Bit,BitLen,Bits,Bytes,Cmp,CmpAbs,Div,DivMod,Divide,Exp,FillBytes,Float64,Format,GCD,GobDecode,GobEncode,Int64,IsInt64,IsUint64,Lsh,MarshalJSON,MarshalText,Mod,ModInverse,ModSqrt,Mul,MulRange,Neg,Not,Or,ProbablyPrime,Quo,QuoRem,Rand,Rem,Rsh,Scan,Set,SetBit,SetBits,SetBytes,SetInt64,SetString,SetUint64,Sign,Sqrt,String,Sub,Text,TrailingZeroBits,Uint64,UnmarshalJSON,UnmarshalText,Xor,exp,expSlow,lehmerGCD,modSqrt3Mod4Prime,modSqrt5Mod8Prime,modSqrtTonelliShanks,mul,scaleDenom,scan,setFromScanner"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,46 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package big
+
+import (
+	q "math/big"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "big",
+		Path: "math/big",
+		Deps: map[string]string{
+			"bytes":              "bytes",
+			"errors":             "errors",
+			"fmt":                "fmt",
+			"internal/byteorder": "byteorder",
+			"internal/cpu":       "cpu",
+			"io":                 "io",
+			"math":               "math",
+			"math/bits":          "bits",
+			"math/rand":          "rand",
+			"slices":             "slices",
+			"strconv":            "strconv",
+			"strings":            "strings",
+			"sync":               "sync",
+			"unsafe":             "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Rat": {reflect.TypeOf((*q.Rat)(nil)).Elem(), "", "Abs,Add,AppendText,Cmp,Denom,Float32,Float64,FloatPrec,FloatString,GobDecode,GobEncode,Inv,IsInt,MarshalText,Mul,Neg,Num,Quo,RatString,Scan,Set,SetFloat64,SetFrac,SetFrac64,SetInt,SetInt64,SetString,SetUint64,Sign,String,Sub,UnmarshalText,marshal,norm"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
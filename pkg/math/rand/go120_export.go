@@ -0,0 +1,38 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package rand
+
+import (
+	q "math/rand"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "rand",
+		Path: "math/rand",
+		Deps: map[string]string{
+			"internal/godebug": "godebug",
+			"math":             "math",
+			"sync":             "sync",
+			"sync/atomic":      "atomic",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Read": reflect.ValueOf(q.Read),
+			"Seed": reflect.ValueOf(q.Seed),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,65 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package rand
+
+import (
+	q "math/rand/v2"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "rand",
+		Path: "math/rand/v2",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/byteorder":   "byteorder",
+			"internal/chacha8rand": "chacha8rand",
+			"math":                 "math",
+			"math/bits":            "bits",
+			"unsafe":               "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{
+			"Source": reflect.TypeOf((*q.Source)(nil)).Elem(),
+		},
+		NamedTypes: map[string]gossa.NamedType{
+			"ChaCha8": {reflect.TypeOf((*q.ChaCha8)(nil)).Elem(), "", "AppendBinary,MarshalBinary,Read,Seed,Uint64,UnmarshalBinary"},
+			"PCG":     {reflect.TypeOf((*q.PCG)(nil)).Elem(), "", "AppendBinary,MarshalBinary,Seed,Uint64,UnmarshalBinary,next"},
+			"Rand":    {reflect.TypeOf((*q.Rand)(nil)).Elem(), "", "ExpFloat64,Float32,Float64,Int,Int32,Int32N,Int64,Int64N,IntN,N,NormFloat64,Perm,Shuffle,Uint,Uint32,Uint32N,Uint64,Uint64N,UintN,uint32n,uint64n"},
+			"Zipf":    {reflect.TypeOf((*q.Zipf)(nil)).Elem(), "", "Uint64,h,hinv"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"ExpFloat64":  reflect.ValueOf(q.ExpFloat64),
+			"Float32":     reflect.ValueOf(q.Float32),
+			"Float64":     reflect.ValueOf(q.Float64),
+			"Int":         reflect.ValueOf(q.Int),
+			"Int32":       reflect.ValueOf(q.Int32),
+			"Int32N":      reflect.ValueOf(q.Int32N),
+			"Int64":       reflect.ValueOf(q.Int64),
+			"Int64N":      reflect.ValueOf(q.Int64N),
+			"IntN":        reflect.ValueOf(q.IntN),
+			"New":         reflect.ValueOf(q.New),
+			"NewChaCha8":  reflect.ValueOf(q.NewChaCha8),
+			"NewPCG":      reflect.ValueOf(q.NewPCG),
+			"NewZipf":     reflect.ValueOf(q.NewZipf),
+			"NormFloat64": reflect.ValueOf(q.NormFloat64),
+			"Perm":        reflect.ValueOf(q.Perm),
+			"Shuffle":     reflect.ValueOf(q.Shuffle),
+			"Uint32":      reflect.ValueOf(q.Uint32),
+			"Uint32N":     reflect.ValueOf(q.Uint32N),
+			"Uint64":      reflect.ValueOf(q.Uint64),
+			"Uint64N":     reflect.ValueOf(q.Uint64N),
+			"UintN":       reflect.ValueOf(q.UintN),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
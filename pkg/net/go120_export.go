@@ -0,0 +1,57 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package net
+
+import (
+	q "net"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "net",
+		Path: "net",
+		Deps: map[string]string{
+			"cmp":                                    "cmp",
+			"context":                                "context",
+			"errors":                                 "errors",
+			"internal/bytealg":                       "bytealg",
+			"internal/godebug":                       "godebug",
+			"internal/nettrace":                      "nettrace",
+			"internal/poll":                          "poll",
+			"internal/singleflight":                  "singleflight",
+			"internal/strconv":                       "strconv",
+			"internal/stringslite":                   "stringslite",
+			"internal/syscall/unix":                  "unix",
+			"io":                                     "io",
+			"io/fs":                                  "fs",
+			"net/netip":                              "netip",
+			"os":                                     "os",
+			"runtime":                                "runtime",
+			"runtime/cgo":                            "cgo",
+			"slices":                                 "slices",
+			"sync":                                   "sync",
+			"sync/atomic":                            "atomic",
+			"syscall":                                "syscall",
+			"time":                                   "time",
+			"unsafe":                                 "unsafe",
+			"vendor/golang.org/x/net/dns/dnsmessage": "dnsmessage",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"FlagRunning": {reflect.TypeOf(q.FlagRunning), constant.MakeInt64(int64(q.FlagRunning))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,56 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package net
+
+import (
+	q "net"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "net",
+		Path: "net",
+		Deps: map[string]string{
+			"cmp":                                    "cmp",
+			"context":                                "context",
+			"errors":                                 "errors",
+			"internal/bytealg":                       "bytealg",
+			"internal/godebug":                       "godebug",
+			"internal/nettrace":                      "nettrace",
+			"internal/poll":                          "poll",
+			"internal/singleflight":                  "singleflight",
+			"internal/strconv":                       "strconv",
+			"internal/stringslite":                   "stringslite",
+			"internal/syscall/unix":                  "unix",
+			"io":                                     "io",
+			"io/fs":                                  "fs",
+			"net/netip":                              "netip",
+			"os":                                     "os",
+			"runtime":                                "runtime",
+			"runtime/cgo":                            "cgo",
+			"slices":                                 "slices",
+			"sync":                                   "sync",
+			"sync/atomic":                            "atomic",
+			"syscall":                                "syscall",
+			"time":                                   "time",
+			"unsafe":                                 "unsafe",
+			"vendor/golang.org/x/net/dns/dnsmessage": "dnsmessage",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"TCPConn": {reflect.TypeOf((*q.TCPConn)(nil)).Elem(), "", "CloseRead,CloseWrite,MultipathTCP,ReadFrom,SetKeepAlive,SetKeepAliveConfig,SetKeepAlivePeriod,SetLinger,SetNoDelay,SyscallConn,WriteTo,readFrom,writeTo"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
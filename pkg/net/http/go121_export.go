@@ -0,0 +1,79 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package http
+
+import (
+	q "net/http"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "http",
+		Path: "net/http",
+		Deps: map[string]string{
+			"bufio":                                  "bufio",
+			"bytes":                                  "bytes",
+			"compress/flate":                         "flate",
+			"compress/gzip":                          "gzip",
+			"container/list":                         "list",
+			"context":                                "context",
+			"crypto/tls":                             "tls",
+			"encoding/base64":                        "base64",
+			"errors":                                 "errors",
+			"fmt":                                    "fmt",
+			"internal/godebug":                       "godebug",
+			"io":                                     "io",
+			"io/fs":                                  "fs",
+			"log":                                    "log",
+			"maps":                                   "maps",
+			"math":                                   "math",
+			"math/rand/v2":                           "rand",
+			"mime":                                   "mime",
+			"mime/multipart":                         "multipart",
+			"net":                                    "net",
+			"net/http/httptrace":                     "httptrace",
+			"net/http/internal":                      "internal",
+			"net/http/internal/ascii":                "ascii",
+			"net/http/internal/http2":                "http2",
+			"net/textproto":                          "textproto",
+			"net/url":                                "url",
+			"os":                                     "os",
+			"path":                                   "path",
+			"path/filepath":                          "filepath",
+			"reflect":                                "reflect",
+			"runtime":                                "runtime",
+			"slices":                                 "slices",
+			"sort":                                   "sort",
+			"strconv":                                "strconv",
+			"strings":                                "strings",
+			"sync":                                   "sync",
+			"sync/atomic":                            "atomic",
+			"time":                                   "time",
+			"unicode":                                "unicode",
+			"unicode/utf8":                           "utf8",
+			"unsafe":                                 "unsafe",
+			"vendor/golang.org/x/net/http/httpguts":  "httpguts",
+			"vendor/golang.org/x/net/http/httpproxy": "httpproxy",
+			"vendor/golang.org/x/net/idna":           "idna",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"ProtocolError":      {reflect.TypeOf((*q.ProtocolError)(nil)).Elem(), "", "Error,Is"},
+			"ResponseController": {reflect.TypeOf((*q.ResponseController)(nil)).Elem(), "", "EnableFullDuplex,Flush,Hijack,SetReadDeadline,SetWriteDeadline"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"ErrSchemeMismatch": reflect.ValueOf(&q.ErrSchemeMismatch),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,52 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package httputil
+
+import (
+	q "net/http/httputil"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "httputil",
+		Path: "net/http/httputil",
+		Deps: map[string]string{
+			"bufio":                                 "bufio",
+			"bytes":                                 "bytes",
+			"context":                               "context",
+			"errors":                                "errors",
+			"fmt":                                   "fmt",
+			"internal/godebug":                      "godebug",
+			"io":                                    "io",
+			"log":                                   "log",
+			"mime":                                  "mime",
+			"net":                                   "net",
+			"net/http":                              "http",
+			"net/http/httptrace":                    "httptrace",
+			"net/http/internal":                     "internal",
+			"net/http/internal/ascii":               "ascii",
+			"net/textproto":                         "textproto",
+			"net/url":                               "url",
+			"strings":                               "strings",
+			"sync":                                  "sync",
+			"time":                                  "time",
+			"vendor/golang.org/x/net/http/httpguts": "httpguts",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"ProxyRequest": {reflect.TypeOf((*q.ProxyRequest)(nil)).Elem(), "", "SetURL,SetXForwarded"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
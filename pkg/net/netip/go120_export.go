@@ -0,0 +1,41 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package netip
+
+import (
+	q "net/netip"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "netip",
+		Path: "net/netip",
+		Deps: map[string]string{
+			"cmp":                "cmp",
+			"errors":             "errors",
+			"internal/bytealg":   "bytealg",
+			"internal/byteorder": "byteorder",
+			"math":               "math",
+			"math/bits":          "bits",
+			"strconv":            "strconv",
+			"unique":             "unique",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"IPv6LinkLocalAllRouters": reflect.ValueOf(q.IPv6LinkLocalAllRouters),
+			"IPv6Loopback":            reflect.ValueOf(q.IPv6Loopback),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
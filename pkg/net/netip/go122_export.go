@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package netip
+
+import (
+	q "net/netip"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "netip",
+		Path: "net/netip",
+		Deps: map[string]string{
+			"cmp":                "cmp",
+			"errors":             "errors",
+			"internal/bytealg":   "bytealg",
+			"internal/byteorder": "byteorder",
+			"math":               "math",
+			"math/bits":          "bits",
+			"strconv":            "strconv",
+			"unique":             "unique",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"AddrPort": {reflect.TypeOf((*q.AddrPort)(nil)).Elem(), "Addr,AppendBinary,AppendText,AppendTo,Compare,IsValid,MarshalBinary,MarshalText,Port,String", "UnmarshalBinary,UnmarshalText"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,44 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package url
+
+import (
+	q "net/url"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "url",
+		Path: "net/url",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"errors":           "errors",
+			"fmt":              "fmt",
+			"internal/godebug": "godebug",
+			"net/netip":        "netip",
+			"path":             "path",
+			"slices":           "slices",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"URL": {reflect.TypeOf((*q.URL)(nil)).Elem(), "", "AppendBinary,Clone,EscapedFragment,EscapedPath,Hostname,IsAbs,JoinPath,MarshalBinary,Parse,Port,Query,Redacted,RequestURI,ResolveReference,String,UnmarshalBinary,joinPath,setFragment,setPath"},
+		},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"JoinPath": reflect.ValueOf(q.JoinPath),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
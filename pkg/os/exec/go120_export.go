@@ -0,0 +1,48 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package exec
+
+import (
+	q "os/exec"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "exec",
+		Path: "os/exec",
+		Deps: map[string]string{
+			"bytes":                    "bytes",
+			"context":                  "context",
+			"errors":                   "errors",
+			"internal/godebug":         "godebug",
+			"internal/syscall/execenv": "execenv",
+			"internal/syscall/unix":    "unix",
+			"io":                       "io",
+			"io/fs":                    "fs",
+			"os":                       "os",
+			"path/filepath":            "filepath",
+			"runtime":                  "runtime",
+			"strconv":                  "strconv",
+			"strings":                  "strings",
+			"sync/atomic":              "atomic",
+			"syscall":                  "syscall",
+			"time":                     "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"ErrWaitDelay": reflect.ValueOf(&q.ErrWaitDelay),
+		},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,52 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package os
+
+import (
+	q "os"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "os",
+		Path: "os",
+		Deps: map[string]string{
+			"errors":                   "errors",
+			"internal/bytealg":         "bytealg",
+			"internal/byteorder":       "byteorder",
+			"internal/filepathlite":    "filepathlite",
+			"internal/goarch":          "goarch",
+			"internal/poll":            "poll",
+			"internal/strconv":         "strconv",
+			"internal/stringslite":     "stringslite",
+			"internal/syscall/execenv": "execenv",
+			"internal/syscall/unix":    "unix",
+			"internal/testlog":         "testlog",
+			"io":                       "io",
+			"io/fs":                    "fs",
+			"runtime":                  "runtime",
+			"slices":                   "slices",
+			"sync":                     "sync",
+			"sync/atomic":              "atomic",
+			"syscall":                  "syscall",
+			"time":                     "time",
+			"unsafe":                   "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"File": {reflect.TypeOf((*q.File)(nil)).Elem(), "", "Chdir,Chmod,Chown,Close,Fd,Name,Read,ReadAt,ReadDir,ReadFrom,Readdir,Readdirnames,Seek,SetDeadline,SetReadDeadline,SetWriteDeadline,Stat,Sync,SyscallConn,Truncate,Write,WriteAt,WriteString,WriteTo,checkValid,chmod,copyFileRange,fd,lstatat,lstatatNolog,pread,pwrite,read,readFrom,readdir,seek,setDeadline,setReadDeadline,setWriteDeadline,spliceToFile,wrapErr,write,writeTo"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
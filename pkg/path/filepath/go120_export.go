@@ -0,0 +1,44 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package filepath
+
+import (
+	q "path/filepath"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "filepath",
+		Path: "path/filepath",
+		Deps: map[string]string{
+			"errors":                "errors",
+			"internal/bytealg":      "bytealg",
+			"internal/filepathlite": "filepathlite",
+			"io/fs":                 "fs",
+			"os":                    "os",
+			"runtime":               "runtime",
+			"slices":                "slices",
+			"strings":               "strings",
+			"syscall":               "syscall",
+			"unicode/utf8":          "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"SkipAll": reflect.ValueOf(&q.SkipAll),
+		},
+		Funcs: map[string]reflect.Value{
+			"IsLocal": reflect.ValueOf(q.IsLocal),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,52 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package reflect
+
+import (
+	q "reflect"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "reflect",
+		Path: "reflect",
+		Deps: map[string]string{
+			"errors":                "errors",
+			"internal/abi":          "abi",
+			"internal/bytealg":      "bytealg",
+			"internal/goarch":       "goarch",
+			"internal/goexperiment": "goexperiment",
+			"internal/race":         "race",
+			"internal/runtime/maps": "maps",
+			"internal/runtime/sys":  "sys",
+			"internal/strconv":      "strconv",
+			"internal/unsafeheader": "unsafeheader",
+			"iter":                  "iter",
+			"math":                  "math",
+			"runtime":               "runtime",
+			"strconv":               "strconv",
+			"sync":                  "sync",
+			"unicode":               "unicode",
+			"unicode/utf8":          "utf8",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"SliceHeader":  {reflect.TypeOf((*q.SliceHeader)(nil)).Elem(), "", ""},
+			"StringHeader": {reflect.TypeOf((*q.StringHeader)(nil)).Elem(), "", ""},
+			"Value":        {reflect.TypeOf((*q.Value)(nil)).Elem(), "Addr,Bool,Bytes,Call,CallSlice,CanAddr,CanComplex,CanConvert,CanFloat,CanInt,CanInterface,CanSet,CanUint,Cap,Clear,Close,Comparable,Complex,Convert,Elem,Equal,Field,FieldByIndex,FieldByIndexErr,FieldByName,FieldByNameFunc,Fields,Float,Grow,Index,Int,Interface,InterfaceData,IsNil,IsValid,IsZero,Kind,Len,MapIndex,MapKeys,MapRange,Method,MethodByName,Methods,NumField,NumMethod,OverflowComplex,OverflowFloat,OverflowInt,OverflowUint,Pointer,Recv,Send,Seq,Seq2,Set,SetBool,SetBytes,SetCap,SetComplex,SetFloat,SetInt,SetIterKey,SetIterValue,SetLen,SetMapIndex,SetPointer,SetString,SetUint,SetZero,Slice,Slice3,String,TryRecv,TrySend,Type,Uint,UnsafeAddr,UnsafePointer,abiType,abiTypeSlow,assignTo,bytesSlow,call,capNonSlice,extendSlice,grow,lenNonSlice,panicNotBool,pointer,recv,runes,send,setRunes,stringNonString,typ,typeSlow", ""},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
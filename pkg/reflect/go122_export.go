@@ -0,0 +1,50 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package reflect
+
+import (
+	q "reflect"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "reflect",
+		Path: "reflect",
+		Deps: map[string]string{
+			"errors":                "errors",
+			"internal/abi":          "abi",
+			"internal/bytealg":      "bytealg",
+			"internal/goarch":       "goarch",
+			"internal/goexperiment": "goexperiment",
+			"internal/race":         "race",
+			"internal/runtime/maps": "maps",
+			"internal/runtime/sys":  "sys",
+			"internal/strconv":      "strconv",
+			"internal/unsafeheader": "unsafeheader",
+			"iter":                  "iter",
+			"math":                  "math",
+			"runtime":               "runtime",
+			"strconv":               "strconv",
+			"sync":                  "sync",
+			"unicode":               "unicode",
+			"unicode/utf8":          "utf8",
+			"unsafe":                "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"PtrTo": reflect.ValueOf(q.PtrTo),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
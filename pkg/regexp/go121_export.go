@@ -0,0 +1,42 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package regexp
+
+import (
+	q "regexp"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "regexp",
+		Path: "regexp",
+		Deps: map[string]string{
+			"bytes":         "bytes",
+			"io":            "io",
+			"iter":          "iter",
+			"regexp/syntax": "syntax",
+			"slices":        "slices",
+			"strconv":       "strconv",
+			"strings":       "strings",
+			"sync":          "sync",
+			"unicode":       "unicode",
+			"unicode/utf8":  "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Regexp": {reflect.TypeOf((*q.Regexp)(nil)).Elem(), "", "AppendText,Copy,Expand,ExpandString,Find,FindAll,FindAllIndex,FindAllString,FindAllStringIndex,FindAllStringSubmatch,FindAllStringSubmatchIndex,FindAllSubmatch,FindAllSubmatchIndex,FindIndex,FindReaderIndex,FindReaderSubmatchIndex,FindString,FindStringIndex,FindStringSubmatch,FindStringSubmatchIndex,FindSubmatch,FindSubmatchIndex,LiteralPrefix,Longest,MarshalText,Match,MatchReader,MatchString,NumSubexp,ReplaceAll,ReplaceAllFunc,ReplaceAllLiteral,ReplaceAllLiteralString,ReplaceAllString,ReplaceAllStringFunc,Split,String,SubexpIndex,SubexpNames,UnmarshalText,_All,_AllIndex,_AllString,_AllStringIndex,_AllStringSubmatch,_AllStringSubmatchIndex,_AllSubmatch,_AllSubmatchIndex,all,allIndex,allString,allStringIndex,allStringSubmatch,allStringSubmatchIndex,allSubmatch,allSubmatchIndex,backtrack,doMatch,doOnePass,expand,find,get,matches,pad,put,replaceAll,tryBacktrack"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
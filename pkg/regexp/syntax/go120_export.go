@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package syntax
+
+import (
+	q "regexp/syntax"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "syntax",
+		Path: "regexp/syntax",
+		Deps: map[string]string{
+			"slices":       "slices",
+			"sort":         "sort",
+			"strconv":      "strconv",
+			"strings":      "strings",
+			"sync":         "sync",
+			"unicode":      "unicode",
+			"unicode/utf8": "utf8",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs:      map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{
+			"ErrLarge": {reflect.TypeOf(q.ErrLarge), constant.MakeString(string(q.ErrLarge))},
+		},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,38 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package coverage
+
+import (
+	q "runtime/coverage"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "coverage",
+		Path: "runtime/coverage",
+		Deps: map[string]string{
+			"internal/coverage/cfile": "cfile",
+			"io":                      "io",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"ClearCounters":    reflect.ValueOf(q.ClearCounters),
+			"WriteCounters":    reflect.ValueOf(q.WriteCounters),
+			"WriteCountersDir": reflect.ValueOf(q.WriteCountersDir),
+			"WriteMeta":        reflect.ValueOf(q.WriteMeta),
+			"WriteMetaDir":     reflect.ValueOf(q.WriteMetaDir),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
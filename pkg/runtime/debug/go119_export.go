@@ -0,0 +1,41 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package debug
+
+import (
+	q "runtime/debug"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "debug",
+		Path: "runtime/debug",
+		Deps: map[string]string{
+			"fmt":           "fmt",
+			"internal/poll": "poll",
+			"os":            "os",
+			"runtime":       "runtime",
+			"slices":        "slices",
+			"strconv":       "strconv",
+			"strings":       "strings",
+			"time":          "time",
+			"unsafe":        "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"SetMemoryLimit": reflect.ValueOf(q.SetMemoryLimit),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
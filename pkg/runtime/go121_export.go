@@ -0,0 +1,59 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package runtime
+
+import (
+	q "runtime"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "runtime",
+		Path: "runtime",
+		Deps: map[string]string{
+			"internal/abi":                   "abi",
+			"internal/bytealg":               "bytealg",
+			"internal/byteorder":             "byteorder",
+			"internal/chacha8rand":           "chacha8rand",
+			"internal/coverage/rtcov":        "rtcov",
+			"internal/cpu":                   "cpu",
+			"internal/goarch":                "goarch",
+			"internal/godebugs":              "godebugs",
+			"internal/goexperiment":          "goexperiment",
+			"internal/goos":                  "goos",
+			"internal/profilerecord":         "profilerecord",
+			"internal/runtime/atomic":        "atomic",
+			"internal/runtime/cgroup":        "cgroup",
+			"internal/runtime/exithook":      "exithook",
+			"internal/runtime/gc":            "gc",
+			"internal/runtime/gc/scan":       "scan",
+			"internal/runtime/maps":          "maps",
+			"internal/runtime/math":          "math",
+			"internal/runtime/pprof/label":   "label",
+			"internal/runtime/sys":           "sys",
+			"internal/runtime/syscall/linux": "linux",
+			"internal/strconv":               "strconv",
+			"internal/stringslite":           "stringslite",
+			"internal/trace/tracev2":         "tracev2",
+			"math/bits":                      "bits",
+			"unsafe":                         "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"PanicNilError": {reflect.TypeOf((*q.PanicNilError)(nil)).Elem(), "", "Error,RuntimeError"},
+			"Pinner":        {reflect.TypeOf((*q.Pinner)(nil)).Elem(), "", "Pin,Unpin"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+// The generic functions of slices are instantiated for the elements of
+// the types int, float64 and string.
+package slices
+
+import (
+	q "slices"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/cmp"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "slices",
+		Path: "slices",
+		Deps: map[string]string{
+			"cmp": "cmp",
+		},
+		GenericFuncs: map[string]gossa.GenericFunc{
+			"BinarySearch": {"func BinarySearch[S ~[]E, E cmp.Ordered](x S, target E) (int, bool)", []reflect.Value{reflect.ValueOf(q.BinarySearch[[]int]), reflect.ValueOf(q.BinarySearch[[]float64]), reflect.ValueOf(q.BinarySearch[[]string])}},
+			"Clone":        {"func Clone[S ~[]E, E any](s S) S", []reflect.Value{reflect.ValueOf(q.Clone[[]int]), reflect.ValueOf(q.Clone[[]float64]), reflect.ValueOf(q.Clone[[]string])}},
+			"Compact":      {"func Compact[S ~[]E, E comparable](s S) S", []reflect.Value{reflect.ValueOf(q.Compact[[]int]), reflect.ValueOf(q.Compact[[]float64]), reflect.ValueOf(q.Compact[[]string])}},
+			"Contains":     {"func Contains[S ~[]E, E comparable](s S, v E) bool", []reflect.Value{reflect.ValueOf(q.Contains[[]int]), reflect.ValueOf(q.Contains[[]float64]), reflect.ValueOf(q.Contains[[]string])}},
+			"ContainsFunc": {"func ContainsFunc[S ~[]E, E any](s S, f func(E) bool) bool", []reflect.Value{reflect.ValueOf(q.ContainsFunc[[]int]), reflect.ValueOf(q.ContainsFunc[[]float64]), reflect.ValueOf(q.ContainsFunc[[]string])}},
+			"Equal":        {"func Equal[S ~[]E, E comparable](s1, s2 S) bool", []reflect.Value{reflect.ValueOf(q.Equal[[]int]), reflect.ValueOf(q.Equal[[]float64]), reflect.ValueOf(q.Equal[[]string])}},
+			"Index":        {"func Index[S ~[]E, E comparable](s S, v E) int", []reflect.Value{reflect.ValueOf(q.Index[[]int]), reflect.ValueOf(q.Index[[]float64]), reflect.ValueOf(q.Index[[]string])}},
+			"IndexFunc":    {"func IndexFunc[S ~[]E, E any](s S, f func(E) bool) int", []reflect.Value{reflect.ValueOf(q.IndexFunc[[]int]), reflect.ValueOf(q.IndexFunc[[]float64]), reflect.ValueOf(q.IndexFunc[[]string])}},
+			"Insert":       {"func Insert[S ~[]E, E any](s S, i int, v ...E) S", []reflect.Value{reflect.ValueOf(q.Insert[[]int]), reflect.ValueOf(q.Insert[[]float64]), reflect.ValueOf(q.Insert[[]string])}},
+			"IsSorted":     {"func IsSorted[S ~[]E, E cmp.Ordered](x S) bool", []reflect.Value{reflect.ValueOf(q.IsSorted[[]int]), reflect.ValueOf(q.IsSorted[[]float64]), reflect.ValueOf(q.IsSorted[[]string])}},
+			"Max":          {"func Max[S ~[]E, E cmp.Ordered](x S) E", []reflect.Value{reflect.ValueOf(q.Max[[]int]), reflect.ValueOf(q.Max[[]float64]), reflect.ValueOf(q.Max[[]string])}},
+			"Min":          {"func Min[S ~[]E, E cmp.Ordered](x S) E", []reflect.Value{reflect.ValueOf(q.Min[[]int]), reflect.ValueOf(q.Min[[]float64]), reflect.ValueOf(q.Min[[]string])}},
+			"Reverse":      {"func Reverse[S ~[]E, E any](s S)", []reflect.Value{reflect.ValueOf(q.Reverse[[]int]), reflect.ValueOf(q.Reverse[[]float64]), reflect.ValueOf(q.Reverse[[]string])}},
+			"Sort":         {"func Sort[S ~[]E, E cmp.Ordered](x S)", []reflect.Value{reflect.ValueOf(q.Sort[[]int]), reflect.ValueOf(q.Sort[[]float64]), reflect.ValueOf(q.Sort[[]string])}},
+			"SortFunc":     {"func SortFunc[S ~[]E, E any](x S, cmp func(a, b E) int)", []reflect.Value{reflect.ValueOf(q.SortFunc[[]int]), reflect.ValueOf(q.SortFunc[[]float64]), reflect.ValueOf(q.SortFunc[[]string])}},
+		},
+	})
+}
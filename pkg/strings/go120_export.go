@@ -0,0 +1,44 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package strings
+
+import (
+	q "strings"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "strings",
+		Path: "strings",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/abi":         "abi",
+			"internal/bytealg":     "bytealg",
+			"internal/stringslite": "stringslite",
+			"io":                   "io",
+			"iter":                 "iter",
+			"math/bits":            "bits",
+			"sync":                 "sync",
+			"unicode":              "unicode",
+			"unicode/utf8":         "utf8",
+			"unsafe":               "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"CutPrefix": reflect.ValueOf(q.CutPrefix),
+			"CutSuffix": reflect.ValueOf(q.CutSuffix),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,43 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package strings
+
+import (
+	q "strings"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "strings",
+		Path: "strings",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/abi":         "abi",
+			"internal/bytealg":     "bytealg",
+			"internal/stringslite": "stringslite",
+			"io":                   "io",
+			"iter":                 "iter",
+			"math/bits":            "bits",
+			"sync":                 "sync",
+			"unicode":              "unicode",
+			"unicode/utf8":         "utf8",
+			"unsafe":               "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"ContainsFunc": reflect.ValueOf(q.ContainsFunc),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,38 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.19
+// +build go1.19
+
+package atomic
+
+import (
+	q "sync/atomic"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "atomic",
+		Path: "sync/atomic",
+		Deps: map[string]string{
+			"unsafe": "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Bool":    {reflect.TypeOf((*q.Bool)(nil)).Elem(), "", "CompareAndSwap,Load,Store,Swap"},
+			"Int32":   {reflect.TypeOf((*q.Int32)(nil)).Elem(), "", "Add,And,CompareAndSwap,Load,Or,Store,Swap"},
+			"Int64":   {reflect.TypeOf((*q.Int64)(nil)).Elem(), "", "Add,And,CompareAndSwap,Load,Or,Store,Swap"},
+			"Uint32":  {reflect.TypeOf((*q.Uint32)(nil)).Elem(), "", "Add,And,CompareAndSwap,Load,Or,Store,Swap"},
+			"Uint64":  {reflect.TypeOf((*q.Uint64)(nil)).Elem(), "", "Add,And,CompareAndSwap,Load,Or,Store,Swap"},
+			"Uintptr": {reflect.TypeOf((*q.Uintptr)(nil)).Elem(), "", "Add,And,CompareAndSwap,Load,Or,Store,Swap"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
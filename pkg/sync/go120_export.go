@@ -0,0 +1,39 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package sync
+
+import (
+	q "sync"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "sync",
+		Path: "sync",
+		Deps: map[string]string{
+			"internal/race":           "race",
+			"internal/runtime/atomic": "atomic",
+			"internal/sync":           "sync",
+			"internal/synctest":       "synctest",
+			"runtime":                 "runtime",
+			"sync/atomic":             "atomic",
+			"unsafe":                  "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Map": {reflect.TypeOf((*q.Map)(nil)).Elem(), "", "Clear,CompareAndDelete,CompareAndSwap,Delete,Load,LoadAndDelete,LoadOrStore,Range,Store,Swap"},
+		},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,39 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package sync
+
+import (
+	q "sync"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "sync",
+		Path: "sync",
+		Deps: map[string]string{
+			"internal/race":           "race",
+			"internal/runtime/atomic": "atomic",
+			"internal/sync":           "sync",
+			"internal/synctest":       "synctest",
+			"runtime":                 "runtime",
+			"sync/atomic":             "atomic",
+			"unsafe":                  "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"OnceFunc": reflect.ValueOf(q.OnceFunc),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,56 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package testing
+
+import (
+	q "testing"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "testing",
+		Path: "testing",
+		Deps: map[string]string{
+			"bytes":            "bytes",
+			"context":          "context",
+			"errors":           "errors",
+			"flag":             "flag",
+			"fmt":              "fmt",
+			"internal/race":    "race",
+			"internal/sysinfo": "sysinfo",
+			"io":               "io",
+			"math":             "math",
+			"math/rand":        "rand",
+			"os":               "os",
+			"path/filepath":    "filepath",
+			"reflect":          "reflect",
+			"runtime":          "runtime",
+			"runtime/debug":    "debug",
+			"runtime/trace":    "trace",
+			"slices":           "slices",
+			"strconv":          "strconv",
+			"strings":          "strings",
+			"sync":             "sync",
+			"sync/atomic":      "atomic",
+			"time":             "time",
+			"unicode":          "unicode",
+			"unsafe":           "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Testing": reflect.ValueOf(q.Testing),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.22
+// +build go1.22
+
+package slogtest
+
+import (
+	q "testing/slogtest"
+
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "slogtest",
+		Path: "testing/slogtest",
+		Deps: map[string]string{
+			"context":  "context",
+			"errors":   "errors",
+			"fmt":      "fmt",
+			"log/slog": "slog",
+			"reflect":  "reflect",
+			"runtime":  "runtime",
+			"testing":  "testing",
+			"time":     "time",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Run": reflect.ValueOf(q.Run),
+		},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	})
+}
@@ -0,0 +1,45 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.20
+// +build go1.20
+
+package time
+
+import (
+	q "time"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name: "time",
+		Path: "time",
+		Deps: map[string]string{
+			"errors":               "errors",
+			"internal/bytealg":     "bytealg",
+			"internal/stringslite": "stringslite",
+			"math/bits":            "bits",
+			"runtime":              "runtime",
+			"sync":                 "sync",
+			"syscall":              "syscall",
+			"unsafe":               "unsafe",
+		},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{
+			"Time": {reflect.TypeOf((*q.Time)(nil)).Elem(), "Add,AddDate,After,AppendBinary,AppendFormat,AppendText,Before,Clock,Compare,Date,Day,Equal,Format,GoString,GobEncode,Hour,ISOWeek,In,IsDST,IsZero,Local,Location,MarshalBinary,MarshalJSON,MarshalText,Minute,Month,Nanosecond,Round,Second,String,Sub,Truncate,UTC,Unix,UnixMicro,UnixMilli,UnixNano,Weekday,Year,YearDay,Zone,ZoneBounds,absSec,appendFormat,appendFormatRFC3339,appendStrictRFC3339,appendTo,locabs", "GobDecode,UnmarshalBinary,UnmarshalJSON,UnmarshalText,addSec,mono,nsec,sec,setLoc,setMono,stripMono,unixSec"},
+		},
+		AliasTypes:  map[string]reflect.Type{},
+		Vars:        map[string]reflect.Value{},
+		Funcs:       map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{
+			"DateOnly": {"untyped string", constant.MakeString(string(q.DateOnly))},
+			"DateTime": {"untyped string", constant.MakeString(string(q.DateTime))},
+			"TimeOnly": {"untyped string", constant.MakeString(string(q.TimeOnly))},
+		},
+	})
+}
@@ -0,0 +1,40 @@
+// export by github.com/goplus/gossa/cmd/qexp
+
+//go:build go1.21
+// +build go1.21
+
+package unicode
+
+import (
+	q "unicode"
+
+	"go/constant"
+	"reflect"
+
+	"github.com/goplus/gossa"
+)
+
+func init() {
+	gossa.RegisterPackage(&gossa.Package{
+		Name:       "unicode",
+		Path:       "unicode",
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]gossa.NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars: map[string]reflect.Value{
+			"Cypro_Minoan": reflect.ValueOf(&q.Cypro_Minoan),
+			"Kawi":         reflect.ValueOf(&q.Kawi),
+			"Nag_Mundari":  reflect.ValueOf(&q.Nag_Mundari),
+			"Old_Uyghur":   reflect.ValueOf(&q.Old_Uyghur),
+			"Tangsa":       reflect.ValueOf(&q.Tangsa),
+			"Toto":         reflect.ValueOf(&q.Toto),
+			"Vithkuqi":     reflect.ValueOf(&q.Vithkuqi),
+		},
+		Funcs:       map[string]reflect.Value{},
+		TypedConsts: map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{
+			"Version": {"untyped string", constant.MakeString(string(q.Version))},
+		},
+	})
+}
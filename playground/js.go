@@ -0,0 +1,34 @@
+//go:build js && wasm
+// +build js,wasm
+
+package playground
+
+import "syscall/js"
+
+// Register sets the JavaScript function gossaRun(src) of the page, which
+// runs the snippet src by Run in a new goroutine and returns a Promise of
+// {exitCode, error}, where error is null if the snippet ran without error.
+func Register() {
+	promise := js.Global().Get("Promise")
+	js.Global().Set("gossaRun", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		var src string
+		if len(args) > 0 {
+			src = args[0].String()
+		}
+		// a blocking snippet must not block the event loop of the page
+		executor := js.FuncOf(func(this js.Value, fns []js.Value) interface{} {
+			resolve := fns[0]
+			go func() {
+				code, err := Run(src)
+				r := map[string]interface{}{"exitCode": code, "error": nil}
+				if err != nil {
+					r["error"] = err.Error()
+				}
+				resolve.Invoke(r)
+			}()
+			return nil
+		})
+		defer executor.Release()
+		return promise.New(executor)
+	}))
+}
@@ -0,0 +1,102 @@
+// Package playground runs Go snippets with a subset of the standard
+// library which works in a browser, eg. gossa built for GOOS=js
+// GOARCH=wasm as the engine of a playground page:
+//
+//	//go:build js && wasm
+//
+//	package main
+//
+//	import "github.com/goplus/gossa/playground"
+//
+//	func main() {
+//		playground.Register()
+//		select {}
+//	}
+//
+// Importing the package registers the packages of Packages. The output of
+// the snippets is written to the standard output, routed to the page by
+// the fs.writeSync hook of wasm_exec.js.
+package playground
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/bufio"
+	_ "github.com/goplus/gossa/pkg/bytes"
+	_ "github.com/goplus/gossa/pkg/container/heap"
+	_ "github.com/goplus/gossa/pkg/container/list"
+	_ "github.com/goplus/gossa/pkg/encoding/base64"
+	_ "github.com/goplus/gossa/pkg/encoding/hex"
+	_ "github.com/goplus/gossa/pkg/encoding/json"
+	_ "github.com/goplus/gossa/pkg/errors"
+	_ "github.com/goplus/gossa/pkg/fmt"
+	_ "github.com/goplus/gossa/pkg/math"
+	_ "github.com/goplus/gossa/pkg/math/bits"
+	_ "github.com/goplus/gossa/pkg/math/rand"
+	_ "github.com/goplus/gossa/pkg/regexp"
+	_ "github.com/goplus/gossa/pkg/sort"
+	_ "github.com/goplus/gossa/pkg/strconv"
+	_ "github.com/goplus/gossa/pkg/strings"
+	_ "github.com/goplus/gossa/pkg/sync"
+	_ "github.com/goplus/gossa/pkg/text/tabwriter"
+	_ "github.com/goplus/gossa/pkg/time"
+	_ "github.com/goplus/gossa/pkg/unicode"
+	_ "github.com/goplus/gossa/pkg/unicode/utf8"
+)
+
+// Packages are the packages snippets may import.
+var Packages = []string{
+	"bufio",
+	"bytes",
+	"container/heap",
+	"container/list",
+	"encoding/base64",
+	"encoding/hex",
+	"encoding/json",
+	"errors",
+	"fmt",
+	"math",
+	"math/bits",
+	"math/rand",
+	"regexp",
+	"sort",
+	"strconv",
+	"strings",
+	"sync",
+	"text/tabwriter",
+	"time",
+	"unicode",
+	"unicode/utf8",
+}
+
+// Run runs the snippet src, the main.go file of a main package, which may
+// import the packages of Packages.
+func Run(src string) (exitCode int, err error) {
+	if err := checkImports(src); err != nil {
+		return 2, err
+	}
+	return gossa.NewContext(0).RunFile("main.go", src, nil)
+}
+
+// checkImports checks the imports of src are packages of Packages. The
+// syntax errors of src are reported by Run.
+func checkImports(src string) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if n := sort.SearchStrings(Packages, path); n < len(Packages) && Packages[n] == path {
+			continue
+		}
+		return fmt.Errorf("%v: package %v is not available in the playground", fset.Position(spec.Pos()), path)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package playground
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestPackages(t *testing.T) {
+	if !sort.StringsAreSorted(Packages) {
+		t.Fatal("Packages must be sorted")
+	}
+}
+
+func TestRun(t *testing.T) {
+	src := `package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func main() {
+	words := strings.Fields("b c a")
+	sort.Strings(words)
+	data, err := json.Marshal(words)
+	if err != nil {
+		panic(err)
+	}
+	if s := fmt.Sprint(string(data), errors.New("ok")); s != "[\"a\",\"b\",\"c\"]ok" {
+		panic(s)
+	}
+}
+`
+	if code, err := Run(src); code != 0 || err != nil {
+		t.Fatalf("Run = %v, %v", code, err)
+	}
+	src = `package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func main() {
+	fmt.Println(exec.Command("ls"))
+}
+`
+	if _, err := Run(src); err == nil || err.Error() != "main.go:5:2: package os/exec is not available in the playground" {
+		t.Fatalf("bad import error %v", err)
+	}
+	if code, err := Run("package main\n\nfunc main() {\n\tpanic(\"boom\")\n}\n"); code != 2 || err == nil || err.Error() != "boom" {
+		t.Fatalf("panic = %v, %v", code, err)
+	}
+}
@@ -0,0 +1,80 @@
+package gossa
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"unsafe"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// runtime/pprof has no getter of the goroutine labels, the host labels
+// are saved and restored by the runtime functions of runtime/pprof.
+
+//go:linkname runtime_getProfLabel runtime/pprof.runtime_getProfLabel
+func runtime_getProfLabel() unsafe.Pointer
+
+//go:linkname runtime_setProfLabel runtime/pprof.runtime_setProfLabel
+func runtime_setProfLabel(labels unsafe.Pointer)
+
+// Goroutine labels set in EnablePprofLabels mode. Host CPU profiles
+// attribute samples to the interpreted function by these labels, eg.
+//
+//	go tool pprof -tags cpu.prof
+//	go tool pprof -tagfocus=gossa.func=main.fib cpu.prof
+const (
+	PprofLabelFunc = "gossa.func" // interpreted function name
+	PprofLabelPos  = "gossa.pos"  // interpreted function position
+)
+
+func (p *Function) pprofLabels() context.Context {
+	if p.labels == nil {
+		pos := p.Interp.fset.Position(p.Fn.Pos())
+		p.labelSet = pprof.Labels(
+			PprofLabelFunc, p.Fn.String(),
+			PprofLabelPos, fmt.Sprintf("%v:%v", pos.Filename, pos.Line),
+		)
+		p.labels = pprof.WithLabels(context.Background(), p.labelSet)
+	}
+	return p.labels
+}
+
+// funcLabels returns the pprof labels of pfn merged with the labels of
+// the caller goroutine.
+func (i *Interp) funcLabels(pfn *Function) context.Context {
+	if ctx := i.goroutineLabels(); ctx != nil {
+		return pprof.WithLabels(ctx, pfn.labelSet)
+	}
+	return pfn.labels
+}
+
+// restoreLabels restores the goroutine labels of the host after a call of
+// the host into the interpreter, deferred as
+//
+//	defer restoreLabels(runtime_getProfLabel())
+func restoreLabels(labels unsafe.Pointer) {
+	runtime_setProfLabel(labels)
+}
+
+// wrapPprofLabels sets the goroutine labels of pfn on function entry,
+// and restores them after instructions running other functions.
+func wrapPprofLabels(pfn *Function, instr ssa.Instruction, entry bool, ifn func(fr *frame)) func(fr *frame) {
+	pfn.pprofLabels()
+	switch instr.(type) {
+	case *ssa.Call, *ssa.RunDefers:
+		prev := ifn
+		ifn = func(fr *frame) {
+			prev(fr)
+			pprof.SetGoroutineLabels(fr.interp.funcLabels(pfn))
+		}
+	}
+	if entry {
+		prev := ifn
+		ifn = func(fr *frame) {
+			pprof.SetGoroutineLabels(fr.interp.funcLabels(pfn))
+			prev(fr)
+		}
+	}
+	return ifn
+}
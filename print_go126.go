@@ -0,0 +1,22 @@
+//go:build go1.26
+// +build go1.26
+
+package gossa
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// writeFloat prints v like the gc runtime printfloat32 and printfloat64,
+// the shortest representation of v of bitSize, eg. 1.5 or 1e+21.
+func writeFloat(buf *bytes.Buffer, v float64, bitSize int) {
+	var b [24]byte
+	buf.Write(strconv.AppendFloat(b[:0], v, 'g', -1, bitSize))
+}
+
+// writeComplex prints v like the gc runtime printcomplex64 and
+// printcomplex128, eg. (1+2i).
+func writeComplex(buf *bytes.Buffer, v complex128, bitSize int) {
+	buf.WriteString(strconv.FormatComplex(v, 'g', -1, bitSize))
+}
@@ -0,0 +1,88 @@
+//go:build !go1.26
+// +build !go1.26
+
+package gossa
+
+import "bytes"
+
+// writeFloat prints v like the gc runtime printfloat before go1.26, eg.
+// +1.500000e+000, for both float32 and float64.
+func writeFloat(buf *bytes.Buffer, v float64, bitSize int) {
+	switch {
+	case v != v:
+		buf.WriteString("NaN")
+		return
+	case v+v == v && v > 0:
+		buf.WriteString("+Inf")
+		return
+	case v+v == v && v < 0:
+		buf.WriteString("-Inf")
+		return
+	}
+
+	const n = 7 // digits printed
+	var b [n + 7]byte
+	b[0] = '+'
+	e := 0 // exp
+	if v == 0 {
+		if 1/v < 0 {
+			b[0] = '-'
+		}
+	} else {
+		if v < 0 {
+			v = -v
+			b[0] = '-'
+		}
+
+		// normalize
+		for v >= 10 {
+			e++
+			v /= 10
+		}
+		for v < 1 {
+			e--
+			v *= 10
+		}
+
+		// round
+		h := 5.0
+		for i := 0; i < n; i++ {
+			h /= 10
+		}
+		v += h
+		if v >= 10 {
+			e++
+			v /= 10
+		}
+	}
+
+	// format +d.dddd+edd
+	for i := 0; i < n; i++ {
+		s := int(v)
+		b[i+2] = byte(s + '0')
+		v -= float64(s)
+		v *= 10
+	}
+	b[1] = b[2]
+	b[2] = '.'
+
+	b[n+2] = 'e'
+	b[n+3] = '+'
+	if e < 0 {
+		e = -e
+		b[n+3] = '-'
+	}
+
+	b[n+4] = byte(e/100 + '0')
+	b[n+5] = byte(e/10)%10 + '0'
+	b[n+6] = byte(e%10) + '0'
+	buf.Write(b[:])
+}
+
+// writeComplex prints v like the gc runtime printcomplex before go1.26.
+func writeComplex(buf *bytes.Buffer, v complex128, bitSize int) {
+	buf.WriteByte('(')
+	writeFloat(buf, real(v), bitSize/2)
+	writeFloat(buf, imag(v), bitSize/2)
+	buf.WriteString("i)")
+}
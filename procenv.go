@@ -0,0 +1,321 @@
+package gossa
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// procEnv is the process identity seen by the program: os.Args, the
+// command line flags, the environment and the working directory. A nil env
+// or an empty workdir is the one of the host process.
+type procEnv struct {
+	mu      sync.RWMutex
+	args    *[]string     // os.Args of the program
+	flags   *flag.FlagSet // flag.CommandLine of the program
+	usage   func()        // flag.Usage of the program
+	env     map[string]string
+	workdir string
+}
+
+// initProc initializes the process view of the program with the os.Args
+// of WithArgs or of the host and a command line FlagSet of its own, so the
+// flags the program defines and parses do not change the flags of the host.
+func (i *Interp) initProc() {
+	if i.proc.args == nil {
+		args := append([]string(nil), os.Args...)
+		i.proc.args = &args
+	}
+	args := *i.proc.args
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		i.proc.usage()
+	}
+	i.proc.flags = flags
+	i.proc.usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage of %s:\n", flags.Name())
+		flags.PrintDefaults()
+	}
+}
+
+// SetArgs sets the os.Args of the program, parsed by flag.Parse. NewInterp
+// copies os.Args of the host, unless set by the WithArgs option, which the
+// package initializers run by NewInterp see.
+func (i *Interp) SetArgs(args []string) {
+	*i.proc.args = append([]string(nil), args...)
+}
+
+// SetEnv sets the environment of the program, read and changed by
+// os.Getenv, os.LookupEnv, os.Environ, os.ExpandEnv, os.Setenv,
+// os.Unsetenv and os.Clearenv without changing the host environment. A nil
+// env restores the host environment.
+func (i *Interp) SetEnv(env map[string]string) {
+	i.proc.mu.Lock()
+	defer i.proc.mu.Unlock()
+	if env == nil {
+		i.proc.env = nil
+		return
+	}
+	i.proc.env = make(map[string]string, len(env))
+	for k, v := range env {
+		i.proc.env[k] = v
+	}
+}
+
+// SetWorkdir sets the working directory of the program, returned by
+// os.Getwd and changed by os.Chdir without changing the one of the host.
+// The relative names of os.Open, os.Create, os.OpenFile, os.Stat,
+// os.Lstat, os.ReadFile, os.WriteFile, os.Mkdir, os.MkdirAll, os.Remove,
+// os.RemoveAll and os.Rename are relative to dir. An empty dir restores
+// the host working directory.
+func (i *Interp) SetWorkdir(dir string) error {
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		dir = abs
+	}
+	i.proc.mu.Lock()
+	i.proc.workdir = dir
+	i.proc.mu.Unlock()
+	return nil
+}
+
+// path returns name relative to the working directory of the program.
+func (i *Interp) path(name string) string {
+	i.proc.mu.RLock()
+	dir := i.proc.workdir
+	i.proc.mu.RUnlock()
+	if dir == "" || name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+func (i *Interp) lookupEnv(key string) (string, bool) {
+	i.proc.mu.RLock()
+	defer i.proc.mu.RUnlock()
+	if i.proc.env == nil {
+		return os.LookupEnv(key)
+	}
+	v, ok := i.proc.env[key]
+	return v, ok
+}
+
+func (i *Interp) getenv(key string) string {
+	v, _ := i.lookupEnv(key)
+	return v
+}
+
+// isProcFunc reports whether fn is a function of the os and flag packages
+// bound to the process view of the interp by findProcFunc.
+func isProcFunc(fn *ssa.Function) bool {
+	if fn.Pkg == nil || fn.Signature.Recv() != nil {
+		return false
+	}
+	path := fn.Pkg.Pkg.Path()
+	return path == "os" || path == "flag"
+}
+
+// findProcFunc returns the function name of the os or flag package bound
+// to the process view of interp, see SetArgs, SetEnv and SetWorkdir. The
+// functions of the flag package are the methods of the FlagSet of the
+// program, flag.Parse exits the program on errors like flag.ExitOnError.
+func findProcFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	if strings.HasPrefix(name, "flag.") && name != "flag.Parse" {
+		return findFlagFunc(interp, name[len("flag."):])
+	}
+	var fn interface{}
+	switch name {
+	case "flag.Parse":
+		fn = func() {
+			args := *interp.proc.args
+			if len(args) > 0 {
+				args = args[1:]
+			}
+			if err := interp.proc.flags.Parse(args); err != nil {
+				if err == flag.ErrHelp {
+					panic(exitPanic(0))
+				}
+				panic(exitPanic(2))
+			}
+		}
+	case "os.Getenv":
+		fn = interp.getenv
+	case "os.LookupEnv":
+		fn = interp.lookupEnv
+	case "os.ExpandEnv":
+		fn = func(s string) string {
+			return os.Expand(s, interp.getenv)
+		}
+	case "os.Environ":
+		fn = func() []string {
+			interp.proc.mu.RLock()
+			defer interp.proc.mu.RUnlock()
+			if interp.proc.env == nil {
+				return os.Environ()
+			}
+			env := make([]string, 0, len(interp.proc.env))
+			for k, v := range interp.proc.env {
+				env = append(env, k+"="+v)
+			}
+			sort.Strings(env)
+			return env
+		}
+	case "os.Setenv":
+		fn = func(key, value string) error {
+			interp.proc.mu.Lock()
+			defer interp.proc.mu.Unlock()
+			if interp.proc.env == nil {
+				return os.Setenv(key, value)
+			}
+			interp.proc.env[key] = value
+			return nil
+		}
+	case "os.Unsetenv":
+		fn = func(key string) error {
+			interp.proc.mu.Lock()
+			defer interp.proc.mu.Unlock()
+			if interp.proc.env == nil {
+				return os.Unsetenv(key)
+			}
+			delete(interp.proc.env, key)
+			return nil
+		}
+	case "os.Clearenv":
+		fn = func() {
+			interp.proc.mu.Lock()
+			defer interp.proc.mu.Unlock()
+			if interp.proc.env == nil {
+				os.Clearenv()
+				return
+			}
+			interp.proc.env = make(map[string]string)
+		}
+	case "os.Getwd":
+		fn = func() (string, error) {
+			interp.proc.mu.RLock()
+			defer interp.proc.mu.RUnlock()
+			if interp.proc.workdir == "" {
+				return os.Getwd()
+			}
+			return interp.proc.workdir, nil
+		}
+	case "os.Chdir":
+		fn = func(dir string) error {
+			interp.proc.mu.RLock()
+			virtual := interp.proc.workdir != ""
+			interp.proc.mu.RUnlock()
+			if !virtual {
+				return os.Chdir(dir)
+			}
+			dir = interp.path(dir)
+			fi, err := os.Stat(dir)
+			if err != nil {
+				if e, ok := err.(*os.PathError); ok {
+					e.Op = "chdir"
+				}
+				return err
+			}
+			if !fi.IsDir() {
+				return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+			}
+			interp.proc.mu.Lock()
+			interp.proc.workdir = dir
+			interp.proc.mu.Unlock()
+			return nil
+		}
+	case "os.Open":
+		fn = func(name string) (*os.File, error) {
+			return os.Open(interp.path(name))
+		}
+	case "os.Create":
+		fn = func(name string) (*os.File, error) {
+			return os.Create(interp.path(name))
+		}
+	case "os.OpenFile":
+		fn = func(name string, mode int, perm os.FileMode) (*os.File, error) {
+			return os.OpenFile(interp.path(name), mode, perm)
+		}
+	case "os.Stat":
+		fn = func(name string) (os.FileInfo, error) {
+			return os.Stat(interp.path(name))
+		}
+	case "os.Lstat":
+		fn = func(name string) (os.FileInfo, error) {
+			return os.Lstat(interp.path(name))
+		}
+	case "os.ReadFile":
+		fn = func(name string) ([]byte, error) {
+			return ioutil.ReadFile(interp.path(name))
+		}
+	case "os.WriteFile":
+		fn = func(name string, data []byte, perm os.FileMode) error {
+			return ioutil.WriteFile(interp.path(name), data, perm)
+		}
+	case "os.Mkdir":
+		fn = func(name string, perm os.FileMode) error {
+			return os.Mkdir(interp.path(name), perm)
+		}
+	case "os.MkdirAll":
+		fn = func(name string, perm os.FileMode) error {
+			return os.MkdirAll(interp.path(name), perm)
+		}
+	case "os.Remove":
+		fn = func(name string) error {
+			return os.Remove(interp.path(name))
+		}
+	case "os.RemoveAll":
+		fn = func(name string) error {
+			return os.RemoveAll(interp.path(name))
+		}
+	case "os.Rename":
+		fn = func(oldpath, newpath string) error {
+			return os.Rename(interp.path(oldpath), interp.path(newpath))
+		}
+	default:
+		return
+	}
+	return reflect.ValueOf(fn), true
+}
+
+// findFlagFunc returns the function name of the flag package calling the
+// method name of the current flag.CommandLine of the program.
+func findFlagFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	m, ok := reflect.TypeOf(interp.proc.flags).MethodByName(name)
+	if !ok {
+		return
+	}
+	ftyp := reflect.ValueOf(interp.proc.flags).Method(m.Index).Type()
+	return reflect.MakeFunc(ftyp, func(args []reflect.Value) []reflect.Value {
+		return reflect.ValueOf(interp.proc.flags).Method(m.Index).Call(args)
+	}), true
+}
+
+// procGlobal returns the address of the global name of the os or flag
+// package in the process view of interp.
+func procGlobal(interp *Interp, pkgpath string, name string) (interface{}, bool) {
+	switch pkgpath + "." + name {
+	case "os.Args":
+		return interp.proc.args, true
+	case "flag.CommandLine":
+		return &interp.proc.flags, true
+	case "flag.Usage":
+		return &interp.proc.usage, true
+	}
+	return nil, false
+}
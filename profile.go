@@ -0,0 +1,312 @@
+package gossa
+
+import (
+	"compress/gzip"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// FuncProfile is the profile record of an interpreted function.
+type FuncProfile struct {
+	Func   *ssa.Function   // ssa function
+	Pos    token.Position  // function position
+	Calls  int64           // number of invocations
+	Time   time.Duration   // cumulative time, callees included
+	Instrs []*InstrProfile // instructions executed by the function
+}
+
+// InstrProfile is the profile record of an SSA instruction kind.
+type InstrProfile struct {
+	Kind  string        // instruction kind, eg. "BinOp"
+	Count int64         // number of executions
+	Time  time.Duration // cumulative time, callees included for calls
+}
+
+// Profile is a report of an interpreter run with EnableProfiling,
+// records are sorted by cumulative time. Record times include the
+// overhead of timing each instruction.
+type Profile struct {
+	Funcs    []*FuncProfile
+	Instrs   []*InstrProfile
+	Overhead time.Duration // calibrated timing overhead of an instruction
+}
+
+type funcRecord struct {
+	fn     *ssa.Function
+	calls  int64
+	nanos  int64
+	instrs map[string]*instrRecord
+}
+
+type instrRecord struct {
+	kind  string
+	count int64
+	nanos int64
+}
+
+// profiler records function and instruction statistics for EnableProfiling.
+// Records are created when functions are compiled, and updated atomically
+// at run time.
+type profiler struct {
+	fset   *token.FileSet
+	mu     sync.Mutex // guards record creation by compile workers
+	funcs  map[*ssa.Function]*funcRecord
+	instrs map[string]*instrRecord
+}
+
+var (
+	overheadOnce sync.Once
+	overhead     time.Duration
+	nopInstr     = func(fr *frame) {} // not inlined, like the instruction closures
+)
+
+// timingOverhead returns the time measured by the instruction wrapper of
+// profiler.wrap around an empty instruction, the least mean of a few rounds.
+func timingOverhead() time.Duration {
+	overheadOnce.Do(func() {
+		const rounds, n = 5, 10000
+		for r := 0; r < rounds; r++ {
+			var nanos int64
+			for i := 0; i < n; i++ {
+				start := time.Now()
+				nopInstr(nil)
+				nanos += int64(time.Since(start))
+			}
+			if d := time.Duration(nanos / n); r == 0 || d < overhead {
+				overhead = d
+			}
+		}
+	})
+	return overhead
+}
+
+func newProfiler(fset *token.FileSet) *profiler {
+	return &profiler{
+		fset:   fset,
+		funcs:  make(map[*ssa.Function]*funcRecord),
+		instrs: make(map[string]*instrRecord),
+	}
+}
+
+// wrap wraps the instruction closure ifn with time and count recording.
+// entry reports whether instr is the first instruction of the function.
+func (p *profiler) wrap(fn *ssa.Function, instr ssa.Instruction, entry bool, ifn func(fr *frame)) func(fr *frame) {
+	kind := strings.TrimPrefix(fmt.Sprintf("%T", instr), "*ssa.")
+	p.mu.Lock()
+	ir, ok := p.instrs[kind]
+	if !ok {
+		ir = &instrRecord{kind: kind}
+		p.instrs[kind] = ir
+	}
+	rec, ok := p.funcs[fn]
+	if !ok {
+		rec = &funcRecord{fn: fn, instrs: make(map[string]*instrRecord)}
+		p.funcs[fn] = rec
+	}
+	fir, ok := rec.instrs[kind]
+	if !ok {
+		fir = &instrRecord{kind: kind}
+		rec.instrs[kind] = fir
+	}
+	p.mu.Unlock()
+	pfn := func(fr *frame) {
+		start := time.Now()
+		ifn(fr)
+		d := int64(time.Since(start))
+		atomic.AddInt64(&ir.count, 1)
+		atomic.AddInt64(&ir.nanos, d)
+		atomic.AddInt64(&fir.count, 1)
+		atomic.AddInt64(&fir.nanos, d)
+	}
+	if _, ok := instr.(*ssa.Return); ok {
+		prev := pfn
+		pfn = func(f *frame) {
+			prev(f)
+			atomic.AddInt64(&rec.nanos, int64(time.Since(f.started)))
+		}
+	}
+	if entry {
+		prev := pfn
+		pfn = func(f *frame) {
+			f.started = time.Now()
+			atomic.AddInt64(&rec.calls, 1)
+			prev(f)
+		}
+	}
+	return pfn
+}
+
+func (p *profiler) profile() *Profile {
+	prof := &Profile{Overhead: timingOverhead()}
+	for fn, r := range p.funcs {
+		prof.Funcs = append(prof.Funcs, &FuncProfile{
+			Func:   fn,
+			Pos:    p.fset.Position(fn.Pos()),
+			Calls:  atomic.LoadInt64(&r.calls),
+			Time:   time.Duration(atomic.LoadInt64(&r.nanos)),
+			Instrs: instrProfiles(r.instrs),
+		})
+	}
+	prof.Instrs = instrProfiles(p.instrs)
+	sort.Slice(prof.Funcs, func(i, j int) bool {
+		if prof.Funcs[i].Time != prof.Funcs[j].Time {
+			return prof.Funcs[i].Time > prof.Funcs[j].Time
+		}
+		return prof.Funcs[i].Func.String() < prof.Funcs[j].Func.String()
+	})
+	return prof
+}
+
+// instrProfiles returns the instruction records sorted by cumulative time.
+func instrProfiles(records map[string]*instrRecord) (instrs []*InstrProfile) {
+	for kind, r := range records {
+		instrs = append(instrs, &InstrProfile{
+			Kind:  kind,
+			Count: atomic.LoadInt64(&r.count),
+			Time:  time.Duration(atomic.LoadInt64(&r.nanos)),
+		})
+	}
+	sort.Slice(instrs, func(i, j int) bool {
+		if instrs[i].Time != instrs[j].Time {
+			return instrs[i].Time > instrs[j].Time
+		}
+		return instrs[i].Kind < instrs[j].Kind
+	})
+	return
+}
+
+// Profile returns the profile report of the interpreter, or nil if the
+// interpreter is not in EnableProfiling mode.
+func (i *Interp) Profile() *Profile {
+	if i.profile == nil {
+		return nil
+	}
+	return i.profile.profile()
+}
+
+// WriteTo writes a text report of the profile to w.
+func (p *Profile) WriteTo(w io.Writer) (n int64, err error) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%12s %14s  %s\n", "calls", "time", "function")
+	for _, f := range p.Funcs {
+		fmt.Fprintf(&buf, "%12d %14v  %v %v\n", f.Calls, f.Time, f.Func, f.Pos)
+	}
+	fmt.Fprintf(&buf, "\n%12s %14s  %s\n", "count", "time", "instruction")
+	for _, r := range p.Instrs {
+		fmt.Fprintf(&buf, "%12d %14v  %v\n", r.Count, r.Time, r.Kind)
+	}
+	c, err := io.WriteString(w, buf.String())
+	return int64(c), err
+}
+
+// WritePprof writes the function records of the profile to w in the
+// gzip-compressed protocol buffer format read by "go tool pprof".
+// Each function is a sample with calls and cumulative time values,
+// located at the function source position.
+func (p *Profile) WritePprof(w io.Writer) error {
+	strs := map[string]int{"": 0}
+	table := []string{""}
+	str := func(s string) int64 {
+		if i, ok := strs[s]; ok {
+			return int64(i)
+		}
+		strs[s] = len(table)
+		table = append(table, s)
+		return int64(len(table) - 1)
+	}
+	var b protobuf
+	valueType := func(tag int, typ, unit string) {
+		b.message(tag, func() {
+			b.int64(1, str(typ))
+			b.int64(2, str(unit))
+		})
+	}
+	valueType(1, "calls", "count")
+	valueType(1, "time", "nanoseconds")
+	for i, f := range p.Funcs {
+		id := uint64(i + 1)
+		b.message(2, func() {
+			b.uint64(1, id)
+			b.int64(2, f.Calls)
+			b.int64(2, int64(f.Time))
+		})
+	}
+	for i, f := range p.Funcs {
+		id := uint64(i + 1)
+		b.message(4, func() {
+			b.uint64(1, id)
+			b.message(4, func() {
+				b.uint64(1, id)
+				b.int64(2, int64(f.Pos.Line))
+			})
+		})
+	}
+	for i, f := range p.Funcs {
+		id := uint64(i + 1)
+		name := str(f.Func.String())
+		file := str(f.Pos.Filename)
+		b.message(5, func() {
+			b.uint64(1, id)
+			b.int64(2, name)
+			b.int64(3, name)
+			b.int64(4, file)
+			b.int64(5, int64(f.Pos.Line))
+		})
+	}
+	valueType(11, "time", "nanoseconds")
+	for _, s := range table {
+		b.string(6, s)
+	}
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// protobuf is a minimal protocol buffer encoder for WritePprof.
+type protobuf struct {
+	data []byte
+}
+
+func (b *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protobuf) uint64(tag int, x uint64) {
+	b.varint(uint64(tag) << 3)
+	b.varint(x)
+}
+
+func (b *protobuf) int64(tag int, x int64) {
+	b.uint64(tag, uint64(x))
+}
+
+func (b *protobuf) string(tag int, s string) {
+	b.varint(uint64(tag)<<3 | 2)
+	b.varint(uint64(len(s)))
+	b.data = append(b.data, s...)
+}
+
+func (b *protobuf) message(tag int, f func()) {
+	data := b.data
+	b.data = nil
+	f()
+	msg := b.data
+	b.data = data
+	b.varint(uint64(tag)<<3 | 2)
+	b.varint(uint64(len(msg)))
+	b.data = append(b.data, msg...)
+}
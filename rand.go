@@ -0,0 +1,88 @@
+package gossa
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// lockedSource is a rand.Source safe for the goroutines of a program, like
+// the source of the math/rand functions. It implements rand.Read and
+// rand.Seed under its lock, the Read and Seed methods of rand.Rand change
+// the unguarded read state of the Rand.
+type lockedSource struct {
+	mu      sync.Mutex
+	src     rand.Source64
+	readVal int64 // bytes of Read not consumed, see rand.Rand
+	readPos int
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+	s.readPos = 0
+}
+
+// Read generates len(p) random bytes into p, the bytes of rand.Rand.Read
+// of the source.
+func (s *lockedSource) Read(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for n = 0; n < len(p); n++ {
+		if s.readPos == 0 {
+			s.readVal = s.src.Int63()
+			s.readPos = 7
+		}
+		p[n] = byte(s.readVal)
+		s.readVal >>= 8
+		s.readPos--
+	}
+	return
+}
+
+// SetRandSeed seeds the source of the math/rand functions of the program,
+// for a reproducible random stream. Each interpreter has its own source,
+// seeded by the time it is first used unless seeded by SetRandSeed or by
+// rand.Seed of the program.
+func (i *Interp) SetRandSeed(seed int64) {
+	i.getRand()
+	i.randSrc.Seed(seed)
+}
+
+func (i *Interp) getRand() *rand.Rand {
+	i.randOnce.Do(func() {
+		src := rand.NewSource(time.Now().UnixNano()).(rand.Source64)
+		i.randSrc = &lockedSource{src: src}
+		i.rand = rand.New(i.randSrc)
+	})
+	return i.rand
+}
+
+// findRandFunc returns the function name of math/rand bound to the source
+// of interp, the method of the same name of its rand.Rand, or of its
+// source for Read and Seed.
+func findRandFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	switch name {
+	case "ExpFloat64", "Float32", "Float64", "Int", "Int31", "Int31n", "Int63",
+		"Int63n", "Intn", "NormFloat64", "Perm", "Shuffle", "Uint32", "Uint64":
+		return reflect.ValueOf(interp.getRand()).MethodByName(name), true
+	case "Read", "Seed":
+		interp.getRand()
+		return reflect.ValueOf(interp.randSrc).MethodByName(name), true
+	}
+	return
+}
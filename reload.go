@@ -0,0 +1,144 @@
+package gossa
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Reload replaces the program of the interpreter by the program of newpkg,
+// a changed main package loaded by the same context, keeping the state of
+// the global variables. Globals of the same package and name whose types
+// are unchanged keep their variables and values, values of globals with
+// convertible types are converted, other globals are initialized by the
+// init functions of the new program, which run once compiled.
+//
+// The functions of the new program are compiled and initialized before
+// Reload returns, a failed Reload restores the old program. Values created
+// by the old program, eg. in kept globals, keep their types and methods.
+// Reload must not be called while functions of the interpreter run.
+func (i *Interp) Reload(newpkg *ssa.Package) (err error) {
+	old := interpProgram{
+		fset:    i.fset,
+		prog:    i.prog,
+		mainpkg: i.mainpkg,
+		globals: i.globals,
+		funcs:   i.funcs,
+		msets:   i.msets,
+		record:  i.record,
+	}
+	defer func() {
+		if err != nil {
+			old.restore(i)
+		}
+	}()
+	oldGlobals := make(map[string]*ssa.Global, len(old.globals))
+	for g := range old.globals {
+		// synthetic globals, eg. init$guard, are not kept
+		if g := g.(*ssa.Global); g.Object() != nil {
+			oldGlobals[g.String()] = g
+		}
+	}
+
+	i.funcsMutex.Lock()
+	i.fset = newpkg.Prog.Fset
+	i.prog = newpkg.Prog
+	i.mainpkg = newpkg
+	i.globals = make(map[ssa.Value]value)
+	// functions and method sets of the old program run for its values
+	i.funcs = make(map[*ssa.Function]*Function, len(old.funcs))
+	for fn, pfn := range old.funcs {
+		i.funcs[fn] = pfn
+	}
+	i.msets = make(map[reflect.Type](map[string]*ssa.Function), len(old.msets))
+	for typ, mset := range old.msets {
+		i.msets[typ] = mset
+	}
+	i.funcsMutex.Unlock()
+	i.record = NewTypesRecord(i.loader, i)
+	i.record.nomethods = i.mode&DisableMethodSynthesis != 0
+	i.record.Load(newpkg)
+
+	var pkgs []*ssa.Package
+	var kept []*ssa.Global
+	// values of the kept globals of changed types, set after init
+	converted := make(map[*ssa.Global]reflect.Value)
+	for _, pkg := range newpkg.Prog.AllPackages() {
+		if pkg.Func("init").Blocks == nil {
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+		for _, m := range pkg.Members {
+			g, ok := m.(*ssa.Global)
+			if !ok {
+				continue
+			}
+			typ := i.preToType(deref(g.Type()))
+			if og, ok := oldGlobals[g.String()]; ok {
+				ov := reflect.ValueOf(old.globals[og])
+				if ov.Type().Elem() == typ {
+					i.globals[g] = old.globals[og]
+					kept = append(kept, g)
+					continue
+				}
+				if ov.Elem().Type().ConvertibleTo(typ) {
+					converted[g] = ov.Elem().Convert(typ)
+				}
+			}
+			i.globals[g] = reflect.New(typ).Interface()
+		}
+	}
+	if err = checkPackages(i, pkgs); err != nil {
+		return err
+	}
+
+	// keep the values of the kept variables over the initializers
+	values := make(map[*ssa.Global]reflect.Value, len(kept))
+	for _, g := range kept {
+		values[g] = copyValue(reflect.ValueOf(i.globals[g]).Elem())
+	}
+	err = i.runInit(pkgs)
+	for g, v := range values {
+		reflect.ValueOf(i.globals[g]).Elem().Set(v)
+	}
+	if err != nil {
+		return fmt.Errorf("init error: %w", err)
+	}
+	for g, v := range converted {
+		reflect.ValueOf(i.globals[g]).Elem().Set(v)
+	}
+	return nil
+}
+
+// copyValue returns a copy of v.
+func copyValue(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// interpProgram is the program state of an interpreter replaced by Reload.
+type interpProgram struct {
+	fset    *token.FileSet
+	prog    *ssa.Program
+	mainpkg *ssa.Package
+	globals map[ssa.Value]value
+	funcs   map[*ssa.Function]*Function
+	msets   map[reflect.Type](map[string]*ssa.Function)
+	record  *TypesRecord
+}
+
+// restore sets the program state of i to p.
+func (p *interpProgram) restore(i *Interp) {
+	i.funcsMutex.Lock()
+	i.funcs = p.funcs
+	i.msets = p.msets
+	i.funcsMutex.Unlock()
+	i.fset = p.fset
+	i.prog = p.prog
+	i.mainpkg = p.mainpkg
+	i.globals = p.globals
+	i.record = p.record
+}
@@ -0,0 +1,55 @@
+package gossa
+
+import (
+	"bytes"
+	"go/token"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Result is the report of an interpreter run.
+type Result struct {
+	ExitCode   int           // exit code of the target program
+	Err        error         // run error, see Interp.Run
+	Panic      interface{}   // panic value of the target program, or nil
+	Stack      []StackFrame  // stack of the target panic() call, innermost first
+	Output     *bytes.Buffer // captured output, see CapturedOutput
+	Duration   time.Duration // run duration
+	Profile    *Profile      // profile report in EnableProfiling mode, or nil
+	Goroutines int           // interpreted goroutines still running at exit
+}
+
+// StackFrame is a frame of an interpreted call stack.
+type StackFrame struct {
+	Func *ssa.Function  // called function
+	Pos  token.Position // position of the executing instruction
+}
+
+// RunResult runs the entry function like Run, reporting a Result.
+func (i *Interp) RunResult(entry string) *Result {
+	start := time.Now()
+	r := &Result{}
+	r.ExitCode, r.Err = i.Run(entry)
+	r.Duration = time.Since(start)
+	switch p := r.Err.(type) {
+	case targetPanic:
+		r.Panic = p.v
+		r.Stack = p.stack()
+	case runtimePanic:
+		r.Panic = p.err
+		r.Stack = frameStack(p.fr)
+	case runtime.Error, *InterpInternalError:
+		r.Panic = p
+	}
+	r.Output = CapturedOutput
+	r.Profile = i.Profile()
+	r.Goroutines = int(atomic.LoadInt32(&i.goroutines)) - 1
+	return r
+}
+
+func (p targetPanic) stack() []StackFrame {
+	return frameStack(p.fr)
+}
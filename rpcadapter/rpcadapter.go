@@ -0,0 +1,260 @@
+// Package rpcadapter serves the methods of interpreted types as net/rpc
+// services, so scripts implement RPC services the host serves:
+//
+//	type Arith int
+//
+//	func (t *Arith) Add(args *Args, reply *int) error {
+//		*reply = args.A + args.B
+//		return nil
+//	}
+//
+// The host registers a value of the script type and serves connections
+// by a net/rpc server codec, eg. of JSON-RPC:
+//
+//	typ, _ := interp.GetType("Arith")
+//	s := rpcadapter.NewServer(interp)
+//	err := s.Register(reflect.New(typ).Interface())
+//	...
+//	go s.ServeCodec(jsonrpc.NewServerCodec(conn))
+//
+// Like net/rpc, the methods of a service are the exported methods with two
+// arguments, the second a pointer, and an error result. Arguments and
+// replies are decoded to and encoded from the script types.
+//
+// net/rpc calls the methods of services by reflect.Method.Func, which the
+// methods of interpreted types do not support, so the requests are served
+// by an rpc.Server of a dispatcher service calling the script methods.
+package rpcadapter
+
+import (
+	"errors"
+	"fmt"
+	"net/rpc"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/goplus/gossa"
+)
+
+var tyError = reflect.TypeOf((*error)(nil)).Elem()
+
+// dispatchMethod is the method of the dispatcher service of rpc.Server.
+const dispatchMethod = "rpcadapter.Call"
+
+// Server serves the registered services of an interpreter.
+type Server struct {
+	interp   *gossa.Interp
+	server   *rpc.Server
+	mu       sync.RWMutex
+	services map[string]*service
+}
+
+type service struct {
+	rcvr    reflect.Value
+	methods map[string]*method
+}
+
+type method struct {
+	fn    reflect.Value // method of the script, the receiver is the first argument
+	arg   reflect.Type
+	reply reflect.Type
+}
+
+// NewServer returns a Server of the services of the interpreter.
+func NewServer(interp *gossa.Interp) *Server {
+	s := &Server{interp: interp, server: rpc.NewServer(), services: make(map[string]*service)}
+	if err := s.server.RegisterName("rpcadapter", dispatcher{s}); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Register publishes the methods of rcvr, a value of an interpreted type,
+// as the service named by the type of rcvr.
+func (s *Server) Register(rcvr interface{}) error {
+	typ := reflect.TypeOf(rcvr)
+	if typ == nil {
+		return errors.New("rpcadapter: Register of nil")
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return s.RegisterName(typ.Name(), rcvr)
+}
+
+// RegisterName is Register with the service name.
+func (s *Server) RegisterName(name string, rcvr interface{}) error {
+	if name == "" {
+		return fmt.Errorf("rpcadapter: no service name for type %T", rcvr)
+	}
+	v := reflect.ValueOf(rcvr)
+	if !v.IsValid() {
+		return errors.New("rpcadapter: Register of nil")
+	}
+	typ := v.Type()
+	svc := &service{rcvr: v, methods: make(map[string]*method)}
+	for n := 0; n < typ.NumMethod(); n++ {
+		m := typ.Method(n)
+		mtyp := m.Type
+		if m.PkgPath != "" || mtyp.NumIn() != 3 || mtyp.NumOut() != 1 || mtyp.Out(0) != tyError ||
+			mtyp.In(2).Kind() != reflect.Ptr {
+			continue
+		}
+		fn, ok := s.interp.GetMethod(typ, m.Name)
+		if !ok {
+			return fmt.Errorf("rpcadapter: %T is not an interpreted type", rcvr)
+		}
+		svc.methods[m.Name] = &method{fn: reflect.ValueOf(fn), arg: mtyp.In(1), reply: mtyp.In(2).Elem()}
+	}
+	if len(svc.methods) == 0 {
+		return fmt.Errorf("rpcadapter: type %T has no suitable methods", rcvr)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, dup := s.services[name]; dup {
+		return fmt.Errorf("rpcadapter: service already defined: %v", name)
+	}
+	s.services[name] = svc
+	return nil
+}
+
+// lookup returns the method of the request service method "Service.Method".
+func (s *Server) lookup(serviceMethod string) (*service, *method, error) {
+	dot := strings.LastIndex(serviceMethod, ".")
+	if dot < 0 {
+		return nil, nil, fmt.Errorf("rpc: service/method request ill-formed: %v", serviceMethod)
+	}
+	s.mu.RLock()
+	svc := s.services[serviceMethod[:dot]]
+	s.mu.RUnlock()
+	if svc == nil {
+		return nil, nil, fmt.Errorf("rpc: can't find service %v", serviceMethod)
+	}
+	m := svc.methods[serviceMethod[dot+1:]]
+	if m == nil {
+		return nil, nil, fmt.Errorf("rpc: can't find method %v", serviceMethod)
+	}
+	return svc, m, nil
+}
+
+// ServeCodec serves the requests read by codec until the client hangs up,
+// see rpc.Server.ServeCodec.
+func (s *Server) ServeCodec(codec rpc.ServerCodec) {
+	s.server.ServeCodec(&serverCodec{ServerCodec: codec, s: s, pending: make(map[uint64]string)})
+}
+
+// request is the request of a script method passed to the dispatcher.
+type request struct {
+	svc  *service
+	m    *method
+	argv reflect.Value
+	err  error // lookup error
+}
+
+// dispatcher is the rpc.Server service calling the script methods.
+type dispatcher struct {
+	s *Server
+}
+
+// Call calls the method of req, an *request, setting the reply to a pointer
+// to the script reply.
+func (d dispatcher) Call(req interface{}, reply *interface{}) error {
+	r := req.(*request)
+	if r.err != nil {
+		return r.err
+	}
+	v, errmsg := d.s.call(r.svc, r.m, r.argv)
+	if errmsg != "" {
+		return errors.New(errmsg)
+	}
+	*reply = v
+	return nil
+}
+
+// serverCodec directs the requests of codec to the dispatcher, decoding the
+// arguments to the script types, and restores the service methods of the
+// responses.
+type serverCodec struct {
+	rpc.ServerCodec
+	s       *Server
+	req     *request // request read by ReadRequestHeader
+	mu      sync.Mutex
+	pending map[uint64]string // seq => service method
+}
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	if err := c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return err
+	}
+	c.req = &request{}
+	c.req.svc, c.req.m, c.req.err = c.s.lookup(r.ServiceMethod)
+	c.mu.Lock()
+	c.pending[r.Seq] = r.ServiceMethod
+	c.mu.Unlock()
+	r.ServiceMethod = dispatchMethod
+	return nil
+}
+
+func (c *serverCodec) ReadRequestBody(body interface{}) error {
+	req := c.req
+	c.req = nil
+	if body == nil || req == nil {
+		return c.ServerCodec.ReadRequestBody(nil)
+	}
+	if req.err != nil {
+		*body.(*interface{}) = req
+		return c.ServerCodec.ReadRequestBody(nil)
+	}
+	argv := newValue(req.m.arg)
+	if err := c.ServerCodec.ReadRequestBody(argv.Interface()); err != nil {
+		return err
+	}
+	if req.m.arg.Kind() != reflect.Ptr {
+		argv = argv.Elem()
+	}
+	req.argv = argv
+	*body.(*interface{}) = req
+	return nil
+}
+
+func (c *serverCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	if sm, ok := c.pending[r.Seq]; ok {
+		r.ServiceMethod = sm
+		delete(c.pending, r.Seq)
+	}
+	c.mu.Unlock()
+	if v, ok := body.(*interface{}); ok {
+		body = *v
+	}
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+// call calls the method m of svc, returning its reply and error message.
+// A panic of the method is its error.
+func (s *Server) call(svc *service, m *method, argv reflect.Value) (reply interface{}, errmsg string) {
+	replyv := reflect.New(m.reply)
+	defer func() {
+		if p := recover(); p != nil {
+			reply, errmsg = struct{}{}, fmt.Sprintf("panic: %v", p)
+			if info, ok := gossa.TargetPanic(p); ok {
+				errmsg = fmt.Sprintf("panic: %v", info.Value)
+			}
+		}
+	}()
+	out := m.fn.Call([]reflect.Value{svc.rcvr, argv, replyv})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return struct{}{}, err.Error()
+	}
+	return replyv.Interface(), ""
+}
+
+// newValue returns a pointer to a new value of typ, or of its element type
+// if typ is a pointer.
+func newValue(typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Ptr {
+		return reflect.New(typ.Elem())
+	}
+	return reflect.New(typ)
+}
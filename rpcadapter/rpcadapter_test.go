@@ -0,0 +1,140 @@
+package rpcadapter
+
+import (
+	"go/token"
+	"net"
+	"net/rpc/jsonrpc"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/pkg/errors"
+	_ "github.com/goplus/gossa/pkg/strings"
+)
+
+func TestServer(t *testing.T) {
+	src := `package main
+
+import (
+	"errors"
+	"strings"
+)
+
+type Arith int
+
+func (t *Arith) Sum(args []int, reply *int) error {
+	*t++
+	for _, a := range args {
+		*reply += a
+	}
+	return nil
+}
+
+func (t *Arith) Div(args [2]int, reply *int) error {
+	if args[1] == 0 {
+		return errors.New("divide by zero")
+	}
+	*reply = args[0] / args[1]
+	return nil
+}
+
+func (t *Arith) Crash(args int, reply *int) error {
+	var p *int
+	if args > 0 {
+		panic("crash")
+	}
+	*reply = *p
+	return nil
+}
+
+func (t *Arith) Calls() int {
+	return int(*t)
+}
+
+type Text string
+
+func (s Text) Upper(arg string, reply *string) error {
+	*reply = strings.ToUpper(arg) + string(s)
+	return nil
+}
+
+func main() {
+}
+`
+	ctx := gossa.NewContext(0)
+	pkg, err := ctx.LoadFile(token.NewFileSet(), "main.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp, err := ctx.NewInterp(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arith, _ := interp.GetType("Arith")
+	text, _ := interp.GetType("Text")
+	rcvr := reflect.New(arith)
+	s := NewServer(interp)
+	if err := s.Register(rcvr.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RegisterName("Text", reflect.ValueOf("!").Convert(text).Interface()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Register(rcvr.Interface()); err == nil || err.Error() != "rpcadapter: service already defined: Arith" {
+		t.Fatalf("bad duplicate error %v", err)
+	}
+	if err := s.RegisterName("None", 1); err == nil || err.Error() != "rpcadapter: type int has no suitable methods" {
+		t.Fatalf("bad methods error %v", err)
+	}
+
+	cli, srv := net.Pipe()
+	go s.ServeCodec(jsonrpc.NewServerCodec(srv))
+	client := jsonrpc.NewClient(cli)
+	defer client.Close()
+	var n int
+	if err := client.Call("Arith.Sum", []int{1, 2, 3}, &n); err != nil || n != 6 {
+		t.Fatalf("Sum = %v, %v", n, err)
+	}
+	if err := client.Call("Arith.Sum", []int{4}, &n); err != nil || n != 4 {
+		t.Fatalf("Sum = %v, %v", n, err)
+	}
+	if err := client.Call("Arith.Div", [2]int{7, 2}, &n); err != nil || n != 3 {
+		t.Fatalf("Div = %v, %v", n, err)
+	}
+	if err := client.Call("Arith.Div", [2]int{7, 0}, &n); err == nil || err.Error() != "divide by zero" {
+		t.Fatalf("bad Div error %v", err)
+	}
+	if err := client.Call("Arith.Crash", 1, &n); err == nil || err.Error() != "panic: crash" {
+		t.Fatalf("bad panic error %v", err)
+	}
+	if err := client.Call("Arith.Crash", 0, &n); err == nil || err.Error() != "panic: runtime error: invalid memory address or nil pointer dereference" {
+		t.Fatalf("bad runtime error %v", err)
+	}
+	if err := client.Call("Arith.Calls", 0, &n); err == nil || err.Error() != "rpc: can't find method Arith.Calls" {
+		t.Fatalf("bad method error %v", err)
+	}
+	if err := client.Call("None.Calls", 0, &n); err == nil || err.Error() != "rpc: can't find service None.Calls" {
+		t.Fatalf("bad service error %v", err)
+	}
+	var r string
+	if err := client.Call("Text.Upper", "gossa", &r); err != nil || r != "GOSSA!" {
+		t.Fatalf("Upper = %v, %v", r, err)
+	}
+	// concurrent calls get their replies
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var n int
+			if err := client.Call("Arith.Div", [2]int{i * 2, 2}, &n); err != nil || n != i {
+				t.Errorf("Div(%v) = %v, %v", i, n, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := rcvr.Elem().Int(); n != 2 {
+		t.Fatalf("Sum calls = %v, want 2", n)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 
 	"golang.org/x/tools/go/types/typeutil"
 )
@@ -26,6 +27,10 @@ var (
 var (
 	tyEmptyInterface = reflect.TypeOf((*interface{})(nil)).Elem()
 	tyErrorInterface = reflect.TypeOf((*error)(nil)).Elem()
+	tyInt            = reflect.TypeOf(0)
+	tyString         = reflect.TypeOf("")
+	tyBytes          = reflect.TypeOf([]byte(nil))
+	tyRunes          = reflect.TypeOf([]rune(nil))
 )
 
 func init() {
@@ -36,12 +41,23 @@ func init() {
 }
 
 type TypesLoader struct {
+	mu        sync.RWMutex // guards the loader, RegisterPackage may run while programs load
 	packages  map[string]*types.Package
 	installed map[string]*Package
 	rcache    map[reflect.Type]types.Type
 	tcache    *typeutil.Map
 	curpkg    *Package
 	mode      Mode
+	local     map[string]*Package             // packages registered by RegisterPackage
+	gen       int                             // registration generation
+	replaced  map[reflect.Type][]replacedType // rcache entries dropped by RegisterPackage
+	filter    func(path string) error         // import policy, see SetImportFilter
+}
+
+// replacedType is a type dropped from the rcache at generation gen.
+type replacedType struct {
+	gen int
+	typ types.Type
 }
 
 // install package and readonly
@@ -52,6 +68,8 @@ func NewTypesLoader(mode Mode) Loader {
 		rcache:    make(map[reflect.Type]types.Type),
 		tcache:    &typeutil.Map{},
 		mode:      mode,
+		local:     make(map[string]*Package),
+		replaced:  make(map[reflect.Type][]replacedType),
 	}
 	r.packages["unsafe"] = types.Unsafe
 	r.rcache[tyErrorInterface] = typesError
@@ -60,11 +78,15 @@ func NewTypesLoader(mode Mode) Loader {
 }
 
 func (r *TypesLoader) Installed(path string) (pkg *Package, ok bool) {
+	r.mu.RLock()
 	pkg, ok = r.installed[path]
+	r.mu.RUnlock()
 	return
 }
 
 func (r *TypesLoader) Packages() (pkgs []*types.Package) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	for _, pkg := range r.packages {
 		pkgs = append(pkgs, pkg)
 	}
@@ -72,11 +94,15 @@ func (r *TypesLoader) Packages() (pkgs []*types.Package) {
 }
 
 func (r *TypesLoader) LookupPackage(pkgpath string) (*types.Package, bool) {
+	r.mu.RLock()
 	pkg, ok := r.packages[pkgpath]
+	r.mu.RUnlock()
 	return pkg, ok
 }
 
 func (r *TypesLoader) LookupReflect(typ types.Type) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	if rt := r.tcache.At(typ); rt != nil {
 		return rt.(reflect.Type), true
 	}
@@ -84,15 +110,49 @@ func (r *TypesLoader) LookupReflect(typ types.Type) (reflect.Type, bool) {
 }
 
 func (r *TypesLoader) LookupTypes(typ reflect.Type) (types.Type, bool) {
+	r.mu.RLock()
 	t, ok := r.rcache[typ]
+	r.mu.RUnlock()
 	return t, ok
 }
 
 func (r *TypesLoader) Import(path string) (*types.Package, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.importPackage(path)
+}
+
+// importPackage is Import with r.mu held.
+func (r *TypesLoader) importPackage(path string) (*types.Package, error) {
+	// follow the rewrites, a rewrite to the path itself imports it
+	for seen := map[string]bool{path: true}; ; {
+		err := applyFilter(r.filter, path)
+		if err == nil {
+			break
+		}
+		rw, ok := err.(RewriteImport)
+		if !ok {
+			return nil, err
+		}
+		if string(rw) == path {
+			break
+		}
+		if seen[string(rw)] {
+			return nil, &ImportDeniedError{Path: path, Err: fmt.Errorf("rewrite cycle to %v", string(rw))}
+		}
+		path = string(rw)
+		seen[path] = true
+	}
 	if p, ok := r.packages[path]; ok {
 		return p, nil
 	}
-	pkg, ok := registerPkgs[path]
+	pkg, ok := r.local[path]
+	if !ok {
+		pkg, ok = registerPkgs[path]
+	}
+	if !ok {
+		pkg, ok = externPackage(path)
+	}
 	if !ok {
 		return nil, fmt.Errorf("Not found package %v", path)
 	}
@@ -100,7 +160,7 @@ func (r *TypesLoader) Import(path string) (*types.Package, error) {
 	r.packages[path] = p
 	var list []*types.Package
 	for dep, _ := range pkg.Deps {
-		p, err := r.Import(dep)
+		p, err := r.importPackage(dep)
 		if err == nil {
 			list = append(list, p)
 		}
@@ -113,6 +173,140 @@ func (r *TypesLoader) Import(path string) (*types.Package, error) {
 	return p, nil
 }
 
+// RegisterPackage registers or replaces pkg for the loader, without
+// changing the global registration of RegisterPackage. A replaced package
+// and the packages depending on it are installed again on next import.
+// Type records created before keep the types of the replaced package.
+func (r *TypesLoader) RegisterPackage(pkg *Package) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.local[pkg.Path] = pkg
+	if _, ok := r.packages[pkg.Path]; !ok {
+		return
+	}
+	drop := map[string]bool{pkg.Path: true}
+	for changed := true; changed; {
+		changed = false
+		for path, p := range r.installed {
+			if drop[path] {
+				continue
+			}
+			for dep := range p.Deps {
+				if drop[dep] {
+					drop[path] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	r.gen++
+	for rt, typ := range r.rcache {
+		if typeMentions(typ, drop) {
+			r.replaced[rt] = append(r.replaced[rt], replacedType{r.gen, typ})
+			delete(r.rcache, rt)
+		}
+	}
+	for path := range drop {
+		delete(r.packages, path)
+		delete(r.installed, path)
+	}
+}
+
+// SetImportFilter sets fn consulted for each import of a registered
+// package by its path, and for each function, method or variable of a
+// registered package used by the program by its full name, eg. "net.Dial"
+// or "(*net.Dialer).Dial", before the program runs. An error of fn denies
+// the import or the program, a RewriteImport error loads the registered
+// package of another path instead of the import, eg. an in-process stub.
+// A nil fn removes the filter.
+func (r *TypesLoader) SetImportFilter(fn func(path string) error) {
+	r.mu.Lock()
+	r.filter = fn
+	r.mu.Unlock()
+}
+
+// filterSymbol returns the error of the import filter for path.
+func (r *TypesLoader) filterSymbol(path string) error {
+	r.mu.RLock()
+	filter := r.filter
+	r.mu.RUnlock()
+	return applyFilter(filter, path)
+}
+
+// applyFilter returns the error of the import filter fn for path.
+func applyFilter(fn func(path string) error, path string) error {
+	if fn == nil {
+		return nil
+	}
+	err := fn(path)
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(RewriteImport); ok {
+		return err
+	}
+	return &ImportDeniedError{Path: path, Err: err}
+}
+
+func (r *TypesLoader) generation() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.gen
+}
+
+// lookupTypesAt is LookupTypes for a type record created at generation gen.
+func (r *TypesLoader) lookupTypesAt(rt reflect.Type, gen int) (types.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.replaced[rt] {
+		if t.gen > gen {
+			return t.typ, true
+		}
+	}
+	t, ok := r.rcache[rt]
+	return t, ok
+}
+
+// typeMentions reports whether typ refers to a named type of the packages.
+func typeMentions(typ types.Type, pkgs map[string]bool) bool {
+	switch t := typ.(type) {
+	case *types.Named:
+		return t.Obj().Pkg() != nil && pkgs[t.Obj().Pkg().Path()]
+	case *types.Pointer:
+		return typeMentions(t.Elem(), pkgs)
+	case *types.Slice:
+		return typeMentions(t.Elem(), pkgs)
+	case *types.Array:
+		return typeMentions(t.Elem(), pkgs)
+	case *types.Chan:
+		return typeMentions(t.Elem(), pkgs)
+	case *types.Map:
+		return typeMentions(t.Key(), pkgs) || typeMentions(t.Elem(), pkgs)
+	case *types.Signature:
+		return typeMentions(t.Params(), pkgs) || typeMentions(t.Results(), pkgs)
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if typeMentions(t.At(i).Type(), pkgs) {
+				return true
+			}
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if typeMentions(t.Field(i).Type(), pkgs) {
+				return true
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			if typeMentions(t.Method(i).Type(), pkgs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *TypesLoader) installPackage(pkg *Package) (err error) {
 	defer func() {
 		if e := recover(); e != nil {
@@ -148,15 +342,24 @@ func (r *TypesLoader) installPackage(pkg *Package) (err error) {
 	for name, c := range pkg.UntypedConsts {
 		r.InsertUntypedConst(p, name, c)
 	}
-	return
+	return r.installGenerics(p, pkg)
 }
 
 func (r *TypesLoader) InsertInterface(p *types.Package, name string, rt reflect.Type) {
-	r.ToType(rt)
+	r.insertAliased(p, name, r.ToType(rt))
 }
 
 func (r *TypesLoader) InsertNamedType(p *types.Package, name string, t NamedType) {
-	r.ToType(t.Typ)
+	r.insertAliased(p, name, r.ToType(t.Typ))
+}
+
+// insertAliased inserts the name of typ registered in p as an alias, if
+// typ is declared by another package, eg. types of encoding/json moved to
+// encoding/json/v2 and aliased by encoding/json.
+func (r *TypesLoader) insertAliased(p *types.Package, name string, typ types.Type) {
+	if p.Scope().Lookup(name) == nil {
+		p.Scope().Insert(types.NewTypeName(token.NoPos, p, name, typ))
+	}
 }
 
 func (r *TypesLoader) InsertAlias(p *types.Package, name string, rt reflect.Type) {
@@ -0,0 +1,75 @@
+package gossa
+
+import (
+	"runtime"
+	"sync"
+)
+
+// CallResult is the result of a function call of a Runner.
+type CallResult struct {
+	Name   string  // function name
+	Args   []Value // call arguments
+	Result Value   // result of the call, see RunFunc
+	Err    error   // error or panic of the call
+}
+
+// Runner runs functions of an interpreter concurrently on a bounded number
+// of goroutines, like errgroup.Group with a limit. Each call has its own
+// frames, a panic of a call is its error and does not stop the others.
+type Runner struct {
+	interp  *Interp
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	results []CallResult
+}
+
+// NewRunner returns a Runner of the interpreter running at most workers
+// calls at a time, or GOMAXPROCS calls if workers <= 0.
+func (i *Interp) NewRunner(workers int) *Runner {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Runner{interp: i, sem: make(chan struct{}, workers)}
+}
+
+// Go calls the function name of the main package with args in a new
+// goroutine, blocking until a worker is free. It returns the index of the
+// call in the results of Wait.
+func (r *Runner) Go(name string, args ...Value) int {
+	r.sem <- struct{}{}
+	r.mu.Lock()
+	n := len(r.results)
+	r.results = append(r.results, CallResult{Name: name, Args: args})
+	r.mu.Unlock()
+	r.wg.Add(1)
+	go func() {
+		defer func() {
+			<-r.sem
+			r.wg.Done()
+		}()
+		v, err := r.interp.RunFunc(name, args...)
+		r.mu.Lock()
+		r.results[n].Result = v
+		r.results[n].Err = err
+		r.mu.Unlock()
+	}()
+	return n
+}
+
+// Wait waits for the calls of r, returning their results in the order of
+// the Go calls and the error of the first failed call, if any. The Runner
+// may be used again after Wait, for a new set of calls.
+func (r *Runner) Wait() ([]CallResult, error) {
+	r.wg.Wait()
+	r.mu.Lock()
+	results := r.results
+	r.results = nil
+	r.mu.Unlock()
+	for _, res := range results {
+		if res.Err != nil {
+			return results, res.Err
+		}
+	}
+	return results, nil
+}
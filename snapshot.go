@@ -0,0 +1,173 @@
+package gossa
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Snapshot is a copy of the global variables of an interpreter, see
+// Interp.SnapshotGlobals.
+type Snapshot struct {
+	interp *Interp
+	values map[ssa.Value]reflect.Value
+}
+
+// SnapshotGlobals returns a deep copy of the global variables of the
+// interpreted packages, eg. to roll a script back to a known good state
+// after a failed action by RestoreGlobals. Values of the types defined by
+// the interpreted program are copied: pointers and slices into global
+// variables are kept, other pointers, slices, maps and structs are copied.
+// Values of host named types, eg. os.File or sync.Mutex, pointers to them,
+// channels and funcs are shared. It should not be called while the program
+// runs.
+func (i *Interp) SnapshotGlobals() Snapshot {
+	c := i.newCopier()
+	s := Snapshot{interp: i, values: make(map[ssa.Value]reflect.Value, len(i.globals))}
+	for g, v := range i.globals {
+		s.values[g] = c.copy(reflect.ValueOf(v).Elem())
+	}
+	return s
+}
+
+// RestoreGlobals sets the global variables to a copy of the snapshot s of
+// the interpreter, which may be restored again. It should not be called
+// while the program runs.
+func (i *Interp) RestoreGlobals(s Snapshot) error {
+	if s.interp != i {
+		return errors.New("restore globals: snapshot of another interpreter")
+	}
+	c := i.newCopier()
+	for g, v := range s.values {
+		reflect.ValueOf(i.globals[g]).Elem().Set(c.copy(v))
+	}
+	return nil
+}
+
+// copier deep copies values, keeping the aliasing of pointers and maps.
+type copier struct {
+	interp  *Interp
+	globals []memRange                // memory of global variables, sorted by address
+	ptrs    map[copyKey]reflect.Value // copied pointers and maps
+}
+
+type memRange struct {
+	start, end uintptr
+}
+
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func (i *Interp) newCopier() *copier {
+	c := &copier{
+		interp:  i,
+		globals: make([]memRange, 0, len(i.globals)),
+		ptrs:    make(map[copyKey]reflect.Value),
+	}
+	for _, v := range i.globals {
+		p := reflect.ValueOf(v)
+		size := p.Type().Elem().Size()
+		if size == 0 {
+			size = 1
+		}
+		c.globals = append(c.globals, memRange{p.Pointer(), p.Pointer() + size})
+	}
+	sort.Slice(c.globals, func(i, j int) bool {
+		return c.globals[i].start < c.globals[j].start
+	})
+	return c
+}
+
+// isGlobal reports whether ptr points into a global variable.
+func (c *copier) isGlobal(ptr uintptr) bool {
+	n := sort.Search(len(c.globals), func(i int) bool {
+		return c.globals[i].end > ptr
+	})
+	return n < len(c.globals) && c.globals[n].start <= ptr
+}
+
+// isHost reports whether typ is a named type not defined by the interpreted
+// program, whose values are shared.
+func (c *copier) isHost(typ reflect.Type) bool {
+	if typ.PkgPath() == "" {
+		return false
+	}
+	_, local := c.interp.findType(typ, true)
+	return !local
+}
+
+// copy returns a deep copy of v, settable to a value of v's type.
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	typ := v.Type()
+	if c.isHost(typ) {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || c.isGlobal(v.Pointer()) || c.isHost(typ.Elem()) {
+			return v
+		}
+		key := copyKey{v.Pointer(), typ}
+		if p, ok := c.ptrs[key]; ok {
+			return p
+		}
+		p := reflect.New(typ.Elem())
+		c.ptrs[key] = p
+		p.Elem().Set(c.copy(v.Elem()))
+		return p
+	case reflect.Struct:
+		if !v.CanAddr() {
+			// unexported fields are accessed by address
+			x := reflect.New(typ).Elem()
+			x.Set(v)
+			v = x
+		}
+		n := reflect.New(typ).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			accessField(n.Field(i)).Set(c.copy(accessField(v.Field(i))))
+		}
+		return n
+	case reflect.Array:
+		n := reflect.New(typ).Elem()
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return n
+	case reflect.Slice:
+		if v.IsNil() || c.isGlobal(v.Pointer()) {
+			return v
+		}
+		n := reflect.MakeSlice(typ, v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := copyKey{v.Pointer(), typ}
+		if m, ok := c.ptrs[key]; ok {
+			return m
+		}
+		m := reflect.MakeMapWithSize(typ, v.Len())
+		c.ptrs[key] = m
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return m
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.New(typ).Elem()
+		n.Set(c.copy(v.Elem()))
+		return n
+	}
+	return v
+}
@@ -0,0 +1,25 @@
+// Package crypto registers the standard crypto packages, see package
+// gossa/stdlib/minimal. crypto/tls is registered by gossa/stdlib/net.
+package crypto
+
+import (
+	_ "github.com/goplus/gossa/pkg/crypto"
+	_ "github.com/goplus/gossa/pkg/crypto/aes"
+	_ "github.com/goplus/gossa/pkg/crypto/cipher"
+	_ "github.com/goplus/gossa/pkg/crypto/des"
+	_ "github.com/goplus/gossa/pkg/crypto/dsa"
+	_ "github.com/goplus/gossa/pkg/crypto/ecdsa"
+	_ "github.com/goplus/gossa/pkg/crypto/ed25519"
+	_ "github.com/goplus/gossa/pkg/crypto/elliptic"
+	_ "github.com/goplus/gossa/pkg/crypto/hmac"
+	_ "github.com/goplus/gossa/pkg/crypto/md5"
+	_ "github.com/goplus/gossa/pkg/crypto/rand"
+	_ "github.com/goplus/gossa/pkg/crypto/rc4"
+	_ "github.com/goplus/gossa/pkg/crypto/rsa"
+	_ "github.com/goplus/gossa/pkg/crypto/sha1"
+	_ "github.com/goplus/gossa/pkg/crypto/sha256"
+	_ "github.com/goplus/gossa/pkg/crypto/sha512"
+	_ "github.com/goplus/gossa/pkg/crypto/subtle"
+	_ "github.com/goplus/gossa/pkg/crypto/x509"
+	_ "github.com/goplus/gossa/pkg/crypto/x509/pkix"
+)
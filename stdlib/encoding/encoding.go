@@ -0,0 +1,23 @@
+// Package encoding registers the standard encoding and hash packages, see
+// package gossa/stdlib/minimal.
+package encoding
+
+import (
+	_ "github.com/goplus/gossa/pkg/encoding"
+	_ "github.com/goplus/gossa/pkg/encoding/asn1"
+	_ "github.com/goplus/gossa/pkg/encoding/base32"
+	_ "github.com/goplus/gossa/pkg/encoding/base64"
+	_ "github.com/goplus/gossa/pkg/encoding/binary"
+	_ "github.com/goplus/gossa/pkg/encoding/csv"
+	_ "github.com/goplus/gossa/pkg/encoding/gob"
+	_ "github.com/goplus/gossa/pkg/encoding/hex"
+	_ "github.com/goplus/gossa/pkg/encoding/json"
+	_ "github.com/goplus/gossa/pkg/encoding/pem"
+	_ "github.com/goplus/gossa/pkg/encoding/xml"
+	_ "github.com/goplus/gossa/pkg/hash"
+	_ "github.com/goplus/gossa/pkg/hash/adler32"
+	_ "github.com/goplus/gossa/pkg/hash/crc32"
+	_ "github.com/goplus/gossa/pkg/hash/crc64"
+	_ "github.com/goplus/gossa/pkg/hash/fnv"
+	_ "github.com/goplus/gossa/pkg/hash/maphash"
+)
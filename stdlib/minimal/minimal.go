@@ -0,0 +1,41 @@
+// Package minimal registers a small set of standard packages, without file,
+// process or network access, for embedders which do not link the whole
+// gossa/pkg tree:
+//
+//	import (
+//		"github.com/goplus/gossa"
+//		_ "github.com/goplus/gossa/stdlib/minimal"
+//	)
+//
+// The other packages of gossa/stdlib register further sets, gossa.PackageList
+// lists the packages compiled in. Scripts importing other packages fail to
+// compile.
+package minimal
+
+import (
+	_ "github.com/goplus/gossa/pkg/bufio"
+	_ "github.com/goplus/gossa/pkg/bytes"
+	_ "github.com/goplus/gossa/pkg/container/heap"
+	_ "github.com/goplus/gossa/pkg/container/list"
+	_ "github.com/goplus/gossa/pkg/container/ring"
+	_ "github.com/goplus/gossa/pkg/context"
+	_ "github.com/goplus/gossa/pkg/errors"
+	_ "github.com/goplus/gossa/pkg/fmt"
+	_ "github.com/goplus/gossa/pkg/io"
+	_ "github.com/goplus/gossa/pkg/math"
+	_ "github.com/goplus/gossa/pkg/math/big"
+	_ "github.com/goplus/gossa/pkg/math/bits"
+	_ "github.com/goplus/gossa/pkg/math/cmplx"
+	_ "github.com/goplus/gossa/pkg/math/rand"
+	_ "github.com/goplus/gossa/pkg/regexp"
+	_ "github.com/goplus/gossa/pkg/sort"
+	_ "github.com/goplus/gossa/pkg/strconv"
+	_ "github.com/goplus/gossa/pkg/strings"
+	_ "github.com/goplus/gossa/pkg/sync"
+	_ "github.com/goplus/gossa/pkg/sync/atomic"
+	_ "github.com/goplus/gossa/pkg/text/tabwriter"
+	_ "github.com/goplus/gossa/pkg/time"
+	_ "github.com/goplus/gossa/pkg/unicode"
+	_ "github.com/goplus/gossa/pkg/unicode/utf16"
+	_ "github.com/goplus/gossa/pkg/unicode/utf8"
+)
@@ -0,0 +1,9 @@
+//go:build go1.21
+// +build go1.21
+
+package minimal
+
+import (
+	_ "github.com/goplus/gossa/pkg/cmp"
+	_ "github.com/goplus/gossa/pkg/slices"
+)
@@ -0,0 +1,50 @@
+package minimal_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/goplus/gossa"
+	_ "github.com/goplus/gossa/stdlib/minimal"
+)
+
+func TestMinimal(t *testing.T) {
+	list := gossa.PackageList()
+	if !sort.StringsAreSorted(list) {
+		t.Fatalf("unsorted list %v", list)
+	}
+	has := func(path string) bool {
+		i := sort.SearchStrings(list, path)
+		return i < len(list) && list[i] == path
+	}
+	if !has("fmt") || !has("strings") || has("os") || has("net/http") || has("text/template") {
+		t.Fatalf("bad package list %v", list)
+	}
+	src := `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func main() {
+	if s := fmt.Sprint(strings.Repeat("a", 3)); s != "aaa" {
+		panic(s)
+	}
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	src = `package main
+
+import "os"
+
+func main() {
+	os.Exit(1)
+}
+`
+	if _, err := gossa.RunFile("main.go", src, nil, 0); err == nil {
+		t.Fatal("os imported")
+	}
+}
@@ -0,0 +1,19 @@
+// Package net registers the standard network packages, see package
+// gossa/stdlib/minimal. Scripts importing them can open connections and
+// listen, see Context.SetDenylist to restrict them.
+package net
+
+import (
+	_ "github.com/goplus/gossa/pkg/crypto/tls"
+	_ "github.com/goplus/gossa/pkg/mime"
+	_ "github.com/goplus/gossa/pkg/mime/multipart"
+	_ "github.com/goplus/gossa/pkg/net"
+	_ "github.com/goplus/gossa/pkg/net/http"
+	_ "github.com/goplus/gossa/pkg/net/http/cookiejar"
+	_ "github.com/goplus/gossa/pkg/net/http/httptest"
+	_ "github.com/goplus/gossa/pkg/net/mail"
+	_ "github.com/goplus/gossa/pkg/net/rpc"
+	_ "github.com/goplus/gossa/pkg/net/smtp"
+	_ "github.com/goplus/gossa/pkg/net/textproto"
+	_ "github.com/goplus/gossa/pkg/net/url"
+)
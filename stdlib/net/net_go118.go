@@ -0,0 +1,8 @@
+//go:build go1.18
+// +build go1.18
+
+package net
+
+import (
+	_ "github.com/goplus/gossa/pkg/net/netip"
+)
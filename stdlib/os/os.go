@@ -0,0 +1,17 @@
+// Package os registers the standard packages accessing files, processes
+// and the environment, see package gossa/stdlib/minimal. text/template is
+// registered here as its ParseFiles and ParseGlob read files.
+package os
+
+import (
+	_ "github.com/goplus/gossa/pkg/flag"
+	_ "github.com/goplus/gossa/pkg/io/ioutil"
+	_ "github.com/goplus/gossa/pkg/log"
+	_ "github.com/goplus/gossa/pkg/os"
+	_ "github.com/goplus/gossa/pkg/os/exec"
+	_ "github.com/goplus/gossa/pkg/os/signal"
+	_ "github.com/goplus/gossa/pkg/os/user"
+	_ "github.com/goplus/gossa/pkg/path"
+	_ "github.com/goplus/gossa/pkg/path/filepath"
+	_ "github.com/goplus/gossa/pkg/text/template"
+)
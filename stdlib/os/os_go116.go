@@ -0,0 +1,9 @@
+//go:build go1.16
+// +build go1.16
+
+package os
+
+import (
+	_ "github.com/goplus/gossa/pkg/embed"
+	_ "github.com/goplus/gossa/pkg/io/fs"
+)
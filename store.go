@@ -0,0 +1,215 @@
+package gossa
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StorePkgPath is the import path of the script key/value store package.
+// Scripts keep state between runs in the Store of their Interp:
+//
+//	import "gossa/store"
+//
+//	data, ok, err := store.Get("counter")
+//	err = store.Set("counter", data)
+const StorePkgPath = "gossa/store"
+
+// ErrNoStore is returned by the gossa/store functions of scripts run
+// outside an interpreter, where no store is bound.
+var ErrNoStore = errors.New("gossa/store: no store")
+
+// Store is the host backend of the gossa/store package.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	Get(key string) (value []byte, ok bool, err error)
+	Set(key string, value []byte) error
+	Delete(key string) error
+	Keys(prefix string) ([]string, error) // sorted keys with prefix
+}
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// NewMemStore returns an empty in-memory Store.
+func NewMemStore() Store {
+	return &memStore{data: make(map[string][]byte)}
+}
+
+func (s *memStore) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), v...), true, nil
+}
+
+func (s *memStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	s.data[key] = append([]byte(nil), value...)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.data, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) Keys(prefix string) ([]string, error) {
+	s.mu.RLock()
+	var keys []string
+	for k := range s.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// fileStore is a memStore saved to a JSON file on each change.
+type fileStore struct {
+	memStore
+	filename string
+}
+
+// NewFileStore returns a Store persisted in the JSON file filename,
+// loading its content if the file exists.
+func NewFileStore(filename string) (Store, error) {
+	s := &fileStore{memStore: memStore{data: make(map[string][]byte)}, filename: filename}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = append([]byte(nil), value...)
+	return s.save()
+}
+
+func (s *fileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; !ok {
+		return nil
+	}
+	delete(s.data, key)
+	return s.save()
+}
+
+// save writes the store to a temporary file renamed to filename.
+func (s *fileStore) save() error {
+	data, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(s.filename), filepath.Base(s.filename)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.filename)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// SetStore sets the backend of the gossa/store package for interpreters
+// created by the context. If nil, each interpreter has its own in-memory
+// store.
+func (c *Context) SetStore(s Store) {
+	c.store = s
+}
+
+// SetStore sets the backend of the gossa/store package for the interpreter,
+// overriding the context store.
+func (i *Interp) SetStore(s Store) {
+	i.store = s
+}
+
+func (i *Interp) getStore() Store {
+	if i.store != nil {
+		return i.store
+	}
+	if i.ctx.store != nil {
+		return i.ctx.store
+	}
+	i.memOnce.Do(func() {
+		i.memStore = NewMemStore()
+	})
+	return i.memStore
+}
+
+// findStoreFunc returns the gossa/store function name bound to the interp store.
+func findStoreFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	switch name {
+	case "Get":
+		return reflect.ValueOf(func(key string) ([]byte, bool, error) {
+			return interp.getStore().Get(key)
+		}), true
+	case "Set":
+		return reflect.ValueOf(func(key string, value []byte) error {
+			return interp.getStore().Set(key, value)
+		}), true
+	case "Delete":
+		return reflect.ValueOf(func(key string) error {
+			return interp.getStore().Delete(key)
+		}), true
+	case "Keys":
+		return reflect.ValueOf(func(prefix string) ([]string, error) {
+			return interp.getStore().Keys(prefix)
+		}), true
+	}
+	return
+}
+
+func init() {
+	RegisterPackage(&Package{
+		Name:       "store",
+		Path:       StorePkgPath,
+		Deps:       map[string]string{},
+		Interfaces: map[string]reflect.Type{},
+		NamedTypes: map[string]NamedType{},
+		AliasTypes: map[string]reflect.Type{},
+		Vars:       map[string]reflect.Value{},
+		Funcs: map[string]reflect.Value{
+			"Get":    reflect.ValueOf(func(key string) ([]byte, bool, error) { return nil, false, ErrNoStore }),
+			"Set":    reflect.ValueOf(func(key string, value []byte) error { return ErrNoStore }),
+			"Delete": reflect.ValueOf(func(key string) error { return ErrNoStore }),
+			"Keys":   reflect.ValueOf(func(prefix string) ([]string, error) { return nil, ErrNoStore }),
+		},
+		TypedConsts:   map[string]TypedConst{},
+		UntypedConsts: map[string]UntypedConst{},
+	})
+}
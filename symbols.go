@@ -0,0 +1,53 @@
+package gossa
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// SymbolInfo describes an exported package level symbol of an interpreted
+// package, see Interp.Symbols.
+type SymbolInfo struct {
+	Name  string         // symbol name
+	Kind  string         // "func", "var", "const" or "type"
+	Type  types.Type     // symbol type, the default type of untyped consts
+	RType reflect.Type   // interpreter type of Type
+	Pos   token.Position // declaration position
+}
+
+// Symbols returns the exported functions, variables, constants and types
+// of the interpreted package pkgPath sorted by name, or nil if it is not
+// a package of the program, eg. to list the entry points for RunFuncIn.
+func (i *Interp) Symbols(pkgPath string) []SymbolInfo {
+	pkg, ok := i.lookupPackage(pkgPath)
+	if !ok {
+		return nil
+	}
+	var syms []SymbolInfo
+	for name, m := range pkg.Members {
+		if !token.IsExported(name) {
+			continue
+		}
+		sym := SymbolInfo{Name: name, Pos: i.fset.Position(m.Pos())}
+		switch m := m.(type) {
+		case *ssa.Function:
+			sym.Kind, sym.Type = "func", m.Signature
+		case *ssa.Global:
+			sym.Kind, sym.Type = "var", deref(m.Type())
+		case *ssa.NamedConst:
+			sym.Kind, sym.Type = "const", types.Default(m.Type())
+		case *ssa.Type:
+			sym.Kind, sym.Type = "type", m.Type()
+		}
+		sym.RType = i.toType(sym.Type)
+		syms = append(syms, sym)
+	}
+	sort.Slice(syms, func(i, j int) bool {
+		return syms[i].Name < syms[j].Name
+	})
+	return syms
+}
@@ -0,0 +1,96 @@
+package main
+
+// Tests of complex arithmetic, which must match the gc runtime, including
+// divisions whose naive formula overflows or underflows.
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+type C complex128
+type C64 complex64
+type F float32
+
+func assert(got, want interface{}) {
+	if g, w := fmt.Sprint(got), fmt.Sprint(want); g != w {
+		panic(fmt.Sprintf("got %v, want %v", g, w))
+	}
+}
+
+var inf = math.Inf(1)
+var nan = math.NaN()
+
+func quo(x, y complex128) complex128 {
+	return x / y
+}
+
+// Division.
+func init() {
+	assert(quo(1+2i, 3-4i), complex(-0.2, 0.4))
+	assert(quo(1, 1i), 0-1i)
+	// |y|² overflows in the naive formula
+	assert(quo(complex(1e300, 1e300), complex(1e300, 1e300)), 1+0i)
+	assert(quo(complex(1e308, 1e308), complex(5e307, 1e308)), complex(1.2, -0.4))
+	// |y|² underflows in the naive formula
+	assert(quo(complex(1e-300, 1e-300), complex(1e-300, 1e-300)), 1+0i)
+	// division by zero and infinities do not panic
+	assert(quo(1, 0), complex(inf, nan))
+	assert(quo(0, 0), complex(nan, nan))
+	assert(quo(1, complex(inf, 0)), 0+0i)
+	assert(quo(complex(inf, 0), 1+1i), complex(inf, -inf))
+	assert(cmplx.IsNaN(quo(complex(nan, 0), 1)), true)
+}
+
+// Named and complex64 types.
+func init() {
+	x, y := C(1+2i), C(3-4i)
+	assert(x/y, C(complex(-0.2, 0.4)))
+	assert(x*y, C(11+2i))
+	x /= C(1 + 1i)
+	x *= 2
+	assert(x, C(3+1i))
+	assert(-x, C(-3-1i))
+	// complex64 divides as complex128, rounding the result once
+	a, b := complex64(1+2i), complex64(3-4i)
+	assert(a/b, complex64(complex(-0.2, 0.4)))
+	assert(C64(a)/C64(b), C64(complex(-0.2, 0.4)))
+	assert(C64(complex(1e30, 1e30))/C64(complex(1e30, 1e30)), C64(1))
+	assert(quo(complex128(C64(1e-30+1e-30i)), 1e-30+1e-30i) != 1, true)
+	f := F(1.5)
+	assert(complex(f, f), complex64(1.5+1.5i))
+	assert(fmt.Sprintf("%T %T", real(x), complex(f, 2)), "float64 complex64")
+	assert(real(C64(a)), float32(1))
+	assert(imag(x), 1.0)
+}
+
+// Comparison.
+func init() {
+	x := complex(nan, 0)
+	assert(x == x, false)
+	assert(x != x, true)
+	assert(C(1+1i) == C(1+1i), true)
+	var z interface{} = 1 + 1i
+	assert(z == interface{}(1+1i), true)
+	m := map[complex128]int{1i: 1, 2i: 2}
+	m[1i]++
+	assert(m[1i], 2)
+}
+
+// math/cmplx.
+func init() {
+	assert(cmplx.Abs(3+4i), 5.0)
+	assert(cmplx.Sqrt(-1), 1i)
+	assert(cmplx.Conj(1+2i), 1-2i)
+	assert(cmplx.Phase(-1), math.Pi)
+	assert(cmplx.IsInf(complex(inf, 0)), true)
+	assert(cmplx.Abs(cmplx.Exp(complex(0, math.Pi))+1) < 1e-15, true)
+	r, θ := cmplx.Polar(2i)
+	assert(cmplx.Abs(cmplx.Rect(r, θ)-2i) < 1e-15, true)
+	assert(cmplx.Pow(0, 0), 1+0i)
+	assert(cmplx.Inf(), complex(inf, inf))
+}
+
+func main() {
+}
@@ -6,11 +6,15 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/types"
+	"io"
 	"log"
 	"os"
+	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"golang.org/x/tools/go/ssa"
 )
@@ -116,6 +120,70 @@ func CreateTestMainPackage(pkg *ssa.Package) (*ssa.Package, error) {
 	return testmain, nil
 }
 
+// testDeps implements the deps of testing.MainStart for the testmain of
+// Go 1.18 and later, set to its deps variable by NewInterp. The deps are
+// host values, as testing calls methods like InitRuntimeCoverage whose
+// results reflectx can not return from interpreted methods.
+type testDeps struct {
+	matchPat string
+	matchRe  *regexp.Regexp
+}
+
+type corpusEntry = struct {
+	Parent     string
+	Path       string
+	Data       []byte
+	Values     []interface{}
+	Generation int
+	IsSeed     bool
+}
+
+func (*testDeps) ImportPath() string                          { return "" }
+func (*testDeps) ModulePath() string                          { return "" }
+func (*testDeps) SetPanicOnExit0(bool)                        {}
+func (*testDeps) StartCPUProfile(io.Writer) error             { return nil }
+func (*testDeps) StopCPUProfile()                             {}
+func (*testDeps) StartTestLog(io.Writer)                      {}
+func (*testDeps) StopTestLog() error                          { return nil }
+func (*testDeps) WriteHeapProfile(io.Writer) error            { return nil }
+func (*testDeps) WriteProfileTo(string, io.Writer, int) error { return nil }
+
+func (*testDeps) CoordinateFuzzing(time.Duration, int64, time.Duration, int64, int, []corpusEntry, []reflect.Type, string, string) error {
+	return nil
+}
+func (*testDeps) RunFuzzWorker(func(corpusEntry) error) error { return nil }
+func (*testDeps) ReadCorpus(string, []reflect.Type) ([]corpusEntry, error) {
+	return nil, nil
+}
+func (*testDeps) CheckCorpus([]interface{}, []reflect.Type) error { return nil }
+func (*testDeps) ResetCoverage()                                  {}
+func (*testDeps) SnapshotCoverage()                               {}
+func (*testDeps) InitRuntimeCoverage() (mode string, tearDown func(string, string) (string, error), snapcov func() float64) {
+	return
+}
+
+func (d *testDeps) MatchString(pat, str string) (result bool, err error) {
+	if d.matchRe == nil || d.matchPat != pat {
+		d.matchPat = pat
+		d.matchRe, err = regexp.Compile(pat)
+		if err != nil {
+			return
+		}
+	}
+	return d.matchRe.MatchString(str), nil
+}
+
+// setTestDeps sets the deps of the main package of interp if it is a
+// testmain package of CreateTestMainPackage.
+func setTestDeps(interp *Interp) {
+	if fn := interp.mainpkg.Func("main"); fn == nil || fn.Synthetic != "test main function" {
+		return
+	}
+	if p, ok := interp.GetVarAddr("deps"); ok {
+		reflect.ValueOf(p).Elem().Set(reflect.ValueOf(&testDeps{}))
+	}
+}
+
 // An implementation of types.Importer for an already loaded SSA program.
 type testImporter struct {
 	pkg *ssa.Package // package under test; may be non-importable
@@ -202,56 +270,41 @@ package main
 import (
 	"io"
 	"os"
-	"regexp"
 	"testing"
 	"time"
 	"reflect"
 	_test {{printf "%q" .Pkg.Pkg.Path}}
 )
 
-type deps struct{}
-
-func (deps) ImportPath() string { return "" }
-func (deps) SetPanicOnExit0(bool) {}
-func (deps) StartCPUProfile(io.Writer) error { return nil }
-func (deps) StartTestLog(io.Writer) {}
-func (deps) StopCPUProfile() {}
-func (deps) StopTestLog() error { return nil }
-func (deps) WriteHeapProfile(io.Writer) error { return nil }
-func (deps) WriteProfileTo(string, io.Writer, int) error { return nil }
-
-type corpusEntry = struct {
+{{/* testing.corpusEntry, not declared as an alias as go/ssa has no alias types */}}
+{{define "corpusEntry"}}struct {
 	Parent     string
 	Path       string
 	Data       []byte
-	Values     []any
+	Values     []interface{}
 	Generation int
 	IsSeed     bool
-}
-
-func (deps) CoordinateFuzzing(time.Duration, int64, time.Duration, int64, int, []corpusEntry, []reflect.Type, string, string) error {
-	return nil
-}
-func (deps) RunFuzzWorker(func(corpusEntry) error) error { return nil }
-func (deps) ReadCorpus(string, []reflect.Type) ([]corpusEntry, error) {
-	return nil, nil
-}
-func (f deps) CheckCorpus([]any, []reflect.Type) error { return nil }
-func (f deps) ResetCoverage() {}
-func (f deps) SnapshotCoverage() {}
-
-var matchPat string
-var matchRe *regexp.Regexp
-
-func (deps) MatchString(pat, str string) (result bool, err error) {
-	if matchRe == nil || matchPat != pat {
-		matchPat = pat
-		matchRe, err = regexp.Compile(matchPat)
-		if err != nil {
-			return
-		}
-	}
-	return matchRe.MatchString(str), nil
+}{{end}}
+
+// deps is set by the interpreter, see testDeps.
+var deps interface {
+	ImportPath() string
+	ModulePath() string
+	MatchString(pat, str string) (bool, error)
+	SetPanicOnExit0(bool)
+	StartCPUProfile(io.Writer) error
+	StopCPUProfile()
+	StartTestLog(io.Writer)
+	StopTestLog() error
+	WriteHeapProfile(io.Writer) error
+	WriteProfileTo(string, io.Writer, int) error
+	CoordinateFuzzing(time.Duration, int64, time.Duration, int64, int, []{{template "corpusEntry"}}, []reflect.Type, string, string) error
+	RunFuzzWorker(func({{template "corpusEntry"}}) error) error
+	ReadCorpus(string, []reflect.Type) ([]{{template "corpusEntry"}}, error)
+	CheckCorpus([]interface{}, []reflect.Type) error
+	ResetCoverage()
+	SnapshotCoverage()
+	InitRuntimeCoverage() (mode string, tearDown func(string, string) (string, error), snapcov func() float64)
 }
 
 var tests = []testing.InternalTest{
@@ -279,7 +332,7 @@ var examples = []testing.InternalExample{
 }
 
 func main() {
-	m := testing.MainStart(deps{}, tests, benchmarks, fuzzTargets, examples)
+	m := testing.MainStart(deps, tests, benchmarks, fuzzTargets, examples)
 {{with .Main}}
 	_test.{{.Name}}(m)
 {{else}}
@@ -0,0 +1,171 @@
+package gossa
+
+import (
+	"fmt"
+	"go/token"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/petermattis/goid"
+	"golang.org/x/tools/go/ssa"
+)
+
+// timeoutGrace is the time RunFuncTimeout waits after the deadline for the
+// call to stop at a safepoint, before reporting it blocked.
+const timeoutGrace = 10 * time.Millisecond
+
+// TimeoutError is the error of a RunFuncTimeout call exceeding its deadline,
+// or of a program exceeding its step budget.
+type TimeoutError struct {
+	Timeout time.Duration  // deadline of the call
+	Func    *ssa.Function  // interpreted function executing at the deadline
+	Pos     token.Position // position in Func, invalid if Blocked
+	Blocked bool           // blocked in a host call or channel operation
+	Steps   int64          // exceeded step budget, see Context.SetMaxSteps, zero for a timeout
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Steps != 0 {
+		return fmt.Sprintf("step budget %v exceeded: %v at %v", e.Steps, e.Func, e.Pos)
+	}
+	if e.Func == nil {
+		return fmt.Sprintf("timeout after %v", e.Timeout)
+	}
+	if e.Blocked {
+		return fmt.Sprintf("timeout after %v: blocked in %v", e.Timeout, e.Func)
+	}
+	return fmt.Sprintf("timeout after %v: %v at %v", e.Timeout, e.Func, e.Pos)
+}
+
+// deadline is the deadline of a RunFuncTimeout call, shared by its frames,
+// and the step budget of a call of the host, shared by the frames of the
+// goroutines of the call.
+type deadline struct {
+	interp  *Interp
+	timeout time.Duration // zero for a step budget only
+	budget  int64         // step budget, zero for no limit
+	run     *runState     // call of the host stopped when abandoned, or nil
+	steps   *int64        // remaining steps of the budget, atomically updated
+	expired int32         // atomically set at the deadline
+	cur     atomic.Value  // innermost *frame of the call
+	once    sync.Once     // sets err
+	err     *TimeoutError // error of the first safepoint after the deadline
+}
+
+// enter records fr as the innermost frame, at the safepoint of its entry.
+func (d *deadline) enter(fr *frame) {
+	d.check(fr)
+	d.cur.Store(fr)
+}
+
+// leave records the caller of the returning frame fr as the innermost frame.
+func (d *deadline) leave(fr *frame) {
+	d.cur.Store(fr.caller)
+}
+
+// check stops the call at a safepoint of fr after the deadline. The
+// timeout unwinds the call, it is not recoverable by the target program.
+// An abandoned call of the host unwinds like os.Exit.
+func (d *deadline) check(fr *frame) {
+	if d.run != nil && atomic.LoadInt32(&d.run.abandoned) != 0 {
+		panic(exitPanic(d.run.exitCode))
+	}
+	exceeded := d.budget != 0 && atomic.AddInt64(d.steps, -1) < 0
+	if !exceeded && atomic.LoadInt32(&d.expired) == 0 {
+		return
+	}
+	d.once.Do(func() {
+		pos := fr.pfn.Fn.Pos()
+		if fr.pc > 0 {
+			pos = fr.pfn.PosForPC(fr.pc - 1)
+		}
+		d.err = &TimeoutError{
+			Timeout: d.timeout,
+			Func:    fr.pfn.Fn,
+			Pos:     d.interp.fset.Position(pos),
+		}
+		if exceeded {
+			d.err.Steps = d.budget
+		}
+	})
+	// deferred calls are stopped by the same error
+	panic(d.err)
+}
+
+// blocked returns the error of a call not stopped at a safepoint.
+func (d *deadline) blocked() *TimeoutError {
+	e := &TimeoutError{Timeout: d.timeout, Blocked: true}
+	if fr, _ := d.cur.Load().(*frame); fr != nil {
+		e.Func = fr.pfn.Fn
+	}
+	return e
+}
+
+// goroutineDeadline returns the deadline of the caller goroutine, the
+// deadline of its RunFuncTimeout call or the step budget of its call, or
+// nil. Goroutines started by a RunFuncTimeout call have its step budget
+// only.
+func (i *Interp) goroutineDeadline() *deadline {
+	if atomic.LoadInt32(&i.timed) != 0 {
+		if v, ok := i.deadlines.Load(goid.Get()); ok {
+			return v.(*deadline)
+		}
+	}
+	return nil
+}
+
+// enterDeadline records dl as the deadline of the caller goroutine, the
+// returned func removes it.
+func (i *Interp) enterDeadline(dl *deadline) func() {
+	id := goid.Get()
+	i.deadlines.Store(id, dl)
+	atomic.AddInt32(&i.timed, 1)
+	return func() {
+		atomic.AddInt32(&i.timed, -1)
+		i.deadlines.Delete(id)
+	}
+}
+
+// SetMaxSteps limits the steps of each call of the interpreters created by
+// the context to n: the interpreted calls and loop iterations of all the
+// goroutines of the package initializers, of a Run or of a RunFunc call.
+// The step exceeding the budget panics with a TimeoutError, which the
+// program cannot recover, like the deadline of RunFuncTimeout, which is
+// combined with the budget. Zero is no limit.
+func (c *Context) SetMaxSteps(n int64) {
+	c.maxSteps = n
+}
+
+// RunFuncTimeout is RunFunc with a hard deadline of d. The call is stopped
+// at the next call or loop iteration after the deadline, or abandoned if
+// it is blocked in a host call or a channel operation, and a TimeoutError
+// reports the interpreted function executing at the deadline. Goroutines
+// started by the call are not stopped.
+func (i *Interp) RunFuncTimeout(d time.Duration, name string, args ...Value) (Value, error) {
+	dl := &deadline{interp: i, timeout: d}
+	type result struct {
+		r   Value
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		r, err := i.runFuncIn(dl, i.mainpkg.Pkg.Path(), name, args)
+		done <- result{r, err}
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case res := <-done:
+		return res.r, res.err
+	case <-timer.C:
+	}
+	atomic.StoreInt32(&dl.expired, 1)
+	timer.Reset(timeoutGrace)
+	select {
+	case res := <-done:
+		return res.r, res.err
+	case <-timer.C:
+		return nil, dl.blocked()
+	}
+}
@@ -0,0 +1,95 @@
+package gossa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/petermattis/goid"
+)
+
+// tracer writes begin/end events of interpreted calls in the Chrome
+// trace event format, viewable by about://tracing or ui.perfetto.dev.
+type tracer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	names   map[*Function]string // json encoded function name and args
+	started bool                 // array opened
+	err     error
+}
+
+// SetTracer sets w as the trace output of interpreted calls in the Chrome
+// trace event JSON array format, a nil w stops tracing. The array is not
+// closed, as permitted by the format. SetTracer should be called before
+// running the interpreter.
+func (i *Interp) SetTracer(w io.Writer) {
+	if w == nil {
+		i.tracer = nil
+		return
+	}
+	i.tracer = &tracer{
+		w:     w,
+		start: time.Now(),
+		names: make(map[*Function]string),
+	}
+}
+
+func (t *tracer) event(ph byte, fr *frame) {
+	ts := float64(time.Since(t.start).Nanoseconds()) / 1e3
+	gid := goid.Get()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	sep := t.sep()
+	name, ok := t.names[fr.pfn]
+	if !ok {
+		name = t.name(fr.pfn)
+		t.names[fr.pfn] = name
+	}
+	_, t.err = fmt.Fprintf(t.w, "%v\n{\"name\":%v,\"cat\":\"gossa\",\"ph\":\"%c\",\"ts\":%.3f,\"pid\":1,\"tid\":%v}",
+		sep, name, ph, ts, gid)
+}
+
+// threadName writes the thread name metadata event of goroutine id.
+func (t *tracer) threadName(id int64, name string) {
+	data, _ := json.Marshal(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, "%v\n{\"name\":\"thread_name\",\"ph\":\"M\",\"pid\":1,\"tid\":%v,\"args\":{\"name\":%s}}",
+		t.sep(), id, data)
+}
+
+func (t *tracer) name(pfn *Function) string {
+	data, _ := json.Marshal(pfn.Fn.String())
+	pos := pfn.Interp.fset.Position(pfn.Fn.Pos())
+	if !pos.IsValid() {
+		return string(data)
+	}
+	args, _ := json.Marshal(pos.String())
+	return fmt.Sprintf("%s,\"args\":{\"pos\":%s}", data, args)
+}
+
+// sep returns the separator before next event, t.mu must be held.
+func (t *tracer) sep() string {
+	if t.started {
+		return ","
+	}
+	t.started = true
+	return "["
+}
+
+func (t *tracer) begin(fr *frame) {
+	t.event('B', fr)
+}
+
+func (t *tracer) end(fr *frame) {
+	t.event('E', fr)
+}
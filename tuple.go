@@ -0,0 +1,60 @@
+package gossa
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A RunFunc call of a function with several results returns them as a
+// Tuple, in the order of the results of the function:
+//
+//	r, err := interp.RunFunc("Div", 7, 2)
+//	if err != nil {
+//		return err
+//	}
+//	var q, m int
+//	if err := r.(gossa.Tuple).Scan(&q, &m, nil); err != nil {
+//		return err
+//	}
+
+// Len returns the number of results of t.
+func (t tuple) Len() int {
+	return len(t)
+}
+
+// Get returns the i-th result of t. It panics if i is out of range.
+func (t tuple) Get(i int) Value {
+	return t[i]
+}
+
+// Err returns the last result of t if it is a non-nil error, eg. the error
+// result of a function returning (T, error), or nil.
+func (t tuple) Err() error {
+	if len(t) == 0 {
+		return nil
+	}
+	err, _ := t[len(t)-1].(error)
+	return err
+}
+
+// Scan stores the results of t in the values pointed to by ptrs, one per
+// result. A nil ptr skips its result. Results are converted like Unmarshal,
+// eg. a struct of the script is stored in a host struct.
+func (t tuple) Scan(ptrs ...interface{}) error {
+	if len(ptrs) != len(t) {
+		return fmt.Errorf("scan: %v results into %v values", len(t), len(ptrs))
+	}
+	for n, ptr := range ptrs {
+		if ptr == nil {
+			continue
+		}
+		rv := reflect.ValueOf(ptr)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return fmt.Errorf("scan: non-pointer %T for result %v", ptr, n)
+		}
+		if err := marshalValue(rv.Elem(), reflect.ValueOf(t[n]), true, ""); err != nil {
+			return fmt.Errorf("scan: result %v: %w", n, err)
+		}
+	}
+	return nil
+}
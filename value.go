@@ -32,6 +32,13 @@ package gossa
 //
 // Pay close attention to whether or not the dynamic type is a pointer.
 // The compiler cannot help you since value is an empty interface.
+//
+// Struct and array values are copied when boxed and never changed in
+// place: Store copies them into the variable of its address by SetValue,
+// and Load, Field and Index box copies or the immutable parts of their
+// operand. So registers, phis and parameters holding the same value never
+// alias a variable, and reflect.Value results must be boxed by Interface
+// before they are stored in a register.
 
 import (
 	"bytes"
@@ -39,6 +46,7 @@ import (
 	"go/types"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"unsafe"
 
@@ -71,13 +79,16 @@ type closure struct {
 
 type bad struct{}
 
-// Prints in the style of built-in println.
-// (More or less; in gc println is actually a compiler intrinsic and
-// can distinguish println(1) from println(interface{}(1)).)
+// Prints in the style of built-in println, by the rules of the gc runtime
+// for the kind of v: numbers in decimal whatever their String method,
+// pointers, maps, channels and funcs as hex addresses and slices as
+// [len/cap]address.
+// (In gc println is actually a compiler intrinsic and can distinguish
+// println(1) from println(interface{}(1)), see toInterface.)
 func writeValue(buf *bytes.Buffer, v value) {
 	switch v := v.(type) {
-	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, float32, float64, complex64, complex128, string:
-		fmt.Fprintf(buf, "%v", v)
+	case nil:
+		buf.WriteString("0x0")
 
 	case *ssa.Function, *ssa.Builtin, *closure:
 		fmt.Fprintf(buf, "%p", v) // (an address)
@@ -96,16 +107,28 @@ func writeValue(buf *bytes.Buffer, v value) {
 	default:
 		i := reflect.ValueOf(v)
 		switch i.Kind() {
+		case reflect.Bool:
+			buf.WriteString(strconv.FormatBool(i.Bool()))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			buf.WriteString(strconv.FormatInt(i.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			buf.WriteString(strconv.FormatUint(i.Uint(), 10))
+		case reflect.Float32:
+			writeFloat(buf, i.Float(), 32)
+		case reflect.Float64:
+			writeFloat(buf, i.Float(), 64)
+		case reflect.Complex64:
+			writeComplex(buf, i.Complex(), 64)
+		case reflect.Complex128:
+			writeComplex(buf, i.Complex(), 128)
+		case reflect.String:
+			buf.WriteString(i.String())
 		case reflect.Map, reflect.Ptr, reflect.Func, reflect.Chan, reflect.UnsafePointer:
-			fmt.Fprintf(buf, "%p", v)
+			fmt.Fprintf(buf, "%#x", i.Pointer())
 		case reflect.Slice:
-			fmt.Fprintf(buf, "[%v/%v]%p", i.Len(), i.Cap(), v)
-		case reflect.String:
-			fmt.Fprintf(buf, "%v", v)
-		case reflect.Struct, reflect.Array:
-			panic(fmt.Errorf("illegal types for operand: print %T", v))
+			fmt.Fprintf(buf, "[%v/%v]%#x", i.Len(), i.Cap(), i.Pointer())
 		default:
-			fmt.Fprintf(buf, "%v", v)
+			panic(fmt.Errorf("illegal types for operand: print %T", v))
 		}
 	}
 }
@@ -149,6 +172,47 @@ func (it *stringIter) next() tuple {
 	return okv
 }
 
+// intIter iterates the values 0 to n-1 of the integer type typ of a
+// range over an integer (Go 1.22).
+type intIter struct {
+	typ  reflect.Type
+	i, n uint64
+}
+
+func newIntIter(v reflect.Value) *intIter {
+	it := &intIter{typ: v.Type()}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n > 0 {
+			it.n = uint64(n)
+		}
+	default:
+		it.n = v.Uint()
+	}
+	return it
+}
+
+func (it *intIter) next() tuple {
+	if it.i >= it.n {
+		return []value{false, nil, nil}
+	}
+	var k value
+	if it.typ == tyInt {
+		k = int(it.i)
+	} else {
+		v := reflect.New(it.typ).Elem()
+		switch it.typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(int64(it.i))
+		default:
+			v.SetUint(it.i)
+		}
+		k = v.Interface()
+	}
+	it.i++
+	return []value{true, k, nil}
+}
+
 type mapIter struct {
 	iter *reflect.MapIter
 	ok   bool
@@ -0,0 +1,103 @@
+package gossa
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Verify reports the constructs of the program of mainpkg the interpreter
+// cannot execute with the packages of loader, without running or compiling
+// it: unsupported instructions, missing externals, unregistered imports,
+// cgo and unsafe uses. It walks the functions reachable like NewInterp.
+func Verify(mainpkg *ssa.Package, loader Loader) []Diagnostic {
+	prog := mainpkg.Prog
+	ctx := NewContext(0)
+	ctx.Loader = loader
+	visit := visitor{
+		intp:   &Interp{ctx: ctx, loader: loader, fset: prog.Fset, prog: prog},
+		prog:   prog,
+		pkgs:   make(map[*ssa.Package]bool),
+		seen:   make(map[*ssa.Function]bool),
+		verify: true,
+	}
+	for _, pkg := range prog.AllPackages() {
+		if init := pkg.Func("init"); init != nil && init.Blocks != nil {
+			visit.pkgs[pkg] = true
+		}
+	}
+	imports := make(map[string]bool)
+	for pkg := range visit.pkgs {
+		for _, imp := range pkg.Pkg.Imports() {
+			path := imp.Path()
+			if imports[path] {
+				continue
+			}
+			imports[path] = true
+			if path == "C" {
+				visit.report(DiagCgo, token.NoPos, "cgo is not supported")
+				continue
+			}
+			if path == "unsafe" {
+				continue
+			}
+			if p := prog.ImportedPackage(path); p != nil && visit.pkgs[p] {
+				continue
+			}
+			if _, ok := loader.Installed(path); ok {
+				continue
+			}
+			if _, ok := externPackage(path); ok {
+				continue
+			}
+			visit.report(DiagImport, token.NoPos, "unregistered import %q", path)
+		}
+	}
+	visit.program()
+	diags := visit.diags
+	for _, d := range ctx.Diagnose(mainpkg) {
+		switch d.Kind {
+		case DiagExtern, DiagNoBody:
+			// reported by the visitor with function values and globals
+		default:
+			diags = append(diags, d)
+		}
+	}
+	sortDiagnostics(diags)
+	if len(diags) == 0 {
+		return nil
+	}
+	verified := make([]Diagnostic, len(diags))
+	for n, d := range diags {
+		verified[n] = *d
+	}
+	return verified
+}
+
+// typesPkgPath returns the package path of the named type T or *T.
+func typesPkgPath(T types.Type) string {
+	if p, ok := T.(*types.Pointer); ok {
+		T = p.Elem()
+	}
+	if named, ok := T.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+// isSupportedInstr reports whether makeInstr compiles instr.
+func isSupportedInstr(instr ssa.Instruction) bool {
+	switch instr.(type) {
+	case *ssa.Alloc, *ssa.Phi, *ssa.Call, *ssa.BinOp, *ssa.UnOp,
+		*ssa.ChangeInterface, *ssa.ChangeType, *ssa.Convert, *ssa.MakeInterface,
+		*ssa.MakeClosure, *ssa.MakeChan, *ssa.MakeMap, *ssa.MakeSlice,
+		*ssa.Slice, *ssa.FieldAddr, *ssa.Field, *ssa.IndexAddr, *ssa.Index,
+		*ssa.Lookup, *ssa.Select, *ssa.SliceToArrayPointer, *ssa.Range,
+		*ssa.Next, *ssa.TypeAssert, *ssa.Extract, *ssa.Jump, *ssa.If,
+		*ssa.Return, *ssa.RunDefers, *ssa.Panic, *ssa.Go, *ssa.Defer,
+		*ssa.Send, *ssa.Store, *ssa.MapUpdate, *ssa.DebugRef:
+		return true
+	}
+	return false
+}
@@ -2,8 +2,10 @@ package gossa
 
 import (
 	"fmt"
-	"log"
+	"go/token"
 	"reflect"
+	"runtime"
+	"sync"
 
 	"golang.org/x/tools/go/ssa"
 )
@@ -14,6 +16,16 @@ func checkPackages(intp *Interp, pkgs []*ssa.Package) (err error) {
 			err = v.(error)
 		}
 	}()
+	if intp.mode&DisableUnsafe != 0 {
+		if diags := deniedDiagnostics(intp.prog, pkgs, intp.ctx.denied()); len(diags) > 0 {
+			if intp.ctx.diagFunc != nil {
+				for _, d := range diags {
+					intp.ctx.diagFunc(d)
+				}
+			}
+			return DeniedError(diags)
+		}
+	}
 	visit := visitor{
 		intp: intp,
 		prog: intp.prog,
@@ -24,14 +36,72 @@ func checkPackages(intp *Interp, pkgs []*ssa.Package) (err error) {
 		visit.pkgs[pkg] = true
 	}
 	visit.program()
-	return
+	if len(visit.missing) > 0 {
+		sortMissing(visit.missing)
+		return visit.missing
+	}
+	return visit.compile(runtime.GOMAXPROCS(0))
 }
 
 type visitor struct {
-	intp *Interp
-	prog *ssa.Program
-	pkgs map[*ssa.Package]bool
-	seen map[*ssa.Function]bool
+	intp  *Interp
+	prog  *ssa.Program
+	pkgs  map[*ssa.Package]bool
+	seen  map[*ssa.Function]bool
+	queue []*Function // functions to compile, in visit order
+	// external symbols without implementation
+	missing MissingSymbolsError
+	globals map[*ssa.Global]bool
+	// verify mode of Verify, reports instead of compiling
+	verify bool
+	ref    token.Pos // position of the visited operand
+	diags  []*Diagnostic
+}
+
+// compile compiles the queued functions with n workers. Functions are
+// independent once visited, so each is compiled by a single worker.
+// It returns the error of the first failed function in visit order.
+func (visit *visitor) compile(n int) error {
+	errs := make([]error, len(visit.queue))
+	if n > len(visit.queue) {
+		n = len(visit.queue)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for w := 0; w < n; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = visit.compileFunc(visit.queue[i])
+			}
+		}()
+	}
+	for i := range visit.queue {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (visit *visitor) compileFunc(pfn *Function) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			if e, ok := v.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v: %v", pfn.Fn, v)
+			}
+		}
+	}()
+	visit.body(pfn)
+	return
 }
 
 func (visit *visitor) program() {
@@ -52,9 +122,13 @@ func (visit *visitor) program() {
 		return !chks[typ.PkgPath()]
 	}
 	for _, T := range visit.prog.RuntimeTypes() {
-		typ := visit.intp.preToType(T)
-		// skip extern type
-		if isExtern(typ) {
+		var typ reflect.Type
+		if visit.verify {
+			if !chks[typesPkgPath(T)] {
+				continue
+			}
+		} else if typ = visit.intp.preToType(T); isExtern(typ) {
+			// skip extern type
 			continue
 		}
 		mmap := make(map[string]*ssa.Function)
@@ -62,15 +136,18 @@ func (visit *visitor) program() {
 		for i, n := 0, mset.Len(); i < n; i++ {
 			sel := mset.At(i)
 			obj := sel.Obj()
-			// skip embbed extern type method
-			if !chks[obj.Pkg().Path()] {
+			// methods promoted from embedded extern types run the
+			// wrapper, unexported ones are not reachable by name
+			if !chks[obj.Pkg().Path()] && !obj.Exported() {
 				continue
 			}
 			fn := visit.prog.MethodValue(sel)
 			mmap[obj.Name()] = fn
 			visit.function(fn)
 		}
-		visit.intp.msets[typ] = mmap
+		if !visit.verify {
+			visit.intp.msets[typ] = mmap
+		}
 	}
 }
 
@@ -79,88 +156,186 @@ func (visit *visitor) function(fn *ssa.Function) {
 		return
 	}
 	visit.seen[fn] = true
-	fnPath := fn.String()
-	if f, ok := visit.intp.ctx.override[fnPath]; ok &&
-		visit.intp.preToType(fn.Type()) == f.Type() {
-		fn.Blocks = nil
+	if isGenericFunc(fn) {
+		// the calls are instantiated, missing instances are reported
 		return
 	}
+	fnPath := fn.String()
+	if f, ok := visit.intp.ctx.override[fnPath]; ok {
+		if visit.verify {
+			return
+		}
+		if visit.intp.preToType(fn.Type()) == f.Type() {
+			fn.Blocks = nil
+			return
+		}
+	}
 	if fn.Blocks == nil {
 		if _, ok := visit.pkgs[fn.Pkg]; ok {
 			if _, ok = externValues[fnPath]; !ok {
+				if visit.verify {
+					visit.report(DiagNoBody, fn.Pos(), "missing function body: %v", fn)
+					return
+				}
 				panic(fmt.Errorf("%v: missing function body", visit.intp.fset.Position(fn.Pos())))
 			}
+		} else if fn.Pkg != nil && fn.Name() == "init" {
+			// imports are filtered by the loader
+		} else if visit.filter(fnPath) {
+			if _, ok := findExternFunc(visit.intp, fn); !ok {
+				visit.addMissing(missingFunc(fn))
+			}
 		}
 		return
 	}
-	visit.intp.loadType(fn.Type())
-	for _, alloc := range fn.Locals {
-		visit.intp.loadType(alloc.Type())
-		visit.intp.loadType(deref(alloc.Type()))
+	if !visit.verify {
+		visit.queue = append(visit.queue, visit.intp.loadFunction(fn))
 	}
-	pfn := visit.intp.loadFunction(fn)
+	var buf [32]*ssa.Value // avoid alloc in common case
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if call, ok := instr.(ssa.CallInstruction); ok {
+				if m := instantiate(visit.intp.loader, call); m != nil {
+					visit.ref = instr.Pos()
+					visit.addMissing(m)
+				}
+			}
+			if visit.verify && !isSupportedInstr(instr) {
+				visit.report(DiagUnsupported, instr.Pos(), "unsupported instruction %T in %v", instr, fn)
+			}
+			switch instr.(type) {
+			case *ssa.Next, *ssa.Extract:
+				// skip *ssa.opaqueType: iter
+				continue
+			}
+			for _, op := range instr.Operands(buf[:0]) {
+				switch v := (*op).(type) {
+				case *ssa.Function:
+					visit.ref = instr.Pos()
+					visit.function(v)
+				case *ssa.Global:
+					visit.ref = instr.Pos()
+					visit.global(v)
+				}
+			}
+		}
+	}
+}
+
+// addMissing records the missing symbol m used at the visited operand.
+func (visit *visitor) addMissing(m *ErrMissingSymbol) {
+	visit.missing = append(visit.missing, m)
+	if visit.verify {
+		visit.report(DiagExtern, visit.ref, "%v", m)
+	}
+}
+
+// filter reports whether the extern symbol name used at the visited operand
+// is allowed by the import filter of the loader. A denied symbol fails the
+// check, or is reported in verify mode.
+func (visit *visitor) filter(name string) bool {
+	l, ok := visit.intp.loader.(symbolFilter)
+	if !ok {
+		return true
+	}
+	err := l.filterSymbol(name)
+	if _, ok := err.(RewriteImport); ok || err == nil {
+		return true
+	}
+	if visit.verify {
+		visit.report(DiagDenied, visit.ref, "%v", err)
+		return false
+	}
+	panic(fmt.Errorf("%v: %v", visit.intp.fset.Position(visit.ref), err))
+}
+
+// symbolFilter is a Loader with an import filter.
+type symbolFilter interface {
+	filterSymbol(name string) error
+}
+
+// report records a diagnostic in verify mode.
+func (visit *visitor) report(kind DiagnosticKind, pos token.Pos, format string, args ...interface{}) {
+	visit.diags = append(visit.diags, &Diagnostic{
+		Kind: kind,
+		Pos:  visit.prog.Fset.Position(pos),
+		Msg:  fmt.Sprintf(format, args...),
+	})
+}
+
+// global checks that the external global g is registered.
+func (visit *visitor) global(g *ssa.Global) {
+	if g.Pkg == nil || visit.pkgs[g.Pkg] || visit.globals[g] {
+		return
+	}
+	if visit.globals == nil {
+		visit.globals = make(map[*ssa.Global]bool)
+	}
+	visit.globals[g] = true
+	if !visit.filter(g.String()) {
+		return
+	}
+	if pkg, ok := visit.intp.installed(g.Pkg.Pkg.Path()); ok {
+		if _, ok := pkg.Vars[g.Name()]; ok {
+			return
+		}
+	}
+	visit.addMissing(&ErrMissingSymbol{Pkg: g.Pkg.Pkg.Path(), Name: g.Name(), Kind: "var"})
+}
+
+// body compiles the instructions of pfn.
+func (visit *visitor) body(pfn *Function) {
+	fn := pfn.Fn
 	for _, p := range fn.Params {
 		pfn.regIndex(p)
 	}
 	for _, p := range fn.FreeVars {
 		pfn.regIndex(p)
 	}
-	var buf [32]*ssa.Value // avoid alloc in common case
+	var fold *folding
+	if visit.intp.mode&DisableClosureCompiler != 0 {
+		fold = noFolding(fn)
+	} else {
+		fold = foldFunction(pfn)
+	}
+	lastPos := fn.Pos()
 	for _, b := range fn.Blocks {
 		Instrs := make([]func(*frame), len(b.Instrs), len(b.Instrs))
 		ssaInstrs := make([]ssa.Instruction, len(b.Instrs), len(b.Instrs))
+		info := &BlockInfo{Block: b, Offset: len(pfn.Instrs), Instrs: make([]InstrInfo, len(b.Instrs))}
 		var index int
 		for i := 0; i < len(b.Instrs); i++ {
 			instr := b.Instrs[i]
-			ops := instr.Operands(buf[:0])
-			switch instr := instr.(type) {
-			case *ssa.Alloc:
-				visit.intp.loadType(instr.Type())
-				visit.intp.loadType(deref(instr.Type()))
-			case *ssa.Next:
-				// skip *ssa.opaqueType: iter
-				ops = nil
-			case *ssa.Extract:
-				// skip
-				ops = nil
-			case *ssa.TypeAssert:
-				visit.intp.loadType(instr.AssertedType)
-			case *ssa.MakeChan:
-				visit.intp.loadType(instr.Type())
-			case *ssa.MakeMap:
-				visit.intp.loadType(instr.Type())
-			case *ssa.MakeSlice:
-				visit.intp.loadType(instr.Type())
-			case *ssa.SliceToArrayPointer:
-				visit.intp.loadType(instr.Type())
-			case *ssa.Convert:
-				visit.intp.loadType(instr.Type())
-			case *ssa.ChangeType:
-				visit.intp.loadType(instr.Type())
-			case *ssa.MakeInterface:
-				visit.intp.loadType(instr.Type())
-			}
-			for _, op := range ops {
-				switch v := (*op).(type) {
-				case *ssa.Function:
-					visit.function(v)
-				case nil:
-					// skip
-				default:
-					visit.intp.loadType(v.Type())
-				}
+			if pos := instr.Pos(); pos.IsValid() {
+				lastPos = pos
+			}
+			if reason, ok := fold.elided(instr); ok {
+				info.Instrs[i] = InstrInfo{Instr: instr, PC: -1, Elided: reason}
+				continue
+			}
+			var ifn func(*frame)
+			if succ, ok := fold.jump(instr); ok {
+				ifn = makeJump(b, succ)
+			} else if visit.intp.mode&DisableClosureCompiler != 0 {
+				ifn = makeEvalInstr(visit.intp, pfn, instr)
+			} else {
+				ifn = makeInstr(visit.intp, pfn, instr)
 			}
-			ifn := makeInstr(visit.intp, pfn, instr)
 			if ifn == nil {
+				info.Instrs[i] = InstrInfo{Instr: instr, PC: -1, Elided: elidedReason(instr)}
 				continue
 			}
+			info.Instrs[i] = InstrInfo{Instr: instr, PC: info.Offset + index}
+			if visit.intp.mode&StrictPanicSeparation != 0 {
+				ifn = wrapInternalError(pfn, instr, ifn)
+			}
 			if visit.intp.mode&EnableTracing != 0 {
 				pfn := ifn
 				ifn = func(fr *frame) {
 					if v, ok := instr.(ssa.Value); ok {
-						log.Printf("\t%-20T %v = %-40v\t%v\n", instr, v.Name(), instr, v.Type())
+						fr.interp.tracef(fr, LogTrace, instr.Pos(), "\t%-20T %v = %-40v\t%v", instr, v.Name(), instr, v.Type())
 					} else {
-						log.Printf("\t%-20T %v\n", instr, instr)
+						fr.interp.tracef(fr, LogTrace, instr.Pos(), "\t%-20T %v", instr, instr)
 					}
 					pfn(fr)
 				}
@@ -168,14 +343,14 @@ func (visit *visitor) function(fn *ssa.Function) {
 					pfn := ifn
 					bi := b.Index
 					ifn = func(fr *frame) {
-						log.Printf(".%v\n", bi)
+						fr.interp.tracef(fr, LogTrace, instr.Pos(), ".%v", bi)
 						pfn(fr)
 					}
 				}
 				if index == 0 && b.Index == 0 {
 					pfn := ifn
 					ifn = func(fr *frame) {
-						log.Printf("Entering %v%v.", fr.pfn.Fn, loc(fr.interp.fset, fr.pfn.Fn.Pos()))
+						fr.interp.tracef(fr, LogDebug, fr.pfn.Fn.Pos(), "Entering %v%v.", fr.pfn.Fn, loc(fr.interp.fset, fr.pfn.Fn.Pos()))
 						pfn(fr)
 					}
 				}
@@ -188,23 +363,32 @@ func (visit *visitor) function(fn *ssa.Function) {
 							caller = fr.caller.pfn.InstrForPC(fr.caller.pc - 1)
 						}
 						if caller == nil {
-							log.Printf("Leaving %v.\n", fr.pfn.Fn)
+							fr.interp.tracef(fr, LogDebug, instr.Pos(), "Leaving %v.", fr.pfn.Fn)
 						} else {
-							log.Printf("Leaving %v, resuming %v call %v%v.\n",
+							fr.interp.tracef(fr, LogDebug, instr.Pos(), "Leaving %v, resuming %v call %v%v.",
 								fr.pfn.Fn, fr.caller.pfn.Fn, caller, loc(fr.interp.fset, caller.Pos()))
 						}
 					}
 				}
 			}
+			if visit.intp.mode&EnablePprofLabels != 0 {
+				ifn = wrapPprofLabels(pfn, instr, index == 0 && b.Index == 0, ifn)
+			}
+			if visit.intp.profile != nil {
+				ifn = visit.intp.profile.wrap(fn, instr, index == 0 && b.Index == 0, ifn)
+			}
 			Instrs[index] = ifn
 			ssaInstrs[index] = instr
+			pfn.pos = append(pfn.pos, lastPos)
 			index++
 		}
 		Instrs = Instrs[:index]
+		ssaInstrs = ssaInstrs[:index]
 		offset := len(pfn.Instrs)
 		pfn.Blocks = append(pfn.Blocks, offset)
 		pfn.Instrs = append(pfn.Instrs, Instrs...)
 		pfn.ssaInstrs = append(pfn.ssaInstrs, ssaInstrs...)
+		pfn.blockInfos = append(pfn.blockInfos, info)
 		if b == fn.Recover && visit.intp.mode&DisableRecover == 0 {
 			pfn.Recover = pfn.Instrs[offset:]
 		}
@@ -0,0 +1,13 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package worker
+
+import "errors"
+
+func setLimits(limits []Limit) error {
+	if len(limits) != 0 {
+		return errors.New("worker: resource limits are not supported on this platform")
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package worker
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func setLimits(limits []Limit) error {
+	for _, l := range limits {
+		lim := rlimit(l.Cur, l.Max)
+		if err := syscall.Setrlimit(l.Resource, lim); err != nil {
+			return fmt.Errorf("worker setrlimit %v: %w", l.Resource, err)
+		}
+	}
+	return nil
+}
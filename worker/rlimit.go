@@ -0,0 +1,10 @@
+//go:build aix || darwin || linux || netbsd || openbsd || solaris
+// +build aix darwin linux netbsd openbsd solaris
+
+package worker
+
+import "syscall"
+
+func rlimit(cur, max uint64) *syscall.Rlimit {
+	return &syscall.Rlimit{Cur: cur, Max: max}
+}
@@ -0,0 +1,22 @@
+//go:build dragonfly || freebsd
+// +build dragonfly freebsd
+
+package worker
+
+import (
+	"math"
+	"syscall"
+)
+
+// rlimit returns the limits as the int64 fields of syscall.Rlimit, values
+// above math.MaxInt64 are RLIM_INFINITY.
+func rlimit(cur, max uint64) *syscall.Rlimit {
+	return &syscall.Rlimit{Cur: rlim(cur), Max: rlim(max)}
+}
+
+func rlim(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(v)
+}
@@ -0,0 +1,410 @@
+// Package worker runs scripts in a separate worker process, so hostile
+// scripts can be given OS-level isolation (rlimits, seccomp) while the
+// host keeps the embedding API of gossa.Context.
+//
+// The worker is the host binary itself, started with the WorkerArg
+// argument. The host must call Main at the start of its main function:
+//
+//	func main() {
+//		worker.Main()
+//		...
+//		code, err := (&worker.Runner{Limits: limits}).RunFile("main.go", src, nil)
+//	}
+//
+// Host functions registered by Register are imported by scripts from
+// PkgPath. In the worker they are bridged to the host process by RPC,
+// arguments and results must be gob encodable.
+package worker
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/goplus/gossa"
+)
+
+// WorkerArg is the first argument of the host binary in worker mode.
+const WorkerArg = "gossa-worker"
+
+// PkgPath is the script import path of the host functions.
+const PkgPath = "gossa/host"
+
+var (
+	hostPkg = &gossa.Package{
+		Name:          "host",
+		Path:          PkgPath,
+		Deps:          map[string]string{},
+		Interfaces:    map[string]reflect.Type{},
+		NamedTypes:    map[string]gossa.NamedType{},
+		AliasTypes:    map[string]reflect.Type{},
+		Vars:          map[string]reflect.Value{},
+		Funcs:         map[string]reflect.Value{},
+		TypedConsts:   map[string]gossa.TypedConst{},
+		UntypedConsts: map[string]gossa.UntypedConst{},
+	}
+	sandbox func() error
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+func init() {
+	gossa.RegisterPackage(hostPkg)
+}
+
+// Register registers the host function fn as PkgPath.name. It must be
+// called before Main, in the same order in the host and the worker, for
+// instance from an init function.
+func Register(name string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic(fmt.Errorf("worker.Register %v: not a function: %T", name, fn))
+	}
+	typ := v.Type()
+	for i := 0; i < typ.NumIn(); i++ {
+		registerGob(typ.In(i))
+	}
+	for i := 0; i < typ.NumOut(); i++ {
+		registerGob(typ.Out(i))
+	}
+	hostPkg.Funcs[name] = v
+}
+
+func registerGob(typ reflect.Type) {
+	if typ.Kind() != reflect.Interface {
+		gob.Register(reflect.Zero(typ).Interface())
+	}
+}
+
+// SetSandbox sets the function run by the worker process before the
+// script, after the rlimits are applied. It may install a seccomp filter
+// or drop privileges; an error aborts the run.
+func SetSandbox(fn func() error) {
+	sandbox = fn
+}
+
+// Limit is a resource limit of the worker process, see setrlimit(2).
+type Limit struct {
+	Resource int    // resource, eg. syscall.RLIMIT_CPU
+	Cur      uint64 // soft limit
+	Max      uint64 // hard limit
+}
+
+// Runner runs scripts in worker processes.
+type Runner struct {
+	Path    string        // worker binary, the current executable if empty
+	Mode    gossa.Mode    // interpreter options
+	Dir     string        // working directory of the worker
+	Env     []string      // environment of the worker, os.Environ() if nil
+	Stdin   io.Reader     // script stdin
+	Stdout  io.Writer     // script stdout
+	Stderr  io.Writer     // script stderr
+	Limits  []Limit       // resource limits
+	Timeout time.Duration // kill the worker after, if non-zero
+}
+
+// RunFile runs the file in a worker process, as gossa.Context.RunFile.
+// src must be nil, string, []byte or io.Reader.
+func (r *Runner) RunFile(filename string, src interface{}, args []string) (exitCode int, err error) {
+	job := &Job{Filename: filename, Args: args, Mode: r.Mode, Limits: r.Limits}
+	switch s := src.(type) {
+	case nil:
+	case string:
+		job.Src, job.HasSrc = []byte(s), true
+	case []byte:
+		job.Src, job.HasSrc = s, true
+	case io.Reader:
+		if job.Src, err = ioutil.ReadAll(s); err != nil {
+			return 2, err
+		}
+		job.HasSrc = true
+	default:
+		return 2, fmt.Errorf("worker: invalid source type %T", src)
+	}
+	return r.run(job)
+}
+
+// Run runs the file or package path in a worker process, as
+// gossa.Context.Run.
+func (r *Runner) Run(path string, args []string) (exitCode int, err error) {
+	return r.run(&Job{Path: path, Args: args, Mode: r.Mode, Limits: r.Limits})
+}
+
+func (r *Runner) run(job *Job) (exitCode int, err error) {
+	path := r.Path
+	if path == "" {
+		if path, err = os.Executable(); err != nil {
+			return 2, err
+		}
+	}
+	ctx := context.Background()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	cmdR, hostW, err := os.Pipe()
+	if err != nil {
+		return 2, err
+	}
+	hostR, cmdW, err := os.Pipe()
+	if err != nil {
+		cmdR.Close()
+		hostW.Close()
+		return 2, err
+	}
+	cmd := exec.CommandContext(ctx, path, WorkerArg)
+	cmd.Dir = r.Dir
+	cmd.Env = r.Env
+	cmd.Stdin = r.Stdin
+	cmd.Stdout = r.Stdout
+	cmd.Stderr = r.Stderr
+	cmd.ExtraFiles = []*os.File{cmdR, cmdW}
+	err = cmd.Start()
+	cmdR.Close()
+	cmdW.Close()
+	if err != nil {
+		hostR.Close()
+		hostW.Close()
+		return 2, err
+	}
+	h := &Host{job: job, done: make(chan struct{})}
+	srv := rpc.NewServer()
+	srv.Register(h)
+	go func() {
+		srv.ServeConn(&pipeConn{hostR, hostW})
+	}()
+	err = cmd.Wait()
+	hostR.Close()
+	hostW.Close()
+	select {
+	case <-h.done:
+		if h.result.Err != "" {
+			return h.result.ExitCode, errors.New(h.result.Err)
+		}
+		return h.result.ExitCode, nil
+	default:
+	}
+	if ctx.Err() != nil {
+		return 2, fmt.Errorf("worker: %w", ctx.Err())
+	}
+	if err == nil {
+		err = errors.New("worker exited without result")
+	}
+	exitCode = 2
+	if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() > 0 {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	return exitCode, fmt.Errorf("worker: %w", err)
+}
+
+// Job is the run request sent to a worker.
+type Job struct {
+	Filename string
+	Src      []byte
+	HasSrc   bool
+	Path     string
+	Args     []string
+	Mode     gossa.Mode
+	Limits   []Limit
+}
+
+// Result is the run result sent by a worker.
+type Result struct {
+	ExitCode int
+	Err      string
+}
+
+// Call is a host function call sent by a worker.
+type Call struct {
+	Name string
+	Args []interface{}
+}
+
+// Reply is the results of a host function call.
+type Reply struct {
+	Results []interface{}
+	Errors  map[int]string // error results by index, nil errors are absent
+}
+
+// Host is the RPC service of the host process for a worker.
+type Host struct {
+	job    *Job
+	result Result
+	once   sync.Once
+	done   chan struct{}
+}
+
+// Job returns the run request of the worker.
+func (h *Host) Job(_ int, job *Job) error {
+	*job = *h.job
+	return nil
+}
+
+// Done records the run result of the worker.
+func (h *Host) Done(result Result, _ *int) error {
+	h.once.Do(func() {
+		h.result = result
+		close(h.done)
+	})
+	return nil
+}
+
+// Call calls a registered host function.
+func (h *Host) Call(call Call, reply *Reply) (err error) {
+	fn, ok := hostPkg.Funcs[call.Name]
+	if !ok {
+		return fmt.Errorf("undefined: %v.%v", PkgPath, call.Name)
+	}
+	typ := fn.Type()
+	if len(call.Args) != typ.NumIn() {
+		return fmt.Errorf("%v.%v: bad number of arguments %v", PkgPath, call.Name, len(call.Args))
+	}
+	args := make([]reflect.Value, len(call.Args))
+	for i, arg := range call.Args {
+		if arg == nil {
+			args[i] = reflect.Zero(typ.In(i))
+		} else {
+			args[i] = reflect.ValueOf(arg)
+		}
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%v.%v: panic: %v", PkgPath, call.Name, v)
+		}
+	}()
+	var rs []reflect.Value
+	if typ.IsVariadic() {
+		rs = fn.CallSlice(args)
+	} else {
+		rs = fn.Call(args)
+	}
+	reply.Results, reply.Errors = encodeResults(rs)
+	return nil
+}
+
+func encodeResults(rs []reflect.Value) (results []interface{}, errs map[int]string) {
+	results = make([]interface{}, len(rs))
+	for i, r := range rs {
+		if r.Type() == errorType {
+			if !r.IsNil() {
+				if errs == nil {
+					errs = make(map[int]string)
+				}
+				errs[i] = r.Interface().(error).Error()
+			}
+			continue
+		}
+		results[i] = r.Interface()
+	}
+	return
+}
+
+// Main runs the worker and exits if the process is started in worker mode,
+// otherwise it returns.
+func Main() {
+	if len(os.Args) < 2 || os.Args[1] != WorkerArg {
+		return
+	}
+	conn := &pipeConn{os.NewFile(3, "gossa-worker-r"), os.NewFile(4, "gossa-worker-w")}
+	client := rpc.NewClient(conn)
+	var job Job
+	if err := client.Call("Host.Job", 0, &job); err != nil {
+		fmt.Fprintln(os.Stderr, "gossa-worker:", err)
+		os.Exit(2)
+	}
+	code, err := serve(client, &job)
+	result := Result{ExitCode: code}
+	if err != nil {
+		result.Err = err.Error()
+	}
+	var ack int
+	client.Call("Host.Done", result, &ack)
+	client.Close()
+	os.Exit(code)
+}
+
+func serve(client *rpc.Client, job *Job) (int, error) {
+	if err := setLimits(job.Limits); err != nil {
+		return 2, err
+	}
+	if sandbox != nil {
+		if err := sandbox(); err != nil {
+			return 2, fmt.Errorf("worker sandbox: %w", err)
+		}
+	}
+	for name, fn := range hostPkg.Funcs {
+		hostPkg.Funcs[name] = bridge(client, name, fn.Type())
+	}
+	ctx := gossa.NewContext(job.Mode)
+	if job.Path != "" {
+		return ctx.Run(job.Path, job.Args)
+	}
+	var src interface{}
+	if job.HasSrc {
+		src = job.Src
+	}
+	return ctx.RunFile(job.Filename, src, job.Args)
+}
+
+// bridge returns a func of type typ calling the host function name.
+func bridge(client *rpc.Client, name string, typ reflect.Type) reflect.Value {
+	return reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		call := Call{Name: name, Args: make([]interface{}, len(args))}
+		for i, arg := range args {
+			call.Args[i] = arg.Interface()
+		}
+		var reply Reply
+		if err := client.Call("Host.Call", call, &reply); err != nil {
+			panic(err)
+		}
+		rs := make([]reflect.Value, typ.NumOut())
+		for i := range rs {
+			out := typ.Out(i)
+			if msg, ok := reply.Errors[i]; ok {
+				rs[i] = reflect.ValueOf(errors.New(msg))
+			} else if i < len(reply.Results) && reply.Results[i] != nil {
+				rs[i] = reflect.ValueOf(reply.Results[i])
+			} else {
+				rs[i] = reflect.Zero(out)
+			}
+			if rs[i].Type() != out {
+				v := reflect.New(out).Elem()
+				v.Set(rs[i])
+				rs[i] = v
+			}
+		}
+		return rs
+	})
+}
+
+// pipeConn joins the two pipes of a worker into a connection.
+type pipeConn struct {
+	r *os.File
+	w *os.File
+}
+
+func (c *pipeConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *pipeConn) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+func (c *pipeConn) Close() error {
+	err := c.r.Close()
+	if e := c.w.Close(); err == nil {
+		err = e
+	}
+	return err
+}
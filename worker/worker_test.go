@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/goplus/gossa/pkg/os"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+func init() {
+	Register("Add", func(a, b int) int { return a + b })
+	Register("Join", func(sep string, s ...string) string { return strings.Join(s, sep) })
+	Register("Check", func(s string) (string, error) {
+		if s == "" {
+			return "", errors.New("empty")
+		}
+		return s, nil
+	})
+}
+
+func TestRunner(t *testing.T) {
+	src := `package main
+
+import (
+	"os"
+	"gossa/host"
+)
+
+func main() {
+	println(host.Add(1, 2), host.Join(",", "a", "b"), os.Getpid() != 0)
+	if _, err := host.Check(""); err == nil || err.Error() != "empty" {
+		panic(err)
+	}
+	if s, err := host.Check("ok"); err != nil || s != "ok" {
+		panic(err)
+	}
+	os.Exit(3)
+}
+`
+	var stdout bytes.Buffer
+	r := &Runner{Stdout: &stdout}
+	code, err := r.RunFile("main.go", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 3 {
+		t.Fatalf("exit code %v, output %v", code, stdout.String())
+	}
+	if s := stdout.String(); s != "3 a,b true\n" {
+		t.Fatalf("bad output %q", s)
+	}
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	src := `package main
+
+func main() {
+	for {
+	}
+}
+`
+	r := &Runner{Timeout: 500 * time.Millisecond}
+	_, err := r.RunFile("main.go", src, nil)
+	if err == nil || !strings.Contains(err.Error(), "deadline") {
+		t.Fatalf("bad timeout error %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package gossa
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// In DisableMethodSynthesis mode the types of the script have no methods
+// for reflect, so their values do not implement the interfaces of the
+// host. A value of the script converted to a host interface of
+// ifaceWrappers is wrapped by a host type implementing the interface by
+// the methods of the value, which are called by the interpreter.
+
+// ifaceWrappers are the wrappers of the values of the script converted to
+// host interfaces.
+var ifaceWrappers = map[reflect.Type]func(r recvValue) interface{}{
+	tyErrorInterface: func(r recvValue) interface{} { return errorWrapper{r} },
+	reflect.TypeOf((*fmt.Stringer)(nil)).Elem(): func(r recvValue) interface{} { return stringerWrapper{r} },
+	reflect.TypeOf((*io.Reader)(nil)).Elem():    func(r recvValue) interface{} { return readerWrapper{r} },
+	reflect.TypeOf((*io.Writer)(nil)).Elem():    func(r recvValue) interface{} { return writerWrapper{r} },
+	reflect.TypeOf((*io.Closer)(nil)).Elem():    func(r recvValue) interface{} { return closerWrapper{r} },
+	reflect.TypeOf((*sort.Interface)(nil)).Elem(): func(r recvValue) interface{} {
+		return sortWrapper{r}
+	},
+}
+
+// recvValue is a value of the script wrapped for a host interface.
+type recvValue struct {
+	interp *Interp
+	v      value
+}
+
+// wrapped is implemented by the wrappers of recvValue.
+type wrapped interface {
+	recv() recvValue
+}
+
+func (r recvValue) recv() recvValue {
+	return r
+}
+
+// call calls the method name of the value with args.
+func (r recvValue) call(name string, args ...value) value {
+	fn, ok := r.interp.findMethod(reflect.TypeOf(r.v), name)
+	if !ok {
+		panic(fmt.Errorf("no code for method: %T.%v", r.v, name))
+	}
+	return r.interp.call(nil, fn, append([]value{r.v}, args...), nil)
+}
+
+// wrapIface returns x converted to the interface type typ in
+// DisableMethodSynthesis mode, wrapped if typ is a host interface of
+// ifaceWrappers and the type of x does not implement it for reflect.
+func (i *Interp) wrapIface(typ reflect.Type, x value) value {
+	if x == nil {
+		return nil
+	}
+	if wrap, ok := ifaceWrappers[typ]; ok && !reflect.TypeOf(x).Implements(typ) {
+		return wrap(recvValue{i, x})
+	}
+	return x
+}
+
+// unwrapIface returns the value of the script wrapped by x, or x.
+func unwrapIface(x value) value {
+	if w, ok := x.(wrapped); ok {
+		return w.recv().v
+	}
+	return x
+}
+
+// implements reports whether the value x of a script type implements the
+// interface type T of the script in DisableMethodSynthesis mode.
+func (i *Interp) implements(x value, T types.Type) bool {
+	itype, ok := T.Underlying().(*types.Interface)
+	if !ok {
+		return false
+	}
+	if t, ok := i.findType(reflect.TypeOf(x), false); ok {
+		return types.Implements(t, itype)
+	}
+	return false
+}
+
+type errorWrapper struct{ recvValue }
+
+func (w errorWrapper) Error() string {
+	return w.call("Error").(string)
+}
+
+type stringerWrapper struct{ recvValue }
+
+func (w stringerWrapper) String() string {
+	return w.call("String").(string)
+}
+
+type readerWrapper struct{ recvValue }
+
+func (w readerWrapper) Read(p []byte) (int, error) {
+	r := w.call("Read", p).(tuple)
+	err, _ := r[1].(error)
+	return r[0].(int), err
+}
+
+type writerWrapper struct{ recvValue }
+
+func (w writerWrapper) Write(p []byte) (int, error) {
+	r := w.call("Write", p).(tuple)
+	err, _ := r[1].(error)
+	return r[0].(int), err
+}
+
+type closerWrapper struct{ recvValue }
+
+func (w closerWrapper) Close() error {
+	err, _ := w.call("Close").(error)
+	return err
+}
+
+type sortWrapper struct{ recvValue }
+
+func (w sortWrapper) Len() int {
+	return w.call("Len").(int)
+}
+
+func (w sortWrapper) Less(i, j int) bool {
+	return w.call("Less", i, j).(bool)
+}
+
+func (w sortWrapper) Swap(i, j int) {
+	w.call("Swap", i, j)
+}
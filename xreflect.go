@@ -0,0 +1,201 @@
+package gossa
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/goplus/reflectx"
+	"golang.org/x/tools/go/ssa"
+)
+
+// The interpreter emulates the methods of reflect.Type and reflect.Value
+// which report interpreter defined types, built by reflectx, otherwise
+// than compiled Go. They run for the reflect calls of the target program,
+// not of hosts.
+var typeOfType = reflect.TypeOf(reflect.TypeOf(0))
+
+// reflectTypeMethod returns the emulated method name of reflect.Type.
+func (i *Interp) reflectTypeMethod(name string) (ext reflect.Value, ok bool) {
+	switch name {
+	case "Method":
+		return reflect.ValueOf(i.typeMethod), true
+	case "MethodByName":
+		return reflect.ValueOf(i.typeMethodByName), true
+	case "NumMethod":
+		return reflect.ValueOf(i.typeNumMethod), true
+	case "String":
+		return reflect.ValueOf(i.typeString), true
+	}
+	return
+}
+
+// findReflectFunc returns the emulated method name of reflect.Value.
+func findReflectFunc(interp *Interp, name string) (ext reflect.Value, ok bool) {
+	var fn interface{}
+	switch name {
+	case "(reflect.Value).Method":
+		fn = func(v reflect.Value, index int) reflect.Value {
+			names, mset, ok := interp.reflectValueMethods(v)
+			if !ok {
+				return v.Method(index)
+			}
+			if index < 0 || index >= len(names) {
+				panic("reflect: Method index out of range")
+			}
+			return interp.methodValue(v, mset[names[index]])
+		}
+	case "(reflect.Value).MethodByName":
+		fn = func(v reflect.Value, name string) reflect.Value {
+			_, mset, ok := interp.reflectValueMethods(v)
+			if !ok {
+				return v.MethodByName(name)
+			}
+			if f, ok := mset[name]; ok && token.IsExported(name) {
+				return interp.methodValue(v, f)
+			}
+			return reflect.Value{}
+		}
+	case "(reflect.Value).NumMethod":
+		fn = func(v reflect.Value) int {
+			if names, _, ok := interp.reflectValueMethods(v); ok {
+				return len(names)
+			}
+			return v.NumMethod()
+		}
+	default:
+		return
+	}
+	return reflect.ValueOf(fn), true
+}
+
+// reflectMethods returns the names of the exported methods of the interpreter
+// defined type t in the order of reflect, and its method set.
+func (i *Interp) reflectMethods(t reflect.Type) (names []string, mset map[string]*ssa.Function, ok bool) {
+	if t.Kind() == reflect.Interface {
+		return nil, nil, false
+	}
+	if mset, ok = i.msets[t]; !ok {
+		return
+	}
+	for name := range mset {
+		if token.IsExported(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// reflectValueMethods is reflectMethods of the type of v, for the values of the
+// interpreter defined types which can be called.
+func (i *Interp) reflectValueMethods(v reflect.Value) ([]string, map[string]*ssa.Function, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, nil, false
+	}
+	return i.reflectMethods(v.Type())
+}
+
+func (i *Interp) typeMethod(t reflect.Type, index int) reflect.Method {
+	names, mset, ok := i.reflectMethods(t)
+	if !ok {
+		// method tables of reflectx are not read by reflect
+		return reflectx.MethodByIndex(t, index)
+	}
+	if index < 0 || index >= len(names) {
+		panic("reflect: Method index out of range")
+	}
+	return i.reflectMethod(t, mset[names[index]], index)
+}
+
+func (i *Interp) typeMethodByName(t reflect.Type, name string) (reflect.Method, bool) {
+	names, mset, ok := i.reflectMethods(t)
+	if !ok {
+		return reflectx.MethodByName(t, name)
+	}
+	if fn, ok := mset[name]; ok && token.IsExported(name) {
+		return i.reflectMethod(t, fn, sort.SearchStrings(names, name)), true
+	}
+	return reflect.Method{}, false
+}
+
+func (i *Interp) typeNumMethod(t reflect.Type) int {
+	if names, _, ok := i.reflectMethods(t); ok {
+		return len(names)
+	}
+	return t.NumMethod()
+}
+
+// reflectMethod returns the method fn of type t with the receiver as the
+// first argument, calling the interpreted fn without the reflectx wrapper.
+func (i *Interp) reflectMethod(t reflect.Type, fn *ssa.Function, index int) reflect.Method {
+	ftyp := i.preToType(fn.Signature)
+	in := []reflect.Type{t}
+	for n := 0; n < ftyp.NumIn(); n++ {
+		in = append(in, ftyp.In(n))
+	}
+	out := make([]reflect.Type, ftyp.NumOut())
+	for n := range out {
+		out[n] = ftyp.Out(n)
+	}
+	mtyp := reflect.FuncOf(in, out, ftyp.IsVariadic())
+	pfn := i.loadFunction(fn)
+	return reflect.Method{
+		Name:  fn.Name(),
+		Type:  mtyp,
+		Index: index,
+		Func: reflect.MakeFunc(mtyp, func(args []reflect.Value) []reflect.Value {
+			return i.callFunctionByReflect(i.tryDeferFrame(), mtyp, pfn, args, nil)
+		}),
+	}
+}
+
+// methodValue returns the method fn bound to the receiver v.
+func (i *Interp) methodValue(v reflect.Value, fn *ssa.Function) reflect.Value {
+	ftyp := i.preToType(fn.Signature)
+	pfn := i.loadFunction(fn)
+	return reflect.MakeFunc(ftyp, func(args []reflect.Value) []reflect.Value {
+		return i.callFunctionByReflect(i.tryDeferFrame(), ftyp, pfn, append([]reflect.Value{v}, args...), nil)
+	})
+}
+
+// typeString implements reflect.Type.String. Named types are qualified by
+// their package name, as reflectx takes the last element of the package
+// path, eg. "v2.T" for the type T of package "example.com/greet/v2".
+func (i *Interp) typeString(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() != "" {
+			if typ, ok := i.findType(t, false); ok {
+				if named, ok := typ.(*types.Named); ok && named.Obj().Pkg() != nil {
+					return named.Obj().Pkg().Name() + "." + t.Name()
+				}
+			}
+		}
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + i.typeString(t.Elem())
+	case reflect.Slice:
+		return "[]" + i.typeString(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + i.typeString(t.Elem())
+	case reflect.Map:
+		return "map[" + i.typeString(t.Key()) + "]" + i.typeString(t.Elem())
+	case reflect.Chan:
+		elem := i.typeString(t.Elem())
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			return "<-chan " + elem
+		case reflect.SendDir:
+			return "chan<- " + elem
+		}
+		if t.Elem().Kind() == reflect.Chan && t.Elem().ChanDir() == reflect.RecvDir {
+			return "chan (" + elem + ")"
+		}
+		return "chan " + elem
+	}
+	return t.String()
+}
@@ -105,6 +105,9 @@ func toMockType(typ types.Type) reflect.Type {
 		for i := 0; i < in; i++ {
 			ins[i] = tyEmptyStruct
 		}
+		if t.Variadic() {
+			ins[in-1] = tyEmptySlice
+		}
 		for i := 0; i < out; i++ {
 			outs[i] = tyEmptyStruct
 		}
@@ -151,15 +154,29 @@ type TypesRecord struct {
 	finder FindMethod
 	rcache map[reflect.Type]types.Type
 	tcache *typeutil.Map
+	gen    int // loader registration generation, see TypesLoader.RegisterPackage
+	// in DisableMethodSynthesis mode the types have no methods and the
+	// interfaces with methods are empty interfaces
+	nomethods bool
+}
+
+// generationLoader is a Loader whose packages may be replaced.
+type generationLoader interface {
+	generation() int
+	lookupTypesAt(rt reflect.Type, gen int) (types.Type, bool)
 }
 
 func NewTypesRecord(loader Loader, finder FindMethod) *TypesRecord {
-	return &TypesRecord{
+	r := &TypesRecord{
 		loader: loader,
 		finder: finder,
 		rcache: make(map[reflect.Type]types.Type),
 		tcache: &typeutil.Map{},
 	}
+	if l, ok := loader.(generationLoader); ok {
+		r.gen = l.generation()
+	}
+	return r
 }
 
 func (r *TypesRecord) LookupReflect(typ types.Type) (rt reflect.Type, ok bool) {
@@ -178,7 +195,11 @@ func (r *TypesRecord) LookupLocalTypes(rt reflect.Type) (typ types.Type, ok bool
 }
 
 func (r *TypesRecord) LookupTypes(rt reflect.Type) (typ types.Type, ok bool) {
-	typ, ok = r.loader.LookupTypes(rt)
+	if l, isgen := r.loader.(generationLoader); isgen {
+		typ, ok = l.lookupTypesAt(rt, r.gen)
+	} else {
+		typ, ok = r.loader.LookupTypes(rt)
+	}
 	if !ok {
 		typ, ok = r.rcache[rt]
 	}
@@ -248,7 +269,7 @@ type _tuple struct{}
 
 func (r *TypesRecord) toInterfaceType(t *types.Interface) reflect.Type {
 	n := t.NumMethods()
-	if n == 0 {
+	if n == 0 || r.nomethods {
 		return tyEmptyInterface
 	}
 	ms := make([]reflect.Method, n, n)
@@ -275,7 +296,10 @@ func (r *TypesRecord) toNamedType(t *types.Named) reflect.Type {
 		}
 		return r.ToType(ut)
 	}
-	methods := IntuitiveMethodSet(t)
+	var methods []*types.Selection
+	if !r.nomethods {
+		methods = IntuitiveMethodSet(t)
+	}
 	numMethods := len(methods)
 	if numMethods == 0 {
 		styp := toMockType(t.Underlying())
@@ -317,6 +341,9 @@ func (r *TypesRecord) toStructType(t *types.Struct) reflect.Type {
 		flds[i] = r.toStructField(t.Field(i), t.Tag(i))
 	}
 	typ := reflectx.StructOf(flds)
+	if r.nomethods {
+		return typ
+	}
 	methods := IntuitiveMethodSet(t)
 	if numMethods := len(methods); numMethods != 0 {
 		// anonymous structs with methods. struct { T }
@@ -378,17 +405,32 @@ func (r *TypesRecord) setMethods(typ reflect.Type, methods []*types.Selection) {
 		idx := methods[i].Index()
 		if len(idx) > 1 {
 			isptr := isPointer(fn.Type().Underlying().(*types.Signature).Recv().Type())
+			variadic := sig.Variadic()
 			mfn = func(args []reflect.Value) []reflect.Value {
 				v := args[0]
 				for v.Kind() == reflect.Ptr {
 					v = v.Elem()
 				}
 				v = reflectx.FieldByIndexX(v, idx[:len(idx)-1])
-				if isptr && v.Kind() != reflect.Ptr {
+				if v.Kind() == reflect.Interface {
+					// method promoted from an embedded interface
+					if v.IsNil() {
+						panic(runtimeError{msg: "invalid memory address or nil pointer dereference"})
+					}
+					v = v.Elem()
+				} else if isptr && v.Kind() != reflect.Ptr {
+					if !v.CanAddr() {
+						c := reflect.New(v.Type()).Elem()
+						c.Set(v)
+						v = c
+					}
 					v = v.Addr()
 				}
 				m, _ := reflectx.MethodByName(v.Type(), fn.Name())
 				args[0] = v
+				if variadic {
+					return m.Func.CallSlice(args)
+				}
 				return m.Func.Call(args)
 			}
 		} else {